@@ -0,0 +1,132 @@
+// Package main provides the agent-sandbox command line client.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+
+	"github.com/HJH0924/agent-sandbox/internal/portforward"
+	"github.com/HJH0924/agent-sandbox/sdk/go/preview/v1/previewv1connect"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	Version = "1.0.0"
+)
+
+var (
+	serverURL string
+	apiKey    string
+	address   string
+	rootCmd   = &cobra.Command{
+		Use:     "agent-sandbox",
+		Short:   "Agent Sandbox command line client",
+		Version: Version,
+	}
+	portForwardCmd = &cobra.Command{
+		Use:   "port-forward [LOCAL_PORT:]REMOTE_PORT...",
+		Short: "Forward local ports to ports inside a sandbox",
+		Long: "Listen on local ports and forward each connection to the same or another port inside the sandbox.\n" +
+			"Use 0 or leave LOCAL_PORT empty (:5432) to pick a random local port.",
+		Example: "  agent-sandbox port-forward 5432\n  agent-sandbox port-forward 15432:5432 :9229",
+		Args:    cobra.MinimumNArgs(1),
+		RunE:    runPortForward,
+	}
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringVarP(&serverURL, "server", "s", envOrDefault("AGENT_SANDBOX_SERVER", "http://localhost:8080"), "API server URL (env AGENT_SANDBOX_SERVER)")
+	rootCmd.PersistentFlags().StringVarP(&apiKey, "api-key", "k", os.Getenv("AGENT_SANDBOX_API_KEY"), "sandbox API key (env AGENT_SANDBOX_API_KEY)")
+	portForwardCmd.Flags().StringVar(&address, "address", "127.0.0.1", "local address to listen on")
+	rootCmd.AddCommand(portForwardCmd)
+}
+
+func main() {
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func runPortForward(cmd *cobra.Command, args []string) error {
+	if apiKey == "" {
+		return errors.New("API key is required, set --api-key or AGENT_SANDBOX_API_KEY")
+	}
+
+	mappings := make([]portforward.Mapping, 0, len(args))
+
+	for _, arg := range args {
+		m, err := portforward.ParseMapping(arg)
+		if err != nil {
+			return err
+		}
+
+		mappings = append(mappings, m)
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	client := previewv1connect.NewPreviewServiceClient(portforward.NewHTTPClient(), serverURL)
+	forwarder := portforward.New(client, apiKey, logger)
+
+	// 先监听所有端口，任一端口不可用时直接退出
+	listeners := make([]net.Listener, 0, len(mappings))
+	defer func() {
+		for _, l := range listeners {
+			_ = l.Close()
+		}
+	}()
+
+	for _, m := range mappings {
+		var lc net.ListenConfig
+
+		l, err := lc.Listen(ctx, "tcp", net.JoinHostPort(address, strconv.Itoa(m.Local)))
+		if err != nil {
+			return fmt.Errorf("failed to listen on local port %d: %w", m.Local, err)
+		}
+
+		listeners = append(listeners, l)
+
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Forwarding from %s -> %d\n", l.Addr(), m.Remote)
+	}
+
+	var wg sync.WaitGroup
+
+	errs := make(chan error, len(mappings))
+
+	for i, m := range mappings {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if err := forwarder.Serve(ctx, listeners[i], m.Remote); err != nil {
+				errs <- err
+
+				stop()
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	return <-errs
+}
+
+func envOrDefault(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+
+	return fallback
+}
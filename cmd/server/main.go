@@ -3,22 +3,42 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/HJH0924/agent-sandbox/domain/admin"
+	adminService "github.com/HJH0924/agent-sandbox/domain/admin/service"
+	"github.com/HJH0924/agent-sandbox/domain/code"
+	codeService "github.com/HJH0924/agent-sandbox/domain/code/service"
 	"github.com/HJH0924/agent-sandbox/domain/core"
 	coreService "github.com/HJH0924/agent-sandbox/domain/core/service"
 	"github.com/HJH0924/agent-sandbox/domain/file"
 	fileService "github.com/HJH0924/agent-sandbox/domain/file/service"
+	"github.com/HJH0924/agent-sandbox/domain/preview"
+	previewService "github.com/HJH0924/agent-sandbox/domain/preview/service"
+	"github.com/HJH0924/agent-sandbox/domain/schedule"
+	scheduleService "github.com/HJH0924/agent-sandbox/domain/schedule/service"
 	"github.com/HJH0924/agent-sandbox/domain/shell"
 	shellService "github.com/HJH0924/agent-sandbox/domain/shell/service"
+	"github.com/HJH0924/agent-sandbox/internal/approval"
 	"github.com/HJH0924/agent-sandbox/internal/config"
+	"github.com/HJH0924/agent-sandbox/internal/diagnostic"
+	"github.com/HJH0924/agent-sandbox/internal/history"
+	"github.com/HJH0924/agent-sandbox/internal/launcher"
+	"github.com/HJH0924/agent-sandbox/internal/policy"
+	"github.com/HJH0924/agent-sandbox/internal/recording"
 	"github.com/HJH0924/agent-sandbox/internal/router"
+	"github.com/HJH0924/agent-sandbox/internal/sandbox"
+	"github.com/HJH0924/agent-sandbox/internal/secret"
+	"github.com/HJH0924/agent-sandbox/internal/testreport"
 
 	"github.com/spf13/cobra"
 )
@@ -43,6 +63,9 @@ func init() {
 }
 
 func main() {
+	// 作为命令启动器重新执行时直接应用设置并执行目标命令
+	launcher.Init()
+
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
@@ -57,8 +80,11 @@ func run(_ *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
+	// 创建沙箱密钥存储，日志中的密钥值被替换
+	secrets := secret.NewStore()
+
 	// 初始化日志
-	logger := initLogger(cfg.Log)
+	logger := initLogger(cfg.Log, secrets)
 	slog.SetDefault(logger)
 
 	logger.Info("starting agent sandbox server",
@@ -76,31 +102,141 @@ func run(_ *cobra.Command, _ []string) {
 	// 创建 API Key 存储
 	apiKeyStore := coreService.NewMemoryAPIKeyStore()
 
+	// 创建沙箱用户分配器
+	users := initUsers(cfg.Sandbox, logger)
+
+	// 默认网络策略
+	defaultNetwork, err := sandbox.ParseNetworkPolicy(cfg.Sandbox.DefaultNetwork)
+	if err != nil {
+		logger.Error("invalid default network policy", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	// 创建沙箱注册表
+	registry := sandbox.NewRegistry()
+
+	// 创建命令审批队列
+	approvals := approval.NewQueue(time.Duration(cfg.Sandbox.ApprovalTimeout) * time.Second)
+
+	if cfg.Server.AdminAPIKey == "" {
+		logger.Warn("admin API key not configured, commands requiring approval cannot be approved")
+	}
+
+	// 创建命令执行历史和录制存储
+	executions := initHistory(cfg.Sandbox.History)
+	recordings := initRecordings(cfg.Sandbox.Recording, logger)
+
 	// 创建服务
-	coreSvc := coreService.NewService(apiKeyStore)
-	fileSvc := fileService.NewService(cfg.Sandbox.MaxFileSize, cfg.Sandbox.WorkspaceDir)
-	shellSvc := shellService.NewService(cfg.Sandbox.ShellTimeout, cfg.Sandbox.WorkspaceDir)
+	fileSvc := fileService.NewService(cfg.Sandbox.MaxFileSize, cfg.Sandbox.WorkspaceDir,
+		fileService.WithUsers(users),
+		fileService.WithSecrets(secrets),
+	)
+	shellSvc := shellService.NewService(cfg.Sandbox.ShellTimeout, cfg.Sandbox.WorkspaceDir,
+		shellService.WithUsers(users),
+		shellService.WithRegistry(registry),
+		shellService.WithShell(shellService.Shell{
+			Path:  cfg.Sandbox.Shell,
+			Login: cfg.Sandbox.LoginShell,
+		}),
+		shellService.WithEnvironment(initEnvironment(cfg.Sandbox.Environment, logger)),
+		shellService.WithPolicy(initPolicy(cfg.Sandbox.Policy, logger)),
+		shellService.WithApprovals(approvals),
+		shellService.WithConcurrency(shellService.Concurrency{
+			MaxExecutions: cfg.Sandbox.Concurrency.MaxExecutions,
+			MaxPerSandbox: cfg.Sandbox.Concurrency.MaxPerSandbox,
+			QueueSize:     cfg.Sandbox.Concurrency.QueueSize,
+		}),
+		shellService.WithIsolation(initIsolation(cfg.Sandbox, logger)),
+		shellService.WithMaxOutputSize(cfg.Sandbox.MaxOutputSize),
+		shellService.WithOutputSpill(cfg.Sandbox.SpillOutput),
+		shellService.WithKillGracePeriod(time.Duration(cfg.Sandbox.KillGracePeriod)*time.Second),
+		shellService.WithResourceLimits(shellService.ResourceLimits{
+			CPUSeconds:   cfg.Sandbox.Limits.CPUSeconds,
+			AddressSpace: cfg.Sandbox.Limits.AddressSpace,
+			OpenFiles:    cfg.Sandbox.Limits.OpenFiles,
+			MaxProcesses: cfg.Sandbox.Limits.MaxProcesses,
+			FileSize:     cfg.Sandbox.Limits.FileSize,
+		}),
+		shellService.WithCgroup(initCgroup(cfg.Sandbox.Limits, logger)),
+		shellService.WithHistory(executions),
+		shellService.WithRecordings(recordings),
+		shellService.WithSecrets(secrets),
+	)
+	codeSvc := codeService.NewService(shellSvc, initLanguages(cfg.Sandbox.Languages),
+		codeService.WithCellTimeout(time.Duration(cfg.Sandbox.ShellTimeout)*time.Second),
+		codeService.WithTestRunners(initTestRunners(cfg.Sandbox.TestRunners, logger)),
+		codeService.WithCheckers(initCheckers(cfg.Sandbox.Checkers, logger)),
+	)
+	scheduleSvc := scheduleService.NewService(shellSvc)
+
+	// 销毁沙箱时停止其定时任务、会话、终端和内核，并删除密钥、执行历史和录制
+	coreOpts := []coreService.Option{
+		coreService.WithUsers(users),
+		coreService.WithRegistry(registry),
+		coreService.WithDefaultNetwork(defaultNetwork),
+		coreService.WithCleanup(scheduleSvc.RemoveSandbox),
+		coreService.WithCleanup(shellSvc.CloseSandbox),
+		coreService.WithCleanup(codeSvc.CloseSandbox),
+		coreService.WithCleanup(secrets.Remove),
+	}
+	if executions != nil {
+		coreOpts = append(coreOpts, coreService.WithCleanup(executions.Remove))
+	}
+
+	if recordings != nil {
+		coreOpts = append(coreOpts, coreService.WithCleanup(recordings.Remove))
+	}
+
+	coreSvc := coreService.NewService(apiKeyStore, coreOpts...)
 
 	// 创建处理器
 	coreHandler := core.NewHandler(coreSvc, logger)
 	fileHandler := file.NewHandler(fileSvc, logger)
 	shellHandler := shell.NewHandler(shellSvc, logger)
+	codeHandler := code.NewHandler(codeSvc, logger)
+	scheduleHandler := schedule.NewHandler(scheduleSvc, logger)
+	adminHandler := admin.NewHandler(adminService.NewService(approvals), logger)
+
+	// 端口预览
+	var (
+		previewHandler *preview.Handler
+		previewProxy   *preview.Proxy
+	)
+
+	if cfg.Server.Preview.Enabled {
+		previewSvc := previewService.NewService(registry, initPreviewSecret(cfg.Server.Preview, logger),
+			previewService.WithTokenTTL(time.Duration(cfg.Server.Preview.TokenTTL)*time.Second),
+		)
+		previewHandler = preview.NewHandler(previewSvc, logger)
+		previewProxy = preview.NewProxy(previewSvc, apiKeyStore, logger)
+	}
 
 	// 设置路由
 	handler := router.Setup(&router.Config{
-		CoreHandler:  coreHandler,
-		FileHandler:  fileHandler,
-		ShellHandler: shellHandler,
-		APIKeyStore:  apiKeyStore,
-		Logger:       logger,
+		CoreHandler:     coreHandler,
+		FileHandler:     fileHandler,
+		ShellHandler:    shellHandler,
+		CodeHandler:     codeHandler,
+		AdminHandler:    adminHandler,
+		PreviewHandler:  previewHandler,
+		PreviewProxy:    previewProxy,
+		ScheduleHandler: scheduleHandler,
+		AdminAPIKey:     cfg.Server.AdminAPIKey,
+		APIKeyStore:     apiKeyStore,
+		Logger:          logger,
 	})
 
-	// 创建 HTTP 服务器
+	// 创建 HTTP 服务器（启用 h2c 以支持双向流式接口）
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetUnencryptedHTTP2(true)
+
 	server := &http.Server{
 		Addr:         cfg.Server.Address(),
 		Handler:      handler,
 		ReadTimeout:  cfg.Server.ReadTimeout,
 		WriteTimeout: cfg.Server.WriteTimeout,
+		Protocols:    protocols,
 	}
 
 	// 启动服务器
@@ -132,7 +268,7 @@ func run(_ *cobra.Command, _ []string) {
 	logger.Info("server stopped")
 }
 
-func initLogger(cfg config.LogConfig) *slog.Logger {
+func initLogger(cfg config.LogConfig, secrets *secret.Store) *slog.Logger {
 	// 解析日志级别
 	levelMap := map[string]slog.Level{
 		"debug": slog.LevelDebug,
@@ -156,5 +292,237 @@ func initLogger(cfg config.LogConfig) *slog.Logger {
 		handler = slog.NewTextHandler(os.Stdout, opts)
 	}
 
-	return slog.New(handler)
+	return slog.New(secret.NewLogHandler(handler, secrets))
+}
+
+// initCgroup 按配置创建沙箱 cgroup，未配置内存或进程数限制、或者系统不支持时返回 nil.
+func initCgroup(cfg config.LimitsConfig, logger *slog.Logger) *shellService.Cgroup {
+	if cfg.Memory <= 0 && cfg.Pids <= 0 {
+		return nil
+	}
+
+	cgroup, err := shellService.NewCgroup(cfg.CgroupRoot, cfg.Memory, cfg.Pids)
+	if err != nil {
+		logger.Warn("cgroup limits disabled",
+			slog.String("root", cfg.CgroupRoot),
+			slog.Any("error", err))
+
+		return nil
+	}
+
+	return cgroup
+}
+
+// initUsers 按配置创建沙箱用户分配器，未启用时返回 nil.
+func initUsers(cfg config.SandboxConfig, logger *slog.Logger) *sandbox.Users {
+	if !cfg.Users.Enabled {
+		return nil
+	}
+
+	// 切换用户和修改文件所有者需要 root 权限
+	if os.Geteuid() != 0 {
+		logger.Error("sandbox users require the server to run as root")
+		os.Exit(1)
+	}
+
+	workspaceDir, err := filepath.Abs(cfg.WorkspaceDir)
+	if err != nil {
+		logger.Error("failed to resolve workspace directory",
+			slog.String("dir", cfg.WorkspaceDir),
+			slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	// 工作空间根目录只允许 root 列出，沙箱之间无法枚举彼此的工作空间
+	if err := os.Chmod(workspaceDir, 0o711); err != nil {
+		logger.Error("failed to restrict workspace directory",
+			slog.String("dir", workspaceDir),
+			slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	return sandbox.NewUsers(workspaceDir, cfg.Users.UIDStart, cfg.Users.Count)
+}
+
+// initPolicy 按配置编译服务端命令策略，配置无效时退出.
+func initPolicy(cfg config.PolicyConfig, logger *slog.Logger) *policy.Policy {
+	rules := make([]policy.Rule, 0, len(cfg.Rules))
+	for _, r := range cfg.Rules {
+		rules = append(rules, policy.Rule{
+			Name:    r.Name,
+			Action:  policy.Action(r.Action),
+			Pattern: r.Pattern,
+			Prefix:  r.Prefix,
+		})
+	}
+
+	p, err := policy.New(rules, policy.Action(cfg.Default))
+	if err != nil {
+		logger.Error("invalid command policy", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	logger.Info("command policy loaded",
+		slog.Int("rules", len(rules)),
+		slog.String("default", cfg.Default))
+
+	return p
+}
+
+// initHistory 按配置创建命令执行历史，max_entries 为 0 时不记录.
+func initHistory(cfg config.HistoryConfig) *history.Store {
+	if cfg.MaxEntries <= 0 {
+		return nil
+	}
+
+	return history.NewStore(cfg.MaxEntries, cfg.StoreOutput)
+}
+
+// initRecordings 按配置创建录制存储，未启用或目录无法创建时返回 nil.
+func initRecordings(cfg config.RecordingConfig, logger *slog.Logger) *recording.Store {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	store, err := recording.NewStore(cfg.Dir, cfg.MaxPerSandbox, cfg.MaxSize)
+	if err != nil {
+		logger.Warn("recording disabled",
+			slog.String("dir", cfg.Dir),
+			slog.Any("error", err))
+
+		return nil
+	}
+
+	return store
+}
+
+// initPreviewSecret 返回签名预览令牌的密钥，未配置时随机生成，此时令牌在服务重启后失效.
+func initPreviewSecret(cfg config.PreviewConfig, logger *slog.Logger) []byte {
+	if cfg.Secret != "" {
+		return []byte(cfg.Secret)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		logger.Error("failed to generate preview secret", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	logger.Info("preview secret not configured, preview tokens will not survive a restart")
+
+	return secret
+}
+
+// initLanguages 将配置中的语言转换为代码运行服务使用的解释器配置.
+func initLanguages(cfg map[string]config.LanguageConfig) map[string]codeService.Language {
+	languages := make(map[string]codeService.Language, len(cfg))
+	for name, lang := range cfg {
+		languages[name] = codeService.Language{
+			Command:   lang.Command,
+			Extension: lang.Extension,
+			Repl:      lang.Repl,
+			ReplRun:   lang.ReplRun,
+		}
+	}
+
+	return languages
+}
+
+// initEnvironment 将配置中的基础环境转换为 Shell 服务使用的配置，变量格式无效时退出.
+func initEnvironment(cfg config.EnvironmentConfig, logger *slog.Logger) shellService.Environment {
+	for _, kv := range cfg.Vars {
+		if name, _, ok := strings.Cut(kv, "="); !ok || name == "" {
+			logger.Error("invalid environment variable, expected NAME=value", slog.String("var", kv))
+			os.Exit(1)
+		}
+	}
+
+	if filepath.IsAbs(cfg.Profile) {
+		if _, err := os.Stat(cfg.Profile); err != nil {
+			logger.Warn("profile not found, commands will fail to load it",
+				slog.String("profile", cfg.Profile),
+				slog.Any("error", err))
+		}
+	}
+
+	return shellService.Environment{
+		Path:        cfg.Path,
+		Lang:        cfg.Lang,
+		TZ:          cfg.TZ,
+		Passthrough: cfg.Passthrough,
+		Vars:        cfg.Vars,
+		Profile:     cfg.Profile,
+	}
+}
+
+// initTestRunners 将配置中的测试运行器转换为代码运行服务使用的配置，报告格式未知时退出.
+func initTestRunners(cfg map[string]config.TestRunnerConfig, logger *slog.Logger) map[string]codeService.TestRunner {
+	runners := make(map[string]codeService.TestRunner, len(cfg))
+	for name, runner := range cfg {
+		if _, err := testreport.Lookup(runner.Format); err != nil {
+			logger.Error("invalid test runner",
+				slog.String("runner", name),
+				slog.Any("error", err))
+			os.Exit(1)
+		}
+
+		runners[name] = codeService.TestRunner{
+			Command:     runner.Command,
+			DefaultArgs: runner.DefaultArgs,
+			Format:      runner.Format,
+			Report:      runner.Report,
+		}
+	}
+
+	return runners
+}
+
+// initCheckers 将配置中的检查器转换为代码运行服务使用的配置，输出格式未知时退出.
+func initCheckers(cfg map[string]config.CheckerConfig, logger *slog.Logger) map[string]codeService.Checker {
+	checkers := make(map[string]codeService.Checker, len(cfg))
+	for name, checker := range cfg {
+		if _, err := diagnostic.Lookup(checker.Format); err != nil {
+			logger.Error("invalid checker",
+				slog.String("checker", name),
+				slog.Any("error", err))
+			os.Exit(1)
+		}
+
+		checkers[name] = codeService.Checker{
+			Command:     checker.Command,
+			DefaultArgs: checker.DefaultArgs,
+			Format:      checker.Format,
+		}
+	}
+
+	return checkers
+}
+
+// initIsolation 按配置启用命名空间隔离，内核不支持时根据 required 退出或回退到非隔离模式.
+func initIsolation(cfg config.SandboxConfig, logger *slog.Logger) *shellService.Isolation {
+	if !cfg.Isolation.Enabled {
+		return nil
+	}
+
+	isolation := &shellService.Isolation{
+		ReadOnlyPaths: cfg.Isolation.ReadOnlyPaths,
+		Hostname:      cfg.Isolation.Hostname,
+	}
+
+	if err := isolation.Check(cfg.WorkspaceDir); err != nil {
+		if cfg.Isolation.Required {
+			logger.Error("namespace isolation is required but unavailable",
+				slog.Any("error", err))
+			os.Exit(1)
+		}
+
+		logger.Warn("namespace isolation unavailable, commands will run without isolation",
+			slog.Any("error", err))
+
+		return nil
+	}
+
+	logger.Info("namespace isolation enabled")
+
+	return isolation
 }
@@ -23,7 +23,11 @@ export default defineConfig({
         items: [
           { text: '核心服务', link: '/core/index' },
           { text: '文件服务', link: '/file/index' },
-          { text: 'Shell 服务', link: '/shell/index' }
+          { text: 'Shell 服务', link: '/shell/index' },
+          { text: '代码服务', link: '/code/index' },
+          { text: '预览服务', link: '/preview/index' },
+          { text: '定时任务', link: '/schedule/index' },
+          { text: '管理服务', link: '/admin/index' }
         ]
       }
     ],
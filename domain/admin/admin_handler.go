@@ -0,0 +1,108 @@
+// Package admin provides handlers for administrative operations.
+package admin
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/HJH0924/agent-sandbox/domain/admin/service"
+	"github.com/HJH0924/agent-sandbox/internal/approval"
+	adminv1 "github.com/HJH0924/agent-sandbox/sdk/go/admin/v1"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Handler 管理服务处理器.
+type Handler struct {
+	adminService *service.Service
+	logger       *slog.Logger
+}
+
+// NewHandler 创建管理服务处理器.
+func NewHandler(adminService *service.Service, logger *slog.Logger) *Handler {
+	return &Handler{
+		adminService: adminService,
+		logger:       logger,
+	}
+}
+
+// ListPendingApprovals 列出等待审批的命令.
+func (h *Handler) ListPendingApprovals(
+	_ context.Context,
+	req *connect.Request[adminv1.ListPendingApprovalsRequest],
+) (*connect.Response[adminv1.ListPendingApprovalsResponse], error) {
+	pending := h.adminService.ListPendingApprovals(req.Msg.GetSandboxId())
+
+	approvals := make([]*adminv1.Approval, 0, len(pending))
+	for _, p := range pending {
+		approvals = append(approvals, &adminv1.Approval{
+			Id:        p.ID,
+			SandboxId: p.SandboxID,
+			Command:   p.Command,
+			Rule:      p.Rule,
+			CreatedAt: timestamppb.New(p.CreatedAt),
+			ExpiresAt: timestamppb.New(p.ExpiresAt),
+		})
+	}
+
+	return connect.NewResponse(&adminv1.ListPendingApprovalsResponse{
+		Approvals: approvals,
+	}), nil
+}
+
+// Approve 批准命令.
+func (h *Handler) Approve(
+	ctx context.Context,
+	req *connect.Request[adminv1.ApproveRequest],
+) (*connect.Response[adminv1.ApproveResponse], error) {
+	id := req.Msg.GetId()
+
+	if err := h.adminService.Approve(id); err != nil {
+		h.logger.WarnContext(ctx, "failed to approve command",
+			slog.String("approval_id", id),
+			slog.Any("error", err))
+
+		return nil, connect.NewError(errorCode(err), err)
+	}
+
+	h.logger.InfoContext(ctx, "command approved",
+		slog.String("approval_id", id))
+
+	return connect.NewResponse(&adminv1.ApproveResponse{}), nil
+}
+
+// Reject 拒绝命令.
+func (h *Handler) Reject(
+	ctx context.Context,
+	req *connect.Request[adminv1.RejectRequest],
+) (*connect.Response[adminv1.RejectResponse], error) {
+	id := req.Msg.GetId()
+
+	if err := h.adminService.Reject(id, req.Msg.GetReason()); err != nil {
+		h.logger.WarnContext(ctx, "failed to reject command",
+			slog.String("approval_id", id),
+			slog.Any("error", err))
+
+		return nil, connect.NewError(errorCode(err), err)
+	}
+
+	h.logger.InfoContext(ctx, "command rejected",
+		slog.String("approval_id", id),
+		slog.String("reason", req.Msg.GetReason()))
+
+	return connect.NewResponse(&adminv1.RejectResponse{}), nil
+}
+
+// errorCode 将审批错误映射为 RPC 错误码.
+func errorCode(err error) connect.Code {
+	switch {
+	case errors.Is(err, approval.ErrNotFound):
+		return connect.CodeNotFound
+	case errors.Is(err, approval.ErrDecided):
+		return connect.CodeFailedPrecondition
+	default:
+		return connect.CodeInternal
+	}
+}
@@ -0,0 +1,58 @@
+package admin
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/HJH0924/agent-sandbox/domain/admin/service"
+	"github.com/HJH0924/agent-sandbox/internal/approval"
+	adminv1 "github.com/HJH0924/agent-sandbox/sdk/go/admin/v1"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_Approvals(t *testing.T) {
+	approvals := approval.NewQueue(time.Minute)
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	handler := NewHandler(service.NewService(approvals), logger)
+
+	ctx := context.Background()
+
+	first := approvals.Submit("sandbox-1", "git push", "git-push")
+	second := approvals.Submit("sandbox-2", "npm publish", "publish")
+
+	resp, err := handler.ListPendingApprovals(ctx, connect.NewRequest(&adminv1.ListPendingApprovalsRequest{}))
+	require.NoError(t, err)
+	require.Len(t, resp.Msg.GetApprovals(), 2)
+	assert.Equal(t, first.ID, resp.Msg.GetApprovals()[0].GetId())
+	assert.Equal(t, "git push", resp.Msg.GetApprovals()[0].GetCommand())
+	assert.Equal(t, "git-push", resp.Msg.GetApprovals()[0].GetRule())
+
+	// 按沙箱过滤
+	resp, err = handler.ListPendingApprovals(ctx, connect.NewRequest(&adminv1.ListPendingApprovalsRequest{SandboxId: "sandbox-2"}))
+	require.NoError(t, err)
+	require.Len(t, resp.Msg.GetApprovals(), 1)
+	assert.Equal(t, second.ID, resp.Msg.GetApprovals()[0].GetId())
+
+	_, err = handler.Approve(ctx, connect.NewRequest(&adminv1.ApproveRequest{Id: first.ID}))
+	require.NoError(t, err)
+
+	_, err = handler.Reject(ctx, connect.NewRequest(&adminv1.RejectRequest{Id: second.ID, Reason: "no"}))
+	require.NoError(t, err)
+
+	resp, err = handler.ListPendingApprovals(ctx, connect.NewRequest(&adminv1.ListPendingApprovalsRequest{}))
+	require.NoError(t, err)
+	assert.Empty(t, resp.Msg.GetApprovals())
+
+	// 已处理和不存在的请求
+	_, err = handler.Approve(ctx, connect.NewRequest(&adminv1.ApproveRequest{Id: second.ID}))
+	assert.Equal(t, connect.CodeFailedPrecondition, connect.CodeOf(err))
+
+	_, err = handler.Reject(ctx, connect.NewRequest(&adminv1.RejectRequest{Id: "unknown"}))
+	assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+}
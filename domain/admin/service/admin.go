@@ -0,0 +1,46 @@
+// Package service implements administrative operations such as command approvals.
+package service
+
+import (
+	"github.com/HJH0924/agent-sandbox/internal/approval"
+)
+
+// Service 管理服务.
+type Service struct {
+	approvals *approval.Queue
+}
+
+// NewService 创建管理服务实例.
+func NewService(approvals *approval.Queue) *Service {
+	return &Service{
+		approvals: approvals,
+	}
+}
+
+// ListPendingApprovals 列出等待审批的命令，sandboxID 非空时只列出该沙箱的请求.
+func (s *Service) ListPendingApprovals(sandboxID string) []approval.Request {
+	pending := s.approvals.Pending()
+	if sandboxID == "" {
+		return pending
+	}
+
+	filtered := make([]approval.Request, 0, len(pending))
+
+	for _, req := range pending {
+		if req.SandboxID == sandboxID {
+			filtered = append(filtered, req)
+		}
+	}
+
+	return filtered
+}
+
+// Approve 批准命令.
+func (s *Service) Approve(id string) error {
+	return s.approvals.Approve(id)
+}
+
+// Reject 拒绝命令.
+func (s *Service) Reject(id, reason string) error {
+	return s.approvals.Reject(id, reason)
+}
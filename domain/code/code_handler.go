@@ -0,0 +1,411 @@
+// Package code provides handlers for running code snippets within the sandbox.
+package code
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/HJH0924/agent-sandbox/domain/code/service"
+	"github.com/HJH0924/agent-sandbox/domain/shell"
+	shellService "github.com/HJH0924/agent-sandbox/domain/shell/service"
+	"github.com/HJH0924/agent-sandbox/internal/diagnostic"
+	"github.com/HJH0924/agent-sandbox/internal/middleware"
+	"github.com/HJH0924/agent-sandbox/internal/testreport"
+	codev1 "github.com/HJH0924/agent-sandbox/sdk/go/code/v1"
+
+	"connectrpc.com/connect"
+)
+
+// Handler 代码运行服务处理器.
+type Handler struct {
+	codeService *service.Service
+	logger      *slog.Logger
+}
+
+// NewHandler 创建代码运行服务处理器.
+func NewHandler(codeService *service.Service, logger *slog.Logger) *Handler {
+	return &Handler{
+		codeService: codeService,
+		logger:      logger,
+	}
+}
+
+// RunCode 运行代码片段.
+func (h *Handler) RunCode(
+	ctx context.Context,
+	req *connect.Request[codev1.RunCodeRequest],
+) (*connect.Response[codev1.RunCodeResponse], error) {
+	sandboxID, _ := middleware.GetSandboxIDFromContext(ctx)
+	keyID, _ := middleware.GetAPIKeyIDFromContext(ctx)
+	language := req.Msg.GetLanguage()
+
+	h.logger.InfoContext(ctx, "running code",
+		slog.String("language", language),
+		slog.Int("code_length", len(req.Msg.GetCode())))
+
+	result, err := h.codeService.RunCode(ctx, &service.RunRequest{
+		SandboxID:   sandboxID,
+		Language:    language,
+		Code:        req.Msg.GetCode(),
+		CallerKeyID: keyID,
+	})
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to run code",
+			slog.String("language", language),
+			slog.Any("error", err))
+
+		return nil, connect.NewError(errorCode(err), err)
+	}
+
+	h.logger.InfoContext(ctx, "code finished",
+		slog.String("language", language),
+		slog.Int("exit_code", result.ExitCode),
+		slog.Int("files", len(result.Files)))
+
+	resp := &codev1.RunCodeResponse{
+		ExitCode:       int32(result.ExitCode), // #nosec G115 -- exit codes fit in int32
+		Truncated:      result.Truncated,
+		Signal:         result.Signal,
+		TimedOut:       result.TimedOut,
+		LimitsExceeded: result.LimitsExceeded,
+		Files:          toProducedFiles(result.Files),
+		QueueWaitMs:    result.QueueWait.Milliseconds(),
+		StdoutEncoding: string(result.StdoutEncoding),
+		StderrEncoding: string(result.StderrEncoding),
+		RecordingId:    result.RecordingID,
+	}
+
+	// 非 UTF-8 的输出无法放入 proto string，按请求返回原始字节或替换无效字节后的文本
+	if req.Msg.GetRawOutput() {
+		resp.StdoutData = result.StdoutData
+		resp.StderrData = result.StderrData
+	} else {
+		resp.Stdout, _ = shellService.RenderText([]byte(result.Stdout))
+		resp.Stderr, _ = shellService.RenderText([]byte(result.Stderr))
+	}
+
+	return connect.NewResponse(resp), nil
+}
+
+// errorCode 将代码运行错误映射为 RPC 错误码，命令执行错误沿用 Shell 服务的映射.
+func errorCode(err error) connect.Code {
+	switch {
+	case errors.Is(err, service.ErrUnsupportedLanguage),
+		errors.Is(err, service.ErrKernelUnsupported),
+		errors.Is(err, service.ErrUnknownTestRunner),
+		errors.Is(err, service.ErrUnknownChecker):
+		return connect.CodeInvalidArgument
+	case errors.Is(err, service.ErrKernelNotFound):
+		return connect.CodeNotFound
+	case errors.Is(err, service.ErrTooManyKernels):
+		return connect.CodeResourceExhausted
+	default:
+		return shell.ErrorCode(err)
+	}
+}
+
+// CreateKernel 启动内核.
+func (h *Handler) CreateKernel(
+	ctx context.Context,
+	req *connect.Request[codev1.CreateKernelRequest],
+) (*connect.Response[codev1.CreateKernelResponse], error) {
+	sandboxID, _ := middleware.GetSandboxIDFromContext(ctx)
+	language := req.Msg.GetLanguage()
+
+	kernel, err := h.codeService.CreateKernel(ctx, sandboxID, language)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to create kernel",
+			slog.String("language", language),
+			slog.Any("error", err))
+
+		return nil, connect.NewError(errorCode(err), err)
+	}
+
+	h.logger.InfoContext(ctx, "kernel created",
+		slog.String("kernel_id", kernel.ID),
+		slog.String("language", language))
+
+	return connect.NewResponse(&codev1.CreateKernelResponse{
+		KernelId: kernel.ID,
+	}), nil
+}
+
+// ExecuteCell 在内核中运行代码单元，流式返回输出，最后返回运行结果.
+func (h *Handler) ExecuteCell(
+	ctx context.Context,
+	req *connect.Request[codev1.ExecuteCellRequest],
+	stream *connect.ServerStream[codev1.ExecuteCellResponse],
+) error {
+	sandboxID, _ := middleware.GetSandboxIDFromContext(ctx)
+	kernelID := req.Msg.GetKernelId()
+
+	h.logger.InfoContext(ctx, "executing cell",
+		slog.String("kernel_id", kernelID),
+		slog.Int("code_length", len(req.Msg.GetCode())))
+
+	result, err := h.codeService.ExecuteCell(ctx, sandboxID, kernelID, req.Msg.GetCode(),
+		func(s service.Stream, data []byte) {
+			msg := &codev1.ExecuteCellResponse{Event: &codev1.ExecuteCellResponse_Stdout{Stdout: data}}
+			if s == service.Stderr {
+				msg.Event = &codev1.ExecuteCellResponse_Stderr{Stderr: data}
+			}
+
+			// 客户端断开时 ctx 随之结束，单元会被中断
+			_ = stream.Send(msg)
+		})
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to execute cell",
+			slog.String("kernel_id", kernelID),
+			slog.Any("error", err))
+
+		return connect.NewError(errorCode(err), err)
+	}
+
+	h.logger.InfoContext(ctx, "cell finished",
+		slog.String("kernel_id", kernelID),
+		slog.String("status", string(result.Status)),
+		slog.Bool("restarted", result.Restarted))
+
+	return stream.Send(&codev1.ExecuteCellResponse{
+		Event: &codev1.ExecuteCellResponse_Result{Result: &codev1.CellResult{
+			Status:      toCellStatus(result.Status),
+			ExitCode:    int32(result.ExitCode), // #nosec G115 -- exit codes fit in int32
+			Restarted:   result.Restarted,
+			Files:       toProducedFiles(result.Files),
+			RecordingId: result.RecordingID,
+		}},
+	})
+}
+
+// InterruptKernel 中断内核中正在运行的单元.
+func (h *Handler) InterruptKernel(
+	ctx context.Context,
+	req *connect.Request[codev1.InterruptKernelRequest],
+) (*connect.Response[codev1.InterruptKernelResponse], error) {
+	sandboxID, _ := middleware.GetSandboxIDFromContext(ctx)
+	kernelID := req.Msg.GetKernelId()
+
+	if err := h.codeService.InterruptKernel(sandboxID, kernelID); err != nil {
+		return nil, connect.NewError(errorCode(err), err)
+	}
+
+	h.logger.InfoContext(ctx, "kernel interrupted",
+		slog.String("kernel_id", kernelID))
+
+	return connect.NewResponse(&codev1.InterruptKernelResponse{}), nil
+}
+
+// ShutdownKernel 终止内核.
+func (h *Handler) ShutdownKernel(
+	ctx context.Context,
+	req *connect.Request[codev1.ShutdownKernelRequest],
+) (*connect.Response[codev1.ShutdownKernelResponse], error) {
+	sandboxID, _ := middleware.GetSandboxIDFromContext(ctx)
+	kernelID := req.Msg.GetKernelId()
+
+	if err := h.codeService.ShutdownKernel(sandboxID, kernelID); err != nil {
+		return nil, connect.NewError(errorCode(err), err)
+	}
+
+	h.logger.InfoContext(ctx, "kernel shut down",
+		slog.String("kernel_id", kernelID))
+
+	return connect.NewResponse(&codev1.ShutdownKernelResponse{}), nil
+}
+
+// toCellStatus 将单元状态转换为 proto 枚举.
+func toCellStatus(status service.CellStatus) codev1.CellStatus {
+	switch status {
+	case service.CellOK:
+		return codev1.CellStatus_CELL_STATUS_OK
+	case service.CellError:
+		return codev1.CellStatus_CELL_STATUS_ERROR
+	case service.CellInterrupted:
+		return codev1.CellStatus_CELL_STATUS_INTERRUPTED
+	case service.CellCrashed:
+		return codev1.CellStatus_CELL_STATUS_CRASHED
+	default:
+		return codev1.CellStatus_CELL_STATUS_UNSPECIFIED
+	}
+}
+
+// toProducedFiles 将产生的文件转换为响应.
+func toProducedFiles(files []service.File) []*codev1.ProducedFile {
+	produced := make([]*codev1.ProducedFile, 0, len(files))
+	for _, f := range files {
+		produced = append(produced, &codev1.ProducedFile{
+			Path: f.Path,
+			Size: f.Size,
+		})
+	}
+
+	return produced
+}
+
+// RunTests 运行测试并返回每个测试的结果.
+func (h *Handler) RunTests(
+	ctx context.Context,
+	req *connect.Request[codev1.RunTestsRequest],
+) (*connect.Response[codev1.RunTestsResponse], error) {
+	sandboxID, _ := middleware.GetSandboxIDFromContext(ctx)
+	keyID, _ := middleware.GetAPIKeyIDFromContext(ctx)
+	runner := req.Msg.GetRunner()
+
+	h.logger.InfoContext(ctx, "running tests",
+		slog.String("runner", runner),
+		slog.Any("args", req.Msg.GetArgs()))
+
+	result, err := h.codeService.RunTests(ctx, &service.TestRequest{
+		SandboxID:   sandboxID,
+		Runner:      runner,
+		Args:        req.Msg.GetArgs(),
+		CallerKeyID: keyID,
+	})
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to run tests",
+			slog.String("runner", runner),
+			slog.Any("error", err))
+
+		return nil, connect.NewError(errorCode(err), err)
+	}
+
+	summary := result.Report.Summary
+
+	h.logger.InfoContext(ctx, "tests finished",
+		slog.String("runner", runner),
+		slog.Int("exit_code", result.ExitCode),
+		slog.Int("total", summary.Total),
+		slog.Int("failed", summary.Failed+summary.Errors))
+
+	tests := make([]*codev1.TestCase, 0, len(result.Report.Cases))
+	for _, c := range result.Report.Cases {
+		tests = append(tests, &codev1.TestCase{
+			Name:       c.Name,
+			Suite:      c.Suite,
+			Status:     toTestStatus(c.Status),
+			DurationMs: c.Duration.Milliseconds(),
+			Message:    c.Message,
+			File:       c.File,
+			Line:       int32(c.Line), // #nosec G115 -- line numbers fit in int32
+		})
+	}
+
+	stderr, _ := shellService.RenderText([]byte(result.Stderr))
+
+	return connect.NewResponse(&codev1.RunTestsResponse{
+		Tests: tests,
+		Summary: &codev1.TestSummary{
+			Total:      int32(summary.Total),   // #nosec G115 -- test counts fit in int32
+			Passed:     int32(summary.Passed),  // #nosec G115 -- test counts fit in int32
+			Failed:     int32(summary.Failed),  // #nosec G115 -- test counts fit in int32
+			Skipped:    int32(summary.Skipped), // #nosec G115 -- test counts fit in int32
+			Errors:     int32(summary.Errors),  // #nosec G115 -- test counts fit in int32
+			DurationMs: summary.Duration.Milliseconds(),
+		},
+		ExitCode:    int32(result.ExitCode), // #nosec G115 -- exit codes fit in int32
+		TimedOut:    result.TimedOut,
+		Signal:      result.Signal,
+		Stderr:      stderr,
+		ReportError: result.ReportError,
+		Truncated:   result.Truncated,
+		QueueWaitMs: result.QueueWait.Milliseconds(),
+		RecordingId: result.RecordingID,
+	}), nil
+}
+
+// toTestStatus 将测试结果转换为 proto 枚举.
+func toTestStatus(status testreport.Status) codev1.TestStatus {
+	switch status {
+	case testreport.StatusPassed:
+		return codev1.TestStatus_TEST_STATUS_PASSED
+	case testreport.StatusFailed:
+		return codev1.TestStatus_TEST_STATUS_FAILED
+	case testreport.StatusSkipped:
+		return codev1.TestStatus_TEST_STATUS_SKIPPED
+	case testreport.StatusError:
+		return codev1.TestStatus_TEST_STATUS_ERROR
+	default:
+		return codev1.TestStatus_TEST_STATUS_UNSPECIFIED
+	}
+}
+
+// Diagnostics 运行检查器并返回结构化的诊断.
+func (h *Handler) Diagnostics(
+	ctx context.Context,
+	req *connect.Request[codev1.DiagnosticsRequest],
+) (*connect.Response[codev1.DiagnosticsResponse], error) {
+	sandboxID, _ := middleware.GetSandboxIDFromContext(ctx)
+	keyID, _ := middleware.GetAPIKeyIDFromContext(ctx)
+
+	h.logger.InfoContext(ctx, "running checkers",
+		slog.Any("checkers", req.Msg.GetCheckers()),
+		slog.Any("args", req.Msg.GetArgs()))
+
+	result, err := h.codeService.Diagnostics(ctx, &service.DiagnosticsRequest{
+		SandboxID:   sandboxID,
+		Checkers:    req.Msg.GetCheckers(),
+		Args:        req.Msg.GetArgs(),
+		CallerKeyID: keyID,
+	})
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to run checkers",
+			slog.Any("checkers", req.Msg.GetCheckers()),
+			slog.Any("error", err))
+
+		return nil, connect.NewError(errorCode(err), err)
+	}
+
+	h.logger.InfoContext(ctx, "checkers finished",
+		slog.Int("checkers", len(result.Checkers)),
+		slog.Int("diagnostics", len(result.Diagnostics)))
+
+	diagnostics := make([]*codev1.Diagnostic, 0, len(result.Diagnostics))
+	for _, d := range result.Diagnostics {
+		diagnostics = append(diagnostics, &codev1.Diagnostic{
+			File:     d.File,
+			Line:     int32(d.Line),   // #nosec G115 -- line numbers fit in int32
+			Column:   int32(d.Column), // #nosec G115 -- column numbers fit in int32
+			Severity: toDiagnosticSeverity(d.Severity),
+			Message:  d.Message,
+			Rule:     d.Rule,
+			Checker:  d.Checker,
+		})
+	}
+
+	checkers := make([]*codev1.CheckerResult, 0, len(result.Checkers))
+	for _, c := range result.Checkers {
+		output, _ := shellService.RenderText([]byte(c.Output))
+
+		checkers = append(checkers, &codev1.CheckerResult{
+			Name:        c.Name,
+			ExitCode:    int32(c.ExitCode), // #nosec G115 -- exit codes fit in int32
+			Signal:      c.Signal,
+			TimedOut:    c.TimedOut,
+			Error:       c.Error,
+			Output:      output,
+			Truncated:   c.Truncated,
+			QueueWaitMs: c.QueueWait.Milliseconds(),
+			RecordingId: c.RecordingID,
+		})
+	}
+
+	return connect.NewResponse(&codev1.DiagnosticsResponse{
+		Diagnostics: diagnostics,
+		Checkers:    checkers,
+	}), nil
+}
+
+// toDiagnosticSeverity 将诊断的严重程度转换为 proto 枚举.
+func toDiagnosticSeverity(severity diagnostic.Severity) codev1.DiagnosticSeverity {
+	switch severity {
+	case diagnostic.SeverityError:
+		return codev1.DiagnosticSeverity_DIAGNOSTIC_SEVERITY_ERROR
+	case diagnostic.SeverityWarning:
+		return codev1.DiagnosticSeverity_DIAGNOSTIC_SEVERITY_WARNING
+	case diagnostic.SeverityInfo:
+		return codev1.DiagnosticSeverity_DIAGNOSTIC_SEVERITY_INFO
+	default:
+		return codev1.DiagnosticSeverity_DIAGNOSTIC_SEVERITY_UNSPECIFIED
+	}
+}
@@ -0,0 +1,195 @@
+package code
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/HJH0924/agent-sandbox/domain/code/service"
+	shellService "github.com/HJH0924/agent-sandbox/domain/shell/service"
+	codev1 "github.com/HJH0924/agent-sandbox/sdk/go/code/v1"
+	"github.com/HJH0924/agent-sandbox/sdk/go/code/v1/codev1connect"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHandler(t *testing.T) *Handler {
+	t.Helper()
+
+	codeService := service.NewService(shellService.NewService(10, t.TempDir()), map[string]service.Language{
+		"sh":      {Command: []string{"sh"}, Extension: ".sh"},
+		"missing": {Command: []string{"agent-sandbox-no-such-interpreter"}, Extension: ".x"},
+		"bash": {
+			Extension: ".sh",
+			Repl:      []string{"bash", "--norc", "--noprofile"},
+			ReplRun:   ". '{file}' </dev/null\n" + `printf '\n{marker} %d\n' "$?"; printf '\n{marker}\n' >&2`,
+		},
+	}, service.WithTestRunners(map[string]service.TestRunner{
+		"tap": {Command: []string{"printf", `ok 1 - adds\nnot ok 2 - subtracts\n  ---\n  at: 'math.test.js:7:3'\n  ...\n`}, Format: "tap"},
+	}), service.WithCheckers(map[string]service.Checker{
+		"vet": {Command: []string{"sh", "-c", `printf './main.go:3:7: undefined: x\n' >&2; exit 1`}, Format: "go"},
+	}))
+
+	return NewHandler(codeService, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+}
+
+func TestHandler_RunCode(t *testing.T) {
+	handler := newTestHandler(t)
+
+	resp, err := handler.RunCode(context.Background(), connect.NewRequest(&codev1.RunCodeRequest{
+		Language: "sh",
+		Code:     "echo hello; echo oops >&2; echo 1 > out.txt; exit 2",
+	}))
+
+	// 非零退出码通过响应返回
+	require.NoError(t, err)
+	assert.Equal(t, "hello\n", resp.Msg.GetStdout())
+	assert.Equal(t, "oops\n", resp.Msg.GetStderr())
+	assert.Equal(t, int32(2), resp.Msg.GetExitCode())
+	require.Len(t, resp.Msg.GetFiles(), 1)
+	assert.Equal(t, "out.txt", resp.Msg.GetFiles()[0].GetPath())
+	assert.Equal(t, int64(2), resp.Msg.GetFiles()[0].GetSize())
+}
+
+func TestHandler_RunCode_NonUTF8Output(t *testing.T) {
+	handler := newTestHandler(t)
+	ctx := context.Background()
+
+	resp, err := handler.RunCode(ctx, connect.NewRequest(&codev1.RunCodeRequest{Language: "sh", Code: `printf 'caf\351'`}))
+	require.NoError(t, err)
+	assert.Equal(t, "caf\uFFFD", resp.Msg.GetStdout())
+	assert.Equal(t, "unknown", resp.Msg.GetStdoutEncoding())
+
+	resp, err = handler.RunCode(ctx, connect.NewRequest(&codev1.RunCodeRequest{Language: "sh", Code: `printf 'caf\351'`, RawOutput: true}))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("caf\xe9"), resp.Msg.GetStdoutData())
+	assert.Empty(t, resp.Msg.GetStdout())
+}
+
+func TestHandler_RunCode_Errors(t *testing.T) {
+	handler := newTestHandler(t)
+	ctx := context.Background()
+
+	_, err := handler.RunCode(ctx, connect.NewRequest(&codev1.RunCodeRequest{Language: "cobol", Code: "x"}))
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+
+	_, err = handler.RunCode(ctx, connect.NewRequest(&codev1.RunCodeRequest{Language: "missing", Code: "x"}))
+	assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+}
+
+func TestHandler_RunTests(t *testing.T) {
+	handler := newTestHandler(t)
+	ctx := context.Background()
+
+	resp, err := handler.RunTests(ctx, connect.NewRequest(&codev1.RunTestsRequest{Runner: "tap"}))
+	require.NoError(t, err)
+	assert.Empty(t, resp.Msg.GetReportError())
+	assert.Equal(t, int32(2), resp.Msg.GetSummary().GetTotal())
+	assert.Equal(t, int32(1), resp.Msg.GetSummary().GetFailed())
+	require.Len(t, resp.Msg.GetTests(), 2)
+	assert.Equal(t, codev1.TestStatus_TEST_STATUS_PASSED, resp.Msg.GetTests()[0].GetStatus())
+
+	failed := resp.Msg.GetTests()[1]
+	assert.Equal(t, "subtracts", failed.GetName())
+	assert.Equal(t, codev1.TestStatus_TEST_STATUS_FAILED, failed.GetStatus())
+	assert.Equal(t, "math.test.js", failed.GetFile())
+	assert.Equal(t, int32(7), failed.GetLine())
+
+	_, err = handler.RunTests(ctx, connect.NewRequest(&codev1.RunTestsRequest{Runner: "pytest"}))
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+}
+
+func TestHandler_Diagnostics(t *testing.T) {
+	handler := newTestHandler(t)
+	ctx := context.Background()
+
+	resp, err := handler.Diagnostics(ctx, connect.NewRequest(&codev1.DiagnosticsRequest{}))
+	require.NoError(t, err)
+	require.Len(t, resp.Msg.GetDiagnostics(), 1)
+
+	d := resp.Msg.GetDiagnostics()[0]
+	assert.Equal(t, "main.go", d.GetFile())
+	assert.Equal(t, int32(3), d.GetLine())
+	assert.Equal(t, int32(7), d.GetColumn())
+	assert.Equal(t, codev1.DiagnosticSeverity_DIAGNOSTIC_SEVERITY_ERROR, d.GetSeverity())
+	assert.Equal(t, "undefined: x", d.GetMessage())
+	assert.Equal(t, "vet", d.GetChecker())
+
+	require.Len(t, resp.Msg.GetCheckers(), 1)
+	assert.Equal(t, int32(1), resp.Msg.GetCheckers()[0].GetExitCode())
+	assert.Empty(t, resp.Msg.GetCheckers()[0].GetOutput())
+
+	_, err = handler.Diagnostics(ctx, connect.NewRequest(&codev1.DiagnosticsRequest{Checkers: []string{"tsc"}}))
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+}
+
+func TestHandler_Kernel(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle(codev1connect.NewCodeServiceHandler(newTestHandler(t)))
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client := codev1connect.NewCodeServiceClient(server.Client(), server.URL)
+	ctx := context.Background()
+
+	created, err := client.CreateKernel(ctx, connect.NewRequest(&codev1.CreateKernelRequest{Language: "bash"}))
+	require.NoError(t, err)
+
+	kernelID := created.Msg.GetKernelId()
+
+	// executeCell 运行单元并返回拼接后的 stdout 和最终结果
+	executeCell := func(code string) (string, *codev1.CellResult) {
+		stream, err := client.ExecuteCell(ctx, connect.NewRequest(&codev1.ExecuteCellRequest{KernelId: kernelID, Code: code}))
+		require.NoError(t, err)
+
+		var (
+			stdout string
+			result *codev1.CellResult
+		)
+
+		for stream.Receive() {
+			stdout += string(stream.Msg().GetStdout())
+			if r := stream.Msg().GetResult(); r != nil {
+				result = r
+			}
+		}
+
+		require.NoError(t, stream.Err())
+		require.NotNil(t, result)
+
+		return stdout, result
+	}
+
+	_, result := executeCell("greeting=hello")
+	assert.Equal(t, codev1.CellStatus_CELL_STATUS_OK, result.GetStatus())
+
+	stdout, result := executeCell("echo $greeting")
+	assert.Equal(t, "hello\n", stdout)
+	assert.Equal(t, codev1.CellStatus_CELL_STATUS_OK, result.GetStatus())
+
+	_, result = executeCell("exit 3")
+	assert.Equal(t, codev1.CellStatus_CELL_STATUS_CRASHED, result.GetStatus())
+	assert.Equal(t, int32(3), result.GetExitCode())
+
+	stdout, result = executeCell("echo ${greeting:-gone}")
+	assert.Equal(t, "gone\n", stdout)
+	assert.True(t, result.GetRestarted())
+
+	_, err = client.InterruptKernel(ctx, connect.NewRequest(&codev1.InterruptKernelRequest{KernelId: kernelID}))
+	require.NoError(t, err)
+
+	_, err = client.ShutdownKernel(ctx, connect.NewRequest(&codev1.ShutdownKernelRequest{KernelId: kernelID}))
+	require.NoError(t, err)
+
+	_, err = client.ShutdownKernel(ctx, connect.NewRequest(&codev1.ShutdownKernelRequest{KernelId: kernelID}))
+	assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+
+	_, err = client.CreateKernel(ctx, connect.NewRequest(&codev1.CreateKernelRequest{Language: "sh"}))
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+}
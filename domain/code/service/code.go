@@ -0,0 +1,313 @@
+// Package service implements running code snippets with per-language interpreters.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	shellService "github.com/HJH0924/agent-sandbox/domain/shell/service"
+	"github.com/HJH0924/agent-sandbox/internal/sandbox"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// codeDir 工作空间中保存待运行代码的目录.
+	codeDir = ".agent-sandbox/code"
+	// internalDir 服务在工作空间中使用的目录，不计入代码产生的文件.
+	internalDir = ".agent-sandbox"
+	// maxScannedFiles 检测产生的文件时最多扫描的文件数.
+	maxScannedFiles = 10000
+)
+
+// ErrUnsupportedLanguage 没有为该语言配置解释器.
+var ErrUnsupportedLanguage = errors.New("unsupported language")
+
+// shells 解释器为 shell 的语言，代码本身就是命令.
+var shells = []string{"sh", "bash", "dash", "zsh", "ksh"}
+
+// Language 语言的解释器配置.
+type Language struct {
+	// Command 解释器及其参数，代码文件的路径追加在最后
+	Command []string
+	// Extension 代码文件的扩展名（如 .py）
+	Extension string
+	// Repl 启动交互式解释器的命令，为空时该语言不支持内核
+	Repl []string
+	// ReplRun 让解释器运行单元文件并输出结束标记的语句，{file} 替换为单元文件的路径，{marker} 替换为结束标记.
+	// 语句执行完后必须在 stdout 输出以换行开头的 "{marker} <状态>"（0 表示成功），在 stderr 输出以换行开头的 "{marker}"
+	ReplRun string
+}
+
+// Service 代码运行服务，代码通过 Shell 服务执行，命令策略、资源限制和并发限制同样适用.
+type Service struct {
+	shell       *shellService.Service
+	languages   map[string]Language
+	testRunners map[string]TestRunner
+	checkers    map[string]Checker
+	cellTimeout time.Duration
+
+	kernelsMu sync.Mutex
+	kernels   map[string]*Kernel
+	// starting 每个沙箱正在启动的内核数，与已启动的内核一起计入上限
+	starting map[string]int
+}
+
+// Option 代码运行服务的可选配置.
+type Option func(*Service)
+
+// WithCellTimeout 设置内核单元的最长运行时间，超时后中断单元，<= 0 表示不限制.
+func WithCellTimeout(timeout time.Duration) Option {
+	return func(s *Service) {
+		s.cellTimeout = timeout
+	}
+}
+
+// NewService 创建代码运行服务实例.
+func NewService(shell *shellService.Service, languages map[string]Language, opts ...Option) *Service {
+	s := &Service{
+		shell:     shell,
+		languages: languages,
+		kernels:   make(map[string]*Kernel),
+		starting:  make(map[string]int),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// File 代码运行期间新建或修改的文件.
+type File struct {
+	// Path 相对工作空间的路径
+	Path string
+	Size int64
+}
+
+// RunResult 代码运行结果.
+type RunResult struct {
+	// Stdout/Stderr 保留的 stdout 和 stderr，被截断时插入截断说明
+	Stdout string
+	Stderr string
+	// StdoutData/StderrData 保留的原始字节，被截断时为开头和结尾的字节直接拼接
+	StdoutData []byte
+	StderrData []byte
+	// StdoutEncoding/StderrEncoding 检测到的 stdout 和 stderr 编码
+	StdoutEncoding shellService.Encoding
+	StderrEncoding shellService.Encoding
+	ExitCode       int
+	// Truncated 表示 stdout 或 stderr 超过上限被截断
+	Truncated bool
+	// Signal 导致进程结束的信号，正常退出时为空
+	Signal string
+	// TimedOut 表示进程因超时被终止
+	TimedOut bool
+	// LimitsExceeded 运行期间触发的资源限制
+	LimitsExceeded []string
+	// Files 运行期间在工作空间中新建或修改的文件，按路径排序
+	Files []File
+	// QueueWait 因并发限制排队等待的时间
+	QueueWait time.Duration
+	// RecordingID 输出的录制，未开启录制时为空
+	RecordingID string
+}
+
+// Languages 返回已配置的语言名称，按名称排序.
+func (s *Service) Languages() []string {
+	names := make([]string, 0, len(s.languages))
+	for name := range s.languages {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// RunRequest 代码运行请求.
+type RunRequest struct {
+	SandboxID string
+	Language  string
+	Code      string
+	// CallerKeyID 调用方 API Key 的标识，记录在执行历史中
+	CallerKeyID string
+}
+
+// RunCode 将代码写入工作空间中的临时文件并用语言对应的解释器运行，运行结束后删除该文件.
+// 解释器为 shell 时代码同样按命令策略检查. 非零退出码和超时通过结果返回而不是错误.
+func (s *Service) RunCode(ctx context.Context, req *RunRequest) (*RunResult, error) {
+	lang, ok := s.languages[req.Language]
+	if !ok || len(lang.Command) == 0 {
+		return nil, fmt.Errorf("%w %q (supported: %s)", ErrUnsupportedLanguage, req.Language, strings.Join(s.Languages(), ", "))
+	}
+
+	if err := s.checkShellCode(req.SandboxID, lang.Command, req.Code); err != nil {
+		return nil, err
+	}
+
+	dir, user, err := s.shell.Workspace(req.SandboxID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := scanFiles(dir)
+
+	name, err := writeCode(dir, user, req.Code, lang.Extension)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = os.Remove(filepath.Join(dir, name))
+	}()
+
+	// 使用相对路径，启用隔离时工作空间挂载在其他位置
+	argv := append(append([]string{}, lang.Command...), name)
+
+	result, err := s.shell.Execute(ctx, &shellService.ExecuteRequest{
+		SandboxID:   req.SandboxID,
+		Argv:        argv,
+		CallerKeyID: req.CallerKeyID,
+	})
+	// 没有结果说明代码没有运行（如被策略拒绝或解释器不存在）
+	if result == nil {
+		return nil, err
+	}
+
+	return &RunResult{
+		Stdout:         result.Stdout,
+		Stderr:         result.Stderr,
+		StdoutData:     result.StdoutData,
+		StderrData:     result.StderrData,
+		StdoutEncoding: result.StdoutEncoding,
+		StderrEncoding: result.StderrEncoding,
+		ExitCode:       result.ExitCode,
+		Truncated:      result.Truncated,
+		Signal:         result.Signal,
+		TimedOut:       result.TimedOut,
+		LimitsExceeded: result.LimitsExceeded,
+		Files:          changedFiles(before, scanFiles(dir)),
+		QueueWait:      result.QueueWait,
+		RecordingID:    result.RecordingID,
+	}, nil
+}
+
+// checkShellCode 解释器为 shell 时按命令策略检查代码.
+// 命令策略只能看到解释器和代码文件的路径，shell 代码需要作为命令字符串单独检查；
+// 命中审批规则时返回 ErrApprovalRequired，代码运行不支持等待审批.
+func (s *Service) checkShellCode(sandboxID string, interpreter []string, code string) error {
+	if len(interpreter) == 0 || !slices.Contains(shells, filepath.Base(interpreter[0])) {
+		return nil
+	}
+
+	return s.shell.CheckCommand(sandboxID, code)
+}
+
+// writeCode 将代码写入工作空间中的新文件，返回相对工作空间的路径.
+func writeCode(dir string, user *sandbox.User, code, extension string) (string, error) {
+	name := filepath.Join(codeDir, uuid.New().String()+extension)
+
+	// 工作空间由沙箱用户控制，不能跟随其中的符号链接创建文件
+	f, err := sandbox.CreateFile(dir, name, 0o600, user)
+	if err != nil {
+		return "", fmt.Errorf("failed to create code file: %w", err)
+	}
+
+	if _, err := f.WriteString(code); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+
+		return "", fmt.Errorf("failed to write code file: %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write code file: %w", err)
+	}
+
+	return name, nil
+}
+
+// fileState 文件的大小和修改时间.
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// scanFiles 记录工作空间中普通文件的状态，跳过服务自己使用的目录，最多扫描 maxScannedFiles 个文件.
+func scanFiles(dir string) map[string]fileState {
+	files := make(map[string]fileState)
+
+	root := dir
+	if root == "" {
+		root = "."
+	}
+
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		rel, relErr := filepath.Rel(root, path)
+		if relErr != nil {
+			return nil
+		}
+
+		if d.IsDir() {
+			if rel == internalDir {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+
+		files[rel] = fileState{size: info.Size(), modTime: info.ModTime()}
+
+		if len(files) >= maxScannedFiles {
+			return filepath.SkipAll
+		}
+
+		return nil
+	})
+
+	return files
+}
+
+// changedFiles 返回 after 中新增或发生变化的文件，按路径排序.
+func changedFiles(before, after map[string]fileState) []File {
+	var files []File
+
+	for path, state := range after {
+		if prev, ok := before[path]; ok && prev.size == state.size && prev.modTime.Equal(state.modTime) {
+			continue
+		}
+
+		files = append(files, File{Path: path, Size: state.size})
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+
+	return files
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	shellService "github.com/HJH0924/agent-sandbox/domain/shell/service"
+	"github.com/HJH0924/agent-sandbox/internal/policy"
+	"github.com/HJH0924/agent-sandbox/internal/sandbox"
+)
+
+var testLanguages = map[string]Language{
+	"sh":      {Command: []string{"sh"}, Extension: ".sh"},
+	"missing": {Command: []string{"agent-sandbox-no-such-interpreter"}, Extension: ".x"},
+}
+
+func TestCodeService_RunCode(t *testing.T) {
+	workspace := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(workspace, "existing.txt"), []byte("old"), 0o600); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	service := NewService(shellService.NewService(10, workspace), testLanguages)
+
+	code := "echo out; echo err >&2; echo data > result.csv; mkdir -p plots && echo png > plots/a.png; exit 3"
+
+	result, err := service.RunCode(context.Background(), &RunRequest{Language: "sh", Code: code})
+	if err != nil {
+		t.Fatalf("RunCode failed: %v", err)
+	}
+
+	if result.Stdout != "out\n" || result.Stderr != "err\n" {
+		t.Fatalf("Expected separate stdout and stderr, got %q and %q", result.Stdout, result.Stderr)
+	}
+
+	if result.ExitCode != 3 {
+		t.Fatalf("Expected exit code 3, got %d", result.ExitCode)
+	}
+
+	// 只报告新建的文件，代码文件本身不计入
+	if len(result.Files) != 2 || result.Files[0].Path != "plots/a.png" || result.Files[1].Path != "result.csv" {
+		t.Fatalf("Expected produced files plots/a.png and result.csv, got %+v", result.Files)
+	}
+
+	if result.Files[1].Size != 5 {
+		t.Fatalf("Expected result.csv to be 5 bytes, got %d", result.Files[1].Size)
+	}
+
+	// 代码文件在运行后删除
+	entries, err := os.ReadDir(filepath.Join(workspace, codeDir))
+	if err != nil {
+		t.Fatalf("Failed to read code directory: %v", err)
+	}
+
+	if len(entries) != 0 {
+		t.Fatalf("Expected code files to be removed, got %d", len(entries))
+	}
+}
+
+func TestCodeService_RunCode_CodeDirSymlink(t *testing.T) {
+	workspace, outside := t.TempDir(), t.TempDir()
+
+	// 沙箱将代码目录替换为指向宿主机目录的符号链接
+	if err := os.MkdirAll(filepath.Dir(filepath.Join(workspace, codeDir)), 0o750); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+
+	if err := os.Symlink(outside, filepath.Join(workspace, codeDir)); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	service := NewService(shellService.NewService(10, workspace), testLanguages)
+
+	if _, err := service.RunCode(context.Background(), &RunRequest{Language: "sh", Code: "echo hi"}); !errors.Is(err, sandbox.ErrUnsafePath) {
+		t.Fatalf("Expected ErrUnsafePath, got %v", err)
+	}
+
+	if entries, _ := os.ReadDir(outside); len(entries) != 0 {
+		t.Fatalf("Expected nothing to be written outside the workspace, got %v", entries)
+	}
+}
+
+func TestCodeService_RunCode_ModifiedFiles(t *testing.T) {
+	workspace := t.TempDir()
+	service := NewService(shellService.NewService(10, workspace), testLanguages)
+
+	if _, err := service.RunCode(context.Background(), &RunRequest{Language: "sh", Code: "echo a > a.txt; echo b > b.txt"}); err != nil {
+		t.Fatalf("RunCode failed: %v", err)
+	}
+
+	// 修改过的文件同样被报告，未改动的文件不报告
+	result, err := service.RunCode(context.Background(), &RunRequest{Language: "sh", Code: "echo more >> a.txt; cat b.txt"})
+	if err != nil {
+		t.Fatalf("RunCode failed: %v", err)
+	}
+
+	if len(result.Files) != 1 || result.Files[0].Path != "a.txt" {
+		t.Fatalf("Expected only a.txt to be reported, got %+v", result.Files)
+	}
+}
+
+func TestCodeService_RunCode_Python(t *testing.T) {
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not available")
+	}
+
+	service := NewService(shellService.NewService(10, t.TempDir()), map[string]Language{
+		"python": {Command: []string{"python3"}, Extension: ".py"},
+	})
+
+	result, err := service.RunCode(context.Background(), &RunRequest{Language: "python", Code: "import sys\nprint(sum(range(10)))\nsys.exit(0)\n"})
+	if err != nil {
+		t.Fatalf("RunCode failed: %v", err)
+	}
+
+	if result.Stdout != "45\n" || result.ExitCode != 0 {
+		t.Fatalf("Expected 45 with exit code 0, got %q (exit code %d)", result.Stdout, result.ExitCode)
+	}
+}
+
+func TestCodeService_RunCode_Errors(t *testing.T) {
+	workspace := t.TempDir()
+
+	deny, err := policy.New([]policy.Rule{{Name: "no-sh", Action: policy.ActionDeny, Prefix: []string{"sh"}}}, policy.ActionAllow)
+	if err != nil {
+		t.Fatalf("Failed to create policy: %v", err)
+	}
+
+	service := NewService(shellService.NewService(10, workspace, shellService.WithPolicy(deny)), testLanguages)
+
+	_, err = service.RunCode(context.Background(), &RunRequest{Language: "cobol", Code: "DISPLAY 'HI'."})
+	if !errors.Is(err, ErrUnsupportedLanguage) {
+		t.Fatalf("Expected ErrUnsupportedLanguage, got %v", err)
+	}
+
+	_, err = service.RunCode(context.Background(), &RunRequest{Language: "missing", Code: ""})
+	if !errors.Is(err, shellService.ErrProgramNotFound) {
+		t.Fatalf("Expected ErrProgramNotFound, got %v", err)
+	}
+
+	// 解释器命令同样受命令策略约束
+	_, err = service.RunCode(context.Background(), &RunRequest{Language: "sh", Code: "echo hi"})
+	if !errors.Is(err, shellService.ErrCommandDenied) {
+		t.Fatalf("Expected ErrCommandDenied, got %v", err)
+	}
+}
+
+func TestCodeService_RunCode_ShellPolicy(t *testing.T) {
+	workspace := t.TempDir()
+
+	deny, err := policy.New([]policy.Rule{{Name: "no-rm", Action: policy.ActionDeny, Prefix: []string{"rm"}}}, policy.ActionAllow)
+	if err != nil {
+		t.Fatalf("Failed to create policy: %v", err)
+	}
+
+	service := NewService(shellService.NewService(10, workspace, shellService.WithPolicy(deny)), map[string]Language{
+		"bash": {Command: []string{"bash"}, Extension: ".sh"},
+	})
+
+	if err := os.WriteFile(filepath.Join(workspace, "keep.txt"), []byte("data"), 0o600); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	// shell 代码按命令检查，而不只是检查 bash <代码文件>
+	_, err = service.RunCode(context.Background(), &RunRequest{Language: "bash", Code: "echo start; rm -f keep.txt"})
+	if !errors.Is(err, shellService.ErrCommandDenied) {
+		t.Fatalf("Expected ErrCommandDenied, got %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(workspace, "keep.txt")); err != nil {
+		t.Fatalf("Expected denied code not to run: %v", err)
+	}
+
+	result, err := service.RunCode(context.Background(), &RunRequest{Language: "bash", Code: "echo ok"})
+	if err != nil || result.Stdout != "ok\n" {
+		t.Fatalf("Expected allowed code to run, got %+v, %v", result, err)
+	}
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	shellService "github.com/HJH0924/agent-sandbox/domain/shell/service"
+	"github.com/HJH0924/agent-sandbox/internal/diagnostic"
+)
+
+// ErrUnknownChecker 没有配置该检查器.
+var ErrUnknownChecker = errors.New("unknown checker")
+
+// Checker 编译器或 linter 检查器配置.
+type Checker struct {
+	// Command 运行检查的程序及参数，请求中的参数追加在最后
+	Command []string
+	// DefaultArgs 请求没有指定参数时追加的参数（如 ./...）
+	DefaultArgs []string
+	// Format 输出格式，对应 diagnostic 中注册的解析器（go、gcc、golangci-lint）
+	Format string
+}
+
+// WithCheckers 设置 Diagnostics 可用的检查器，键为检查器名称.
+func WithCheckers(checkers map[string]Checker) Option {
+	return func(s *Service) {
+		s.checkers = checkers
+	}
+}
+
+// Checkers 返回已配置的检查器名称，按名称排序.
+func (s *Service) Checkers() []string {
+	names := make([]string, 0, len(s.checkers))
+	for name := range s.checkers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// DiagnosticsRequest 运行检查器的请求.
+type DiagnosticsRequest struct {
+	SandboxID string
+	// Checkers 要运行的检查器，为空时按名称顺序运行所有检查器
+	Checkers []string
+	// Args 追加在每个检查器命令之后的参数，为空时使用检查器的 DefaultArgs
+	Args []string
+	// CallerKeyID 调用方 API Key 的标识，记录在执行历史中
+	CallerKeyID string
+}
+
+// Diagnostic 一条诊断及报告它的检查器.
+type Diagnostic struct {
+	diagnostic.Diagnostic
+	Checker string
+}
+
+// CheckerResult 一个检查器的运行结果.
+type CheckerResult struct {
+	Name     string
+	ExitCode int
+	// Signal 导致进程结束的信号，正常退出时为空
+	Signal   string
+	TimedOut bool
+	// Error 检查器没有运行（如程序不存在、被策略拒绝）或输出无法解析的原因
+	Error string
+	// Output 检查器失败却没有解析出任何诊断时的 stdout 和 stderr（如 go.mod 不存在），其他情况为空
+	Output string
+	// Truncated 表示输出被截断且没有保存完整输出，诊断可能不完整
+	Truncated bool
+	// QueueWait 因并发限制排队等待的时间
+	QueueWait time.Duration
+	// RecordingID 输出的录制，未开启录制时为空
+	RecordingID string
+}
+
+// DiagnosticsResult 运行检查器的结果.
+type DiagnosticsResult struct {
+	// Diagnostics 所有检查器的诊断，多个检查器报告的相同诊断只保留第一个
+	Diagnostics []Diagnostic
+	Checkers    []CheckerResult
+}
+
+// Diagnostics 在工作空间中依次运行检查器并将输出解析为诊断. 检查器与 RunCode 一样通过
+// Shell 服务执行；检查失败（非零退出码）和单个检查器无法运行通过结果返回而不是错误.
+func (s *Service) Diagnostics(ctx context.Context, req *DiagnosticsRequest) (*DiagnosticsResult, error) {
+	names := req.Checkers
+	if len(names) == 0 {
+		names = s.Checkers()
+	}
+
+	parsers := make([]diagnostic.Parser, 0, len(names))
+
+	for _, name := range names {
+		checker, ok := s.checkers[name]
+		if !ok || len(checker.Command) == 0 {
+			return nil, fmt.Errorf("%w %q (configured: %s)", ErrUnknownChecker, name, strings.Join(s.Checkers(), ", "))
+		}
+
+		parse, err := diagnostic.Lookup(checker.Format)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: %w", ErrUnknownChecker, name, err)
+		}
+
+		parsers = append(parsers, parse)
+	}
+
+	dir, _, err := s.shell.Workspace(req.SandboxID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DiagnosticsResult{
+		Diagnostics: []Diagnostic{},
+		Checkers:    make([]CheckerResult, 0, len(names)),
+	}
+
+	type key struct {
+		file         string
+		line, column int
+		message      string
+	}
+
+	seen := make(map[key]bool)
+
+	for i, name := range names {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		diagnostics, checked := s.runChecker(ctx, req, dir, name, parsers[i])
+		result.Checkers = append(result.Checkers, checked)
+
+		for _, d := range diagnostics {
+			d.File = workspacePath(dir, d.File)
+
+			k := key{d.File, d.Line, d.Column, d.Message}
+			if seen[k] {
+				continue
+			}
+
+			seen[k] = true
+
+			result.Diagnostics = append(result.Diagnostics, Diagnostic{Diagnostic: d, Checker: name})
+		}
+	}
+
+	return result, nil
+}
+
+// runChecker 运行一个检查器并解析其 stdout 和 stderr.
+func (s *Service) runChecker(
+	ctx context.Context,
+	req *DiagnosticsRequest,
+	dir, name string,
+	parse diagnostic.Parser,
+) ([]diagnostic.Diagnostic, CheckerResult) {
+	checker := s.checkers[name]
+
+	args := req.Args
+	if len(args) == 0 {
+		args = checker.DefaultArgs
+	}
+
+	checked := CheckerResult{Name: name}
+
+	result, err := s.shell.Execute(ctx, &shellService.ExecuteRequest{
+		SandboxID:   req.SandboxID,
+		Argv:        append(append([]string{}, checker.Command...), args...),
+		CallerKeyID: req.CallerKeyID,
+	})
+	// 没有结果说明检查器没有运行（如被策略拒绝或程序不存在）
+	if result == nil {
+		checked.Error = err.Error()
+		return nil, checked
+	}
+
+	checked.ExitCode = result.ExitCode
+	checked.Signal = result.Signal
+	checked.TimedOut = result.TimedOut
+	checked.QueueWait = result.QueueWait
+	checked.RecordingID = result.RecordingID
+
+	// go vet 等工具将诊断写到 stderr，golangci-lint 写到 stdout
+	stdout, truncated, err := s.readOutput(req.SandboxID, dir, "", result)
+	checked.Truncated = truncated
+
+	var diagnostics []diagnostic.Diagnostic
+
+	if err == nil {
+		diagnostics, err = parse(append(stdout, "\n"+result.Stderr...))
+	}
+
+	if err != nil {
+		checked.Error = err.Error()
+	}
+
+	if len(diagnostics) == 0 && result.ExitCode != 0 {
+		checked.Output = strings.TrimSpace(string(stdout) + "\n" + result.Stderr)
+	}
+
+	return diagnostics, checked
+}
+
+// workspacePath 将诊断中的路径转换为相对工作空间的路径，工作空间之外的绝对路径保持不变.
+func workspacePath(dir, path string) string {
+	if path == "" {
+		return ""
+	}
+
+	if !filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+
+	if rel, err := filepath.Rel(dir, path); err == nil && filepath.IsLocal(rel) {
+		return rel
+	}
+
+	return path
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	shellService "github.com/HJH0924/agent-sandbox/domain/shell/service"
+	"github.com/HJH0924/agent-sandbox/internal/diagnostic"
+)
+
+const vetOutput = `# example.com/dg
+vet: ./main.go:5:2: "os" imported and not used
+sub/a.go:8:14: fmt.Printf format %d has arg "x" of wrong type string
+`
+
+const buildOutput = `# example.com/dg
+./main.go:5:2: "os" imported and not used
+./main.go:9:12: not enough arguments in call to f
+	have ()
+	want (int)
+`
+
+func newDiagnosticsService(t *testing.T, workspace string) *Service {
+	t.Helper()
+
+	for name, content := range map[string]string{"vet.txt": vetOutput, "build.txt": buildOutput} {
+		if err := os.WriteFile(filepath.Join(workspace, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	lint := `{"Issues":[{"FromLinter":"errcheck","Text":"unchecked error","Pos":{"Filename":"` +
+		filepath.Join(workspace, "lib", "b.go") + `","Line":3,"Column":1}}]}`
+
+	return NewService(shellService.NewService(10, workspace), testLanguages, WithCheckers(map[string]Checker{
+		// 与 go vet/go build 一样将诊断写到 stderr
+		"vet":   {Command: []string{"sh", "-c", `cat vet.txt >&2; exit 1`}, Format: "go"},
+		"build": {Command: []string{"sh", "-c", `cat build.txt >&2; exit 1`}, Format: "go"},
+		// golangci-lint 将 JSON 写到 stdout，参数被追加在命令之后
+		"lint":      {Command: []string{"sh", "-c", `echo "$1"; exit 1`, "lint"}, DefaultArgs: []string{lint}, Format: "golangci-lint"},
+		"nomod":     {Command: []string{"sh", "-c", `echo "go: go.mod file not found" >&2; exit 1`}, Format: "go"},
+		"missing":   {Command: []string{"agent-sandbox-no-such-checker"}, Format: "gcc"},
+		"badformat": {Command: []string{"true"}, Format: "eslint"},
+	}))
+}
+
+func TestCodeService_Diagnostics(t *testing.T) {
+	service := newDiagnosticsService(t, t.TempDir())
+
+	result, err := service.Diagnostics(context.Background(), &DiagnosticsRequest{Checkers: []string{"vet", "build", "lint"}})
+	if err != nil {
+		t.Fatalf("Diagnostics failed: %v", err)
+	}
+
+	if len(result.Checkers) != 3 || result.Checkers[0].Name != "vet" || result.Checkers[0].ExitCode != 1 {
+		t.Fatalf("Unexpected checker results: %+v", result.Checkers)
+	}
+
+	for _, c := range result.Checkers {
+		if c.Error != "" || c.Output != "" {
+			t.Fatalf("Expected %s to be parsed, got error %q and output %q", c.Name, c.Error, c.Output)
+		}
+	}
+
+	// build 报告的 "os" 与 vet 重复，只保留 vet 的
+	want := []struct {
+		file, checker string
+		line          int
+	}{
+		{"main.go", "vet", 5},
+		{"sub/a.go", "vet", 8},
+		{"main.go", "build", 9},
+		{"lib/b.go", "lint", 3},
+	}
+
+	if len(result.Diagnostics) != len(want) {
+		t.Fatalf("Expected %d diagnostics, got %+v", len(want), result.Diagnostics)
+	}
+
+	for i, w := range want {
+		d := result.Diagnostics[i]
+		if d.File != w.file || d.Line != w.line || d.Checker != w.checker {
+			t.Fatalf("Expected %s:%d from %s, got %+v", w.file, w.line, w.checker, d)
+		}
+	}
+
+	if msg := result.Diagnostics[2].Message; msg != "not enough arguments in call to f\nhave ()\nwant (int)" {
+		t.Fatalf("Expected continuation lines in message, got %q", msg)
+	}
+
+	if d := result.Diagnostics[3]; d.Rule != "errcheck" || d.Severity != diagnostic.SeverityError {
+		t.Fatalf("Expected golangci-lint rule and severity, got %+v", d)
+	}
+}
+
+func TestCodeService_Diagnostics_CheckerFailures(t *testing.T) {
+	service := newDiagnosticsService(t, t.TempDir())
+
+	result, err := service.Diagnostics(context.Background(), &DiagnosticsRequest{Checkers: []string{"missing", "nomod"}})
+	if err != nil {
+		t.Fatalf("Diagnostics failed: %v", err)
+	}
+
+	// 无法运行的检查器不影响其他检查器
+	if len(result.Checkers) != 2 || result.Checkers[0].Error == "" {
+		t.Fatalf("Expected missing checker to report an error, got %+v", result.Checkers)
+	}
+
+	// 失败却没有诊断时返回原始输出
+	if nomod := result.Checkers[1]; nomod.ExitCode != 1 || !strings.Contains(nomod.Output, "go.mod file not found") {
+		t.Fatalf("Expected raw output for failure without diagnostics, got %+v", nomod)
+	}
+
+	if len(result.Diagnostics) != 0 {
+		t.Fatalf("Expected no diagnostics, got %+v", result.Diagnostics)
+	}
+}
+
+func TestCodeService_Diagnostics_Errors(t *testing.T) {
+	service := newDiagnosticsService(t, t.TempDir())
+
+	for _, checker := range []string{"eslint", "badformat"} {
+		_, err := service.Diagnostics(context.Background(), &DiagnosticsRequest{Checkers: []string{checker}})
+		if !errors.Is(err, ErrUnknownChecker) {
+			t.Fatalf("Expected ErrUnknownChecker for %s, got %v", checker, err)
+		}
+	}
+}
+
+func TestWorkspacePath(t *testing.T) {
+	dir := t.TempDir()
+
+	cases := map[string]string{
+		"./main.go":                       "main.go",
+		"sub/../a.go":                     "a.go",
+		filepath.Join(dir, "pkg", "x.go"): "pkg/x.go",
+		"/usr/lib/go/src/fmt/print.go":    "/usr/lib/go/src/fmt/print.go",
+		"":                                "",
+	}
+
+	for path, want := range cases {
+		if got := workspacePath(dir, path); got != want {
+			t.Fatalf("workspacePath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
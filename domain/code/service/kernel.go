@@ -0,0 +1,534 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	shellService "github.com/HJH0924/agent-sandbox/domain/shell/service"
+	"github.com/HJH0924/agent-sandbox/internal/recording"
+	"github.com/HJH0924/agent-sandbox/internal/secret"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// maxKernelsPerSandbox 每个沙箱允许同时存在的内核数.
+	maxKernelsPerSandbox = 8
+	// kernelIdleTimeout 内核的最长空闲时间.
+	kernelIdleTimeout = 30 * time.Minute
+	// cellMarkerPrefix 单元结束标记的前缀.
+	cellMarkerPrefix = "__AGENT_SANDBOX_CELL_"
+	// interruptGrace 中断单元后等待其结束的时间，超时后终止解释器.
+	interruptGrace = 5 * time.Second
+)
+
+var (
+	// ErrKernelNotFound 内核不存在或不属于当前沙箱.
+	ErrKernelNotFound = errors.New("kernel not found")
+	// ErrTooManyKernels 沙箱的内核数已达上限.
+	ErrTooManyKernels = fmt.Errorf("too many kernels (max: %d)", maxKernelsPerSandbox)
+	// ErrKernelUnsupported 语言没有配置交互式解释器.
+	ErrKernelUnsupported = errors.New("language does not support kernels")
+)
+
+// CellStatus 单元的运行结果.
+type CellStatus string
+
+const (
+	// CellOK 单元正常结束.
+	CellOK CellStatus = "ok"
+	// CellError 单元抛出了错误，解释器状态保留.
+	CellError CellStatus = "error"
+	// CellInterrupted 单元被中断或超时.
+	CellInterrupted CellStatus = "interrupted"
+	// CellCrashed 解释器进程退出，下一个单元运行前会重新启动.
+	CellCrashed CellStatus = "crashed"
+)
+
+// Stream 单元输出所属的输出流.
+type Stream int
+
+const (
+	// Stdout 标准输出.
+	Stdout Stream = iota
+	// Stderr 标准错误.
+	Stderr
+)
+
+// CellResult 单元运行结果.
+type CellResult struct {
+	Status CellStatus
+	// ExitCode 解释器崩溃时的退出码
+	ExitCode int
+	// Restarted 表示运行单元前解释器重新启动过，之前单元定义的状态已丢失
+	Restarted bool
+	// Files 运行期间在工作空间中新建或修改的文件
+	Files []File
+	// RecordingID 单元输出的录制，未开启录制时为空
+	RecordingID string
+}
+
+// Kernel 长期运行的交互式解释器，单元之间保留变量等状态.
+type Kernel struct {
+	ID        string
+	SandboxID string
+	Language  string
+
+	lang Language
+
+	// cellMu 保证同一时间只有一个单元在运行
+	cellMu sync.Mutex
+
+	// mu 保护当前解释器进程和中断状态
+	mu          sync.Mutex
+	proc        *shellService.Process
+	running     bool
+	interrupted bool
+	closed      bool
+	// interrupts 通知正在运行的单元已发送中断
+	interrupts chan struct{}
+
+	idleTimer *time.Timer
+}
+
+// CreateKernel 为沙箱启动指定语言的内核.
+func (s *Service) CreateKernel(ctx context.Context, sandboxID, language string) (*Kernel, error) {
+	lang, ok := s.languages[language]
+	if !ok {
+		return nil, fmt.Errorf("%w %q (supported: %s)", ErrUnsupportedLanguage, language, strings.Join(s.Languages(), ", "))
+	}
+
+	if len(lang.Repl) == 0 || lang.ReplRun == "" {
+		return nil, fmt.Errorf("%w: %s", ErrKernelUnsupported, language)
+	}
+
+	// 在锁内占用名额，启动解释器（可能等待审批）期间不持有锁
+	if err := s.reserveKernel(sandboxID); err != nil {
+		return nil, err
+	}
+
+	proc, err := s.shell.Spawn(ctx, sandboxID, lang.Repl)
+	if err != nil {
+		s.kernelsMu.Lock()
+		s.releaseKernelLocked(sandboxID)
+		s.kernelsMu.Unlock()
+
+		return nil, err
+	}
+
+	k := &Kernel{
+		ID:         uuid.New().String(),
+		SandboxID:  sandboxID,
+		Language:   language,
+		lang:       lang,
+		proc:       proc,
+		interrupts: make(chan struct{}, 1),
+	}
+	k.idleTimer = time.AfterFunc(kernelIdleTimeout, func() {
+		_ = s.ShutdownKernel(sandboxID, k.ID)
+	})
+
+	s.kernelsMu.Lock()
+	s.releaseKernelLocked(sandboxID)
+	s.kernels[k.ID] = k
+	s.kernelsMu.Unlock()
+
+	return k, nil
+}
+
+// reserveKernel 为沙箱占用一个内核名额，已启动和启动中的内核数达到上限时返回 ErrTooManyKernels.
+func (s *Service) reserveKernel(sandboxID string) error {
+	s.kernelsMu.Lock()
+	defer s.kernelsMu.Unlock()
+
+	count := s.starting[sandboxID]
+
+	for _, k := range s.kernels {
+		if k.SandboxID == sandboxID {
+			count++
+		}
+	}
+
+	if count >= maxKernelsPerSandbox {
+		return ErrTooManyKernels
+	}
+
+	s.starting[sandboxID]++
+
+	return nil
+}
+
+// releaseKernelLocked 归还 reserveKernel 占用的名额，调用方必须持有 kernelsMu.
+func (s *Service) releaseKernelLocked(sandboxID string) {
+	if s.starting[sandboxID]--; s.starting[sandboxID] <= 0 {
+		delete(s.starting, sandboxID)
+	}
+}
+
+// ShutdownKernel 终止内核的解释器进程.
+func (s *Service) ShutdownKernel(sandboxID, kernelID string) error {
+	s.kernelsMu.Lock()
+
+	k, ok := s.kernels[kernelID]
+	if !ok || k.SandboxID != sandboxID {
+		s.kernelsMu.Unlock()
+		return ErrKernelNotFound
+	}
+
+	delete(s.kernels, kernelID)
+	s.kernelsMu.Unlock()
+
+	k.idleTimer.Stop()
+
+	k.mu.Lock()
+	k.closed = true
+	k.proc.Close()
+	k.mu.Unlock()
+
+	return nil
+}
+
+// CloseSandbox 关闭沙箱的所有内核，用于销毁沙箱.
+func (s *Service) CloseSandbox(sandboxID string) {
+	var ids []string
+
+	s.kernelsMu.Lock()
+	for id, k := range s.kernels {
+		if k.SandboxID == sandboxID {
+			ids = append(ids, id)
+		}
+	}
+	s.kernelsMu.Unlock()
+
+	for _, id := range ids {
+		_ = s.ShutdownKernel(sandboxID, id)
+	}
+}
+
+// InterruptKernel 中断内核中正在运行的单元（向解释器发送 SIGINT），没有单元运行时不做任何事.
+func (s *Service) InterruptKernel(sandboxID, kernelID string) error {
+	k, err := s.lookupKernel(sandboxID, kernelID)
+	if err != nil {
+		return err
+	}
+
+	k.interrupt()
+
+	return nil
+}
+
+// ExecuteCell 在内核中运行代码单元，输出产生时通过 output 回调，同一内核的单元依次运行.
+// 单元占用 Shell 服务的并发名额；解释器为 shell 时单元按命令策略检查.
+// 解释器已退出时先重新启动；ctx 结束时中断单元并返回 ctx 的错误.
+func (s *Service) ExecuteCell(
+	ctx context.Context,
+	sandboxID, kernelID, code string,
+	output func(stream Stream, data []byte),
+) (*CellResult, error) {
+	k, err := s.lookupKernel(sandboxID, kernelID)
+	if err != nil {
+		return nil, err
+	}
+
+	// shell 内核的单元本身就是命令
+	if err := s.checkShellCode(sandboxID, k.lang.Repl, code); err != nil {
+		return nil, err
+	}
+
+	k.cellMu.Lock()
+	defer k.cellMu.Unlock()
+
+	k.idleTimer.Stop()
+	defer k.idleTimer.Reset(kernelIdleTimeout)
+
+	// 单元与 Execute 的命令共用并发名额，排队等待时不计入单元的运行时间
+	release, _, err := s.shell.AcquireSlot(ctx, sandboxID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer release()
+
+	result := &CellResult{}
+
+	proc, restarted, err := s.kernelProcess(ctx, k)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Restarted = restarted
+
+	dir, user, err := s.shell.Workspace(sandboxID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := scanFiles(dir)
+
+	name, err := writeCode(dir, user, code, k.lang.Extension)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = os.Remove(filepath.Join(dir, name))
+	}()
+
+	rec := s.shell.StartRecording(sandboxID, recording.KindCell, code, 0, 0)
+	defer rec.Close()
+
+	result.RecordingID = rec.ID()
+
+	// 两个输出流的回调不会并发执行；每个输出流分别替换密钥值，跨越两段输出的密钥值同样被替换
+	redactors := map[Stream]*secret.Redactor{
+		Stdout: s.shell.NewRedactor(sandboxID),
+		Stderr: s.shell.NewRedactor(sandboxID),
+	}
+
+	emit := func(stream Stream, data []byte) {
+		if len(data) == 0 {
+			return
+		}
+
+		_, _ = rec.Write(data)
+		output(stream, data)
+	}
+
+	marker := cellMarkerPrefix + uuid.New().String()
+	script := strings.NewReplacer(
+		"{file}", filepath.Join(proc.Workspace, name),
+		"{marker}", marker,
+	).Replace(k.lang.ReplRun)
+
+	k.setRunning(true)
+	status, err := s.runCell(ctx, k, proc, script+"\n", marker, func(stream Stream, data []byte) {
+		emit(stream, redactors[stream].Redact(data))
+	})
+	k.setRunning(false)
+
+	// 单元结束后两个输出流都已读完，输出保留的字节
+	emit(Stdout, redactors[Stdout].Flush())
+	emit(Stderr, redactors[Stderr].Flush())
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.Status = status
+	if status == CellCrashed {
+		result.ExitCode = proc.ExitCode()
+	}
+
+	result.Files = changedFiles(before, scanFiles(dir))
+
+	return result, nil
+}
+
+// kernelProcess 返回内核当前的解释器进程，进程已退出时重新启动.
+func (s *Service) kernelProcess(ctx context.Context, k *Kernel) (*shellService.Process, bool, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.closed {
+		return nil, false, ErrKernelNotFound
+	}
+
+	select {
+	case <-k.proc.Done():
+	default:
+		return k.proc, false, nil
+	}
+
+	k.proc.Close()
+
+	proc, err := s.shell.Spawn(ctx, k.SandboxID, k.lang.Repl)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to restart kernel: %w", err)
+	}
+
+	k.proc = proc
+
+	return proc, true, nil
+}
+
+// runCell 向解释器写入运行单元的语句，转发输出直到两个输出流都出现结束标记.
+// 超时或 ctx 结束时中断单元，宽限期内仍未结束则终止解释器；ctx 结束时等单元结束后返回 ctx 的错误.
+func (s *Service) runCell(
+	ctx context.Context,
+	k *Kernel,
+	proc *shellService.Process,
+	script, marker string,
+	output func(stream Stream, data []byte),
+) (CellStatus, error) {
+	if _, err := io.WriteString(proc.Stdin, script); err != nil {
+		return crashed(proc), nil
+	}
+
+	var mu sync.Mutex
+
+	stdoutCh := make(chan error, 1)
+	stderrCh := make(chan error, 1)
+
+	var code int
+
+	go func() {
+		var err error
+
+		code, err = shellService.CopyUntilMarker(proc.Stdout, &streamWriter{mu: &mu, stream: Stdout, output: output}, marker)
+		stdoutCh <- err
+	}()
+
+	go func() {
+		_, err := shellService.CopyUntilMarker(proc.Stderr, &streamWriter{mu: &mu, stream: Stderr, output: output}, marker)
+		stderrCh <- err
+	}()
+
+	var timeout <-chan time.Time
+
+	if s.cellTimeout > 0 {
+		timer := time.NewTimer(s.cellTimeout)
+		defer timer.Stop()
+
+		timeout = timer.C
+	}
+
+	var (
+		grace                <-chan time.Time
+		stdoutErr, stderrErr error
+		canceled             error
+	)
+
+	done := ctx.Done()
+
+	for received := 0; received < 2; {
+		select {
+		case stdoutErr = <-stdoutCh:
+			received++
+		case stderrErr = <-stderrCh:
+			received++
+		case <-timeout:
+			timeout = nil
+			k.interrupt()
+		case <-done:
+			done, canceled = nil, ctx.Err()
+			k.interrupt()
+		case <-k.interrupts:
+			if grace == nil {
+				grace = time.After(interruptGrace)
+			}
+		case <-grace:
+			// 解释器没有响应中断，终止后读取会随即结束
+			grace = nil
+			proc.Close()
+		}
+	}
+
+	k.mu.Lock()
+	interrupted := k.interrupted
+	k.mu.Unlock()
+
+	status := CellOK
+
+	switch {
+	case stdoutErr != nil || stderrErr != nil:
+		status = crashed(proc)
+		if interrupted {
+			status = CellInterrupted
+		}
+	case interrupted:
+		status = CellInterrupted
+	case code != 0:
+		status = CellError
+	}
+
+	if canceled != nil {
+		return status, canceled
+	}
+
+	return status, nil
+}
+
+// crashed 等待已退出的解释器进程结束并释放其资源.
+func crashed(proc *shellService.Process) CellStatus {
+	select {
+	case <-proc.Done():
+	case <-time.After(interruptGrace):
+	}
+
+	proc.Close()
+
+	return CellCrashed
+}
+
+// lookupKernel 查找属于沙箱的内核.
+func (s *Service) lookupKernel(sandboxID, kernelID string) (*Kernel, error) {
+	s.kernelsMu.Lock()
+	k, ok := s.kernels[kernelID]
+	s.kernelsMu.Unlock()
+
+	if !ok || k.SandboxID != sandboxID {
+		return nil, ErrKernelNotFound
+	}
+
+	return k, nil
+}
+
+// setRunning 记录是否有单元正在运行，开始运行时清除中断状态.
+func (k *Kernel) setRunning(running bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.running = running
+	if running {
+		k.interrupted = false
+
+		select {
+		case <-k.interrupts:
+		default:
+		}
+	}
+}
+
+// interrupt 向解释器发送 SIGINT 并记录单元被中断，没有单元运行时不发送信号.
+// 单元在宽限期内没有结束时解释器会被终止.
+func (k *Kernel) interrupt() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if !k.running {
+		return
+	}
+
+	k.interrupted = true
+	_ = k.proc.Signal(syscall.SIGINT)
+
+	select {
+	case k.interrupts <- struct{}{}:
+	default:
+	}
+}
+
+// streamWriter 将输出转发给回调，两个输出流共用同一把锁以保证回调不会并发执行.
+type streamWriter struct {
+	mu     *sync.Mutex
+	stream Stream
+	output func(stream Stream, data []byte)
+}
+
+// Write 复制并转发输出.
+func (w *streamWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.output(w.stream, append([]byte(nil), p...))
+
+	return len(p), nil
+}
@@ -0,0 +1,452 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	shellService "github.com/HJH0924/agent-sandbox/domain/shell/service"
+	"github.com/HJH0924/agent-sandbox/internal/policy"
+	"github.com/HJH0924/agent-sandbox/internal/secret"
+)
+
+// bashKernel 测试使用的 bash 内核，trap 保证中断只结束正在运行的命令.
+var bashKernel = Language{
+	Extension: ".sh",
+	Repl:      []string{"bash", "--norc", "--noprofile"},
+	ReplRun: "trap : INT; . '{file}' </dev/null\n" +
+		`printf '\n{marker} %d\n' "$?"; printf '\n{marker}\n' >&2`,
+}
+
+// cellOutput 收集单元的输出.
+type cellOutput struct {
+	mu      sync.Mutex
+	stdout  strings.Builder
+	stderr  strings.Builder
+	started chan struct{}
+	once    sync.Once
+}
+
+func newCellOutput() *cellOutput {
+	return &cellOutput{started: make(chan struct{})}
+}
+
+func (o *cellOutput) write(stream Stream, data []byte) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if stream == Stderr {
+		o.stderr.Write(data)
+	} else {
+		o.stdout.Write(data)
+	}
+
+	o.once.Do(func() { close(o.started) })
+}
+
+func newKernelService(t *testing.T, opts ...Option) *Service {
+	t.Helper()
+
+	return NewService(shellService.NewService(10, t.TempDir()), map[string]Language{
+		"bash": bashKernel,
+		"sh":   {Command: []string{"sh"}, Extension: ".sh"},
+	}, opts...)
+}
+
+func TestKernel_StatePersists(t *testing.T) {
+	service := newKernelService(t)
+	ctx := context.Background()
+
+	kernel, err := service.CreateKernel(ctx, "sandbox-1", "bash")
+	if err != nil {
+		t.Fatalf("CreateKernel failed: %v", err)
+	}
+	defer func() {
+		_ = service.ShutdownKernel("sandbox-1", kernel.ID)
+	}()
+
+	out := newCellOutput()
+
+	result, err := service.ExecuteCell(ctx, "sandbox-1", kernel.ID, "x=41\ncd /tmp", out.write)
+	if err != nil {
+		t.Fatalf("ExecuteCell failed: %v", err)
+	}
+
+	if result.Status != CellOK || result.Restarted {
+		t.Fatalf("Expected ok without restart, got %+v", result)
+	}
+
+	out = newCellOutput()
+
+	result, err = service.ExecuteCell(ctx, "sandbox-1", kernel.ID, "echo $((x + 1)) $PWD; echo warn >&2; touch \"$OLDPWD/made.txt\"", out.write)
+	if err != nil {
+		t.Fatalf("ExecuteCell failed: %v", err)
+	}
+
+	if out.stdout.String() != "42 /tmp\n" || out.stderr.String() != "warn\n" {
+		t.Fatalf("Expected state to persist between cells, got stdout %q stderr %q", out.stdout.String(), out.stderr.String())
+	}
+
+	if len(result.Files) != 1 || result.Files[0].Path != "made.txt" {
+		t.Fatalf("Expected made.txt to be reported, got %+v", result.Files)
+	}
+
+	result, err = service.ExecuteCell(ctx, "sandbox-1", kernel.ID, "false", newCellOutput().write)
+	if err != nil {
+		t.Fatalf("ExecuteCell failed: %v", err)
+	}
+
+	if result.Status != CellError {
+		t.Fatalf("Expected error status, got %q", result.Status)
+	}
+}
+
+func TestKernel_CrashRecovery(t *testing.T) {
+	service := newKernelService(t)
+	ctx := context.Background()
+
+	kernel, err := service.CreateKernel(ctx, "sandbox-1", "bash")
+	if err != nil {
+		t.Fatalf("CreateKernel failed: %v", err)
+	}
+	defer func() {
+		_ = service.ShutdownKernel("sandbox-1", kernel.ID)
+	}()
+
+	if _, err := service.ExecuteCell(ctx, "sandbox-1", kernel.ID, "x=1", newCellOutput().write); err != nil {
+		t.Fatalf("ExecuteCell failed: %v", err)
+	}
+
+	out := newCellOutput()
+
+	result, err := service.ExecuteCell(ctx, "sandbox-1", kernel.ID, "echo bye; exit 7", out.write)
+	if err != nil {
+		t.Fatalf("ExecuteCell failed: %v", err)
+	}
+
+	if result.Status != CellCrashed || result.ExitCode != 7 {
+		t.Fatalf("Expected crash with exit code 7, got %+v", result)
+	}
+
+	if out.stdout.String() != "bye\n" {
+		t.Fatalf("Expected output before the crash, got %q", out.stdout.String())
+	}
+
+	// 下一个单元在新的解释器中运行
+	out = newCellOutput()
+
+	result, err = service.ExecuteCell(ctx, "sandbox-1", kernel.ID, "echo ${x:-unset}", out.write)
+	if err != nil {
+		t.Fatalf("ExecuteCell failed: %v", err)
+	}
+
+	if result.Status != CellOK || !result.Restarted || out.stdout.String() != "unset\n" {
+		t.Fatalf("Expected a restarted kernel with fresh state, got %+v and %q", result, out.stdout.String())
+	}
+}
+
+func TestKernel_Interrupt(t *testing.T) {
+	service := newKernelService(t)
+	ctx := context.Background()
+
+	kernel, err := service.CreateKernel(ctx, "sandbox-1", "bash")
+	if err != nil {
+		t.Fatalf("CreateKernel failed: %v", err)
+	}
+	defer func() {
+		_ = service.ShutdownKernel("sandbox-1", kernel.ID)
+	}()
+
+	if _, err := service.ExecuteCell(ctx, "sandbox-1", kernel.ID, "x=1", newCellOutput().write); err != nil {
+		t.Fatalf("ExecuteCell failed: %v", err)
+	}
+
+	out := newCellOutput()
+	done := make(chan *CellResult, 1)
+
+	go func() {
+		result, err := service.ExecuteCell(ctx, "sandbox-1", kernel.ID, "echo started; sleep 30", out.write)
+		if err != nil {
+			t.Errorf("ExecuteCell failed: %v", err)
+		}
+
+		done <- result
+	}()
+
+	<-out.started
+	// 等待 sleep 启动
+	time.Sleep(200 * time.Millisecond)
+
+	start := time.Now()
+
+	if err := service.InterruptKernel("sandbox-1", kernel.ID); err != nil {
+		t.Fatalf("InterruptKernel failed: %v", err)
+	}
+
+	result := <-done
+	if result == nil || result.Status != CellInterrupted {
+		t.Fatalf("Expected interrupted status, got %+v", result)
+	}
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Expected the cell to stop promptly, took %s", elapsed)
+	}
+
+	// 中断不影响内核状态
+	out = newCellOutput()
+
+	result, err = service.ExecuteCell(ctx, "sandbox-1", kernel.ID, "echo $x", out.write)
+	if err != nil {
+		t.Fatalf("ExecuteCell failed: %v", err)
+	}
+
+	if result.Status != CellOK || result.Restarted || out.stdout.String() != "1\n" {
+		t.Fatalf("Expected kernel state to survive the interrupt, got %+v and %q", result, out.stdout.String())
+	}
+}
+
+func TestKernel_CellTimeout(t *testing.T) {
+	service := newKernelService(t, WithCellTimeout(200*time.Millisecond))
+	ctx := context.Background()
+
+	kernel, err := service.CreateKernel(ctx, "sandbox-1", "bash")
+	if err != nil {
+		t.Fatalf("CreateKernel failed: %v", err)
+	}
+	defer func() {
+		_ = service.ShutdownKernel("sandbox-1", kernel.ID)
+	}()
+
+	result, err := service.ExecuteCell(ctx, "sandbox-1", kernel.ID, "sleep 30", newCellOutput().write)
+	if err != nil {
+		t.Fatalf("ExecuteCell failed: %v", err)
+	}
+
+	if result.Status != CellInterrupted {
+		t.Fatalf("Expected interrupted status, got %q", result.Status)
+	}
+}
+
+func TestKernel_Python(t *testing.T) {
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not available")
+	}
+
+	service := NewService(shellService.NewService(10, t.TempDir()), map[string]Language{
+		"python": {
+			Extension: ".py",
+			Repl:      []string{"python3", "-u", "-i", "-q", "-c", "import sys; sys.ps1 = sys.ps2 = ''"},
+			ReplRun: "__import__('sys').last_value = None\n" +
+				"exec(compile(open('{file}').read(), '{file}', 'exec'))\n" +
+				`print('\n{marker}', int(__import__('sys').last_value is not None)); print('\n{marker}', file=__import__('sys').stderr)`,
+		},
+	})
+	ctx := context.Background()
+
+	kernel, err := service.CreateKernel(ctx, "sandbox-1", "python")
+	if err != nil {
+		t.Fatalf("CreateKernel failed: %v", err)
+	}
+	defer func() {
+		_ = service.ShutdownKernel("sandbox-1", kernel.ID)
+	}()
+
+	if _, err := service.ExecuteCell(ctx, "sandbox-1", kernel.ID, "def f(n):\n    return n * 2\n\nx = f(21)\n", newCellOutput().write); err != nil {
+		t.Fatalf("ExecuteCell failed: %v", err)
+	}
+
+	out := newCellOutput()
+
+	result, err := service.ExecuteCell(ctx, "sandbox-1", kernel.ID, "print(x)", out.write)
+	if err != nil {
+		t.Fatalf("ExecuteCell failed: %v", err)
+	}
+
+	if result.Status != CellOK || out.stdout.String() != "42\n" {
+		t.Fatalf("Expected 42, got %+v and %q", result, out.stdout.String())
+	}
+
+	out = newCellOutput()
+
+	result, err = service.ExecuteCell(ctx, "sandbox-1", kernel.ID, "1 / 0", out.write)
+	if err != nil {
+		t.Fatalf("ExecuteCell failed: %v", err)
+	}
+
+	if result.Status != CellError || !strings.Contains(out.stderr.String(), "ZeroDivisionError") {
+		t.Fatalf("Expected ZeroDivisionError, got %+v and %q", result, out.stderr.String())
+	}
+}
+
+func TestKernel_Errors(t *testing.T) {
+	service := newKernelService(t)
+	ctx := context.Background()
+
+	_, err := service.CreateKernel(ctx, "sandbox-1", "sh")
+	if !errors.Is(err, ErrKernelUnsupported) {
+		t.Fatalf("Expected ErrKernelUnsupported, got %v", err)
+	}
+
+	_, err = service.CreateKernel(ctx, "sandbox-1", "cobol")
+	if !errors.Is(err, ErrUnsupportedLanguage) {
+		t.Fatalf("Expected ErrUnsupportedLanguage, got %v", err)
+	}
+
+	kernel, err := service.CreateKernel(ctx, "sandbox-1", "bash")
+	if err != nil {
+		t.Fatalf("CreateKernel failed: %v", err)
+	}
+
+	// 其他沙箱无法访问
+	if _, err := service.ExecuteCell(ctx, "sandbox-2", kernel.ID, "true", newCellOutput().write); !errors.Is(err, ErrKernelNotFound) {
+		t.Fatalf("Expected ErrKernelNotFound, got %v", err)
+	}
+
+	if err := service.ShutdownKernel("sandbox-1", kernel.ID); err != nil {
+		t.Fatalf("ShutdownKernel failed: %v", err)
+	}
+
+	if _, err := service.ExecuteCell(ctx, "sandbox-1", kernel.ID, "true", newCellOutput().write); !errors.Is(err, ErrKernelNotFound) {
+		t.Fatalf("Expected ErrKernelNotFound after shutdown, got %v", err)
+	}
+
+	if err := service.InterruptKernel("sandbox-1", kernel.ID); !errors.Is(err, ErrKernelNotFound) {
+		t.Fatalf("Expected ErrKernelNotFound, got %v", err)
+	}
+}
+
+func TestKernel_Limit(t *testing.T) {
+	service := newKernelService(t)
+	defer service.CloseSandbox("sandbox-1")
+
+	// 并发创建时启动中的内核同样计入上限
+	var (
+		wg              sync.WaitGroup
+		mu              sync.Mutex
+		created, denied int
+	)
+
+	for i := 0; i < 2*maxKernelsPerSandbox; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := service.CreateKernel(context.Background(), "sandbox-1", "bash")
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			switch {
+			case err == nil:
+				created++
+			case errors.Is(err, ErrTooManyKernels):
+				denied++
+			default:
+				t.Errorf("CreateKernel failed: %v", err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if created != maxKernelsPerSandbox || denied != maxKernelsPerSandbox {
+		t.Fatalf("Expected %d kernels created and %d denied, got %d and %d", maxKernelsPerSandbox, maxKernelsPerSandbox, created, denied)
+	}
+
+	// 启动失败时归还名额
+	service.languages["missing"] = Language{Repl: []string{"agent-sandbox-no-such-interpreter"}, ReplRun: bashKernel.ReplRun}
+
+	for i := 0; i <= maxKernelsPerSandbox; i++ {
+		if _, err := service.CreateKernel(context.Background(), "sandbox-2", "missing"); err == nil || errors.Is(err, ErrTooManyKernels) {
+			t.Fatalf("Expected CreateKernel to fail to start, got %v", err)
+		}
+	}
+
+	if len(service.starting) != 0 {
+		t.Fatalf("Expected no reserved kernels, got %v", service.starting)
+	}
+}
+
+func TestKernel_SecretSplitAcrossWrites(t *testing.T) {
+	store := secret.NewStore()
+	if err := store.Set("sandbox-1", map[string]string{"API_TOKEN": "tok-123456"}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	service := NewService(shellService.NewService(10, t.TempDir(), shellService.WithSecrets(store)), map[string]Language{"bash": bashKernel})
+	ctx := context.Background()
+
+	kernel, err := service.CreateKernel(ctx, "sandbox-1", "bash")
+	if err != nil {
+		t.Fatalf("CreateKernel failed: %v", err)
+	}
+	defer func() {
+		_ = service.ShutdownKernel("sandbox-1", kernel.ID)
+	}()
+
+	out := newCellOutput()
+
+	// 密钥值分两次写入，stderr 只输出密钥值的开头
+	cell := "printf 'token: %s' \"${API_TOKEN:0:6}\"; sleep 0.2; printf '%s end' \"${API_TOKEN:6}\"; printf \"${API_TOKEN:0:6}\" >&2"
+	if _, err := service.ExecuteCell(ctx, "sandbox-1", kernel.ID, cell, out.write); err != nil {
+		t.Fatalf("ExecuteCell failed: %v", err)
+	}
+
+	if out.stdout.String() != "token: [REDACTED:API_TOKEN] end" {
+		t.Fatalf("Expected split secret to be redacted, got %q", out.stdout.String())
+	}
+
+	// 输出结束时保留的字节不是完整的密钥值，原样输出
+	if out.stderr.String() != "tok-12" {
+		t.Fatalf("Expected held bytes to be flushed, got %q", out.stderr.String())
+	}
+}
+
+func TestKernel_CellRestrictions(t *testing.T) {
+	deny, err := policy.New([]policy.Rule{{Name: "no-rm", Action: policy.ActionDeny, Prefix: []string{"rm"}}}, policy.ActionAllow)
+	if err != nil {
+		t.Fatalf("Failed to create policy: %v", err)
+	}
+
+	shell := shellService.NewService(10, t.TempDir(),
+		shellService.WithPolicy(deny),
+		shellService.WithConcurrency(shellService.Concurrency{MaxPerSandbox: 1}),
+	)
+	service := NewService(shell, map[string]Language{"bash": bashKernel})
+	ctx := context.Background()
+
+	kernel, err := service.CreateKernel(ctx, "sandbox-1", "bash")
+	if err != nil {
+		t.Fatalf("CreateKernel failed: %v", err)
+	}
+	defer func() {
+		_ = service.ShutdownKernel("sandbox-1", kernel.ID)
+	}()
+
+	// shell 内核的单元按命令策略检查
+	if _, err := service.ExecuteCell(ctx, "sandbox-1", kernel.ID, "rm -rf data", newCellOutput().write); !errors.Is(err, shellService.ErrCommandDenied) {
+		t.Fatalf("Expected ErrCommandDenied, got %v", err)
+	}
+
+	// 单元占用并发名额，名额已满且不排队时被拒绝
+	release, _, err := shell.AcquireSlot(ctx, "sandbox-1")
+	if err != nil {
+		t.Fatalf("AcquireSlot failed: %v", err)
+	}
+
+	if _, err := service.ExecuteCell(ctx, "sandbox-1", kernel.ID, "echo hi", newCellOutput().write); !errors.Is(err, shellService.ErrQueueFull) {
+		t.Fatalf("Expected ErrQueueFull, got %v", err)
+	}
+
+	release()
+
+	out := newCellOutput()
+	if _, err := service.ExecuteCell(ctx, "sandbox-1", kernel.ID, "echo hi", out.write); err != nil || out.stdout.String() != "hi\n" {
+		t.Fatalf("Expected cell to run after the slot is released, got %q, %v", out.stdout.String(), err)
+	}
+}
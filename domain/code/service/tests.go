@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	shellService "github.com/HJH0924/agent-sandbox/domain/shell/service"
+	"github.com/HJH0924/agent-sandbox/internal/testreport"
+)
+
+// maxReportSize 读取的报告文件（测试报告、保存的完整输出）的最大字节数.
+const maxReportSize = 64 * 1024 * 1024
+
+// ErrUnknownTestRunner 没有配置该测试运行器.
+var ErrUnknownTestRunner = errors.New("unknown test runner")
+
+// TestRunner 测试运行器配置.
+type TestRunner struct {
+	// Command 运行测试的程序及参数，请求中的参数追加在最后
+	Command []string
+	// DefaultArgs 请求没有指定参数时追加的参数（如 ./...）
+	DefaultArgs []string
+	// Format 报告格式，对应 testreport 中注册的解析器（go、junit、tap）
+	Format string
+	// Report 运行器写入的报告文件（相对工作空间的路径），为空时解析 stdout
+	Report string
+}
+
+// WithTestRunners 设置 RunTests 可用的测试运行器，键为运行器名称.
+func WithTestRunners(runners map[string]TestRunner) Option {
+	return func(s *Service) {
+		s.testRunners = runners
+	}
+}
+
+// TestRunners 返回已配置的测试运行器名称，按名称排序.
+func (s *Service) TestRunners() []string {
+	names := make([]string, 0, len(s.testRunners))
+	for name := range s.testRunners {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// TestRequest 运行测试的请求.
+type TestRequest struct {
+	SandboxID string
+	Runner    string
+	// Args 追加在测试命令之后的参数，为空时使用运行器的 DefaultArgs
+	Args []string
+	// CallerKeyID 调用方 API Key 的标识，记录在执行历史中
+	CallerKeyID string
+}
+
+// TestResult 测试运行结果.
+type TestResult struct {
+	Report *testreport.Report
+	// ReportError 报告无法读取或解析的原因，此时 Report 中没有测试
+	ReportError string
+	ExitCode    int
+	// Stderr 运行器 stderr 保留的原始字节（如编译错误）
+	Stderr string
+	// Signal 导致进程结束的信号，正常退出时为空
+	Signal string
+	// TimedOut 表示测试因超时被终止，没有结束的测试记录为出错
+	TimedOut bool
+	// Truncated 表示输出被截断且没有保存完整输出，解析 stdout 的报告可能不完整
+	Truncated bool
+	// QueueWait 因并发限制排队等待的时间
+	QueueWait time.Duration
+	// RecordingID 输出的录制，未开启录制时为空
+	RecordingID string
+}
+
+// RunTests 在工作空间中运行测试命令并解析报告. 与 RunCode 一样通过 Shell 服务执行，
+// 测试失败（非零退出码）和超时通过结果返回而不是错误.
+func (s *Service) RunTests(ctx context.Context, req *TestRequest) (*TestResult, error) {
+	runner, ok := s.testRunners[req.Runner]
+	if !ok || len(runner.Command) == 0 {
+		return nil, fmt.Errorf("%w %q (configured: %s)", ErrUnknownTestRunner, req.Runner, strings.Join(s.TestRunners(), ", "))
+	}
+
+	parse, err := testreport.Lookup(runner.Format)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %w", ErrUnknownTestRunner, req.Runner, err)
+	}
+
+	dir, _, err := s.shell.Workspace(req.SandboxID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 删除上一次运行留下的报告，避免运行器没有写入报告时解析到旧的结果
+	var reportPath string
+
+	if runner.Report != "" {
+		if !filepath.IsLocal(runner.Report) {
+			return nil, fmt.Errorf("%w %q: report must be a path inside the workspace", ErrUnknownTestRunner, req.Runner)
+		}
+
+		reportPath = filepath.Join(dir, runner.Report)
+
+		if resolved, err := resolveInWorkspace(dir, reportPath); err == nil {
+			_ = os.Remove(resolved)
+		}
+	}
+
+	args := req.Args
+	if len(args) == 0 {
+		args = runner.DefaultArgs
+	}
+
+	result, err := s.shell.Execute(ctx, &shellService.ExecuteRequest{
+		SandboxID:   req.SandboxID,
+		Argv:        append(append([]string{}, runner.Command...), args...),
+		CallerKeyID: req.CallerKeyID,
+	})
+	// 没有结果说明测试没有运行（如被策略拒绝或程序不存在）
+	if result == nil {
+		return nil, err
+	}
+
+	tests := &TestResult{
+		ExitCode:    result.ExitCode,
+		Stderr:      result.Stderr,
+		Signal:      result.Signal,
+		TimedOut:    result.TimedOut,
+		QueueWait:   result.QueueWait,
+		RecordingID: result.RecordingID,
+	}
+
+	data, truncated, err := s.readOutput(req.SandboxID, dir, reportPath, result)
+	tests.Truncated = truncated
+
+	if err == nil {
+		tests.Report, err = parse(data)
+	}
+
+	if err != nil {
+		tests.Report = &testreport.Report{Cases: []testreport.Case{}}
+		tests.ReportError = err.Error()
+	}
+
+	return tests, nil
+}
+
+// readOutput 返回要解析的输出：命令写入的报告文件，或 stdout（reportPath 为空时）.
+// stdout 被截断时读取保存的完整输出，没有保存时返回截断后的输出并报告 truncated.
+// 报告文件和完整输出中的密钥值被替换.
+func (s *Service) readOutput(sandboxID, dir, reportPath string, result *shellService.ExecuteResult) ([]byte, bool, error) {
+	path := reportPath
+	if path == "" {
+		if !result.Truncated || result.StdoutFile == "" {
+			return []byte(result.Stdout), result.Truncated, nil
+		}
+
+		path = filepath.Join(dir, result.StdoutFile)
+	}
+
+	resolved, err := resolveInWorkspace(dir, path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, false, fmt.Errorf("report %s was not written", filepath.Base(path))
+		}
+
+		return nil, false, err
+	}
+
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to stat report: %w", err)
+	}
+
+	if !info.Mode().IsRegular() {
+		return nil, false, fmt.Errorf("report %s is not a regular file", filepath.Base(path))
+	}
+
+	if info.Size() > maxReportSize {
+		return nil, false, fmt.Errorf("report too large: %d bytes (max: %d)", info.Size(), maxReportSize)
+	}
+
+	data, err := os.ReadFile(resolved) // #nosec G304 -- resolved is checked to be inside the sandbox workspace
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read report: %w", err)
+	}
+
+	return []byte(s.shell.Redact(sandboxID, string(data))), false, nil
+}
+
+// resolveInWorkspace 解析路径中的符号链接，解析后位于工作空间之外时返回错误.
+// 报告由沙箱中的命令写入，可能是指向工作空间之外（如服务端文件）的符号链接.
+func resolveInWorkspace(dir, path string) (string, error) {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", err
+	}
+
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve workspace: %w", err)
+	}
+
+	if rel, err := filepath.Rel(root, resolved); err != nil || !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("report %s is outside the workspace", filepath.Base(path))
+	}
+
+	return resolved, nil
+}
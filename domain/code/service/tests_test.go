@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	shellService "github.com/HJH0924/agent-sandbox/domain/shell/service"
+	"github.com/HJH0924/agent-sandbox/internal/testreport"
+)
+
+const tapOutput = `TAP version 13
+ok 1 - adds
+not ok 2 - subtracts
+  ---
+  message: 'expected 1, got 2'
+  at: 'math.test.js:7:3'
+  ...
+ok 3 - divides # SKIP not implemented
+1..3
+`
+
+const junitReport = `<testsuites>
+  <testsuite name="tests.test_math">
+    <testcase classname="tests.test_math" name="test_add" time="0.01"/>
+    <testcase classname="tests.test_math" name="test_sub" time="0.02" file="tests/test_math.py" line="9">
+      <failure message="assert 1 == 2">E       assert 1 == 2</failure>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+
+func newTestsService(t *testing.T, workspace string) *Service {
+	t.Helper()
+
+	if err := os.WriteFile(filepath.Join(workspace, "tap.txt"), []byte(tapOutput), 0o600); err != nil {
+		t.Fatalf("Failed to create TAP output: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(workspace, "junit.xml"), []byte(junitReport), 0o600); err != nil {
+		t.Fatalf("Failed to create JUnit report: %v", err)
+	}
+
+	return NewService(shellService.NewService(10, workspace), testLanguages, WithTestRunners(map[string]TestRunner{
+		// 输出 TAP 并以测试失败的退出码结束
+		"tap": {Command: []string{"sh", "-c", `cat tap.txt; exit "${1:-1}"`, "tap"}, Format: "tap"},
+		// 将报告复制到 report 指定的位置
+		"junit": {
+			Command: []string{"sh", "-c", `mkdir -p out && cp junit.xml out/report.xml; exit 1`},
+			Format:  "junit",
+			Report:  "out/report.xml",
+		},
+		"noreport":  {Command: []string{"true"}, Format: "junit", Report: "out/none.xml"},
+		"escape":    {Command: []string{"true"}, Format: "junit", Report: "link.xml"},
+		"badformat": {Command: []string{"true"}, Format: "trx"},
+	}))
+}
+
+func TestCodeService_RunTests_Stdout(t *testing.T) {
+	service := newTestsService(t, t.TempDir())
+
+	result, err := service.RunTests(context.Background(), &TestRequest{Runner: "tap"})
+	if err != nil {
+		t.Fatalf("RunTests failed: %v", err)
+	}
+
+	// 测试失败通过退出码返回而不是错误
+	if result.ExitCode != 1 || result.ReportError != "" {
+		t.Fatalf("Expected exit code 1 and no report error, got %d and %q", result.ExitCode, result.ReportError)
+	}
+
+	summary := result.Report.Summary
+	if summary.Total != 3 || summary.Passed != 1 || summary.Failed != 1 || summary.Skipped != 1 {
+		t.Fatalf("Unexpected summary: %+v", summary)
+	}
+
+	failed := result.Report.Cases[1]
+	if failed.Status != testreport.StatusFailed || failed.Message != "expected 1, got 2" {
+		t.Fatalf("Unexpected failed test: %+v", failed)
+	}
+
+	if failed.File != "math.test.js" || failed.Line != 7 {
+		t.Fatalf("Expected failure location math.test.js:7, got %s:%d", failed.File, failed.Line)
+	}
+
+	// 请求中的参数替换默认参数
+	result, err = service.RunTests(context.Background(), &TestRequest{Runner: "tap", Args: []string{"0"}})
+	if err != nil {
+		t.Fatalf("RunTests failed: %v", err)
+	}
+
+	if result.ExitCode != 0 {
+		t.Fatalf("Expected exit code 0 with args, got %d", result.ExitCode)
+	}
+}
+
+func TestCodeService_RunTests_ReportFile(t *testing.T) {
+	workspace := t.TempDir()
+	service := newTestsService(t, workspace)
+
+	result, err := service.RunTests(context.Background(), &TestRequest{Runner: "junit"})
+	if err != nil {
+		t.Fatalf("RunTests failed: %v", err)
+	}
+
+	if result.ReportError != "" {
+		t.Fatalf("Unexpected report error: %s", result.ReportError)
+	}
+
+	if len(result.Report.Cases) != 2 || result.Report.Summary.Failed != 1 {
+		t.Fatalf("Expected 2 tests with 1 failure, got %+v", result.Report)
+	}
+
+	failed := result.Report.Cases[1]
+	if failed.Name != "test_sub" || failed.File != "tests/test_math.py" || failed.Line != 9 {
+		t.Fatalf("Unexpected failed test: %+v", failed)
+	}
+
+	// 运行器没有写入报告时不解析上一次的报告
+	if err := os.WriteFile(filepath.Join(workspace, "out", "none.xml"), []byte(junitReport), 0o600); err != nil {
+		t.Fatalf("Failed to create stale report: %v", err)
+	}
+
+	result, err = service.RunTests(context.Background(), &TestRequest{Runner: "noreport"})
+	if err != nil {
+		t.Fatalf("RunTests failed: %v", err)
+	}
+
+	if !strings.Contains(result.ReportError, "was not written") || len(result.Report.Cases) != 0 {
+		t.Fatalf("Expected missing report error, got %q and %d tests", result.ReportError, len(result.Report.Cases))
+	}
+}
+
+func TestCodeService_RunTests_ReportOutsideWorkspace(t *testing.T) {
+	workspace := t.TempDir()
+	service := newTestsService(t, workspace)
+
+	outside := filepath.Join(t.TempDir(), "secret.xml")
+	if err := os.WriteFile(outside, []byte(junitReport), 0o600); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	if err := os.Symlink(outside, filepath.Join(workspace, "link.xml")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	result, err := service.RunTests(context.Background(), &TestRequest{Runner: "escape"})
+	if err != nil {
+		t.Fatalf("RunTests failed: %v", err)
+	}
+
+	if !strings.Contains(result.ReportError, "outside the workspace") || len(result.Report.Cases) != 0 {
+		t.Fatalf("Expected report outside workspace to be rejected, got %q", result.ReportError)
+	}
+
+	// 符号链接的目标没有被删除
+	if _, err := os.Stat(outside); err != nil {
+		t.Fatalf("Expected file outside workspace to be kept: %v", err)
+	}
+}
+
+func TestCodeService_RunTests_Errors(t *testing.T) {
+	service := newTestsService(t, t.TempDir())
+
+	for _, runner := range []string{"pytest", "badformat"} {
+		_, err := service.RunTests(context.Background(), &TestRequest{Runner: runner})
+		if !errors.Is(err, ErrUnknownTestRunner) {
+			t.Fatalf("Expected ErrUnknownTestRunner for %s, got %v", runner, err)
+		}
+	}
+
+	if names := service.TestRunners(); len(names) != 5 || names[0] != "badformat" {
+		t.Fatalf("Expected sorted runner names, got %v", names)
+	}
+}
@@ -3,9 +3,14 @@ package core
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 
 	"github.com/HJH0924/agent-sandbox/domain/core/service"
+	"github.com/HJH0924/agent-sandbox/internal/middleware"
+	"github.com/HJH0924/agent-sandbox/internal/policy"
+	"github.com/HJH0924/agent-sandbox/internal/sandbox"
 	corev1 "github.com/HJH0924/agent-sandbox/sdk/go/core/v1"
 
 	"connectrpc.com/connect"
@@ -29,16 +34,35 @@ func NewHandler(coreService *service.Service, logger *slog.Logger) *Handler {
 // InitSandbox 初始化新沙箱并返回沙箱 ID 和 API 密钥.
 func (h *Handler) InitSandbox(
 	ctx context.Context,
-	_ *connect.Request[corev1.InitSandboxRequest],
+	req *connect.Request[corev1.InitSandboxRequest],
 ) (*connect.Response[corev1.InitSandboxResponse], error) {
-	h.logger.InfoContext(ctx, "initializing sandbox")
+	network, err := fromNetworkPolicy(req.Msg.GetNetwork())
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	commandPolicy, err := fromCommandPolicy(req.Msg.GetCommandPolicy())
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	h.logger.InfoContext(ctx, "initializing sandbox",
+		slog.String("network", string(network)),
+		slog.Int("command_rules", len(req.Msg.GetCommandPolicy().GetRules())))
 
 	// 调用 service 层初始化沙箱
-	result, err := h.coreService.InitSandbox()
+	result, err := h.coreService.InitSandbox(&service.InitSandboxRequest{
+		Network: network,
+		Policy:  commandPolicy,
+	})
 	if err != nil {
 		h.logger.ErrorContext(ctx, "failed to initialize sandbox",
 			slog.Any("error", err))
 
+		if errors.Is(err, service.ErrNetworkUnavailable) {
+			return nil, connect.NewError(connect.CodeFailedPrecondition, err)
+		}
+
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 
@@ -50,5 +74,124 @@ func (h *Handler) InitSandbox(
 		SandboxId: result.SandboxID,
 		ApiKey:    result.APIKey,
 		CreatedAt: timestamppb.New(result.CreatedAt),
+		Network:   toNetworkPolicy(result.Network),
 	}), nil
 }
+
+// GetSandbox 返回当前沙箱的信息.
+func (h *Handler) GetSandbox(
+	ctx context.Context,
+	_ *connect.Request[corev1.GetSandboxRequest],
+) (*connect.Response[corev1.GetSandboxResponse], error) {
+	sandboxID, _ := middleware.GetSandboxIDFromContext(ctx)
+
+	info, err := h.coreService.GetSandbox(sandboxID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeNotFound, err)
+	}
+
+	return connect.NewResponse(&corev1.GetSandboxResponse{
+		SandboxId: info.ID,
+		CreatedAt: timestamppb.New(info.CreatedAt),
+		Network:   toNetworkPolicy(info.Network),
+	}), nil
+}
+
+// DestroySandbox 销毁当前沙箱.
+func (h *Handler) DestroySandbox(
+	ctx context.Context,
+	_ *connect.Request[corev1.DestroySandboxRequest],
+) (*connect.Response[corev1.DestroySandboxResponse], error) {
+	sandboxID, _ := middleware.GetSandboxIDFromContext(ctx)
+
+	if err := h.coreService.DestroySandbox(sandboxID); err != nil {
+		h.logger.ErrorContext(ctx, "failed to destroy sandbox",
+			slog.Any("error", err))
+
+		if errors.Is(err, sandbox.ErrSandboxNotFound) {
+			return nil, connect.NewError(connect.CodeNotFound, err)
+		}
+
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	h.logger.InfoContext(ctx, "sandbox destroyed")
+
+	return connect.NewResponse(&corev1.DestroySandboxResponse{}), nil
+}
+
+// fromNetworkPolicy 将请求中的网络策略转换为沙箱网络策略，未指定时返回空字符串.
+func fromNetworkPolicy(policy corev1.NetworkPolicy) (sandbox.NetworkPolicy, error) {
+	switch policy {
+	case corev1.NetworkPolicy_NETWORK_POLICY_UNSPECIFIED:
+		return "", nil
+	case corev1.NetworkPolicy_NETWORK_POLICY_NONE:
+		return sandbox.NetworkNone, nil
+	case corev1.NetworkPolicy_NETWORK_POLICY_LOOPBACK:
+		return sandbox.NetworkLoopback, nil
+	case corev1.NetworkPolicy_NETWORK_POLICY_FULL:
+		return sandbox.NetworkFull, nil
+	default:
+		return "", fmt.Errorf("unknown network policy %d", policy)
+	}
+}
+
+// toNetworkPolicy 将沙箱网络策略转换为响应中的网络策略.
+func toNetworkPolicy(policy sandbox.NetworkPolicy) corev1.NetworkPolicy {
+	switch policy {
+	case sandbox.NetworkNone:
+		return corev1.NetworkPolicy_NETWORK_POLICY_NONE
+	case sandbox.NetworkLoopback:
+		return corev1.NetworkPolicy_NETWORK_POLICY_LOOPBACK
+	case sandbox.NetworkFull:
+		return corev1.NetworkPolicy_NETWORK_POLICY_FULL
+	default:
+		return corev1.NetworkPolicy_NETWORK_POLICY_UNSPECIFIED
+	}
+}
+
+// fromCommandPolicy 将请求中的命令策略编译为沙箱命令策略，未指定时返回 nil.
+func fromCommandPolicy(msg *corev1.CommandPolicy) (*policy.Policy, error) {
+	if msg == nil {
+		return nil, nil
+	}
+
+	defaultAction, err := fromCommandAction(msg.GetDefaultAction())
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]policy.Rule, 0, len(msg.GetRules()))
+
+	for _, r := range msg.GetRules() {
+		action, err := fromCommandAction(r.GetAction())
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, policy.Rule{
+			Name:    r.GetName(),
+			Action:  action,
+			Pattern: r.GetPattern(),
+			Prefix:  r.GetPrefix(),
+		})
+	}
+
+	return policy.New(rules, defaultAction)
+}
+
+// fromCommandAction 将请求中的处理方式转换为策略处理方式，未指定时返回空字符串.
+func fromCommandAction(action corev1.CommandAction) (policy.Action, error) {
+	switch action {
+	case corev1.CommandAction_COMMAND_ACTION_UNSPECIFIED:
+		return "", nil
+	case corev1.CommandAction_COMMAND_ACTION_ALLOW:
+		return policy.ActionAllow, nil
+	case corev1.CommandAction_COMMAND_ACTION_DENY:
+		return policy.ActionDeny, nil
+	case corev1.CommandAction_COMMAND_ACTION_APPROVE:
+		return policy.ActionApprove, nil
+	default:
+		return "", fmt.Errorf("unknown command action %d", action)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/HJH0924/agent-sandbox/domain/core/service"
+	"github.com/HJH0924/agent-sandbox/internal/middleware"
 	corev1 "github.com/HJH0924/agent-sandbox/sdk/go/core/v1"
 
 	"connectrpc.com/connect"
@@ -105,3 +106,99 @@ func TestHandler_InitSandbox_TimestampIsSet(t *testing.T) {
 	// 验证时间戳不是零值
 	assert.False(t, resp.Msg.GetCreatedAt().AsTime().IsZero())
 }
+
+func TestHandler_InitSandbox_Network(t *testing.T) {
+	coreService := service.NewService(service.NewMemoryAPIKeyStore())
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	handler := NewHandler(coreService, logger)
+
+	ctx := context.Background()
+
+	// 未指定时使用默认策略
+	resp, err := handler.InitSandbox(ctx, connect.NewRequest(&corev1.InitSandboxRequest{}))
+	assert.NoError(t, err)
+	assert.Equal(t, corev1.NetworkPolicy_NETWORK_POLICY_FULL, resp.Msg.GetNetwork())
+
+	// 非法的网络策略
+	_, err = handler.InitSandbox(ctx, connect.NewRequest(&corev1.InitSandboxRequest{
+		Network: corev1.NetworkPolicy(99),
+	}))
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+}
+
+func TestHandler_GetSandbox(t *testing.T) {
+	coreService := service.NewService(service.NewMemoryAPIKeyStore())
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	handler := NewHandler(coreService, logger)
+
+	initResp, err := handler.InitSandbox(context.Background(), connect.NewRequest(&corev1.InitSandboxRequest{}))
+	assert.NoError(t, err)
+
+	ctx := context.WithValue(context.Background(), middleware.SandboxIDKey, initResp.Msg.GetSandboxId())
+
+	resp, err := handler.GetSandbox(ctx, connect.NewRequest(&corev1.GetSandboxRequest{}))
+	assert.NoError(t, err)
+	assert.Equal(t, initResp.Msg.GetSandboxId(), resp.Msg.GetSandboxId())
+	assert.Equal(t, corev1.NetworkPolicy_NETWORK_POLICY_FULL, resp.Msg.GetNetwork())
+	assert.Equal(t, initResp.Msg.GetCreatedAt().AsTime(), resp.Msg.GetCreatedAt().AsTime())
+
+	// 未知沙箱
+	ctx = context.WithValue(context.Background(), middleware.SandboxIDKey, "unknown")
+
+	_, err = handler.GetSandbox(ctx, connect.NewRequest(&corev1.GetSandboxRequest{}))
+	assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+}
+
+func TestHandler_InitSandbox_CommandPolicy(t *testing.T) {
+	coreService := service.NewService(service.NewMemoryAPIKeyStore())
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	handler := NewHandler(coreService, logger)
+
+	ctx := context.Background()
+
+	resp, err := handler.InitSandbox(ctx, connect.NewRequest(&corev1.InitSandboxRequest{
+		CommandPolicy: &corev1.CommandPolicy{
+			Rules: []*corev1.CommandRule{
+				{Name: "echo", Action: corev1.CommandAction_COMMAND_ACTION_ALLOW, Prefix: []string{"echo"}},
+			},
+			DefaultAction: corev1.CommandAction_COMMAND_ACTION_DENY,
+		},
+	}))
+	assert.NoError(t, err)
+
+	info, err := coreService.GetSandbox(resp.Msg.GetSandboxId())
+	assert.NoError(t, err)
+	assert.Equal(t, "deny", string(info.Policy.Match("ls").Action))
+	assert.Equal(t, "echo", info.Policy.Match("echo hi").Rule)
+
+	// 非法的规则
+	for _, rule := range []*corev1.CommandRule{
+		{Name: "regex", Action: corev1.CommandAction_COMMAND_ACTION_DENY, Pattern: "("},
+		{Name: "action", Prefix: []string{"ls"}},
+		{Name: "empty", Action: corev1.CommandAction_COMMAND_ACTION_DENY},
+	} {
+		_, err = handler.InitSandbox(ctx, connect.NewRequest(&corev1.InitSandboxRequest{
+			CommandPolicy: &corev1.CommandPolicy{Rules: []*corev1.CommandRule{rule}},
+		}))
+		assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err), rule.GetName())
+	}
+}
+
+func TestHandler_DestroySandbox(t *testing.T) {
+	apiKeyStore := service.NewMemoryAPIKeyStore()
+	handler := NewHandler(service.NewService(apiKeyStore), slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
+	resp, err := handler.InitSandbox(context.Background(), connect.NewRequest(&corev1.InitSandboxRequest{}))
+	assert.NoError(t, err)
+
+	ctx := context.WithValue(context.Background(), middleware.SandboxIDKey, resp.Msg.GetSandboxId())
+
+	_, err = handler.DestroySandbox(ctx, connect.NewRequest(&corev1.DestroySandboxRequest{}))
+	assert.NoError(t, err)
+
+	_, ok := apiKeyStore.Verify(resp.Msg.GetApiKey())
+	assert.False(t, ok)
+
+	_, err = handler.DestroySandbox(ctx, connect.NewRequest(&corev1.DestroySandboxRequest{}))
+	assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+}
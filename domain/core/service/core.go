@@ -4,10 +4,14 @@ package service
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
 
+	"github.com/HJH0924/agent-sandbox/internal/policy"
+	"github.com/HJH0924/agent-sandbox/internal/sandbox"
+
 	"github.com/google/uuid"
 )
 
@@ -74,16 +78,70 @@ func (s *MemoryAPIKeyStore) Delete(sandboxID string) error {
 	return nil
 }
 
+// ErrNetworkUnavailable 无法为沙箱创建所请求的网络策略.
+var ErrNetworkUnavailable = errors.New("network policy unavailable")
+
 // Service 核心服务.
 type Service struct {
-	store APIKeyStore
+	store          APIKeyStore
+	users          *sandbox.Users
+	registry       *sandbox.Registry
+	defaultNetwork sandbox.NetworkPolicy
+	cleanups       []func(sandboxID string)
+}
+
+// Option 核心服务的可选配置.
+type Option func(*Service)
+
+// WithUsers 为每个新沙箱分配专属的非特权用户和工作空间.
+func WithUsers(users *sandbox.Users) Option {
+	return func(s *Service) {
+		s.users = users
+	}
+}
+
+// WithRegistry 使用共享的沙箱注册表，其他服务通过它获取沙箱的运行时信息.
+func WithRegistry(registry *sandbox.Registry) Option {
+	return func(s *Service) {
+		s.registry = registry
+	}
+}
+
+// WithDefaultNetwork 设置创建沙箱时未指定网络策略时使用的默认策略.
+func WithDefaultNetwork(policy sandbox.NetworkPolicy) Option {
+	return func(s *Service) {
+		s.defaultNetwork = policy
+	}
+}
+
+// WithCleanup 注册销毁沙箱时调用的清理函数，用于停止其他服务中属于该沙箱的会话、内核和定时任务等.
+func WithCleanup(cleanup func(sandboxID string)) Option {
+	return func(s *Service) {
+		s.cleanups = append(s.cleanups, cleanup)
+	}
 }
 
 // NewService 创建核心服务实例.
-func NewService(store APIKeyStore) *Service {
-	return &Service{
-		store: store,
+func NewService(store APIKeyStore, opts ...Option) *Service {
+	s := &Service{
+		store:          store,
+		registry:       sandbox.NewRegistry(),
+		defaultNetwork: sandbox.NetworkFull,
 	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// InitSandboxRequest 沙箱初始化请求.
+type InitSandboxRequest struct {
+	// Network 网络策略，为空时使用默认策略
+	Network sandbox.NetworkPolicy
+	// Policy 沙箱的命令策略，为空时只使用服务端策略
+	Policy *policy.Policy
 }
 
 // InitSandboxResult 沙箱初始化结果.
@@ -91,10 +149,16 @@ type InitSandboxResult struct {
 	SandboxID string
 	APIKey    string
 	CreatedAt time.Time
+	Network   sandbox.NetworkPolicy
 }
 
 // InitSandbox 初始化沙箱，生成沙箱 ID 和 API 密钥.
-func (s *Service) InitSandbox() (*InitSandboxResult, error) {
+func (s *Service) InitSandbox(req *InitSandboxRequest) (result *InitSandboxResult, err error) {
+	network := req.Network
+	if network == "" {
+		network = s.defaultNetwork
+	}
+
 	// 生成沙箱 ID
 	sandboxID := uuid.New().String()
 
@@ -106,14 +170,91 @@ func (s *Service) InitSandbox() (*InitSandboxResult, error) {
 
 	apiKey := "sk_" + hex.EncodeToString(apiKeyBytes)
 
+	// 初始化失败时释放已分配的资源
+	var cleanups []func()
+
+	defer func() {
+		if err != nil {
+			for i := len(cleanups) - 1; i >= 0; i-- {
+				cleanups[i]()
+			}
+		}
+	}()
+
+	// 分配沙箱用户
+	if s.users != nil {
+		if _, err := s.users.Create(sandboxID); err != nil {
+			return nil, fmt.Errorf("failed to create sandbox user: %w", err)
+		}
+
+		cleanups = append(cleanups, func() { _ = s.users.Release(sandboxID) })
+	}
+
+	// 创建网络命名空间
+	var netns *sandbox.NetNS
+
+	if network != sandbox.NetworkFull {
+		netns, err = sandbox.NewNetNS(network == sandbox.NetworkLoopback)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrNetworkUnavailable, network, err)
+		}
+
+		cleanups = append(cleanups, func() { _ = netns.Close() })
+	}
+
 	// 存储 API 密钥
 	if err := s.store.Store(sandboxID, apiKey); err != nil {
 		return nil, fmt.Errorf("failed to store api key: %w", err)
 	}
 
+	createdAt := time.Now()
+
+	s.registry.Add(&sandbox.Sandbox{
+		ID:        sandboxID,
+		CreatedAt: createdAt,
+		Network:   network,
+		NetNS:     netns,
+		Policy:    req.Policy,
+	})
+
 	return &InitSandboxResult{
 		SandboxID: sandboxID,
 		APIKey:    apiKey,
-		CreatedAt: time.Now(),
+		CreatedAt: createdAt,
+		Network:   network,
 	}, nil
 }
+
+// GetSandbox 获取沙箱信息.
+func (s *Service) GetSandbox(sandboxID string) (*sandbox.Sandbox, error) {
+	return s.registry.Get(sandboxID)
+}
+
+// DestroySandbox 销毁沙箱：使其 API Key 失效，调用注册的清理函数，释放网络命名空间和沙箱用户.
+// 释放用户前结束该用户的所有进程，并将工作空间改回服务进程所有；工作空间中的文件不会被删除.
+func (s *Service) DestroySandbox(sandboxID string) error {
+	if _, err := s.registry.Get(sandboxID); err != nil {
+		return err
+	}
+
+	// 先删除 API Key，阻止新的请求
+	if err := s.store.Delete(sandboxID); err != nil {
+		return fmt.Errorf("failed to delete api key: %w", err)
+	}
+
+	for _, cleanup := range s.cleanups {
+		cleanup(sandboxID)
+	}
+
+	if err := s.registry.Remove(sandboxID); err != nil {
+		return fmt.Errorf("failed to remove sandbox: %w", err)
+	}
+
+	if s.users != nil {
+		if err := s.users.Release(sandboxID); err != nil {
+			return fmt.Errorf("failed to release sandbox user: %w", err)
+		}
+	}
+
+	return nil
+}
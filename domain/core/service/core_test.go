@@ -1,7 +1,11 @@
 package service
 
 import (
+	"errors"
+	"os"
 	"testing"
+
+	"github.com/HJH0924/agent-sandbox/internal/sandbox"
 )
 
 func TestMemoryAPIKeyStore(t *testing.T) {
@@ -44,7 +48,7 @@ func TestInitSandbox(t *testing.T) {
 	service := NewService(store)
 
 	// Initialize sandbox
-	result, err := service.InitSandbox()
+	result, err := service.InitSandbox(&InitSandboxRequest{})
 	if err != nil {
 		t.Fatalf("Failed to initialize sandbox: %v", err)
 	}
@@ -73,3 +77,111 @@ func TestInitSandbox(t *testing.T) {
 		t.Fatalf("Expected sandbox ID %s, got %s", result.SandboxID, retrievedID)
 	}
 }
+
+func TestInitSandbox_WithUsers(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("changing file ownership requires root")
+	}
+
+	users := sandbox.NewUsers(t.TempDir(), 20000, 1)
+	service := NewService(NewMemoryAPIKeyStore(), WithUsers(users))
+
+	result, err := service.InitSandbox(&InitSandboxRequest{})
+	if err != nil {
+		t.Fatalf("Failed to initialize sandbox: %v", err)
+	}
+
+	user, err := users.Lookup(result.SandboxID)
+	if err != nil {
+		t.Fatalf("Sandbox user should be allocated: %v", err)
+	}
+
+	if _, err := os.Stat(user.Workspace); err != nil {
+		t.Fatalf("Sandbox workspace should exist: %v", err)
+	}
+
+	// UID 范围已用尽时初始化失败
+	if _, err := service.InitSandbox(&InitSandboxRequest{}); !errors.Is(err, sandbox.ErrNoFreeUser) {
+		t.Fatalf("Expected ErrNoFreeUser, got %v", err)
+	}
+}
+
+func TestInitSandbox_Network(t *testing.T) {
+	service := NewService(NewMemoryAPIKeyStore(), WithDefaultNetwork(sandbox.NetworkLoopback))
+
+	result, err := service.InitSandbox(&InitSandboxRequest{Network: sandbox.NetworkFull})
+	if err != nil {
+		t.Fatalf("Failed to initialize sandbox: %v", err)
+	}
+
+	info, err := service.GetSandbox(result.SandboxID)
+	if err != nil {
+		t.Fatalf("Failed to get sandbox: %v", err)
+	}
+
+	if info.Network != sandbox.NetworkFull || info.NetNS != nil {
+		t.Fatalf("Expected full network without namespace, got %+v", info)
+	}
+
+	// 未指定网络策略时使用默认策略
+	result, err = service.InitSandbox(&InitSandboxRequest{})
+	if errors.Is(err, ErrNetworkUnavailable) {
+		t.Skipf("network namespaces not available: %v", err)
+	}
+
+	if err != nil {
+		t.Fatalf("Failed to initialize sandbox: %v", err)
+	}
+
+	if result.Network != sandbox.NetworkLoopback {
+		t.Fatalf("Expected default network %s, got %s", sandbox.NetworkLoopback, result.Network)
+	}
+
+	info, err = service.GetSandbox(result.SandboxID)
+	if err != nil {
+		t.Fatalf("Failed to get sandbox: %v", err)
+	}
+
+	if info.NetNS == nil {
+		t.Fatal("Sandbox should have a network namespace")
+	}
+
+	if _, err := service.GetSandbox("unknown"); !errors.Is(err, sandbox.ErrSandboxNotFound) {
+		t.Fatalf("Expected ErrSandboxNotFound, got %v", err)
+	}
+}
+
+func TestDestroySandbox(t *testing.T) {
+	store := NewMemoryAPIKeyStore()
+
+	var cleaned []string
+
+	service := NewService(store, WithCleanup(func(sandboxID string) {
+		cleaned = append(cleaned, sandboxID)
+	}))
+
+	result, err := service.InitSandbox(&InitSandboxRequest{})
+	if err != nil {
+		t.Fatalf("Failed to initialize sandbox: %v", err)
+	}
+
+	if err := service.DestroySandbox(result.SandboxID); err != nil {
+		t.Fatalf("DestroySandbox failed: %v", err)
+	}
+
+	if _, ok := store.Verify(result.APIKey); ok {
+		t.Fatal("API key should have been deleted")
+	}
+
+	if len(cleaned) != 1 || cleaned[0] != result.SandboxID {
+		t.Fatalf("Expected cleanup for %s, got %v", result.SandboxID, cleaned)
+	}
+
+	if _, err := service.GetSandbox(result.SandboxID); !errors.Is(err, sandbox.ErrSandboxNotFound) {
+		t.Fatalf("Expected ErrSandboxNotFound, got %v", err)
+	}
+
+	if err := service.DestroySandbox(result.SandboxID); !errors.Is(err, sandbox.ErrSandboxNotFound) {
+		t.Fatalf("Expected ErrSandboxNotFound on second destroy, got %v", err)
+	}
+}
@@ -3,9 +3,11 @@ package file
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 
 	"github.com/HJH0924/agent-sandbox/domain/file/service"
+	"github.com/HJH0924/agent-sandbox/internal/middleware"
 	filev1 "github.com/HJH0924/agent-sandbox/sdk/go/file/v1"
 
 	"connectrpc.com/connect"
@@ -31,18 +33,19 @@ func (h *Handler) Read(
 	req *connect.Request[filev1.ReadRequest],
 ) (*connect.Response[filev1.ReadResponse], error) {
 	path := req.Msg.GetPath()
+	sandboxID, _ := middleware.GetSandboxIDFromContext(ctx)
 
 	h.logger.InfoContext(ctx, "reading file",
 		slog.String("path", path))
 
 	// 调用 service 层读取文件
-	result, err := h.fileService.Read(path)
+	result, err := h.fileService.Read(sandboxID, path)
 	if err != nil {
 		h.logger.ErrorContext(ctx, "failed to read file",
 			slog.String("path", path),
 			slog.Any("error", err))
 
-		return nil, connect.NewError(connect.CodeInternal, err)
+		return nil, connect.NewError(errorCode(err), err)
 	}
 
 	h.logger.InfoContext(ctx, "file read successfully",
@@ -61,6 +64,7 @@ func (h *Handler) Write(
 	req *connect.Request[filev1.WriteRequest],
 ) (*connect.Response[filev1.WriteResponse], error) {
 	path := req.Msg.GetPath()
+	sandboxID, _ := middleware.GetSandboxIDFromContext(ctx)
 	content := req.Msg.GetContent()
 
 	h.logger.InfoContext(ctx, "writing file",
@@ -68,12 +72,12 @@ func (h *Handler) Write(
 		slog.Int("content_length", len(content)))
 
 	// 调用 service 层写入文件
-	if err := h.fileService.Write(path, content); err != nil {
+	if err := h.fileService.Write(sandboxID, path, content); err != nil {
 		h.logger.ErrorContext(ctx, "failed to write file",
 			slog.String("path", path),
 			slog.Any("error", err))
 
-		return nil, connect.NewError(connect.CodeInternal, err)
+		return nil, connect.NewError(errorCode(err), err)
 	}
 
 	h.logger.InfoContext(ctx, "file written successfully",
@@ -89,6 +93,7 @@ func (h *Handler) Edit(
 	req *connect.Request[filev1.EditRequest],
 ) (*connect.Response[filev1.EditResponse], error) {
 	path := req.Msg.GetPath()
+	sandboxID, _ := middleware.GetSandboxIDFromContext(ctx)
 	content := req.Msg.GetContent()
 
 	h.logger.InfoContext(ctx, "editing file",
@@ -96,13 +101,13 @@ func (h *Handler) Edit(
 		slog.Int("content_length", len(content)))
 
 	// 调用 service 层编辑文件
-	result, err := h.fileService.Edit(path, content)
+	result, err := h.fileService.Edit(sandboxID, path, content)
 	if err != nil {
 		h.logger.ErrorContext(ctx, "failed to edit file",
 			slog.String("path", path),
 			slog.Any("error", err))
 
-		return nil, connect.NewError(connect.CodeInternal, err)
+		return nil, connect.NewError(errorCode(err), err)
 	}
 
 	h.logger.InfoContext(ctx, "file edited successfully",
@@ -114,3 +119,12 @@ func (h *Handler) Edit(
 		Content: result.Content,
 	}), nil
 }
+
+// errorCode 将文件服务错误映射为 RPC 错误码.
+func errorCode(err error) connect.Code {
+	if errors.Is(err, service.ErrOutsideWorkspace) {
+		return connect.CodePermissionDenied
+	}
+
+	return connect.CodeInternal
+}
@@ -2,23 +2,138 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+
+	"github.com/HJH0924/agent-sandbox/internal/sandbox"
+	"github.com/HJH0924/agent-sandbox/internal/secret"
 )
 
+// ErrOutsideWorkspace 路径位于沙箱工作空间之外.
+var ErrOutsideWorkspace = errors.New("path is outside the workspace")
+
 // Service 文件服务.
 type Service struct {
 	maxFileSize  int64
 	workspaceDir string
+	users        *sandbox.Users
+	secrets      *secret.Store
+}
+
+// Option 文件服务的可选配置.
+type Option func(*Service)
+
+// WithUsers 将每个沙箱的文件操作限制在其专属工作空间内，新建的文件归属沙箱用户.
+func WithUsers(users *sandbox.Users) Option {
+	return func(s *Service) {
+		s.users = users
+	}
+}
+
+// WithSecrets 从读取和编辑后返回的文件内容中替换沙箱的密钥值.
+func WithSecrets(store *secret.Store) Option {
+	return func(s *Service) {
+		s.secrets = store
+	}
 }
 
 // NewService 创建文件服务实例.
-func NewService(maxFileSize int64, workspaceDir string) *Service {
-	return &Service{
+func NewService(maxFileSize int64, workspaceDir string, opts ...Option) *Service {
+	s := &Service{
 		maxFileSize:  maxFileSize,
 		workspaceDir: workspaceDir,
 	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// resolve 返回路径在沙箱工作空间中的完整路径，以及启用沙箱用户时该沙箱的用户.
+func (s *Service) resolve(sandboxID, path string) (string, *sandbox.User, error) {
+	root := s.workspaceDir
+
+	var user *sandbox.User
+
+	if s.users != nil {
+		u, err := s.users.Lookup(sandboxID)
+		if err != nil {
+			return "", nil, err
+		}
+
+		root, user = u.Workspace, u
+	}
+
+	fullPath := filepath.Join(root, path)
+
+	// 拒绝通过 ".." 或符号链接访问工作空间之外（包括其他沙箱）的文件
+	if !within(root, fullPath) || !within(realPath(root), realPath(fullPath)) {
+		return "", nil, fmt.Errorf("%w: %s", ErrOutsideWorkspace, path)
+	}
+
+	return fullPath, user, nil
+}
+
+// within 判断 path 是否位于 root 之内.
+func within(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// realPath 解析路径中已存在部分的符号链接，不存在的部分原样拼接.
+func realPath(path string) string {
+	var rest []string
+
+	for p := path; ; p = filepath.Dir(p) {
+		if resolved, err := filepath.EvalSymlinks(p); err == nil {
+			return filepath.Join(append([]string{resolved}, rest...)...)
+		}
+
+		if filepath.Dir(p) == p {
+			return path
+		}
+
+		rest = append([]string{filepath.Base(p)}, rest...)
+	}
+}
+
+// mkdirAll 创建目录，新建的目录归属沙箱用户.
+func mkdirAll(dir string, user *sandbox.User) error {
+	// 记录需要新建的目录，MkdirAll 之后逐一修改所有者
+	var created []string
+
+	for p := dir; ; p = filepath.Dir(p) {
+		if _, err := os.Lstat(p); err == nil || filepath.Dir(p) == p {
+			break
+		}
+
+		created = append(created, p)
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create directories: %w", err)
+	}
+
+	if user == nil {
+		return nil
+	}
+
+	for _, p := range created {
+		if err := user.Chown(p); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // ReadResult 读取结果.
@@ -26,10 +141,13 @@ type ReadResult struct {
 	Content string
 }
 
-// Read 读取文件.
-func (s *Service) Read(path string) (*ReadResult, error) {
+// Read 读取沙箱中的文件，内容中的密钥值被替换.
+func (s *Service) Read(sandboxID, path string) (*ReadResult, error) {
 	// 确保路径在工作目录下
-	fullPath := filepath.Join(s.workspaceDir, path)
+	fullPath, _, err := s.resolve(sandboxID, path)
+	if err != nil {
+		return nil, err
+	}
 
 	// 检查文件是否存在
 	info, err := os.Stat(fullPath)
@@ -53,14 +171,17 @@ func (s *Service) Read(path string) (*ReadResult, error) {
 	}
 
 	return &ReadResult{
-		Content: string(content),
+		Content: s.secrets.Redact(sandboxID, string(content)),
 	}, nil
 }
 
-// Write 写入文件.
-func (s *Service) Write(path, content string) error {
+// Write 写入沙箱中的文件.
+func (s *Service) Write(sandboxID, path, content string) error {
 	// 确保路径在工作目录下
-	fullPath := filepath.Join(s.workspaceDir, path)
+	fullPath, user, err := s.resolve(sandboxID, path)
+	if err != nil {
+		return err
+	}
 
 	// 检查内容大小
 	contentSize := int64(len(content))
@@ -69,9 +190,8 @@ func (s *Service) Write(path, content string) error {
 	}
 
 	// 创建目录
-	dir := filepath.Dir(fullPath)
-	if err := os.MkdirAll(dir, 0o750); err != nil {
-		return fmt.Errorf("failed to create directories: %w", err)
+	if err := mkdirAll(filepath.Dir(fullPath), user); err != nil {
+		return err
 	}
 
 	// 写入文件
@@ -79,6 +199,10 @@ func (s *Service) Write(path, content string) error {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 
+	if user != nil {
+		return user.Chown(fullPath)
+	}
+
 	return nil
 }
 
@@ -88,10 +212,13 @@ type EditResult struct {
 	Content string
 }
 
-// Edit 编辑文件（直接覆盖内容）.
-func (s *Service) Edit(path, content string) (*EditResult, error) {
+// Edit 编辑沙箱中的文件（直接覆盖内容）.
+func (s *Service) Edit(sandboxID, path, content string) (*EditResult, error) {
 	// 确保路径在工作目录下
-	fullPath := filepath.Join(s.workspaceDir, path)
+	fullPath, _, err := s.resolve(sandboxID, path)
+	if err != nil {
+		return nil, err
+	}
 
 	// 检查内容大小
 	contentSize := int64(len(content))
@@ -115,6 +242,6 @@ func (s *Service) Edit(path, content string) (*EditResult, error) {
 
 	return &EditResult{
 		Path:    path,
-		Content: content,
+		Content: s.secrets.Redact(sandboxID, content),
 	}, nil
 }
@@ -1,9 +1,14 @@
 package service
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"syscall"
 	"testing"
+
+	"github.com/HJH0924/agent-sandbox/internal/sandbox"
+	"github.com/HJH0924/agent-sandbox/internal/secret"
 )
 
 func TestFileService(t *testing.T) {
@@ -26,7 +31,7 @@ func TestFileService(t *testing.T) {
 	testPath := "test/example.txt"
 	testContent := "Hello, World!"
 
-	err = service.Write(testPath, testContent)
+	err = service.Write("", testPath, testContent)
 	if err != nil {
 		t.Fatalf("Failed to write file: %v", err)
 	}
@@ -38,7 +43,7 @@ func TestFileService(t *testing.T) {
 	}
 
 	// Test Read
-	result, err := service.Read(testPath)
+	result, err := service.Read("", testPath)
 	if err != nil {
 		t.Fatalf("Failed to read file: %v", err)
 	}
@@ -50,7 +55,7 @@ func TestFileService(t *testing.T) {
 	// Test Edit
 	newContent := "Hello, Updated World!"
 
-	editResult, err := service.Edit(testPath, newContent)
+	editResult, err := service.Edit("", testPath, newContent)
 	if err != nil {
 		t.Fatalf("Failed to edit file: %v", err)
 	}
@@ -60,7 +65,7 @@ func TestFileService(t *testing.T) {
 	}
 
 	// Verify edited content
-	result, err = service.Read(testPath)
+	result, err = service.Read("", testPath)
 	if err != nil {
 		t.Fatalf("Failed to read file after edit: %v", err)
 	}
@@ -85,7 +90,7 @@ func TestFileService_ReadNonExistent(t *testing.T) {
 	service := NewService(1024*1024, tmpDir)
 
 	// Try to read non-existent file
-	_, err = service.Read("nonexistent.txt")
+	_, err = service.Read("", "nonexistent.txt")
 	if err == nil {
 		t.Fatal("Expected error when reading non-existent file")
 	}
@@ -109,8 +114,112 @@ func TestFileService_TooLarge(t *testing.T) {
 	// Try to write content larger than max size
 	largeContent := string(make([]byte, 200))
 
-	err = service.Write("large.txt", largeContent)
+	err = service.Write("", "large.txt", largeContent)
 	if err == nil {
 		t.Fatal("Expected error when writing content larger than max size")
 	}
 }
+
+func TestFileService_OutsideWorkspace(t *testing.T) {
+	root := t.TempDir()
+	workspace := filepath.Join(root, "workspace")
+
+	if err := os.MkdirAll(workspace, 0o750); err != nil {
+		t.Fatalf("Failed to create workspace: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, "secret.txt"), []byte("secret"), 0o600); err != nil {
+		t.Fatalf("Failed to write secret: %v", err)
+	}
+
+	if err := os.Symlink(root, filepath.Join(workspace, "escape")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	service := NewService(1024, workspace)
+
+	for _, path := range []string{"../secret.txt", "escape/secret.txt", "escape/new.txt"} {
+		if _, err := service.Read("", path); !errors.Is(err, ErrOutsideWorkspace) {
+			t.Fatalf("Expected ErrOutsideWorkspace reading %s, got %v", path, err)
+		}
+
+		if err := service.Write("", path, "x"); !errors.Is(err, ErrOutsideWorkspace) {
+			t.Fatalf("Expected ErrOutsideWorkspace writing %s, got %v", path, err)
+		}
+	}
+}
+
+func TestFileService_SandboxUsers(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("changing file ownership requires root")
+	}
+
+	users := sandbox.NewUsers(t.TempDir(), 20000, 10)
+	service := NewService(1024, "", WithUsers(users))
+
+	user, err := users.Create("sandbox-1")
+	if err != nil {
+		t.Fatalf("Failed to create sandbox user: %v", err)
+	}
+
+	if err := service.Write("sandbox-1", "dir/file.txt", "hello"); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	// 新建的目录和文件归属沙箱用户
+	for _, path := range []string{"dir", "dir/file.txt"} {
+		info, err := os.Stat(filepath.Join(user.Workspace, path))
+		if err != nil {
+			t.Fatalf("Failed to stat %s: %v", path, err)
+		}
+
+		stat, ok := info.Sys().(*syscall.Stat_t)
+		if !ok || stat.Uid != user.UID || stat.Gid != user.GID {
+			t.Fatalf("Expected %s owned by %d, got %+v", path, user.UID, info.Sys())
+		}
+	}
+
+	// 其他沙箱无法访问
+	if _, err := users.Create("sandbox-2"); err != nil {
+		t.Fatalf("Failed to create sandbox user: %v", err)
+	}
+
+	if _, err := service.Read("sandbox-2", "../sandbox-1/dir/file.txt"); !errors.Is(err, ErrOutsideWorkspace) {
+		t.Fatalf("Expected ErrOutsideWorkspace, got %v", err)
+	}
+
+	if _, err := service.Read("unknown", "dir/file.txt"); !errors.Is(err, sandbox.ErrUserNotFound) {
+		t.Fatalf("Expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestFileService_Secrets(t *testing.T) {
+	store := secret.NewStore()
+	if err := store.Set("sandbox-1", map[string]string{"DB_PASSWORD": "hunter22"}); err != nil {
+		t.Fatalf("Failed to set secret: %v", err)
+	}
+
+	service := NewService(1024, t.TempDir(), WithSecrets(store))
+
+	if err := service.Write("sandbox-1", ".env", "DB_PASSWORD=hunter22\n"); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	result, err := service.Read("sandbox-1", ".env")
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+
+	if result.Content != "DB_PASSWORD=[REDACTED:DB_PASSWORD]\n" {
+		t.Errorf("Expected redacted content, got %q", result.Content)
+	}
+
+	edited, err := service.Edit("sandbox-1", ".env", "DB_PASSWORD=hunter22\nDEBUG=1\n")
+	if err != nil {
+		t.Fatalf("Failed to edit file: %v", err)
+	}
+
+	if edited.Content != "DB_PASSWORD=[REDACTED:DB_PASSWORD]\nDEBUG=1\n" {
+		t.Errorf("Expected redacted content, got %q", edited.Content)
+	}
+}
@@ -0,0 +1,184 @@
+// Package preview provides handlers for reaching HTTP and TCP services running inside a sandbox.
+package preview
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/HJH0924/agent-sandbox/domain/preview/service"
+	"github.com/HJH0924/agent-sandbox/internal/middleware"
+	previewv1 "github.com/HJH0924/agent-sandbox/sdk/go/preview/v1"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Handler 预览服务处理器.
+type Handler struct {
+	previewService *service.Service
+	logger         *slog.Logger
+}
+
+// NewHandler 创建预览服务处理器.
+func NewHandler(previewService *service.Service, logger *slog.Logger) *Handler {
+	return &Handler{
+		previewService: previewService,
+		logger:         logger,
+	}
+}
+
+// CreatePreviewURL 生成带短期令牌的预览路径.
+func (h *Handler) CreatePreviewURL(
+	ctx context.Context,
+	req *connect.Request[previewv1.CreatePreviewURLRequest],
+) (*connect.Response[previewv1.CreatePreviewURLResponse], error) {
+	sandboxID, _ := middleware.GetSandboxIDFromContext(ctx)
+	port := int(req.Msg.GetPort())
+
+	token, expiresAt, err := h.previewService.CreateToken(sandboxID, port, time.Duration(req.Msg.GetTtlSeconds())*time.Second)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidPort) {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	h.logger.InfoContext(ctx, "preview token created",
+		slog.Int("port", port),
+		slog.Time("expires_at", expiresAt))
+
+	return connect.NewResponse(&previewv1.CreatePreviewURLResponse{
+		Path:      fmt.Sprintf("%s?%s=%s", PortPath(sandboxID, port), TokenParam, url.QueryEscape(token)),
+		Token:     token,
+		ExpiresAt: timestamppb.New(expiresAt),
+	}), nil
+}
+
+// portForwardBufferSize 每条 data 消息携带的最大字节数.
+const portForwardBufferSize = 32 * 1024
+
+// PortForward 将流转发到沙箱端口的 TCP 连接.
+func (h *Handler) PortForward(
+	ctx context.Context,
+	stream *connect.BidiStream[previewv1.PortForwardRequest, previewv1.PortForwardResponse],
+) error {
+	sandboxID, _ := middleware.GetSandboxIDFromContext(ctx)
+
+	// 第一条消息必须是 start
+	first, err := stream.Receive()
+	if err != nil {
+		return err
+	}
+
+	start := first.GetStart()
+	if start == nil {
+		return connect.NewError(connect.CodeInvalidArgument, errors.New("first port forward message must be start"))
+	}
+
+	port := int(start.GetPort())
+
+	conn, err := h.previewService.Dial(ctx, sandboxID, port)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidPort) {
+			return connect.NewError(connect.CodeInvalidArgument, err)
+		}
+
+		return connect.NewError(connect.CodeUnavailable, err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	h.logger.InfoContext(ctx, "port forward started", slog.Int("port", port))
+
+	if err := stream.Send(&previewv1.PortForwardResponse{
+		Event: &previewv1.PortForwardResponse_Connected{Connected: &previewv1.PortForwardConnected{}},
+	}); err != nil {
+		return err
+	}
+
+	// 在独立 goroutine 中将客户端数据写入沙箱端口，出错时关闭连接以结束读取
+	recvErr := make(chan error, 1)
+
+	go func() {
+		err := forwardToPort(stream, conn)
+
+		recvErr <- err
+		if err != nil {
+			_ = conn.Close()
+		}
+	}()
+
+	sendErr := forwardFromPort(stream, conn)
+
+	h.logger.InfoContext(ctx, "port forward finished", slog.Int("port", port))
+
+	select {
+	case err := <-recvErr:
+		if err != nil {
+			return err
+		}
+	default:
+	}
+
+	return sendErr
+}
+
+// forwardToPort 将客户端数据写入沙箱端口，客户端关闭发送方向时关闭连接的写方向.
+func forwardToPort(
+	stream *connect.BidiStream[previewv1.PortForwardRequest, previewv1.PortForwardResponse],
+	conn net.Conn,
+) error {
+	for {
+		msg, err := stream.Receive()
+		if errors.Is(err, io.EOF) {
+			if tcp, ok := conn.(*net.TCPConn); ok {
+				_ = tcp.CloseWrite()
+			}
+
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if _, err := conn.Write(msg.GetData()); err != nil {
+			return connect.NewError(connect.CodeUnavailable, err)
+		}
+	}
+}
+
+// forwardFromPort 将沙箱端口的数据发送给客户端，直到沙箱端关闭连接.
+func forwardFromPort(
+	stream *connect.BidiStream[previewv1.PortForwardRequest, previewv1.PortForwardResponse],
+	conn net.Conn,
+) error {
+	buf := make([]byte, portForwardBufferSize)
+
+	for {
+		n, err := conn.Read(buf)
+		if n > 0 {
+			if err := stream.Send(&previewv1.PortForwardResponse{
+				Event: &previewv1.PortForwardResponse_Data{Data: buf[:n]},
+			}); err != nil {
+				return err
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return connect.NewError(connect.CodeUnavailable, err)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package preview
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/HJH0924/agent-sandbox/domain/preview/service"
+	"github.com/HJH0924/agent-sandbox/internal/middleware"
+	"github.com/HJH0924/agent-sandbox/internal/sandbox"
+	previewv1 "github.com/HJH0924/agent-sandbox/sdk/go/preview/v1"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_CreatePreviewURL(t *testing.T) {
+	registry := sandbox.NewRegistry()
+	registry.Add(&sandbox.Sandbox{ID: "sandbox-1", CreatedAt: time.Now(), Network: sandbox.NetworkFull})
+
+	previewService := service.NewService(registry, []byte("secret"))
+	handler := NewHandler(previewService, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+	ctx := context.WithValue(context.Background(), middleware.SandboxIDKey, "sandbox-1")
+
+	resp, err := handler.CreatePreviewURL(ctx, connect.NewRequest(&previewv1.CreatePreviewURLRequest{Port: 3000, TtlSeconds: 60}))
+	require.NoError(t, err)
+	assert.Equal(t, "/sandboxes/sandbox-1/ports/3000/?preview_token="+url.QueryEscape(resp.Msg.GetToken()), resp.Msg.GetPath())
+
+	expiresAt, err := previewService.VerifyToken(resp.Msg.GetToken(), "sandbox-1", 3000)
+	require.NoError(t, err)
+	assert.Equal(t, expiresAt, resp.Msg.GetExpiresAt().AsTime().Local())
+
+	_, err = handler.CreatePreviewURL(ctx, connect.NewRequest(&previewv1.CreatePreviewURLRequest{Port: 70000}))
+	require.Error(t, err)
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+}
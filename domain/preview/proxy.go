@@ -0,0 +1,185 @@
+package preview
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HJH0924/agent-sandbox/domain/core/service"
+	previewService "github.com/HJH0924/agent-sandbox/domain/preview/service"
+	"github.com/HJH0924/agent-sandbox/internal/middleware"
+)
+
+const (
+	// Pattern 预览代理的路由.
+	Pattern = "/sandboxes/{id}/ports/{port}/"
+	// TokenParam 携带预览令牌的查询参数.
+	TokenParam = "preview_token"
+	// tokenCookie 保存预览令牌的 Cookie，页面中的后续请求（静态资源、WebSocket）通过它认证.
+	tokenCookie = "agent_sandbox_preview"
+)
+
+// PortPath 返回沙箱端口的预览路径.
+func PortPath(sandboxID string, port int) string {
+	return fmt.Sprintf("/sandboxes/%s/ports/%d/", sandboxID, port)
+}
+
+// target 代理请求的目标沙箱端口.
+type target struct {
+	sandboxID string
+	port      int
+}
+
+// targetKey 请求上下文中目标沙箱端口的键.
+type targetKey struct{}
+
+// Proxy 将 /sandboxes/{id}/ports/{port}/ 下的 HTTP 和 WebSocket 请求转发到沙箱中监听该端口的服务.
+// 请求通过沙箱的 API Key 请求头、预览令牌查询参数或预览 Cookie 认证.
+type Proxy struct {
+	previewService *previewService.Service
+	store          service.APIKeyStore
+	logger         *slog.Logger
+	proxy          *httputil.ReverseProxy
+}
+
+// NewProxy 创建预览代理.
+func NewProxy(previewSvc *previewService.Service, store service.APIKeyStore, logger *slog.Logger) *Proxy {
+	p := &Proxy{
+		previewService: previewSvc,
+		store:          store,
+		logger:         logger,
+	}
+
+	p.proxy = &httputil.ReverseProxy{
+		Rewrite: p.rewrite,
+		Transport: &http.Transport{
+			// 目标由请求上下文决定，不复用连接以免不同沙箱共用连接池
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				t, _ := ctx.Value(targetKey{}).(target)
+				return previewSvc.Dial(ctx, t.sandboxID, t.port)
+			},
+			DisableKeepAlives:     true,
+			ResponseHeaderTimeout: 5 * time.Minute,
+		},
+		ErrorHandler: p.handleError,
+	}
+
+	return p
+}
+
+// ServeHTTP 认证请求并转发到沙箱端口.
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	sandboxID := r.PathValue("id")
+
+	port, err := strconv.Atoi(r.PathValue("port"))
+	if err != nil || previewService.ValidatePort(port) != nil {
+		http.Error(w, previewService.ErrInvalidPort.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if status := p.authenticate(w, r, sandboxID, port); status != http.StatusOK {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	// WebSocket 和流式响应可能超过服务器的读写超时
+	rc := http.NewResponseController(w)
+	_ = rc.SetReadDeadline(time.Time{})
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	ctx := context.WithValue(r.Context(), targetKey{}, target{sandboxID: sandboxID, port: port})
+	p.proxy.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// authenticate 依次检查 API Key 请求头、预览令牌查询参数和预览 Cookie，通过时返回 200.
+// 通过查询参数认证时将令牌写入 Cookie，使页面的后续请求无需携带令牌.
+func (p *Proxy) authenticate(w http.ResponseWriter, r *http.Request, sandboxID string, port int) int {
+	if apiKey := r.Header.Get(middleware.APIKeyHeader); apiKey != "" {
+		id, ok := p.store.Verify(apiKey)
+		if !ok {
+			return http.StatusUnauthorized
+		}
+
+		if id != sandboxID {
+			return http.StatusForbidden
+		}
+
+		return http.StatusOK
+	}
+
+	if token := r.URL.Query().Get(TokenParam); token != "" {
+		expiresAt, err := p.previewService.VerifyToken(token, sandboxID, port)
+		if err != nil {
+			return http.StatusUnauthorized
+		}
+
+		http.SetCookie(w, &http.Cookie{
+			Name:     tokenCookie,
+			Value:    token,
+			Path:     PortPath(sandboxID, port),
+			Expires:  expiresAt,
+			HttpOnly: true,
+			Secure:   r.TLS != nil,
+			SameSite: http.SameSiteLaxMode,
+		})
+
+		return http.StatusOK
+	}
+
+	if cookie, err := r.Cookie(tokenCookie); err == nil {
+		if _, err := p.previewService.VerifyToken(cookie.Value, sandboxID, port); err == nil {
+			return http.StatusOK
+		}
+	}
+
+	return http.StatusUnauthorized
+}
+
+// rewrite 去掉预览路径前缀和认证信息，将请求改写为发往沙箱本地服务的请求.
+func (p *Proxy) rewrite(pr *httputil.ProxyRequest) {
+	t, _ := pr.In.Context().Value(targetKey{}).(target)
+	prefix := PortPath(t.sandboxID, t.port)
+	host := net.JoinHostPort("localhost", strconv.Itoa(t.port))
+
+	pr.Out.URL.Scheme = "http"
+	pr.Out.URL.Host = host
+	pr.Out.URL.Path = "/" + strings.TrimPrefix(pr.In.URL.Path, prefix)
+	pr.Out.URL.RawPath = ""
+	// 开发服务器通常只接受 localhost 的 Host 头，原始 Host 通过 X-Forwarded-Host 传递
+	pr.Out.Host = host
+
+	if query := pr.Out.URL.Query(); query.Has(TokenParam) {
+		query.Del(TokenParam)
+		pr.Out.URL.RawQuery = query.Encode()
+	}
+
+	pr.Out.Header.Del(middleware.APIKeyHeader)
+	pr.Out.Header.Del("Cookie")
+
+	for _, cookie := range pr.In.Cookies() {
+		if cookie.Name != tokenCookie {
+			pr.Out.AddCookie(cookie)
+		}
+	}
+
+	pr.SetXForwarded()
+	pr.Out.Header.Set("X-Forwarded-Prefix", strings.TrimSuffix(prefix, "/"))
+}
+
+// handleError 无法连接沙箱中的服务时返回 502.
+func (p *Proxy) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	t, _ := r.Context().Value(targetKey{}).(target)
+
+	p.logger.WarnContext(r.Context(), "preview proxy failed",
+		slog.String("sandbox_id", t.sandboxID),
+		slog.Int("port", t.port),
+		slog.Any("error", err))
+
+	http.Error(w, fmt.Sprintf("failed to reach port %d in sandbox", t.port), http.StatusBadGateway)
+}
@@ -0,0 +1,206 @@
+package preview
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	coreService "github.com/HJH0924/agent-sandbox/domain/core/service"
+	"github.com/HJH0924/agent-sandbox/domain/preview/service"
+	"github.com/HJH0924/agent-sandbox/internal/middleware"
+	"github.com/HJH0924/agent-sandbox/internal/sandbox"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestProxy 启动经过预览代理的测试服务器，sandbox-1 和 sandbox-2 的 API Key 分别为 key-1 和 key-2.
+func newTestProxy(t *testing.T) (*httptest.Server, *service.Service) {
+	t.Helper()
+
+	store := coreService.NewMemoryAPIKeyStore()
+	require.NoError(t, store.Store("sandbox-1", "key-1"))
+	require.NoError(t, store.Store("sandbox-2", "key-2"))
+
+	registry := sandbox.NewRegistry()
+	for _, id := range []string{"sandbox-1", "sandbox-2"} {
+		registry.Add(&sandbox.Sandbox{ID: id, CreatedAt: time.Now(), Network: sandbox.NetworkFull})
+	}
+
+	previewService := service.NewService(registry, []byte("secret"))
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	mux := http.NewServeMux()
+	mux.Handle(Pattern, NewProxy(previewService, store, logger))
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server, previewService
+}
+
+// newUpstream 启动回显请求信息的上游服务，返回监听端口.
+func newUpstream(t *testing.T) int {
+	t.Helper()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookies := make([]string, 0)
+		for _, cookie := range r.Cookies() {
+			cookies = append(cookies, cookie.Name)
+		}
+
+		_, _ = fmt.Fprintf(w, "path=%s query=%s host=%s key=%s cookies=%s prefix=%s",
+			r.URL.Path, r.URL.RawQuery, r.Host, r.Header.Get(middleware.APIKeyHeader),
+			strings.Join(cookies, ","), r.Header.Get("X-Forwarded-Prefix"))
+	}))
+	t.Cleanup(upstream.Close)
+
+	return upstream.Listener.Addr().(*net.TCPAddr).Port
+}
+
+func get(t *testing.T, rawURL string, header http.Header) (*http.Response, string) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	require.NoError(t, err)
+
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return resp, string(body)
+}
+
+func TestProxy_APIKey(t *testing.T) {
+	server, _ := newTestProxy(t)
+	port := newUpstream(t)
+	base := server.URL + PortPath("sandbox-1", port)
+
+	resp, body := get(t, base+"assets/app.js?v=1", http.Header{middleware.APIKeyHeader: {"key-1"}})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, fmt.Sprintf("path=/assets/app.js query=v=1 host=localhost:%d key= cookies= prefix=/sandboxes/sandbox-1/ports/%d", port, port), body)
+
+	resp, _ = get(t, base, http.Header{middleware.APIKeyHeader: {"wrong"}})
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	// 其他沙箱的 API Key 不能访问
+	resp, _ = get(t, base, http.Header{middleware.APIKeyHeader: {"key-2"}})
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	resp, _ = get(t, base, nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp, _ = get(t, server.URL+"/sandboxes/sandbox-1/ports/99999/", http.Header{middleware.APIKeyHeader: {"key-1"}})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestProxy_Token(t *testing.T) {
+	server, previewService := newTestProxy(t)
+	port := newUpstream(t)
+	base := server.URL + PortPath("sandbox-1", port)
+
+	token, _, err := previewService.CreateToken("sandbox-1", port, 0)
+	require.NoError(t, err)
+
+	resp, body := get(t, base+"?page=2&"+TokenParam+"="+url.QueryEscape(token), nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, "path=/ query=page=2 ")
+
+	// 令牌写入 Cookie，后续请求通过 Cookie 认证，预览 Cookie 不转发给上游
+	require.Len(t, resp.Cookies(), 1)
+	cookie := resp.Cookies()[0]
+	assert.Equal(t, tokenCookie, cookie.Name)
+	assert.Equal(t, PortPath("sandbox-1", port), cookie.Path)
+	assert.True(t, cookie.HttpOnly)
+
+	resp, body = get(t, base+"index.css", http.Header{"Cookie": {tokenCookie + "=" + cookie.Value + "; theme=dark"}})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, "cookies=theme ")
+
+	// 令牌只能用于生成时的端口
+	resp, _ = get(t, server.URL+PortPath("sandbox-1", port+1)+"?"+TokenParam+"="+url.QueryEscape(token), nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp, _ = get(t, server.URL+PortPath("sandbox-2", port), http.Header{"Cookie": {tokenCookie + "=" + cookie.Value}})
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestProxy_Unreachable(t *testing.T) {
+	server, _ := newTestProxy(t)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+
+	resp, _ := get(t, server.URL+PortPath("sandbox-1", port), http.Header{middleware.APIKeyHeader: {"key-1"}})
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+}
+
+func TestProxy_WebSocket(t *testing.T) {
+	server, _ := newTestProxy(t)
+
+	// 上游完成协议升级后回显收到的数据
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != "websocket" {
+			http.Error(w, "upgrade required", http.StatusUpgradeRequired)
+			return
+		}
+
+		conn, rw, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			return
+		}
+		defer func() {
+			_ = conn.Close()
+		}()
+
+		_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\n")
+		_ = rw.Flush()
+		_, _ = io.Copy(conn, rw)
+	}))
+	t.Cleanup(upstream.Close)
+
+	port := upstream.Listener.Addr().(*net.TCPAddr).Port
+
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	require.NoError(t, err)
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	_, err = fmt.Fprintf(conn, "GET %sws HTTP/1.1\r\nHost: example.com\r\n%s: key-1\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\n",
+		PortPath("sandbox-1", port), middleware.APIKeyHeader)
+	require.NoError(t, err)
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+
+	buf := make([]byte, 4)
+	_, err = io.ReadFull(reader, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(buf))
+}
@@ -0,0 +1,142 @@
+// Package service implements preview tokens and connections to services running inside a sandbox.
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HJH0924/agent-sandbox/internal/sandbox"
+)
+
+// defaultTokenTTL 未配置时预览令牌的有效期.
+const defaultTokenTTL = 15 * time.Minute
+
+var (
+	// ErrInvalidPort 端口不在 1-65535 范围内.
+	ErrInvalidPort = errors.New("port must be between 1 and 65535")
+	// ErrInvalidToken 预览令牌无效、已过期、不属于该沙箱端口或沙箱已销毁.
+	ErrInvalidToken = errors.New("invalid or expired preview token")
+)
+
+// Service 预览服务.
+type Service struct {
+	registry *sandbox.Registry
+	secret   []byte
+	tokenTTL time.Duration
+	now      func() time.Time
+}
+
+// Option 预览服务的可选配置.
+type Option func(*Service)
+
+// WithTokenTTL 设置预览令牌的默认及最长有效期.
+func WithTokenTTL(ttl time.Duration) Option {
+	return func(s *Service) {
+		if ttl > 0 {
+			s.tokenTTL = ttl
+		}
+	}
+}
+
+// NewService 创建预览服务，secret 用于签名预览令牌，registry 用于进入沙箱的网络命名空间.
+func NewService(registry *sandbox.Registry, secret []byte, opts ...Option) *Service {
+	s := &Service{
+		registry: registry,
+		secret:   secret,
+		tokenTTL: defaultTokenTTL,
+		now:      time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// ValidatePort 检查端口是否合法.
+func ValidatePort(port int) error {
+	if port < 1 || port > 65535 {
+		return ErrInvalidPort
+	}
+
+	return nil
+}
+
+// CreateToken 为沙箱端口生成预览令牌，ttl 为 0 或超过上限时使用配置的有效期.
+// 令牌格式为 "<过期时间戳>.<签名>"，只能用于生成时指定的沙箱和端口.
+func (s *Service) CreateToken(sandboxID string, port int, ttl time.Duration) (string, time.Time, error) {
+	if err := ValidatePort(port); err != nil {
+		return "", time.Time{}, err
+	}
+
+	if ttl <= 0 || ttl > s.tokenTTL {
+		ttl = s.tokenTTL
+	}
+
+	expiresAt := s.now().Add(ttl).Truncate(time.Second)
+	expiry := strconv.FormatInt(expiresAt.Unix(), 10)
+
+	return expiry + "." + s.sign(sandboxID, port, expiry), expiresAt, nil
+}
+
+// VerifyToken 检查令牌是否属于沙箱端口且未过期，返回令牌的过期时间.
+// 沙箱销毁后令牌随之失效，即使尚未过期.
+func (s *Service) VerifyToken(token, sandboxID string, port int) (time.Time, error) {
+	if s.registry != nil {
+		if _, err := s.registry.Get(sandboxID); err != nil {
+			return time.Time{}, ErrInvalidToken
+		}
+	}
+
+	expiry, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return time.Time{}, ErrInvalidToken
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(s.sign(sandboxID, port, expiry))) {
+		return time.Time{}, ErrInvalidToken
+	}
+
+	unix, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil {
+		return time.Time{}, ErrInvalidToken
+	}
+
+	expiresAt := time.Unix(unix, 0)
+	if !s.now().Before(expiresAt) {
+		return time.Time{}, ErrInvalidToken
+	}
+
+	return expiresAt, nil
+}
+
+// Dial 连接沙箱中监听在回环地址上的端口.
+func (s *Service) Dial(ctx context.Context, sandboxID string, port int) (net.Conn, error) {
+	if err := ValidatePort(port); err != nil {
+		return nil, err
+	}
+
+	conn, err := s.registry.DialPort(ctx, sandboxID, port)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to port %d: %w", port, err)
+	}
+
+	return conn, nil
+}
+
+// sign 计算令牌签名.
+func (s *Service) sign(sandboxID string, port int, expiry string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	_, _ = fmt.Fprintf(mac, "%s\n%d\n%s", sandboxID, port, expiry)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/HJH0924/agent-sandbox/internal/sandbox"
+)
+
+// newRegistry 创建注册了 sandboxIDs 的沙箱注册表.
+func newRegistry(sandboxIDs ...string) *sandbox.Registry {
+	registry := sandbox.NewRegistry()
+
+	for _, id := range sandboxIDs {
+		registry.Add(&sandbox.Sandbox{ID: id, CreatedAt: time.Now(), Network: sandbox.NetworkFull})
+	}
+
+	return registry
+}
+
+func TestToken(t *testing.T) {
+	service := NewService(newRegistry("sandbox-1", "sandbox-2"), []byte("secret"), WithTokenTTL(time.Minute))
+	now := time.Unix(1700000000, 0)
+	service.now = func() time.Time { return now }
+
+	token, expiresAt, err := service.CreateToken("sandbox-1", 3000, 0)
+	if err != nil {
+		t.Fatalf("CreateToken failed: %v", err)
+	}
+
+	if !expiresAt.Equal(now.Add(time.Minute)) {
+		t.Fatalf("Expected default TTL, got expiry %s", expiresAt)
+	}
+
+	if got, err := service.VerifyToken(token, "sandbox-1", 3000); err != nil || !got.Equal(expiresAt) {
+		t.Fatalf("VerifyToken failed: %v, %s", err, got)
+	}
+
+	tests := []struct {
+		name      string
+		token     string
+		sandboxID string
+		port      int
+	}{
+		{name: "other sandbox", token: token, sandboxID: "sandbox-2", port: 3000},
+		{name: "other port", token: token, sandboxID: "sandbox-1", port: 3001},
+		{name: "tampered expiry", token: "9" + token, sandboxID: "sandbox-1", port: 3000},
+		{name: "malformed", token: "not-a-token", sandboxID: "sandbox-1", port: 3000},
+		{name: "other secret", token: mustToken(t, NewService(nil, []byte("other")), "sandbox-1", 3000), sandboxID: "sandbox-1", port: 3000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.VerifyToken(tt.token, tt.sandboxID, tt.port); !errors.Is(err, ErrInvalidToken) {
+				t.Fatalf("Expected ErrInvalidToken, got %v", err)
+			}
+		})
+	}
+
+	// 过期后失效
+	now = now.Add(time.Minute)
+	if _, err := service.VerifyToken(token, "sandbox-1", 3000); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("Expected expired token to be rejected, got %v", err)
+	}
+}
+
+func TestToken_SandboxDestroyed(t *testing.T) {
+	registry := newRegistry("sandbox-1")
+	service := NewService(registry, []byte("secret"))
+	token := mustToken(t, service, "sandbox-1", 3000)
+
+	if _, err := service.VerifyToken(token, "sandbox-1", 3000); err != nil {
+		t.Fatalf("VerifyToken failed: %v", err)
+	}
+
+	// 沙箱销毁后未过期的令牌同样失效
+	if err := registry.Remove("sandbox-1"); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+
+	if _, err := service.VerifyToken(token, "sandbox-1", 3000); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("Expected token of destroyed sandbox to be rejected, got %v", err)
+	}
+}
+
+func TestCreateToken_TTL(t *testing.T) {
+	service := NewService(sandbox.NewRegistry(), []byte("secret"), WithTokenTTL(time.Minute))
+	now := time.Unix(1700000000, 0)
+	service.now = func() time.Time { return now }
+
+	if _, expiresAt, _ := service.CreateToken("sandbox-1", 80, 10*time.Second); !expiresAt.Equal(now.Add(10 * time.Second)) {
+		t.Fatalf("Expected requested TTL, got expiry %s", expiresAt)
+	}
+
+	// 超过上限时使用配置的有效期
+	if _, expiresAt, _ := service.CreateToken("sandbox-1", 80, time.Hour); !expiresAt.Equal(now.Add(time.Minute)) {
+		t.Fatalf("Expected TTL to be capped, got expiry %s", expiresAt)
+	}
+
+	for _, port := range []int{0, -1, 65536} {
+		if _, _, err := service.CreateToken("sandbox-1", port, 0); !errors.Is(err, ErrInvalidPort) {
+			t.Fatalf("Expected ErrInvalidPort for %d, got %v", port, err)
+		}
+	}
+}
+
+func TestDial(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	defer func() {
+		_ = listener.Close()
+	}()
+
+	service := NewService(newRegistry("sandbox-1"), []byte("secret"))
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	conn, err := service.Dial(context.Background(), "sandbox-1", port)
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	_ = conn.Close()
+
+	// 未注册的沙箱不连接宿主机
+	if _, err := service.Dial(context.Background(), "sandbox-2", port); !errors.Is(err, sandbox.ErrSandboxNotFound) {
+		t.Fatalf("Expected ErrSandboxNotFound, got %v", err)
+	}
+
+	if _, err := service.Dial(context.Background(), "sandbox-1", 0); !errors.Is(err, ErrInvalidPort) {
+		t.Fatalf("Expected ErrInvalidPort, got %v", err)
+	}
+}
+
+func mustToken(t *testing.T, service *Service, sandboxID string, port int) string {
+	t.Helper()
+
+	token, _, err := service.CreateToken(sandboxID, port, 0)
+	if err != nil {
+		t.Fatalf("CreateToken failed: %v", err)
+	}
+
+	return token
+}
@@ -0,0 +1,143 @@
+// Package schedule provides handlers for commands that the server runs periodically inside the sandbox.
+package schedule
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/HJH0924/agent-sandbox/domain/schedule/service"
+	"github.com/HJH0924/agent-sandbox/domain/shell"
+	"github.com/HJH0924/agent-sandbox/internal/middleware"
+	schedulev1 "github.com/HJH0924/agent-sandbox/sdk/go/schedule/v1"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Handler 定时任务服务处理器.
+type Handler struct {
+	scheduleService *service.Service
+	logger          *slog.Logger
+}
+
+// NewHandler 创建定时任务服务处理器.
+func NewHandler(scheduleService *service.Service, logger *slog.Logger) *Handler {
+	return &Handler{
+		scheduleService: scheduleService,
+		logger:          logger,
+	}
+}
+
+// errorCode 将定时任务错误映射为 RPC 错误码.
+func errorCode(err error) connect.Code {
+	switch {
+	case errors.Is(err, service.ErrInvalidSchedule):
+		return connect.CodeInvalidArgument
+	case errors.Is(err, service.ErrScheduleNotFound):
+		return connect.CodeNotFound
+	case errors.Is(err, service.ErrTooManySchedules):
+		return connect.CodeResourceExhausted
+	default:
+		return shell.ErrorCode(err)
+	}
+}
+
+// CreateSchedule 创建定时任务.
+func (h *Handler) CreateSchedule(
+	ctx context.Context,
+	req *connect.Request[schedulev1.CreateScheduleRequest],
+) (*connect.Response[schedulev1.CreateScheduleResponse], error) {
+	sandboxID, _ := middleware.GetSandboxIDFromContext(ctx)
+	keyID, _ := middleware.GetAPIKeyIDFromContext(ctx)
+
+	schedule, err := h.scheduleService.CreateSchedule(&service.CreateRequest{
+		SandboxID:   sandboxID,
+		Command:     req.Msg.GetCommand(),
+		Cron:        req.Msg.GetCron(),
+		Interval:    time.Duration(req.Msg.GetIntervalSeconds()) * time.Second,
+		CallerKeyID: keyID,
+	})
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to create schedule",
+			slog.String("command", req.Msg.GetCommand()),
+			slog.Any("error", err))
+
+		return nil, connect.NewError(errorCode(err), err)
+	}
+
+	h.logger.InfoContext(ctx, "schedule created",
+		slog.String("schedule_id", schedule.ID),
+		slog.String("command", schedule.Command),
+		slog.String("cron", schedule.Cron),
+		slog.Duration("interval", schedule.Interval))
+
+	return connect.NewResponse(&schedulev1.CreateScheduleResponse{
+		Schedule: toSchedule(schedule),
+	}), nil
+}
+
+// ListSchedules 列出当前沙箱的定时任务.
+func (h *Handler) ListSchedules(
+	ctx context.Context,
+	_ *connect.Request[schedulev1.ListSchedulesRequest],
+) (*connect.Response[schedulev1.ListSchedulesResponse], error) {
+	sandboxID, _ := middleware.GetSandboxIDFromContext(ctx)
+
+	schedules := h.scheduleService.ListSchedules(sandboxID)
+
+	resp := &schedulev1.ListSchedulesResponse{
+		Schedules: make([]*schedulev1.Schedule, 0, len(schedules)),
+	}
+
+	for _, s := range schedules {
+		resp.Schedules = append(resp.Schedules, toSchedule(s))
+	}
+
+	return connect.NewResponse(resp), nil
+}
+
+// DeleteSchedule 删除定时任务.
+func (h *Handler) DeleteSchedule(
+	ctx context.Context,
+	req *connect.Request[schedulev1.DeleteScheduleRequest],
+) (*connect.Response[schedulev1.DeleteScheduleResponse], error) {
+	sandboxID, _ := middleware.GetSandboxIDFromContext(ctx)
+	scheduleID := req.Msg.GetScheduleId()
+
+	if err := h.scheduleService.DeleteSchedule(sandboxID, scheduleID); err != nil {
+		return nil, connect.NewError(errorCode(err), err)
+	}
+
+	h.logger.InfoContext(ctx, "schedule deleted",
+		slog.String("schedule_id", scheduleID))
+
+	return connect.NewResponse(&schedulev1.DeleteScheduleResponse{}), nil
+}
+
+// toSchedule 将定时任务转换为响应消息.
+func toSchedule(s *service.Schedule) *schedulev1.Schedule {
+	msg := &schedulev1.Schedule{
+		ScheduleId:      s.ID,
+		Command:         s.Command,
+		Cron:            s.Cron,
+		IntervalSeconds: int64(s.Interval / time.Second),
+		CreatedAt:       timestamppb.New(s.CreatedAt),
+		LastExitCode:    int32(s.LastExitCode), // #nosec G115 -- exit codes fit in int32
+		LastError:       s.LastError,
+		RunCount:        int32(s.RunCount),     // #nosec G115 -- run counts fit in int32
+		SkippedCount:    int32(s.SkippedCount), // #nosec G115 -- skip counts fit in int32
+		Running:         s.Running,
+	}
+
+	if !s.NextRunAt.IsZero() {
+		msg.NextRunAt = timestamppb.New(s.NextRunAt)
+	}
+
+	if !s.LastRunAt.IsZero() {
+		msg.LastRunAt = timestamppb.New(s.LastRunAt)
+	}
+
+	return msg
+}
@@ -0,0 +1,101 @@
+package schedule
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/HJH0924/agent-sandbox/domain/schedule/service"
+	shellService "github.com/HJH0924/agent-sandbox/domain/shell/service"
+	"github.com/HJH0924/agent-sandbox/internal/middleware"
+	"github.com/HJH0924/agent-sandbox/internal/policy"
+	schedulev1 "github.com/HJH0924/agent-sandbox/sdk/go/schedule/v1"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHandler(t *testing.T) *Handler {
+	t.Helper()
+
+	commandPolicy, err := policy.New([]policy.Rule{
+		{Name: "no-curl", Action: policy.ActionDeny, Prefix: []string{"curl"}},
+	}, policy.ActionAllow)
+	require.NoError(t, err)
+
+	scheduleService := service.NewService(shellService.NewService(10, t.TempDir(), shellService.WithPolicy(commandPolicy)))
+	t.Cleanup(func() { scheduleService.RemoveSandbox("sandbox-1") })
+
+	return NewHandler(scheduleService, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+}
+
+func TestHandler_Schedules(t *testing.T) {
+	handler := newTestHandler(t)
+	ctx := context.WithValue(context.Background(), middleware.SandboxIDKey, "sandbox-1")
+
+	created, err := handler.CreateSchedule(ctx, connect.NewRequest(&schedulev1.CreateScheduleRequest{
+		Command: "date",
+		Cron:    "*/5 * * * *",
+	}))
+	require.NoError(t, err)
+
+	schedule := created.Msg.GetSchedule()
+	assert.NotEmpty(t, schedule.GetScheduleId())
+	assert.Equal(t, "*/5 * * * *", schedule.GetCron())
+	assert.Equal(t, int32(-1), schedule.GetLastExitCode())
+	assert.NotNil(t, schedule.GetNextRunAt())
+	assert.Nil(t, schedule.GetLastRunAt())
+
+	_, err = handler.CreateSchedule(ctx, connect.NewRequest(&schedulev1.CreateScheduleRequest{
+		Command:         "uptime",
+		IntervalSeconds: 60,
+	}))
+	require.NoError(t, err)
+
+	listed, err := handler.ListSchedules(ctx, connect.NewRequest(&schedulev1.ListSchedulesRequest{}))
+	require.NoError(t, err)
+	require.Len(t, listed.Msg.GetSchedules(), 2)
+	assert.Equal(t, "date", listed.Msg.GetSchedules()[0].GetCommand())
+	assert.Equal(t, int64(60), listed.Msg.GetSchedules()[1].GetIntervalSeconds())
+
+	// 其他沙箱看不到也删不掉
+	other := context.WithValue(context.Background(), middleware.SandboxIDKey, "sandbox-2")
+
+	listed, err = handler.ListSchedules(other, connect.NewRequest(&schedulev1.ListSchedulesRequest{}))
+	require.NoError(t, err)
+	assert.Empty(t, listed.Msg.GetSchedules())
+
+	_, err = handler.DeleteSchedule(other, connect.NewRequest(&schedulev1.DeleteScheduleRequest{ScheduleId: schedule.GetScheduleId()}))
+	assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+
+	_, err = handler.DeleteSchedule(ctx, connect.NewRequest(&schedulev1.DeleteScheduleRequest{ScheduleId: schedule.GetScheduleId()}))
+	require.NoError(t, err)
+
+	listed, err = handler.ListSchedules(ctx, connect.NewRequest(&schedulev1.ListSchedulesRequest{}))
+	require.NoError(t, err)
+	assert.Len(t, listed.Msg.GetSchedules(), 1)
+}
+
+func TestHandler_CreateSchedule_Errors(t *testing.T) {
+	handler := newTestHandler(t)
+	ctx := context.WithValue(context.Background(), middleware.SandboxIDKey, "sandbox-1")
+
+	tests := []struct {
+		name string
+		req  *schedulev1.CreateScheduleRequest
+		code connect.Code
+	}{
+		{name: "no trigger", req: &schedulev1.CreateScheduleRequest{Command: "date"}, code: connect.CodeInvalidArgument},
+		{name: "invalid cron", req: &schedulev1.CreateScheduleRequest{Command: "date", Cron: "every minute"}, code: connect.CodeInvalidArgument},
+		{name: "denied", req: &schedulev1.CreateScheduleRequest{Command: "curl example.com", IntervalSeconds: 60}, code: connect.CodePermissionDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := handler.CreateSchedule(ctx, connect.NewRequest(tt.req))
+			assert.Equal(t, tt.code, connect.CodeOf(err))
+		})
+	}
+}
@@ -0,0 +1,320 @@
+// Package service implements periodic commands that the server runs inside a sandbox.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	shellService "github.com/HJH0924/agent-sandbox/domain/shell/service"
+	"github.com/HJH0924/agent-sandbox/internal/cron"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// maxSchedulesPerSandbox 每个沙箱允许同时存在的定时任务数.
+	maxSchedulesPerSandbox = 16
+	// minInterval 固定间隔任务的最小间隔.
+	minInterval = 10 * time.Second
+)
+
+var (
+	// ErrInvalidSchedule 定时任务的命令、cron 表达式或间隔无效.
+	ErrInvalidSchedule = errors.New("invalid schedule")
+	// ErrScheduleNotFound 定时任务不存在或不属于当前沙箱.
+	ErrScheduleNotFound = errors.New("schedule not found")
+	// ErrTooManySchedules 沙箱的定时任务数已达上限.
+	ErrTooManySchedules = fmt.Errorf("too many schedules (max: %d)", maxSchedulesPerSandbox)
+)
+
+// Schedule 定时任务的状态快照.
+type Schedule struct {
+	ID        string
+	SandboxID string
+	Command   string
+	// Cron cron 表达式，与 Interval 二选一
+	Cron string
+	// Interval 固定间隔
+	Interval  time.Duration
+	CreatedAt time.Time
+	// NextRunAt 下一次运行的时间
+	NextRunAt time.Time
+	// LastRunAt 最近一次开始运行的时间，从未运行时为零值
+	LastRunAt time.Time
+	// LastExitCode 最近一次运行的退出码，命令没有运行时为 -1
+	LastExitCode int
+	// LastError 最近一次运行失败的原因（如被策略拒绝、超时）
+	LastError string
+	// RunCount 已完成的运行次数
+	RunCount int
+	// SkippedCount 因上一次运行尚未结束而跳过的次数
+	SkippedCount int
+	// Running 是否正在运行
+	Running bool
+}
+
+// job 定时任务，字段由 Service.mu 保护.
+type job struct {
+	Schedule
+
+	callerKeyID string
+	cron        *cron.Schedule
+	timer       *time.Timer
+	ctx         context.Context
+	cancel      context.CancelFunc
+}
+
+// Service 定时任务服务，命令通过 Shell 服务执行，结果记录在执行历史中.
+type Service struct {
+	shell *shellService.Service
+	now   func() time.Time
+
+	mu   sync.Mutex
+	jobs map[string]*job
+}
+
+// NewService 创建定时任务服务实例.
+func NewService(shell *shellService.Service) *Service {
+	return &Service{
+		shell: shell,
+		now:   time.Now,
+		jobs:  make(map[string]*job),
+	}
+}
+
+// CreateRequest 创建定时任务的请求.
+type CreateRequest struct {
+	SandboxID string
+	Command   string
+	// Cron cron 表达式（如 "*/5 * * * *"），与 Interval 必须且只能设置一个
+	Cron string
+	// Interval 固定间隔，最小 10 秒
+	Interval time.Duration
+	// CallerKeyID 创建者 API Key 的标识，记录在每次运行的执行历史中
+	CallerKeyID string
+}
+
+// CreateSchedule 创建定时任务，命令在创建时按命令策略检查，需要审批的命令不能定时运行.
+func (s *Service) CreateSchedule(req *CreateRequest) (*Schedule, error) {
+	if strings.TrimSpace(req.Command) == "" {
+		return nil, fmt.Errorf("%w: command is required", ErrInvalidSchedule)
+	}
+
+	var (
+		spec *cron.Schedule
+		err  error
+	)
+
+	switch {
+	case req.Cron != "" && req.Interval != 0:
+		return nil, fmt.Errorf("%w: cron and interval are mutually exclusive", ErrInvalidSchedule)
+	case req.Cron != "":
+		if spec, err = cron.Parse(req.Cron); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidSchedule, err)
+		}
+	case req.Interval < minInterval:
+		return nil, fmt.Errorf("%w: interval must be at least %s", ErrInvalidSchedule, minInterval)
+	}
+
+	if err := s.shell.CheckCommand(req.SandboxID, req.Command); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	j := &job{
+		Schedule: Schedule{
+			ID:           uuid.New().String(),
+			SandboxID:    req.SandboxID,
+			Command:      req.Command,
+			Cron:         req.Cron,
+			Interval:     req.Interval,
+			CreatedAt:    now,
+			LastExitCode: -1,
+		},
+		callerKeyID: req.CallerKeyID,
+		cron:        spec,
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := 0
+
+	for _, other := range s.jobs {
+		if other.SandboxID == req.SandboxID {
+			count++
+		}
+	}
+
+	if count >= maxSchedulesPerSandbox {
+		cancel()
+		return nil, ErrTooManySchedules
+	}
+
+	if !s.scheduleNext(j, now) {
+		cancel()
+		return nil, fmt.Errorf("%w: cron expression never fires", ErrInvalidSchedule)
+	}
+
+	s.jobs[j.ID] = j
+
+	snapshot := j.Schedule
+
+	return &snapshot, nil
+}
+
+// ListSchedules 返回沙箱的定时任务，按创建时间排序.
+func (s *Service) ListSchedules(sandboxID string) []*Schedule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schedules := make([]*Schedule, 0)
+
+	for _, j := range s.jobs {
+		if j.SandboxID == sandboxID {
+			snapshot := j.Schedule
+			schedules = append(schedules, &snapshot)
+		}
+	}
+
+	sort.Slice(schedules, func(i, k int) bool {
+		return schedules[i].CreatedAt.Before(schedules[k].CreatedAt)
+	})
+
+	return schedules
+}
+
+// DeleteSchedule 删除定时任务，正在运行的命令会被终止.
+func (s *Service) DeleteSchedule(sandboxID, scheduleID string) error {
+	s.mu.Lock()
+
+	j, ok := s.jobs[scheduleID]
+	if !ok || j.SandboxID != sandboxID {
+		s.mu.Unlock()
+		return ErrScheduleNotFound
+	}
+
+	delete(s.jobs, scheduleID)
+	s.mu.Unlock()
+
+	j.stop()
+
+	return nil
+}
+
+// RemoveSandbox 删除沙箱的所有定时任务，用于销毁沙箱.
+func (s *Service) RemoveSandbox(sandboxID string) {
+	var removed []*job
+
+	s.mu.Lock()
+	for id, j := range s.jobs {
+		if j.SandboxID == sandboxID {
+			removed = append(removed, j)
+			delete(s.jobs, id)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, j := range removed {
+		j.stop()
+	}
+}
+
+// scheduleNext 计算下一次运行时间并设置定时器，cron 表达式不再触发时返回 false.
+// 调用方必须持有 s.mu.
+func (s *Service) scheduleNext(j *job, now time.Time) bool {
+	next := now.Add(j.Interval)
+	if j.cron != nil {
+		next = j.cron.Next(now)
+	}
+
+	if next.IsZero() {
+		j.NextRunAt = time.Time{}
+		return false
+	}
+
+	j.NextRunAt = next
+
+	if j.timer == nil {
+		j.timer = time.AfterFunc(next.Sub(now), func() { s.run(j) })
+	} else {
+		j.timer.Reset(next.Sub(now))
+	}
+
+	return true
+}
+
+// run 运行一次定时任务，上一次运行尚未结束时跳过本次.
+func (s *Service) run(j *job) {
+	s.mu.Lock()
+
+	if j.ctx.Err() != nil {
+		s.mu.Unlock()
+		return
+	}
+
+	now := s.now()
+	s.scheduleNext(j, now)
+
+	if j.Running {
+		j.SkippedCount++
+		s.mu.Unlock()
+
+		return
+	}
+
+	j.Running = true
+	j.LastRunAt = now
+	s.mu.Unlock()
+
+	exitCode, err := s.execute(j)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j.Running = false
+	j.RunCount++
+	j.LastExitCode = exitCode
+	j.LastError = ""
+
+	if err != nil {
+		j.LastError = err.Error()
+	}
+}
+
+// execute 通过 Shell 服务执行命令，返回退出码.
+// 策略可能在创建后变化，需要审批的命令无人处理，不提交审批请求；被拒绝的命令由 Shell 服务记录到执行历史.
+func (s *Service) execute(j *job) (int, error) {
+	if err := s.shell.CheckCommand(j.SandboxID, j.Command); errors.Is(err, shellService.ErrApprovalRequired) {
+		return -1, err
+	}
+
+	result, err := s.shell.Execute(j.ctx, &shellService.ExecuteRequest{
+		SandboxID:   j.SandboxID,
+		Command:     j.Command,
+		CallerKeyID: j.callerKeyID,
+	})
+	if result == nil {
+		return -1, err
+	}
+
+	return result.ExitCode, err
+}
+
+// stop 停止定时器并终止正在运行的命令.
+func (j *job) stop() {
+	j.cancel()
+
+	if j.timer != nil {
+		j.timer.Stop()
+	}
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	shellService "github.com/HJH0924/agent-sandbox/domain/shell/service"
+	"github.com/HJH0924/agent-sandbox/internal/history"
+	"github.com/HJH0924/agent-sandbox/internal/policy"
+)
+
+func newTestService(t *testing.T) (*Service, *shellService.Service) {
+	t.Helper()
+
+	commandPolicy, err := policy.New([]policy.Rule{
+		{Name: "no-curl", Action: policy.ActionDeny, Prefix: []string{"curl"}},
+		{Name: "review-rm", Action: policy.ActionApprove, Prefix: []string{"rm"}},
+	}, policy.ActionAllow)
+	if err != nil {
+		t.Fatalf("policy.New failed: %v", err)
+	}
+
+	shell := shellService.NewService(10, t.TempDir(),
+		shellService.WithPolicy(commandPolicy),
+		shellService.WithHistory(history.NewStore(10, true)),
+	)
+
+	return NewService(shell), shell
+}
+
+// runNow 立即运行一次定时任务，不等待定时器.
+func runNow(t *testing.T, s *Service, scheduleID string) {
+	t.Helper()
+
+	s.mu.Lock()
+	j, ok := s.jobs[scheduleID]
+	s.mu.Unlock()
+
+	if !ok {
+		t.Fatalf("Schedule %s not found", scheduleID)
+	}
+
+	s.run(j)
+}
+
+func TestCreateSchedule_Validation(t *testing.T) {
+	service, _ := newTestService(t)
+
+	tests := []struct {
+		name string
+		req  *CreateRequest
+		want error
+	}{
+		{name: "empty command", req: &CreateRequest{Interval: time.Minute}, want: ErrInvalidSchedule},
+		{name: "no trigger", req: &CreateRequest{Command: "date"}, want: ErrInvalidSchedule},
+		{name: "both triggers", req: &CreateRequest{Command: "date", Cron: "@hourly", Interval: time.Minute}, want: ErrInvalidSchedule},
+		{name: "interval too short", req: &CreateRequest{Command: "date", Interval: time.Second}, want: ErrInvalidSchedule},
+		{name: "invalid cron", req: &CreateRequest{Command: "date", Cron: "* * *"}, want: ErrInvalidSchedule},
+		{name: "never fires", req: &CreateRequest{Command: "date", Cron: "0 0 30 2 *"}, want: ErrInvalidSchedule},
+		{name: "denied", req: &CreateRequest{Command: "curl example.com", Interval: time.Minute}, want: shellService.ErrCommandDenied},
+		{name: "approval", req: &CreateRequest{Command: "rm -rf build", Interval: time.Minute}, want: shellService.ErrApprovalRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.SandboxID = "sandbox-1"
+
+			if _, err := service.CreateSchedule(tt.req); !errors.Is(err, tt.want) {
+				t.Fatalf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if schedules := service.ListSchedules("sandbox-1"); len(schedules) != 0 {
+		t.Fatalf("Expected no schedules, got %d", len(schedules))
+	}
+}
+
+func TestSchedule_Run(t *testing.T) {
+	service, shell := newTestService(t)
+
+	schedule, err := service.CreateSchedule(&CreateRequest{
+		SandboxID:   "sandbox-1",
+		Command:     "echo tick; exit 3",
+		Interval:    time.Hour,
+		CallerKeyID: "key-1",
+	})
+	if err != nil {
+		t.Fatalf("CreateSchedule failed: %v", err)
+	}
+	defer func() {
+		_ = service.DeleteSchedule("sandbox-1", schedule.ID)
+	}()
+
+	if schedule.LastExitCode != -1 || !schedule.LastRunAt.IsZero() {
+		t.Fatalf("Expected a schedule that never ran, got %+v", schedule)
+	}
+
+	if got := schedule.NextRunAt.Sub(schedule.CreatedAt); got != time.Hour {
+		t.Fatalf("Expected next run after 1h, got %s", got)
+	}
+
+	runNow(t, service, schedule.ID)
+
+	schedules := service.ListSchedules("sandbox-1")
+	if len(schedules) != 1 {
+		t.Fatalf("Expected 1 schedule, got %d", len(schedules))
+	}
+
+	got := schedules[0]
+	if got.RunCount != 1 || got.LastExitCode != 3 || got.LastRunAt.IsZero() || got.Running {
+		t.Fatalf("Unexpected schedule after run: %+v", got)
+	}
+
+	// 每次运行记录在执行历史中
+	page, err := shell.ListExecutions("sandbox-1", 10, "", true)
+	if err != nil {
+		t.Fatalf("ListExecutions failed: %v", err)
+	}
+
+	if len(page.Entries) != 1 {
+		t.Fatalf("Expected 1 execution, got %d", len(page.Entries))
+	}
+
+	entry := page.Entries[0]
+	if entry.Command != "echo tick; exit 3" || entry.ExitCode != 3 || entry.CallerKeyID != "key-1" || entry.Output != "tick\n" {
+		t.Fatalf("Unexpected execution: %+v", entry)
+	}
+}
+
+func TestSchedule_RunApprovalRequired(t *testing.T) {
+	service, shell := newTestService(t)
+
+	schedule, err := service.CreateSchedule(&CreateRequest{SandboxID: "sandbox-1", Command: "date", Interval: time.Hour})
+	if err != nil {
+		t.Fatalf("CreateSchedule failed: %v", err)
+	}
+	defer func() {
+		_ = service.DeleteSchedule("sandbox-1", schedule.ID)
+	}()
+
+	// 创建后命令变为需要审批，运行时不提交审批请求
+	service.mu.Lock()
+	service.jobs[schedule.ID].Command = "rm -rf build"
+	service.mu.Unlock()
+
+	runNow(t, service, schedule.ID)
+
+	got := service.ListSchedules("sandbox-1")[0]
+	if got.LastExitCode != -1 || got.LastError == "" {
+		t.Fatalf("Expected the run to be refused, got %+v", got)
+	}
+
+	page, err := shell.ListExecutions("sandbox-1", 10, "", false)
+	if err != nil {
+		t.Fatalf("ListExecutions failed: %v", err)
+	}
+
+	if len(page.Entries) != 0 {
+		t.Fatalf("Expected no executions, got %d", len(page.Entries))
+	}
+}
+
+func TestDeleteSchedule(t *testing.T) {
+	service, _ := newTestService(t)
+
+	schedule, err := service.CreateSchedule(&CreateRequest{SandboxID: "sandbox-1", Command: "sleep 30", Cron: "@daily"})
+	if err != nil {
+		t.Fatalf("CreateSchedule failed: %v", err)
+	}
+
+	if err := service.DeleteSchedule("sandbox-2", schedule.ID); !errors.Is(err, ErrScheduleNotFound) {
+		t.Fatalf("Expected ErrScheduleNotFound for another sandbox, got %v", err)
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		runNow(t, service, schedule.ID)
+	}()
+
+	// 删除时终止正在运行的命令
+	waitRunning(t, service, "sandbox-1")
+
+	if err := service.DeleteSchedule("sandbox-1", schedule.ID); err != nil {
+		t.Fatalf("DeleteSchedule failed: %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Running command was not stopped")
+	}
+
+	if err := service.DeleteSchedule("sandbox-1", schedule.ID); !errors.Is(err, ErrScheduleNotFound) {
+		t.Fatalf("Expected ErrScheduleNotFound, got %v", err)
+	}
+}
+
+func TestRemoveSandbox(t *testing.T) {
+	service, _ := newTestService(t)
+
+	for _, sandboxID := range []string{"sandbox-1", "sandbox-1", "sandbox-2"} {
+		if _, err := service.CreateSchedule(&CreateRequest{SandboxID: sandboxID, Command: "date", Interval: time.Hour}); err != nil {
+			t.Fatalf("CreateSchedule failed: %v", err)
+		}
+	}
+
+	service.RemoveSandbox("sandbox-1")
+
+	if n := len(service.ListSchedules("sandbox-1")); n != 0 {
+		t.Fatalf("Expected no schedules for sandbox-1, got %d", n)
+	}
+
+	remaining := service.ListSchedules("sandbox-2")
+	if len(remaining) != 1 {
+		t.Fatalf("Expected 1 schedule for sandbox-2, got %d", len(remaining))
+	}
+
+	service.RemoveSandbox("sandbox-2")
+}
+
+func TestCreateSchedule_Limit(t *testing.T) {
+	service, _ := newTestService(t)
+	defer service.RemoveSandbox("sandbox-1")
+
+	for range maxSchedulesPerSandbox {
+		if _, err := service.CreateSchedule(&CreateRequest{SandboxID: "sandbox-1", Command: "date", Interval: time.Hour}); err != nil {
+			t.Fatalf("CreateSchedule failed: %v", err)
+		}
+	}
+
+	if _, err := service.CreateSchedule(&CreateRequest{SandboxID: "sandbox-1", Command: "date", Interval: time.Hour}); !errors.Is(err, ErrTooManySchedules) {
+		t.Fatalf("Expected ErrTooManySchedules, got %v", err)
+	}
+}
+
+// waitRunning 等待沙箱的定时任务开始运行.
+func waitRunning(t *testing.T, s *Service, sandboxID string) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for ctx.Err() == nil {
+		for _, schedule := range s.ListSchedules(sandboxID) {
+			if schedule.Running {
+				return
+			}
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatal("Schedule did not start running")
+}
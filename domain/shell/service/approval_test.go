@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HJH0924/agent-sandbox/internal/approval"
+	"github.com/HJH0924/agent-sandbox/internal/policy"
+)
+
+// newApprovalService 创建 echo 命令需要审批的服务.
+func newApprovalService(t *testing.T) (*Service, *approval.Queue) {
+	t.Helper()
+
+	commandPolicy, err := policy.New([]policy.Rule{
+		{Name: "echo", Action: policy.ActionApprove, Prefix: []string{"echo"}},
+	}, policy.ActionAllow)
+	if err != nil {
+		t.Fatalf("Failed to create policy: %v", err)
+	}
+
+	approvals := approval.NewQueue(time.Minute)
+
+	return NewService(10, t.TempDir(), WithPolicy(commandPolicy), WithApprovals(approvals)), approvals
+}
+
+// decideFirst 等待第一个审批请求出现并处理它.
+func decideFirst(t *testing.T, approvals *approval.Queue, decide func(id string) error) {
+	t.Helper()
+
+	for range 100 {
+		if pending := approvals.Pending(); len(pending) > 0 {
+			if err := decide(pending[0].ID); err != nil {
+				t.Errorf("Failed to decide approval: %v", err)
+			}
+
+			return
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Error("No approval was submitted")
+}
+
+func TestShellService_ApprovalBlocking(t *testing.T) {
+	service, approvals := newApprovalService(t)
+
+	go decideFirst(t, approvals, approvals.Approve)
+
+	result, err := service.Execute(context.Background(), &ExecuteRequest{SandboxID: "sandbox-1", Command: "echo approved"})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if result.Output != "approved\n" || result.ApprovalID == "" {
+		t.Fatalf("Expected approved command to run, got %+v", result)
+	}
+
+	go decideFirst(t, approvals, func(id string) error {
+		return approvals.Reject(id, "too risky")
+	})
+
+	_, err = service.Execute(context.Background(), &ExecuteRequest{SandboxID: "sandbox-1", Command: "echo rejected"})
+	if !errors.Is(err, approval.ErrRejected) {
+		t.Fatalf("Expected ErrRejected, got %v", err)
+	}
+}
+
+func TestShellService_ApprovalPolling(t *testing.T) {
+	service, approvals := newApprovalService(t)
+
+	req := &ExecuteRequest{SandboxID: "sandbox-1", Command: "echo polled", PollApproval: true}
+
+	result, err := service.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if !result.ApprovalPending || result.ApprovalID == "" || result.Output != "" {
+		t.Fatalf("Expected pending approval, got %+v", result)
+	}
+
+	req.ApprovalID = result.ApprovalID
+
+	// 仍在等待审批
+	result, err = service.Execute(context.Background(), req)
+	if err != nil || !result.ApprovalPending {
+		t.Fatalf("Expected approval to still be pending, got %+v %v", result, err)
+	}
+
+	// 审批请求不能用于其他命令或其他沙箱
+	if _, err := service.Execute(context.Background(), &ExecuteRequest{
+		SandboxID: "sandbox-1", Command: "echo other", ApprovalID: req.ApprovalID,
+	}); !errors.Is(err, ErrApprovalMismatch) {
+		t.Fatalf("Expected ErrApprovalMismatch, got %v", err)
+	}
+
+	if _, err := service.Execute(context.Background(), &ExecuteRequest{
+		SandboxID: "sandbox-2", Command: req.Command, ApprovalID: req.ApprovalID,
+	}); !errors.Is(err, approval.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound, got %v", err)
+	}
+
+	if err := approvals.Approve(req.ApprovalID); err != nil {
+		t.Fatalf("Approve failed: %v", err)
+	}
+
+	result, err = service.Execute(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if result.ApprovalPending || result.Output != "polled\n" {
+		t.Fatalf("Expected approved command to run, got %+v", result)
+	}
+
+	// 批准只能使用一次
+	if _, err := service.Execute(context.Background(), req); !errors.Is(err, approval.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound reusing approval, got %v", err)
+	}
+}
+
+func TestShellService_ApprovalSession(t *testing.T) {
+	service, approvals := newApprovalService(t)
+
+	session, err := service.CreateSession("sandbox-1")
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	defer func() {
+		_ = service.CloseSession("sandbox-1", session.ID)
+	}()
+
+	go decideFirst(t, approvals, approvals.Approve)
+
+	result, err := service.ExecuteInSession(context.Background(), "sandbox-1", session.ID, "echo session")
+	if err != nil {
+		t.Fatalf("ExecuteInSession failed: %v", err)
+	}
+
+	if result.Output != "session\n" {
+		t.Fatalf("Expected session output, got %q", result.Output)
+	}
+}
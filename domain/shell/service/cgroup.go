@@ -0,0 +1,81 @@
+package service
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Cgroup 为每个沙箱创建 cgroup v2 子组，限制沙箱内所有命令的内存和进程数.
+type Cgroup struct {
+	root      string
+	memoryMax int64
+	pidsMax   int64
+
+	mu      sync.Mutex
+	created map[string]bool
+}
+
+// cgroupEvents cgroup 中记录的限制触发次数.
+type cgroupEvents struct {
+	oomKills int64
+	pidsMax  int64
+}
+
+// dir 返回沙箱对应的 cgroup 目录.
+func (c *Cgroup) dir(sandboxID string) string {
+	if sandboxID == "" {
+		sandboxID = "default"
+	}
+
+	// 沙箱 ID 为 UUID，这里只做防御性处理避免路径穿越
+	return filepath.Join(c.root, filepath.Base(sandboxID))
+}
+
+// Remove 删除沙箱的 cgroup，cgroup 中仍有进程时会失败.
+func (c *Cgroup) Remove(sandboxID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.created, sandboxID)
+
+	if err := os.Remove(c.dir(sandboxID)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// readCgroupEvents 读取 memory.events 和 pids.events 中的计数，读取失败时返回零值.
+func readCgroupEvents(dir string) cgroupEvents {
+	return cgroupEvents{
+		oomKills: readEventCounter(filepath.Join(dir, "memory.events"), "oom_kill"),
+		pidsMax:  readEventCounter(filepath.Join(dir, "pids.events"), "max"),
+	}
+}
+
+// readEventCounter 读取 cgroup 事件文件中指定键的计数.
+func readEventCounter(path, key string) int64 {
+	f, err := os.Open(path) // #nosec G304 -- path is inside the configured cgroup root
+	if err != nil {
+		return 0
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == key {
+			value, _ := strconv.ParseInt(fields[1], 10, 64)
+			return value
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// cgroupKillTimeout 等待 cgroup 中的进程退出的最长时间.
+	cgroupKillTimeout = 5 * time.Second
+	// cgroupKillInterval 检查 cgroup 中的进程是否退出的间隔.
+	cgroupKillInterval = 10 * time.Millisecond
+)
+
+// NewCgroup 在 root 下创建沙箱 cgroup 的父组并启用 memory 和 pids 控制器.
+// root 必须位于可写的 cgroup v2 层级中，memoryMax/pidsMax 为 0 表示不限制.
+func NewCgroup(root string, memoryMax, pidsMax int64) (*Cgroup, error) {
+	parent := filepath.Dir(root)
+
+	var stat unix.Statfs_t
+	if err := unix.Statfs(parent, &stat); err != nil {
+		return nil, fmt.Errorf("failed to stat cgroup parent %s: %w", parent, err)
+	}
+
+	if stat.Type != unix.CGROUP2_SUPER_MAGIC {
+		return nil, fmt.Errorf("%s is not a cgroup v2 hierarchy", parent)
+	}
+
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create cgroup %s: %w", root, err)
+	}
+
+	// 父组和沙箱根组都需要向下启用控制器
+	for _, dir := range []string{parent, root} {
+		if err := writeCgroupFile(dir, "cgroup.subtree_control", "+memory +pids"); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Cgroup{
+		root:      root,
+		memoryMax: memoryMax,
+		pidsMax:   pidsMax,
+		created:   make(map[string]bool),
+	}, nil
+}
+
+// attach 让命令直接在沙箱的 cgroup 中启动，返回 cgroup 目录和启动后需要调用的释放函数.
+func (c *Cgroup) attach(cmd *exec.Cmd, sandboxID string) (string, func(), error) {
+	dir, err := c.ensure(sandboxID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	fd, err := unix.Open(dir, unix.O_DIRECTORY|unix.O_RDONLY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to open cgroup %s: %w", dir, err)
+	}
+
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+
+	cmd.SysProcAttr.UseCgroupFD = true
+	cmd.SysProcAttr.CgroupFD = fd
+
+	return dir, func() { _ = unix.Close(fd) }, nil
+}
+
+// ensure 创建沙箱的 cgroup 并写入限制.
+func (c *Cgroup) ensure(sandboxID string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	dir := c.dir(sandboxID)
+	if c.created[sandboxID] {
+		return dir, nil
+	}
+
+	if err := os.Mkdir(dir, 0o750); err != nil && !errors.Is(err, os.ErrExist) {
+		return "", fmt.Errorf("failed to create cgroup %s: %w", dir, err)
+	}
+
+	if c.memoryMax > 0 {
+		if err := writeCgroupFile(dir, "memory.max", strconv.FormatInt(c.memoryMax, 10)); err != nil {
+			return "", err
+		}
+	}
+
+	if c.pidsMax > 0 {
+		if err := writeCgroupFile(dir, "pids.max", strconv.FormatInt(c.pidsMax, 10)); err != nil {
+			return "", err
+		}
+	}
+
+	c.created[sandboxID] = true
+
+	return dir, nil
+}
+
+// Kill 结束沙箱 cgroup 中的所有进程并等待 cgroup 变空，cgroup 不存在时直接返回.
+func (c *Cgroup) Kill(sandboxID string) error {
+	dir := c.dir(sandboxID)
+
+	// cgroup.kill 需要 Linux 5.14，不支持时由下面的循环逐个结束 cgroup.procs 中的进程
+	_ = writeCgroupFile(dir, "cgroup.kill", "1")
+
+	deadline := time.Now().Add(cgroupKillTimeout)
+
+	for {
+		pids, err := readCgroupProcs(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+
+			return err
+		}
+
+		if len(pids) == 0 {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("processes in cgroup %s did not exit", dir)
+		}
+
+		for _, pid := range pids {
+			_ = unix.Kill(pid, unix.SIGKILL)
+		}
+
+		time.Sleep(cgroupKillInterval)
+	}
+}
+
+// readCgroupProcs 读取 cgroup 中的进程.
+func readCgroupProcs(dir string) ([]int, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "cgroup.procs")) // #nosec G304 -- path is inside the configured cgroup root
+	if err != nil {
+		return nil, err
+	}
+
+	var pids []int
+
+	for _, field := range strings.Fields(string(data)) {
+		if pid, err := strconv.Atoi(field); err == nil {
+			pids = append(pids, pid)
+		}
+	}
+
+	return pids, nil
+}
+
+// writeCgroupFile 写入 cgroup 控制文件.
+func writeCgroupFile(dir, name, value string) error {
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(value), 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+//go:build !linux
+
+package service
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// errCgroupUnsupported 非 Linux 平台不支持 cgroup.
+var errCgroupUnsupported = errors.New("cgroups are only supported on Linux")
+
+// NewCgroup 在非 Linux 平台上始终返回错误.
+func NewCgroup(_ string, _, _ int64) (*Cgroup, error) {
+	return nil, errCgroupUnsupported
+}
+
+// attach 在非 Linux 平台上始终返回错误.
+func (c *Cgroup) attach(_ *exec.Cmd, _ string) (string, func(), error) {
+	return "", nil, errCgroupUnsupported
+}
+
+// Kill 在非 Linux 平台上始终返回错误.
+func (c *Cgroup) Kill(_ string) error {
+	return errCgroupUnsupported
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+var (
+	// ErrInvalidCommand 执行请求同时指定了 command 和 argv.
+	ErrInvalidCommand = errors.New("command and argv are mutually exclusive")
+	// ErrProgramNotFound argv 模式下找不到要执行的程序.
+	ErrProgramNotFound = errors.New("program not found")
+)
+
+// Shell 执行命令字符串使用的 shell.
+type Shell struct {
+	// Path shell 程序，如 sh、bash 或绝对路径
+	Path string
+	// Login 以登录 shell 方式启动（-l），会先加载 profile
+	Login bool
+}
+
+// defaultShell 默认使用的 shell.
+var defaultShell = Shell{Path: "sh"}
+
+// WithShell 设置执行命令字符串和终端使用的 shell，Path 为空时使用 sh.
+func WithShell(shell Shell) Option {
+	return func(s *Service) {
+		if shell.Path != "" {
+			s.shell = shell
+		}
+	}
+}
+
+// args 返回执行 command 时传给 shell 的参数，command 为空时启动交互式 shell.
+func (sh Shell) args(command string) []string {
+	var args []string
+	if sh.Login {
+		args = append(args, "-l")
+	}
+
+	if command != "" {
+		args = append(args, "-c", command)
+	}
+
+	return args
+}
+
+// commandLine 返回用于策略判定、审批和会话执行的命令字符串，argv 模式下为转义后的参数.
+func (req *ExecuteRequest) commandLine() string {
+	if len(req.Argv) > 0 {
+		return quoteArgv(req.Argv)
+	}
+
+	return req.Command
+}
+
+// newCommand 创建执行请求对应的命令：argv 模式直接执行程序，否则通过 shell 执行命令字符串.
+// 配置了 profile 时 argv 模式也通过 shell 加载 profile 后 exec 程序，参数不经过 shell 解析.
+func (s *Service) newCommand(ctx context.Context, req *ExecuteRequest) (*exec.Cmd, error) {
+	if len(req.Argv) == 0 {
+		return exec.CommandContext(ctx, s.shell.Path, s.shellArgs(req.Command)...), nil
+	}
+
+	// profile 可能修改 PATH，程序是否存在由 shell 判断（不存在时退出码为 127）
+	if profile := s.profile(); profile != "" {
+		args := append([]string{"-c", loadProfile(profile) + `exec "$0" "$@"`}, req.Argv...)
+		return exec.CommandContext(ctx, s.shell.Path, args...), nil
+	}
+
+	path, err := s.lookPath(req.Argv[0])
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, path, req.Argv[1:]...) // #nosec G204 -- executing the requested program is the purpose of argv mode
+	if errors.Is(cmd.Err, exec.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrProgramNotFound, req.Argv[0])
+	}
+
+	cmd.Args[0] = req.Argv[0]
+
+	return cmd, nil
+}
+
+// quoteArgv 将参数转义为等价的 shell 命令字符串.
+func quoteArgv(argv []string) string {
+	quoted := make([]string, 0, len(argv))
+	for _, arg := range argv {
+		quoted = append(quoted, quoteArg(arg))
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+// quoteArg 在需要时用单引号转义参数.
+func quoteArg(arg string) string {
+	if arg != "" && strings.IndexFunc(arg, needsQuote) < 0 {
+		return arg
+	}
+
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// needsQuote 检查字符在 shell 中是否需要转义.
+func needsQuote(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return false
+	default:
+		return !strings.ContainsRune("-_./=:,+@%", r)
+	}
+}
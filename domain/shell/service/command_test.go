@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/HJH0924/agent-sandbox/internal/policy"
+)
+
+func TestShellService_Argv(t *testing.T) {
+	workspace := t.TempDir()
+	service := NewService(10, workspace)
+
+	// 参数原样传递，不经过 shell 展开
+	name := `it's a "$HOME" file.txt`
+
+	result, err := service.Execute(context.Background(), &ExecuteRequest{
+		Argv: []string{"touch", name},
+	})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if result.ExitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d", result.ExitCode)
+	}
+
+	if _, err := os.Stat(filepath.Join(workspace, name)); err != nil {
+		t.Fatalf("Expected file %q to exist: %v", name, err)
+	}
+
+	result, err = service.Execute(context.Background(), &ExecuteRequest{
+		Argv: []string{"printf", "%s|", "a b", "$PATH", "*"},
+	})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if result.Output != "a b|$PATH|*|" {
+		t.Fatalf("Expected arguments to be passed verbatim, got %q", result.Output)
+	}
+}
+
+func TestShellService_ArgvErrors(t *testing.T) {
+	service := NewService(10, t.TempDir())
+
+	_, err := service.Execute(context.Background(), &ExecuteRequest{Command: "ls", Argv: []string{"ls"}})
+	if !errors.Is(err, ErrInvalidCommand) {
+		t.Fatalf("Expected ErrInvalidCommand, got %v", err)
+	}
+
+	_, err = service.Execute(context.Background(), &ExecuteRequest{Argv: []string{"agent-sandbox-no-such-program"}})
+	if !errors.Is(err, ErrProgramNotFound) {
+		t.Fatalf("Expected ErrProgramNotFound, got %v", err)
+	}
+}
+
+func TestShellService_ArgvPolicy(t *testing.T) {
+	commandPolicy, err := policy.New([]policy.Rule{
+		{Name: "rm-root", Action: policy.ActionDeny, Prefix: []string{"rm", "-rf", "/"}},
+	}, policy.ActionAllow)
+	if err != nil {
+		t.Fatalf("Failed to create policy: %v", err)
+	}
+
+	service := NewService(10, t.TempDir(), WithPolicy(commandPolicy))
+
+	_, err = service.Execute(context.Background(), &ExecuteRequest{Argv: []string{"/bin/rm", "-rf", "/"}})
+	if !errors.Is(err, ErrCommandDenied) {
+		t.Fatalf("Expected ErrCommandDenied, got %v", err)
+	}
+}
+
+func TestShellService_ArgvSession(t *testing.T) {
+	service := NewService(10, t.TempDir())
+
+	session, err := service.CreateSession("sandbox-1")
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	defer func() {
+		_ = service.CloseSession("sandbox-1", session.ID)
+	}()
+
+	result, err := service.Execute(context.Background(), &ExecuteRequest{
+		SandboxID: "sandbox-1",
+		SessionID: session.ID,
+		Argv:      []string{"printf", "%s|", "it's", "$HOME", "", "a;b"},
+	})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if result.Output != "it's|$HOME||a;b|" {
+		t.Fatalf("Expected arguments to be passed verbatim, got %q", result.Output)
+	}
+}
+
+func TestShellService_Shell(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not available")
+	}
+
+	service := NewService(10, t.TempDir(), WithShell(Shell{Path: bash}))
+
+	result, err := service.Execute(context.Background(), &ExecuteRequest{Command: "echo ${BASH_VERSION:+bash}"})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if result.Output != "bash\n" {
+		t.Fatalf("Expected command to run in bash, got %q", result.Output)
+	}
+
+	// 登录 shell
+	service = NewService(10, t.TempDir(), WithShell(Shell{Path: bash, Login: true}))
+
+	result, err = service.Execute(context.Background(), &ExecuteRequest{Command: "shopt -q login_shell && echo login"})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	// profile 可能输出额外内容
+	if !strings.HasPrefix(result.Output, "login\n") {
+		t.Fatalf("Expected login shell, got %q", result.Output)
+	}
+
+	// 持久会话同样使用配置的 shell
+	session, err := service.CreateSession("")
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	defer func() {
+		_ = service.CloseSession("", session.ID)
+	}()
+
+	result, err = service.Execute(context.Background(), &ExecuteRequest{SessionID: session.ID, Command: "shopt -q login_shell && echo login"})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	// 启动时 profile 的输出不混入命令的输出
+	if result.Output != "login\n" {
+		t.Fatalf("Expected session in login bash, got %q", result.Output)
+	}
+}
+
+func TestQuoteArgv(t *testing.T) {
+	tests := []struct {
+		argv []string
+		want string
+	}{
+		{[]string{"ls", "-la", "/tmp"}, "ls -la /tmp"},
+		{[]string{"echo", "a b", ""}, "echo 'a b' ''"},
+		{[]string{"echo", "it's"}, `echo 'it'\''s'`},
+		{[]string{"echo", "$HOME", "a;b"}, "echo '$HOME' 'a;b'"},
+	}
+
+	for _, tt := range tests {
+		if got := quoteArgv(tt.argv); got != tt.want {
+			t.Fatalf("quoteArgv(%q) = %q, want %q", tt.argv, got, tt.want)
+		}
+
+		// 策略按与 shell 相同的方式解析转义后的命令
+		if got := policy.Split(quoteArgv(tt.argv)); len(got) != 1 || len(got[0]) != len(tt.argv) {
+			t.Fatalf("Expected %q to split back into %q, got %q", quoteArgv(tt.argv), tt.argv, got)
+		}
+	}
+}
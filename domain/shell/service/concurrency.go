@@ -0,0 +1,152 @@
+package service
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrQueueFull 并发执行数已达上限且等待队列已满.
+var ErrQueueFull = errors.New("execution queue is full")
+
+// Concurrency 命令并发执行限制，0 表示不限制.
+type Concurrency struct {
+	// MaxExecutions 整个服务同时执行的命令数
+	MaxExecutions int
+	// MaxPerSandbox 每个沙箱同时执行的命令数
+	MaxPerSandbox int
+	// QueueSize 等待执行的命令数上限，超出时直接拒绝；0 表示不排队
+	QueueSize int
+}
+
+// WithConcurrency 限制同时执行的命令数，超出的命令按先进先出顺序排队.
+func WithConcurrency(c Concurrency) Option {
+	return func(s *Service) {
+		if c.MaxExecutions > 0 || c.MaxPerSandbox > 0 {
+			s.limiter = newLimiter(c)
+		}
+	}
+}
+
+// AcquireSlot 按并发限制占用一个执行名额，名额不足时排队等待，返回释放函数和排队时间.
+// 用于不经过 Execute 运行的代码（如内核单元）.
+func (s *Service) AcquireSlot(ctx context.Context, sandboxID string) (func(), time.Duration, error) {
+	return s.limiter.acquire(ctx, sandboxID)
+}
+
+// limiter 命令并发限制器.
+type limiter struct {
+	mu         sync.Mutex
+	cfg        Concurrency
+	running    int
+	perSandbox map[string]int
+	waiters    *list.List
+}
+
+// waiter 排队等待执行的命令.
+type waiter struct {
+	sandboxID string
+	ready     chan struct{}
+}
+
+// newLimiter 创建并发限制器.
+func newLimiter(c Concurrency) *limiter {
+	return &limiter{
+		cfg:        c,
+		perSandbox: make(map[string]int),
+		waiters:    list.New(),
+	}
+}
+
+// acquire 占用一个执行名额，名额不足时排队等待，返回释放函数和排队时间.
+func (l *limiter) acquire(ctx context.Context, sandboxID string) (func(), time.Duration, error) {
+	if l == nil {
+		return func() {}, 0, nil
+	}
+
+	l.mu.Lock()
+
+	// 队列中剩下的都是暂时无法执行的命令，能立即执行说明没有插队
+	if l.available(sandboxID) {
+		l.take(sandboxID)
+		l.mu.Unlock()
+
+		return func() { l.release(sandboxID) }, 0, nil
+	}
+
+	if l.waiters.Len() >= l.cfg.QueueSize {
+		l.mu.Unlock()
+		return nil, 0, ErrQueueFull
+	}
+
+	w := &waiter{sandboxID: sandboxID, ready: make(chan struct{})}
+	elem := l.waiters.PushBack(w)
+	l.mu.Unlock()
+
+	start := time.Now()
+
+	select {
+	case <-w.ready:
+		return func() { l.release(sandboxID) }, time.Since(start), nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		select {
+		case <-w.ready:
+			// 取消的同时获得了名额，交还给其他等待者
+			l.put(sandboxID)
+		default:
+			l.waiters.Remove(elem)
+		}
+
+		return nil, 0, ctx.Err()
+	}
+}
+
+// release 释放执行名额.
+func (l *limiter) release(sandboxID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.put(sandboxID)
+}
+
+// available 检查沙箱是否还有执行名额.
+func (l *limiter) available(sandboxID string) bool {
+	if l.cfg.MaxExecutions > 0 && l.running >= l.cfg.MaxExecutions {
+		return false
+	}
+
+	return l.cfg.MaxPerSandbox <= 0 || l.perSandbox[sandboxID] < l.cfg.MaxPerSandbox
+}
+
+// take 占用执行名额.
+func (l *limiter) take(sandboxID string) {
+	l.running++
+	l.perSandbox[sandboxID]++
+}
+
+// put 归还执行名额，并按排队顺序唤醒可以执行的命令.
+func (l *limiter) put(sandboxID string) {
+	l.running--
+
+	if l.perSandbox[sandboxID]--; l.perSandbox[sandboxID] <= 0 {
+		delete(l.perSandbox, sandboxID)
+	}
+
+	for elem := l.waiters.Front(); elem != nil; {
+		next := elem.Next()
+
+		w, _ := elem.Value.(*waiter)
+		if l.available(w.sandboxID) {
+			l.take(w.sandboxID)
+			l.waiters.Remove(elem)
+			close(w.ready)
+		}
+
+		elem = next
+	}
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// runAsync 在后台执行命令并返回结果通道.
+func runAsync(service *Service, sandboxID, command string) <-chan error {
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := service.Execute(context.Background(), &ExecuteRequest{SandboxID: sandboxID, Command: command})
+		done <- err
+	}()
+
+	return done
+}
+
+// waitQueued 等待队列中出现 n 个命令.
+func waitQueued(t *testing.T, service *Service, n int) {
+	t.Helper()
+
+	for range 200 {
+		service.limiter.mu.Lock()
+		queued := service.limiter.waiters.Len()
+		service.limiter.mu.Unlock()
+
+		if queued == n {
+			return
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	t.Fatalf("Expected %d queued commands", n)
+}
+
+// waitRunning 等待 n 个命令占用执行名额.
+func waitRunning(t *testing.T, service *Service, n int) {
+	t.Helper()
+
+	for range 200 {
+		service.limiter.mu.Lock()
+		running := service.limiter.running
+		service.limiter.mu.Unlock()
+
+		if running == n {
+			return
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	t.Fatalf("Expected %d running commands", n)
+}
+
+func TestShellService_ConcurrencyQueue(t *testing.T) {
+	service := NewService(10, t.TempDir(), WithConcurrency(Concurrency{MaxExecutions: 1, QueueSize: 1}))
+
+	first := runAsync(service, "sandbox-1", "sleep 0.3")
+	waitRunning(t, service, 1)
+
+	var (
+		second *ExecuteResult
+		err    error
+		wg     sync.WaitGroup
+	)
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		second, err = service.Execute(context.Background(), &ExecuteRequest{SandboxID: "sandbox-2", Command: "echo queued"})
+	}()
+
+	waitQueued(t, service, 1)
+
+	// 队列已满
+	if _, err := service.Execute(context.Background(), &ExecuteRequest{SandboxID: "sandbox-3", Command: "true"}); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("Expected ErrQueueFull, got %v", err)
+	}
+
+	if err := <-first; err != nil {
+		t.Fatalf("First command failed: %v", err)
+	}
+
+	wg.Wait()
+
+	if err != nil {
+		t.Fatalf("Queued command failed: %v", err)
+	}
+
+	if second.Output != "queued\n" || second.QueueWait <= 0 {
+		t.Fatalf("Expected queued command to run after waiting, got %+v", second)
+	}
+}
+
+func TestShellService_ConcurrencyPerSandbox(t *testing.T) {
+	service := NewService(10, t.TempDir(), WithConcurrency(Concurrency{MaxPerSandbox: 1, QueueSize: 10}))
+
+	first := runAsync(service, "sandbox-1", "sleep 0.3")
+	waitRunning(t, service, 1)
+
+	second := runAsync(service, "sandbox-1", "true")
+	waitQueued(t, service, 1)
+
+	// 其他沙箱不受影响
+	result, err := service.Execute(context.Background(), &ExecuteRequest{SandboxID: "sandbox-2", Command: "echo other"})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if result.QueueWait != 0 {
+		t.Fatalf("Expected other sandbox not to wait, got %s", result.QueueWait)
+	}
+
+	for _, done := range []<-chan error{first, second} {
+		if err := <-done; err != nil {
+			t.Fatalf("Command failed: %v", err)
+		}
+	}
+}
+
+func TestShellService_ConcurrencyCanceled(t *testing.T) {
+	service := NewService(10, t.TempDir(), WithConcurrency(Concurrency{MaxExecutions: 1, QueueSize: 10}))
+
+	first := runAsync(service, "sandbox-1", "sleep 0.3")
+	waitRunning(t, service, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := service.Execute(ctx, &ExecuteRequest{SandboxID: "sandbox-1", Command: "true"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected DeadlineExceeded, got %v", err)
+	}
+
+	// 取消的命令离开队列
+	waitQueued(t, service, 0)
+
+	if err := <-first; err != nil {
+		t.Fatalf("Command failed: %v", err)
+	}
+
+	if _, err := service.Execute(context.Background(), &ExecuteRequest{SandboxID: "sandbox-1", Command: "true"}); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+}
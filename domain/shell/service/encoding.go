@@ -0,0 +1,94 @@
+package service
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Encoding 命令输出的编码.
+type Encoding string
+
+const (
+	// EncodingUTF8 合法的 UTF-8 文本（包括 ASCII 和空输出）.
+	EncodingUTF8 Encoding = "utf-8"
+	// EncodingUnknown 非 UTF-8 编码的文本（如 Latin-1、GBK），无法可靠判断具体编码.
+	EncodingUnknown Encoding = "unknown"
+	// EncodingBinary 二进制数据（包含 NUL 或大量控制字符）.
+	EncodingBinary Encoding = "binary"
+)
+
+// binaryControlRatio 控制字符超过该比例（1/n）时视为二进制数据.
+const binaryControlRatio = 10
+
+// DetectEncoding 判断输出的编码.
+func DetectEncoding(data []byte) Encoding {
+	controls := 0
+
+	for _, c := range data {
+		if c == 0 {
+			return EncodingBinary
+		}
+
+		if isBinaryControl(c) {
+			controls++
+		}
+	}
+
+	if controls*binaryControlRatio > len(data) {
+		return EncodingBinary
+	}
+
+	if utf8.Valid(data) {
+		return EncodingUTF8
+	}
+
+	return EncodingUnknown
+}
+
+// isBinaryControl 判断字节是否为文本中很少出现的控制字符（制表、换行、回车、退格、换页和 ESC 除外）.
+func isBinaryControl(c byte) bool {
+	switch c {
+	case '\t', '\n', '\r', '\b', '\f', 0x1b:
+		return false
+	}
+
+	return c < 0x20 || c == 0x7f
+}
+
+// RenderText 返回输出的 UTF-8 文本形式和检测到的编码，无效的 UTF-8 字节替换为 U+FFFD.
+func RenderText(data []byte) (string, Encoding) {
+	encoding := DetectEncoding(data)
+	if encoding == EncodingUTF8 {
+		return string(data), encoding
+	}
+
+	return strings.ToValidUTF8(string(data), string(utf8.RuneError)), encoding
+}
+
+// trimRuneEnd 去掉结尾被截断的不完整 UTF-8 字符.
+func trimRuneEnd(p []byte) []byte {
+	for i := 1; i < utf8.UTFMax && i <= len(p); i++ {
+		c := p[len(p)-i]
+		if !utf8.RuneStart(c) {
+			continue
+		}
+
+		if c >= utf8.RuneSelf && !utf8.FullRune(p[len(p)-i:]) {
+			return p[:len(p)-i]
+		}
+
+		return p
+	}
+
+	return p
+}
+
+// trimRuneStart 去掉开头被截断的 UTF-8 字符的剩余字节.
+func trimRuneStart(p []byte) []byte {
+	n := 0
+	for n < utf8.UTFMax-1 && n < len(p) && !utf8.RuneStart(p[n]) {
+		n++
+	}
+
+	return p[n:]
+}
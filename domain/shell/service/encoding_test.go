@@ -0,0 +1,94 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestDetectEncoding(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected Encoding
+	}{
+		{"empty", "", EncodingUTF8},
+		{"ascii", "hello\n\tworld\r\n", EncodingUTF8},
+		{"utf-8", "你好，世界\n", EncodingUTF8},
+		{"ansi colors", "\x1b[31mred\x1b[0m\n", EncodingUTF8},
+		{"latin-1", "caf\xe9 cr\xe8me\n", EncodingUnknown},
+		{"gbk", "\xc4\xe3\xba\xc3\n", EncodingUnknown},
+		{"nul", "abc\x00def", EncodingBinary},
+		{"png header", "\x89PNG\r\n\x1a\n", EncodingBinary},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectEncoding([]byte(tt.data)); got != tt.expected {
+				t.Fatalf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRenderText(t *testing.T) {
+	text, encoding := RenderText([]byte("caf\xe9"))
+	if encoding != EncodingUnknown || text != "caf�" {
+		t.Fatalf("Expected lossy rendering, got %q (%s)", text, encoding)
+	}
+
+	text, encoding = RenderText([]byte("naïve"))
+	if encoding != EncodingUTF8 || text != "naïve" {
+		t.Fatalf("Expected UTF-8 to be unchanged, got %q (%s)", text, encoding)
+	}
+}
+
+func TestOutputBuffer_TruncatesOnRuneBoundary(t *testing.T) {
+	b, err := newOutputBuffer(10, "", "", nil)
+	if err != nil {
+		t.Fatalf("Failed to create buffer: %v", err)
+	}
+
+	// 每个汉字 3 个字节，开头和结尾各 5 个字节处都会切开一个字符
+	if _, err := b.Write([]byte("一二三四五六七八")); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+
+	got := b.Text()
+	if !utf8.Valid(got) {
+		t.Fatalf("Expected valid UTF-8, got %q", got)
+	}
+
+	expected := "一\n... [18 bytes truncated] ...\n八"
+	if string(got) != expected {
+		t.Fatalf("Expected %q, got %q", expected, got)
+	}
+
+	// 原始字节在任意位置截断，不丢弃被切开的字符
+	data := []byte("一二三四五六七八")
+	if raw := b.Bytes(); !bytes.Equal(raw, append(data[:5:5], data[len(data)-5:]...)) {
+		t.Fatalf("Expected raw head and tail bytes, got %q", raw)
+	}
+}
+
+func TestShellService_NonUTF8Output(t *testing.T) {
+	service := NewService(30, t.TempDir())
+
+	result, err := service.Execute(context.Background(), &ExecuteRequest{Command: `printf 'caf\351'; printf '\000\001\002' >&2`})
+	if err != nil {
+		t.Fatalf("Failed to execute command: %v", err)
+	}
+
+	if result.Stdout != "caf\xe9" || result.Stderr != "\x00\x01\x02" {
+		t.Fatalf("Expected raw bytes to be kept, got %q and %q", result.Stdout, result.Stderr)
+	}
+
+	if result.StdoutEncoding != EncodingUnknown || result.StderrEncoding != EncodingBinary {
+		t.Fatalf("Unexpected encodings: %s, %s", result.StdoutEncoding, result.StderrEncoding)
+	}
+
+	if !utf8.ValidString(result.Output) || result.Output != "caf�\n\x00\x01\x02" {
+		t.Fatalf("Expected UTF-8 text output, got %q", result.Output)
+	}
+}
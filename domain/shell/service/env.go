@@ -0,0 +1,145 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Environment 沙箱命令的基础环境. 设置后命令不再继承服务进程的环境变量（其中可能有服务端的凭据），
+// 只包含这里配置的变量、HOME（沙箱的工作空间）和沙箱的密钥.
+type Environment struct {
+	// Path 命令的 PATH，为空时使用服务进程的 PATH
+	Path string
+	// Lang LANG 的值（如 C.UTF-8），为空时不设置
+	Lang string
+	// TZ 时区（如 UTC），为空时不设置
+	TZ string
+	// Passthrough 从服务进程继承的环境变量名称（如 HTTP_PROXY）
+	Passthrough []string
+	// Vars 额外的环境变量（NAME=value），可以覆盖上面的变量
+	Vars []string
+	// Profile 在每个命令之前加载（.）的 shell 脚本，相对路径相对沙箱的工作空间，为空时不加载
+	Profile string
+}
+
+// WithEnvironment 设置命令的基础环境，替代服务进程的环境变量.
+func WithEnvironment(env Environment) Option {
+	return func(s *Service) {
+		s.env = &env
+	}
+}
+
+// environ 返回工作空间为 home 的沙箱命令的基础环境变量，未设置基础环境时为服务进程的环境变量.
+func (s *Service) environ(home string) []string {
+	if s.env == nil {
+		return os.Environ()
+	}
+
+	env := []string{"PATH=" + s.searchPath()}
+
+	if home != "" {
+		env = append(env, "HOME="+home)
+	}
+
+	if s.env.Lang != "" {
+		env = append(env, "LANG="+s.env.Lang)
+	}
+
+	if s.env.TZ != "" {
+		env = append(env, "TZ="+s.env.TZ)
+	}
+
+	for _, name := range s.env.Passthrough {
+		if value, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+value)
+		}
+	}
+
+	return append(env, s.env.Vars...)
+}
+
+// setEnv 将 env 中名为 key 的变量设置为 value，替换已有的所有同名变量，没有时追加.
+func setEnv(env []string, key, value string) []string {
+	result := make([]string, 0, len(env)+1)
+
+	for _, kv := range env {
+		if !strings.HasPrefix(kv, key+"=") {
+			result = append(result, kv)
+		}
+	}
+
+	return append(result, key+"="+value)
+}
+
+// searchPath 返回基础环境的 PATH，没有配置时为服务进程的 PATH. 调用方必须已设置基础环境.
+func (s *Service) searchPath() string {
+	if s.env.Path == "" {
+		return os.Getenv("PATH")
+	}
+
+	return s.env.Path
+}
+
+// profile 返回命令之前加载的脚本，没有配置时返回空字符串.
+func (s *Service) profile() string {
+	if s.env == nil {
+		return ""
+	}
+
+	return s.env.Profile
+}
+
+// loadProfile 返回加载 profile 的 shell 语句.
+func loadProfile(profile string) string {
+	return ". " + quoteArg(profilePath(profile)) + "\n"
+}
+
+// profilePath 返回 . 使用的 profile 路径. 不含 / 的文件名会在 PATH 中查找，因此加上 ./.
+func profilePath(profile string) string {
+	if strings.Contains(profile, "/") {
+		return profile
+	}
+
+	return "./" + profile
+}
+
+// shellArgs 返回通过 shell 执行 command 的参数，配置了 profile 时先加载 profile.
+// command 为空时启动交互式 shell：加载 profile 后用 exec 替换为交互式 shell，只保留 profile 导出的变量.
+func (s *Service) shellArgs(command string) []string {
+	profile := s.profile()
+
+	switch {
+	case profile == "":
+		return s.shell.args(command)
+	case command == "":
+		interactive := append([]string{s.shell.Path}, s.shell.args("")...)
+		return []string{"-c", loadProfile(profile) + "exec " + quoteArgv(append(interactive, "-i"))}
+	default:
+		return s.shell.args(loadProfile(profile) + command)
+	}
+}
+
+// lookPath 在基础环境的 PATH 中查找程序，未设置基础环境时使用服务进程的 PATH.
+// 包含路径分隔符的程序不查找.
+func (s *Service) lookPath(file string) (string, error) {
+	if s.env == nil || strings.Contains(file, "/") {
+		return file, nil
+	}
+
+	for _, dir := range filepath.SplitList(s.searchPath()) {
+		// 空目录表示当前目录，相对的是服务进程而不是命令的工作目录
+		if dir == "" {
+			continue
+		}
+
+		candidate := filepath.Join(dir, file)
+
+		if info, err := os.Stat(candidate); err == nil && info.Mode().IsRegular() && info.Mode()&0o111 != 0 {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrProgramNotFound, file)
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/HJH0924/agent-sandbox/internal/secret"
+)
+
+func TestExecute_Environment(t *testing.T) {
+	t.Setenv("AGENT_SANDBOX_TEST_SERVER_TOKEN", "server-only")
+	t.Setenv("AGENT_SANDBOX_TEST_PROXY", "http://proxy:3128")
+
+	workspace := t.TempDir()
+	store := secret.NewStore()
+	service := NewService(30, workspace, WithSecrets(store), WithEnvironment(Environment{
+		Lang:        "C.UTF-8",
+		TZ:          "UTC",
+		Passthrough: []string{"AGENT_SANDBOX_TEST_PROXY", "AGENT_SANDBOX_TEST_UNSET"},
+		Vars:        []string{"EDITOR=vi", "TZ=Asia/Shanghai"},
+	}))
+
+	if err := store.Set("sandbox-1", map[string]string{"API_TOKEN": "tok-123456"}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	for _, req := range []*ExecuteRequest{
+		{SandboxID: "sandbox-1", Command: "env"},
+		{SandboxID: "sandbox-1", Argv: []string{"env"}},
+	} {
+		result, err := service.Execute(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+
+		env := make(map[string]string)
+		for _, line := range strings.Split(result.Stdout, "\n") {
+			if name, value, ok := strings.Cut(line, "="); ok {
+				env[name] = value
+			}
+		}
+
+		// 服务进程的环境变量不被继承
+		if _, ok := env["AGENT_SANDBOX_TEST_SERVER_TOKEN"]; ok {
+			t.Fatalf("Expected server environment not to be inherited, got %q", result.Stdout)
+		}
+
+		want := map[string]string{
+			"HOME":                     workspace,
+			"PATH":                     os.Getenv("PATH"),
+			"LANG":                     "C.UTF-8",
+			"TZ":                       "Asia/Shanghai",
+			"EDITOR":                   "vi",
+			"AGENT_SANDBOX_TEST_PROXY": "http://proxy:3128",
+			"API_TOKEN":                "[REDACTED:API_TOKEN]",
+		}
+
+		for name, value := range want {
+			if env[name] != value {
+				t.Errorf("Expected %s=%s, got %q", name, value, env[name])
+			}
+		}
+
+		if _, ok := env["AGENT_SANDBOX_TEST_UNSET"]; ok {
+			t.Errorf("Expected unset passthrough variable to be omitted")
+		}
+	}
+}
+
+func TestExecute_EnvironmentPath(t *testing.T) {
+	bin := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(bin, "hello"), []byte("#!/bin/sh\necho hello from bin\n"), 0o700); err != nil {
+		t.Fatalf("Failed to create program: %v", err)
+	}
+
+	service := NewService(30, t.TempDir(), WithEnvironment(Environment{Path: bin + ":" + os.Getenv("PATH")}))
+
+	// argv 模式的程序在基础环境的 PATH 中查找
+	result, err := service.Execute(context.Background(), &ExecuteRequest{Argv: []string{"hello"}})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if result.Stdout != "hello from bin\n" {
+		t.Fatalf("Unexpected output: %q", result.Stdout)
+	}
+
+	_, err = service.Execute(context.Background(), &ExecuteRequest{Argv: []string{"agent-sandbox-no-such-program"}})
+	if !errors.Is(err, ErrProgramNotFound) {
+		t.Fatalf("Expected ErrProgramNotFound, got %v", err)
+	}
+}
+
+func TestExecute_Profile(t *testing.T) {
+	workspace := t.TempDir()
+	profile := `export GREETING=hello; greet() { echo "hi $1"; }; echo "profile output"`
+
+	if err := os.WriteFile(filepath.Join(workspace, "profile.sh"), []byte(profile), 0o600); err != nil {
+		t.Fatalf("Failed to create profile: %v", err)
+	}
+
+	// 相对路径相对工作空间
+	service := NewService(30, workspace, WithEnvironment(Environment{Profile: "profile.sh"}))
+	ctx := context.Background()
+
+	result, err := service.Execute(ctx, &ExecuteRequest{Command: `echo "$GREETING"; greet x`})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if result.Stdout != "profile output\nhello\nhi x\n" {
+		t.Fatalf("Unexpected output: %q", result.Stdout)
+	}
+
+	// argv 模式的参数不经过 shell 解析
+	result, err = service.Execute(ctx, &ExecuteRequest{Argv: []string{"sh", "-c", `echo "$GREETING $0 $1"`, "a b", "$HOME"}})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if result.Stdout != "profile output\nhello a b $HOME\n" {
+		t.Fatalf("Unexpected argv output: %q", result.Stdout)
+	}
+
+	// 会话开始时加载一次 profile，输出不出现在命令的结果中
+	session, err := service.CreateSession("")
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	defer func() {
+		_ = service.CloseSession("", session.ID)
+	}()
+
+	result, err = service.Execute(ctx, &ExecuteRequest{SessionID: session.ID, Command: `greet "$GREETING"`})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if result.Output != "hi hello\n" {
+		t.Fatalf("Unexpected session output: %q", result.Output)
+	}
+}
@@ -0,0 +1,12 @@
+package service
+
+import "context"
+
+// ExecuteInSession 在持久会话中执行命令，供测试使用.
+func (s *Service) ExecuteInSession(ctx context.Context, sandboxID, sessionID, command string) (*ExecuteResult, error) {
+	return s.Execute(ctx, &ExecuteRequest{
+		SandboxID: sandboxID,
+		SessionID: sessionID,
+		Command:   command,
+	})
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/HJH0924/agent-sandbox/internal/history"
+)
+
+// ErrHistoryDisabled 服务没有开启执行历史.
+var ErrHistoryDisabled = errors.New("execution history is disabled")
+
+// WithHistory 将每次命令执行记录到执行历史中.
+func WithHistory(store *history.Store) Option {
+	return func(s *Service) {
+		s.history = store
+	}
+}
+
+// ListExecutions 按从新到旧的顺序分页返回沙箱的执行历史.
+func (s *Service) ListExecutions(sandboxID string, pageSize int, pageToken string, includeOutput bool) (*history.Page, error) {
+	if s.history == nil {
+		return nil, ErrHistoryDisabled
+	}
+
+	return s.history.List(sandboxID, pageSize, pageToken, includeOutput)
+}
+
+// record 将一次执行写入执行历史，等待审批的命令尚未执行，不记录. 命令和错误中的密钥值被替换.
+func (s *Service) record(req *ExecuteRequest, startedAt time.Time, result *ExecuteResult, err error) {
+	if s.history == nil || (result != nil && result.ApprovalPending) {
+		return
+	}
+
+	entry := history.Entry{
+		SandboxID:   req.SandboxID,
+		SessionID:   req.SessionID,
+		Command:     s.Redact(req.SandboxID, req.commandLine()),
+		StartedAt:   startedAt,
+		EndedAt:     time.Now(),
+		ExitCode:    -1,
+		CallerKeyID: req.CallerKeyID,
+	}
+
+	if result != nil {
+		entry.Cwd = result.Cwd
+		entry.ExitCode = result.ExitCode
+		entry.OutputBytes = result.StdoutBytes + result.StderrBytes
+		entry.Output = result.Output
+		entry.RecordingID = result.RecordingID
+	} else if req.SessionID == "" {
+		// 命令没有运行（如被策略拒绝），记录它本应使用的工作目录
+		if dir, _, dirErr := s.workDir(req.SandboxID); dirErr == nil {
+			entry.Cwd = s.visibleDir(dir)
+		}
+	}
+
+	if err != nil {
+		entry.Error = s.Redact(req.SandboxID, err.Error())
+	}
+
+	s.history.Add(entry)
+}
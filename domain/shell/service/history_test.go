@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/HJH0924/agent-sandbox/internal/history"
+	"github.com/HJH0924/agent-sandbox/internal/policy"
+)
+
+func TestExecute_History(t *testing.T) {
+	commandPolicy, err := policy.New([]policy.Rule{
+		{Name: "no-curl", Action: policy.ActionDeny, Prefix: []string{"curl"}},
+	}, policy.ActionAllow)
+	if err != nil {
+		t.Fatalf("policy.New failed: %v", err)
+	}
+
+	workspace := t.TempDir()
+	service := NewService(10, workspace, WithPolicy(commandPolicy), WithHistory(history.NewStore(10, true)))
+	ctx := context.Background()
+
+	if _, err := service.Execute(ctx, &ExecuteRequest{SandboxID: "sandbox-1", Command: "echo hello; exit 3", CallerKeyID: "key-1"}); err == nil {
+		t.Fatalf("Expected non-zero exit to return an error")
+	}
+
+	if _, err := service.Execute(ctx, &ExecuteRequest{SandboxID: "sandbox-1", Command: "curl example.com"}); !errors.Is(err, ErrCommandDenied) {
+		t.Fatalf("Expected ErrCommandDenied, got %v", err)
+	}
+
+	session, err := service.CreateSession("sandbox-1")
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	defer func() {
+		_ = service.CloseSession("sandbox-1", session.ID)
+	}()
+
+	for _, command := range []string{"mkdir -p sub && cd sub", "pwd"} {
+		if _, err := service.ExecuteInSession(ctx, "sandbox-1", session.ID, command); err != nil {
+			t.Fatalf("ExecuteInSession failed: %v", err)
+		}
+	}
+
+	page, err := service.ListExecutions("sandbox-1", 0, "", true)
+	if err != nil {
+		t.Fatalf("ListExecutions failed: %v", err)
+	}
+
+	if len(page.Entries) != 4 {
+		t.Fatalf("Expected 4 entries, got %d", len(page.Entries))
+	}
+
+	// 从新到旧：会话中第二条命令的工作目录是 cd 之后的目录
+	pwd := page.Entries[0]
+	if pwd.Command != "pwd" || pwd.SessionID != session.ID || pwd.Cwd != filepath.Join(workspace, "sub") {
+		t.Fatalf("Unexpected session entry: %+v", pwd)
+	}
+
+	if cd := page.Entries[1]; cd.Cwd != workspace {
+		t.Fatalf("Expected session to start in the workspace, got %q", cd.Cwd)
+	}
+
+	denied := page.Entries[2]
+	if denied.Error == "" || denied.ExitCode != -1 || denied.Cwd != workspace {
+		t.Fatalf("Expected denied command to be recorded with its error, got %+v", denied)
+	}
+
+	failed := page.Entries[3]
+	if failed.ExitCode != 3 || failed.OutputBytes != 6 || failed.Output != "hello\n" || failed.CallerKeyID != "key-1" {
+		t.Fatalf("Unexpected entry: %+v", failed)
+	}
+
+	if failed.StartedAt.IsZero() || failed.EndedAt.Before(failed.StartedAt) {
+		t.Fatalf("Unexpected timestamps: %s - %s", failed.StartedAt, failed.EndedAt)
+	}
+
+	// 其他沙箱看不到这些记录
+	page, err = service.ListExecutions("sandbox-2", 0, "", false)
+	if err != nil || len(page.Entries) != 0 {
+		t.Fatalf("Expected no entries for another sandbox, got %v, %v", page, err)
+	}
+}
+
+func TestListExecutions_Disabled(t *testing.T) {
+	service := NewService(10, t.TempDir())
+
+	if _, err := service.ListExecutions("sandbox-1", 0, "", false); !errors.Is(err, ErrHistoryDisabled) {
+		t.Fatalf("Expected ErrHistoryDisabled, got %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"github.com/HJH0924/agent-sandbox/internal/launcher"
+)
+
+// DefaultReadOnlyPaths 隔离环境中默认以只读方式提供的系统目录.
+var DefaultReadOnlyPaths = []string{"/bin", "/sbin", "/usr", "/lib", "/lib32", "/lib64", "/libx32", "/etc", "/opt"}
+
+// defaultHostname 隔离环境中的默认主机名.
+const defaultHostname = "sandbox"
+
+// Isolation 命名空间隔离配置.
+// 启用后命令在新的 user、mount、PID、IPC 和 UTS 命名空间中运行，
+// 工作空间挂载为 /workspace 并作为工作目录，系统目录只读，看不到宿主机的其他进程和文件.
+type Isolation struct {
+	// ReadOnlyPaths 以只读方式挂载的宿主机目录，为空时使用 DefaultReadOnlyPaths
+	ReadOnlyPaths []string
+	// Hostname 隔离环境中的主机名，为空时使用 "sandbox"
+	Hostname string
+}
+
+// WithIsolation 在隔离的命名空间中运行命令，nil 表示不隔离.
+func WithIsolation(isolation *Isolation) Option {
+	return func(s *Service) {
+		s.isolation = isolation
+	}
+}
+
+// visibleDir 返回命令看到的工作目录路径，启用隔离时工作空间挂载为 /workspace.
+func (s *Service) visibleDir(dir string) string {
+	if s.isolation != nil {
+		return launcher.WorkspacePath
+	}
+
+	return dir
+}
+
+// Check 检查内核是否允许创建隔离所需的命名空间（如是否允许非特权 user 命名空间）.
+func (iso *Isolation) Check(workspace string) error {
+	return launcher.CheckIsolation(*iso.spec(workspace))
+}
+
+// spec 返回启动器使用的隔离设置.
+func (iso *Isolation) spec(workspace string) *launcher.Isolation {
+	readOnly := iso.ReadOnlyPaths
+	if len(readOnly) == 0 {
+		readOnly = DefaultReadOnlyPaths
+	}
+
+	hostname := iso.Hostname
+	if hostname == "" {
+		hostname = defaultHostname
+	}
+
+	return &launcher.Isolation{
+		Workspace: workspace,
+		ReadOnly:  readOnly,
+		Hostname:  hostname,
+	}
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// newIsolatedService 创建启用隔离的服务，内核不支持时跳过测试.
+func newIsolatedService(t *testing.T, workspace string, opts ...Option) *Service {
+	t.Helper()
+
+	isolation := &Isolation{}
+	if err := isolation.Check(workspace); err != nil {
+		t.Skipf("namespace isolation not available: %v", err)
+	}
+
+	return NewService(10, workspace, append([]Option{WithIsolation(isolation)}, opts...)...)
+}
+
+func TestShellService_Isolation(t *testing.T) {
+	tmpDir := t.TempDir()
+	service := newIsolatedService(t, tmpDir)
+
+	result, err := service.Execute(context.Background(), &ExecuteRequest{
+		Command: "pwd; hostname; echo $PPID; echo hi > hello.txt; test -e " + tmpDir + " || echo hidden",
+	})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	expected := "/workspace\nsandbox\n1\nhidden\n"
+	if result.Output != expected {
+		t.Fatalf("Expected %q, got %q", expected, result.Output)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, "hello.txt"))
+	if err != nil || string(data) != "hi\n" {
+		t.Fatalf("Expected workspace write to be visible on host, got %q (%v)", data, err)
+	}
+}
+
+func TestShellService_IsolationTimeout(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	isolation := &Isolation{}
+	if err := isolation.Check(tmpDir); err != nil {
+		t.Skipf("namespace isolation not available: %v", err)
+	}
+
+	service := NewService(1, tmpDir, WithIsolation(isolation), WithKillGracePeriod(10*time.Second))
+
+	start := time.Now()
+
+	// exec 后 sleep 取代 shell，没有启动器时它就是命名空间中的 1 号进程
+	result, err := service.Execute(context.Background(), &ExecuteRequest{Command: "exec sleep 30"})
+	if err == nil {
+		t.Fatal("Expected timeout error")
+	}
+
+	// SIGTERM 经命名空间中的启动器转发给命令，不必等到宽限期后的 SIGKILL
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Execute should return shortly after timeout, took %s", elapsed)
+	}
+
+	if !result.TimedOut || result.ExitCode != 128+int(syscall.SIGTERM) {
+		t.Fatalf("Expected timeout with SIGTERM exit code, got %+v", result)
+	}
+}
+
+func TestShellService_IsolationReadOnlySystem(t *testing.T) {
+	service := newIsolatedService(t, t.TempDir())
+
+	result, err := service.Execute(context.Background(), &ExecuteRequest{Command: "touch /usr/agent-sandbox-test"})
+	if err == nil {
+		t.Fatalf("Expected write to /usr to fail")
+	}
+
+	if result == nil || !strings.Contains(result.Output, "Read-only file system") {
+		t.Fatalf("Expected read-only file system error, got %+v", result)
+	}
+}
+
+func TestShellService_IsolationSession(t *testing.T) {
+	service := newIsolatedService(t, t.TempDir(), WithResourceLimits(ResourceLimits{OpenFiles: 64}))
+
+	session, err := service.CreateSession("sandbox-1")
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	defer func() {
+		_ = service.CloseSession("sandbox-1", session.ID)
+	}()
+
+	if _, err := service.ExecuteInSession(context.Background(), "sandbox-1", session.ID, "mkdir -p sub && cd sub"); err != nil {
+		t.Fatalf("ExecuteInSession failed: %v", err)
+	}
+
+	result, err := service.ExecuteInSession(context.Background(), "sandbox-1", session.ID, "pwd; ulimit -n")
+	if err != nil {
+		t.Fatalf("ExecuteInSession failed: %v", err)
+	}
+
+	if result.Output != "/workspace/sub\n64\n" {
+		t.Fatalf("Expected isolated session state, got %q", result.Output)
+	}
+}
+
+func TestShellService_IsolationWithSandboxUser(t *testing.T) {
+	users := newTestUsers(t)
+
+	user, err := users.Create("sandbox-1")
+	if err != nil {
+		t.Fatalf("Failed to create sandbox user: %v", err)
+	}
+
+	service := newIsolatedService(t, t.TempDir(), WithUsers(users))
+
+	if result, err := service.Execute(context.Background(), &ExecuteRequest{SandboxID: "sandbox-1", Command: "echo hi > owned.txt"}); err != nil {
+		t.Fatalf("Execute failed: %v %+v", err, result)
+	}
+
+	info, err := os.Stat(filepath.Join(user.Workspace, "owned.txt"))
+	if err != nil {
+		t.Fatalf("Failed to stat file: %v", err)
+	}
+
+	// 命名空间内的 root 映射为沙箱用户
+	if stat, ok := info.Sys().(*syscall.Stat_t); !ok || stat.Uid != user.UID {
+		t.Fatalf("Expected file owned by %d, got %+v", user.UID, info.Sys())
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/HJH0924/agent-sandbox/internal/launcher"
+	"github.com/HJH0924/agent-sandbox/internal/sandbox"
+)
+
+// sandboxedCommand 命令启动时应用的沙箱设置，用于在命令结束后判断触发了哪些限制.
+type sandboxedCommand struct {
+	cgroupDir string
+	before    cgroupEvents
+	release   func()
+	netns     *sandbox.NetNS
+}
+
+// WithRegistry 按沙箱注册表中记录的网络策略运行命令.
+func WithRegistry(registry *sandbox.Registry) Option {
+	return func(s *Service) {
+		s.registry = registry
+	}
+}
+
+// applySandbox 为命令应用资源限制、命名空间隔离、网络策略和 cgroup，dir 为命令的工作空间.
+// 必须在设置好 cmd 的工作目录、环境变量和运行用户之后调用，之后通过 start 启动命令并调用 release.
+func (s *Service) applySandbox(cmd *exec.Cmd, sandboxID, dir string, longLived bool) (*sandboxedCommand, error) {
+	sc := &sandboxedCommand{release: func() {}}
+
+	if s.registry != nil {
+		if info, err := s.registry.Get(sandboxID); err == nil {
+			sc.netns = info.NetNS
+		}
+	}
+
+	spec := launcher.Spec{Rlimits: s.limits.rlimits(longLived)}
+
+	if s.isolation != nil {
+		workspace, err := filepath.Abs(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get absolute path: %w", err)
+		}
+
+		spec.Isolation = s.isolation.spec(workspace)
+	}
+
+	if len(spec.Rlimits) > 0 || spec.Isolation != nil {
+		if err := launcher.Wrap(cmd, spec); err != nil {
+			return nil, err
+		}
+	}
+
+	if s.cgroup == nil {
+		return sc, nil
+	}
+
+	cgroupDir, release, err := s.cgroup.attach(cmd, sandboxID)
+	if err != nil {
+		return nil, err
+	}
+
+	sc.cgroupDir = cgroupDir
+	sc.before = readCgroupEvents(cgroupDir)
+	sc.release = release
+
+	return sc, nil
+}
+
+// start 启动命令，沙箱有独立的网络命名空间时命令在其中启动.
+func (sc *sandboxedCommand) start(start func() error) error {
+	if sc.netns == nil {
+		return start()
+	}
+
+	return sc.netns.Do(start)
+}
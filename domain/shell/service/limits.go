@@ -0,0 +1,96 @@
+package service
+
+import (
+	"syscall"
+
+	"github.com/HJH0924/agent-sandbox/internal/launcher"
+
+	"golang.org/x/sys/unix"
+)
+
+// 命令触发的资源限制名称.
+const (
+	LimitCPU       = "cpu"
+	LimitFileSize  = "file_size"
+	LimitMemory    = "memory"
+	LimitProcesses = "pids"
+)
+
+// ResourceLimits 每条命令的资源限制（rlimit），0 表示不限制.
+type ResourceLimits struct {
+	// CPUSeconds 单条命令可使用的 CPU 时间（秒）
+	CPUSeconds uint64
+	// AddressSpace 虚拟地址空间上限（字节）
+	AddressSpace uint64
+	// OpenFiles 可打开的文件描述符数量
+	OpenFiles uint64
+	// MaxProcesses 所属用户可拥有的进程数
+	MaxProcesses uint64
+	// FileSize 可写入的单个文件大小上限（字节）
+	FileSize uint64
+}
+
+// WithResourceLimits 设置每条命令的资源限制.
+func WithResourceLimits(limits ResourceLimits) Option {
+	return func(s *Service) {
+		s.limits = limits
+	}
+}
+
+// WithCgroup 设置沙箱级的 cgroup 资源限制.
+func WithCgroup(cgroup *Cgroup) Option {
+	return func(s *Service) {
+		s.cgroup = cgroup
+	}
+}
+
+// rlimits 转换为启动器使用的 rlimit 列表，longLived 为 true 时不限制 CPU 时间.
+func (l ResourceLimits) rlimits(longLived bool) []launcher.Rlimit {
+	var rlimits []launcher.Rlimit
+
+	add := func(resource int, value uint64) {
+		if value > 0 {
+			rlimits = append(rlimits, launcher.Rlimit{Resource: resource, Cur: value, Max: value})
+		}
+	}
+
+	// 持久会话和终端的 CPU 时间会在多条命令间累积，因此只对单条命令限制
+	if l.CPUSeconds > 0 && !longLived {
+		// 硬限制比软限制多 1 秒，使进程先收到可识别的 SIGXCPU
+		rlimits = append(rlimits, launcher.Rlimit{Resource: unix.RLIMIT_CPU, Cur: l.CPUSeconds, Max: l.CPUSeconds + 1})
+	}
+
+	add(unix.RLIMIT_AS, l.AddressSpace)
+	add(unix.RLIMIT_NOFILE, l.OpenFiles)
+	add(unix.RLIMIT_NPROC, l.MaxProcesses)
+	add(unix.RLIMIT_FSIZE, l.FileSize)
+
+	return rlimits
+}
+
+// exceeded 返回命令结束后触发的资源限制.
+func (lc *sandboxedCommand) exceeded(err error) []string {
+	var limits []string
+
+	// 只有命令进程本身被信号终止时才算触发限制：shell 以 128+N 退出码报告的子进程信号
+	// 与命令自己的 exit 152 等无法区分
+	switch terminationSignalNumber(err) {
+	case syscall.SIGXCPU:
+		limits = append(limits, LimitCPU)
+	case syscall.SIGXFSZ:
+		limits = append(limits, LimitFileSize)
+	}
+
+	if lc.cgroupDir != "" {
+		after := readCgroupEvents(lc.cgroupDir)
+		if after.oomKills > lc.before.oomKills {
+			limits = append(limits, LimitMemory)
+		}
+
+		if after.pidsMax > lc.before.pidsMax {
+			limits = append(limits, LimitProcesses)
+		}
+	}
+
+	return limits
+}
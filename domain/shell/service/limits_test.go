@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/HJH0924/agent-sandbox/internal/launcher"
+)
+
+func TestMain(m *testing.M) {
+	// 资源限制通过重新执行测试二进制应用
+	launcher.Init()
+	os.Exit(m.Run())
+}
+
+func TestShellService_FileSizeLimit(t *testing.T) {
+	tmpDir := t.TempDir()
+	service := NewService(10, tmpDir, WithResourceLimits(ResourceLimits{FileSize: 4096}))
+
+	result, err := service.Execute(context.Background(), &ExecuteRequest{
+		Command: "echo start; exec head -c 100000 /dev/zero > big",
+	})
+	if err == nil {
+		t.Fatalf("Expected error when exceeding file size limit")
+	}
+
+	if result == nil {
+		t.Fatalf("Expected result with output, got nil")
+	}
+
+	if !slices.Contains(result.LimitsExceeded, LimitFileSize) {
+		t.Fatalf("Expected %q in limits exceeded, got %v", LimitFileSize, result.LimitsExceeded)
+	}
+
+	info, err := os.Stat(tmpDir + "/big")
+	if err != nil {
+		t.Fatalf("Failed to stat output file: %v", err)
+	}
+
+	if info.Size() > 4096 {
+		t.Fatalf("Expected file size <= 4096, got %d", info.Size())
+	}
+}
+
+func TestShellService_CPULimit(t *testing.T) {
+	service := NewService(10, t.TempDir(), WithResourceLimits(ResourceLimits{CPUSeconds: 1}))
+
+	result, err := service.Execute(context.Background(), &ExecuteRequest{
+		Command: "echo start; while :; do :; done",
+	})
+	if err == nil {
+		t.Fatalf("Expected error when exceeding CPU limit")
+	}
+
+	if result == nil {
+		t.Fatalf("Expected result with output, got nil")
+	}
+
+	if !slices.Contains(result.LimitsExceeded, LimitCPU) {
+		t.Fatalf("Expected %q in limits exceeded, got %v", LimitCPU, result.LimitsExceeded)
+	}
+
+	if result.TimedOut {
+		t.Fatalf("Expected CPU limit to fire before the timeout")
+	}
+}
+
+func TestShellService_SignalExitCodeIsNotLimit(t *testing.T) {
+	service := NewService(10, t.TempDir(), WithResourceLimits(ResourceLimits{CPUSeconds: 10, FileSize: 4096}))
+
+	// 与 SIGXCPU（152）和 SIGXFSZ（153）的 128+N 相同的普通退出码不是触发的限制
+	for _, command := range []string{"exit 152", "exit 153"} {
+		result, err := service.Execute(context.Background(), &ExecuteRequest{Command: command})
+		if result == nil {
+			t.Fatalf("Expected result for %q, got error %v", command, err)
+		}
+
+		if len(result.LimitsExceeded) != 0 || result.Signal != "" {
+			t.Fatalf("Expected no limits or signal for %q, got %v %q", command, result.LimitsExceeded, result.Signal)
+		}
+	}
+}
+
+func TestShellService_OpenFilesLimit(t *testing.T) {
+	service := NewService(10, t.TempDir(), WithResourceLimits(ResourceLimits{OpenFiles: 32}))
+
+	result, err := service.Execute(context.Background(), &ExecuteRequest{Command: "ulimit -n"})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if result.Output != "32\n" {
+		t.Fatalf("Expected open files limit 32, got %q", result.Output)
+	}
+
+	if len(result.LimitsExceeded) != 0 {
+		t.Fatalf("Expected no limits exceeded, got %v", result.LimitsExceeded)
+	}
+}
+
+func TestShellService_SessionLimits(t *testing.T) {
+	service := NewService(10, t.TempDir(), WithResourceLimits(ResourceLimits{CPUSeconds: 1, OpenFiles: 32}))
+
+	session, err := service.CreateSession("sandbox-1")
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	defer func() {
+		_ = service.CloseSession("sandbox-1", session.ID)
+	}()
+
+	// 持久会话只应用非累积的限制，不限制 CPU 时间
+	result, err := service.ExecuteInSession(context.Background(), "sandbox-1", session.ID, "ulimit -n; ulimit -t")
+	if err != nil {
+		t.Fatalf("ExecuteInSession failed: %v", err)
+	}
+
+	if result.Output != "32\nunlimited\n" {
+		t.Fatalf("Expected session limits, got %q", result.Output)
+	}
+}
+
+func TestCgroup_Attach(t *testing.T) {
+	root := "/sys/fs/cgroup/agent-sandbox-test"
+
+	cgroup, err := NewCgroup(root, 64<<20, 16)
+	if err != nil {
+		t.Skipf("cgroup v2 not available: %v", err)
+	}
+
+	defer func() {
+		_ = cgroup.Remove("sandbox-1")
+		_ = os.Remove(root)
+	}()
+
+	service := NewService(10, t.TempDir(), WithCgroup(cgroup))
+
+	result, err := service.Execute(context.Background(), &ExecuteRequest{SandboxID: "sandbox-1", Command: "cat /proc/self/cgroup"})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if !strings.Contains(result.Output, "agent-sandbox-test/sandbox-1") {
+		t.Fatalf("Expected command in sandbox cgroup, got %q", result.Output)
+	}
+}
+
+func TestCgroup_CloseSandbox(t *testing.T) {
+	root := "/sys/fs/cgroup/agent-sandbox-test"
+
+	cgroup, err := NewCgroup(root, 0, 0)
+	if err != nil {
+		t.Skipf("cgroup v2 not available: %v", err)
+	}
+
+	defer func() {
+		_ = cgroup.Remove("sandbox-1")
+		_ = os.Remove(root)
+	}()
+
+	service := NewService(10, t.TempDir(), WithCgroup(cgroup))
+
+	// 命令返回后后台进程仍在沙箱的 cgroup 中运行
+	if _, err := service.Execute(context.Background(), &ExecuteRequest{SandboxID: "sandbox-1", Command: "sleep 60 >/dev/null 2>&1 &"}); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	service.CloseSandbox("sandbox-1")
+
+	if _, err := os.Stat(cgroup.dir("sandbox-1")); !os.IsNotExist(err) {
+		t.Fatalf("Expected sandbox cgroup to be removed, got %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HJH0924/agent-sandbox/internal/sandbox"
+)
+
+// newNetworkService 创建注册了指定网络策略沙箱的服务，权限不足时跳过测试.
+func newNetworkService(t *testing.T, policy sandbox.NetworkPolicy) (*Service, *sandbox.NetNS) {
+	t.Helper()
+
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not available")
+	}
+
+	var netns *sandbox.NetNS
+
+	if policy != sandbox.NetworkFull {
+		ns, err := sandbox.NewNetNS(policy == sandbox.NetworkLoopback)
+		if err != nil {
+			t.Skipf("network namespaces not available: %v", err)
+		}
+
+		t.Cleanup(func() {
+			_ = ns.Close()
+		})
+
+		netns = ns
+	}
+
+	registry := sandbox.NewRegistry()
+	registry.Add(&sandbox.Sandbox{ID: "sandbox-1", CreatedAt: time.Now(), Network: policy, NetNS: netns})
+
+	return NewService(10, t.TempDir(), WithRegistry(registry)), netns
+}
+
+// connect 在沙箱中通过 bash 的 /dev/tcp 连接地址.
+func connect(service *Service, addr string) (*ExecuteResult, error) {
+	host, port, _ := net.SplitHostPort(addr)
+
+	return service.Execute(context.Background(), &ExecuteRequest{
+		SandboxID: "sandbox-1",
+		Command:   fmt.Sprintf("bash -c 'echo hi > /dev/tcp/%s/%s' && echo connected", host, port),
+	})
+}
+
+func TestShellService_NetworkNone(t *testing.T) {
+	service, _ := newNetworkService(t, sandbox.NetworkNone)
+
+	result, err := connect(service, "127.0.0.1:1")
+	if err == nil {
+		t.Fatalf("Expected connection to fail, got %q", result.Output)
+	}
+
+	if result == nil || !strings.Contains(result.Output, "Network is unreachable") {
+		t.Fatalf("Expected network to be unreachable, got %+v", result)
+	}
+}
+
+func TestShellService_NetworkLoopback(t *testing.T) {
+	service, netns := newNetworkService(t, sandbox.NetworkLoopback)
+
+	// 宿主机回环地址上的服务不可访问
+	hostListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	defer func() {
+		_ = hostListener.Close()
+	}()
+
+	if result, err := connect(service, hostListener.Addr().String()); err == nil {
+		t.Fatalf("Expected host listener to be unreachable, got %q", result.Output)
+	}
+
+	// 沙箱自己的回环地址可以访问，且在命令之间共享
+	var sandboxListener net.Listener
+
+	err = netns.Do(func() (err error) {
+		sandboxListener, err = net.Listen("tcp", "127.0.0.1:0")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Failed to listen in sandbox: %v", err)
+	}
+
+	defer func() {
+		_ = sandboxListener.Close()
+	}()
+
+	result, err := connect(service, sandboxListener.Addr().String())
+	if err != nil {
+		t.Fatalf("Expected sandbox listener to be reachable: %v %+v", err, result)
+	}
+
+	if result.Output != "connected\n" {
+		t.Fatalf("Expected connected, got %q", result.Output)
+	}
+}
+
+func TestShellService_NetworkFull(t *testing.T) {
+	service, _ := newNetworkService(t, sandbox.NetworkFull)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	defer func() {
+		_ = listener.Close()
+	}()
+
+	result, err := connect(service, listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Expected host listener to be reachable: %v %+v", err, result)
+	}
+}
+
+func TestShellService_NetworkIsolatedSession(t *testing.T) {
+	service, _ := newNetworkService(t, sandbox.NetworkNone)
+
+	session, err := service.CreateSession("sandbox-1")
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	defer func() {
+		_ = service.CloseSession("sandbox-1", session.ID)
+	}()
+
+	result, err := service.ExecuteInSession(context.Background(), "sandbox-1", session.ID, "cat /proc/net/dev | tail -n +3 | cut -d: -f1 | tr -d ' '")
+	if err != nil {
+		t.Fatalf("ExecuteInSession failed: %v", err)
+	}
+
+	if result.Output != "lo\n" {
+		t.Fatalf("Expected only loopback interface, got %q", result.Output)
+	}
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/HJH0924/agent-sandbox/internal/sandbox"
+)
+
+// outputDir 工作空间中保存完整命令输出的目录.
+const outputDir = ".agent-sandbox/output"
+
+// outputBuffer 有界的输出缓冲区，超过上限时只保留开头和结尾各一半的字节，
+// 可选地将完整输出写入工作空间中的文件.
+type outputBuffer struct {
+	limit int64
+	head  []byte
+	tail  []byte
+	total int64
+	// tee 同时接收全部输出（如录制），不受上限影响
+	tee io.Writer
+
+	spill     *os.File
+	spillPath string
+	spillErr  error
+}
+
+// newOutputBuffer 创建输出缓冲区，spill 非空时同时将完整输出写入 dir 下的该文件（相对路径），
+// user 非空时文件归属该沙箱用户.
+func newOutputBuffer(limit int64, dir, spill string, user *sandbox.User) (*outputBuffer, error) {
+	b := &outputBuffer{limit: limit}
+
+	if spill != "" {
+		// 工作空间由沙箱用户控制，不能跟随其中的符号链接创建文件
+		f, err := sandbox.CreateFile(dir, spill, 0o600, user)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create output file: %w", err)
+		}
+
+		b.spill = f
+		b.spillPath = f.Name()
+	}
+
+	return b, nil
+}
+
+// Write 写入输出，始终返回成功以免阻塞命令.
+func (b *outputBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	b.total += int64(n)
+
+	if b.tee != nil {
+		_, _ = b.tee.Write(p)
+	}
+
+	if b.spill != nil && b.spillErr == nil {
+		_, b.spillErr = b.spill.Write(p)
+	}
+
+	if b.limit <= 0 {
+		b.head = append(b.head, p...)
+		return n, nil
+	}
+
+	headCap := int(b.limit / 2)
+	if room := headCap - len(b.head); room > 0 {
+		take := min(room, len(p))
+		b.head = append(b.head, p[:take]...)
+		p = p[take:]
+	}
+
+	if len(p) == 0 {
+		return n, nil
+	}
+
+	// 结尾部分允许增长到两倍容量后再压缩，避免每次写入都移动数据
+	tailCap := int(b.limit) - headCap
+	b.tail = append(b.tail, p...)
+
+	if len(b.tail) > 2*tailCap {
+		kept := copy(b.tail, b.tail[len(b.tail)-tailCap:])
+		b.tail = b.tail[:kept]
+	}
+
+	return n, nil
+}
+
+// Truncated 返回输出是否超过上限被截断.
+func (b *outputBuffer) Truncated() bool {
+	return b.limit > 0 && b.total > b.limit
+}
+
+// Total 返回写入的总字节数.
+func (b *outputBuffer) Total() int64 {
+	return b.total
+}
+
+// Bytes 返回保留的原始字节，被截断时为开头和结尾的字节直接拼接，截断只通过 Truncated 报告.
+func (b *outputBuffer) Bytes() []byte {
+	return append(append([]byte(nil), b.head...), b.keptTail()...)
+}
+
+// Text 返回用于文本展示的输出，被截断时在开头和结尾之间插入截断说明.
+// 截断处被切开的 UTF-8 字符会被丢弃，以免文本输出被误判为其他编码.
+func (b *outputBuffer) Text() []byte {
+	if !b.Truncated() {
+		return b.Bytes()
+	}
+
+	head := trimRuneEnd(b.head)
+	tail := trimRuneStart(b.keptTail())
+
+	var buf bytes.Buffer
+
+	buf.Write(head)
+	fmt.Fprintf(&buf, "\n... [%d bytes truncated] ...\n", b.total-int64(len(head))-int64(len(tail)))
+	buf.Write(tail)
+
+	return buf.Bytes()
+}
+
+// keptTail 返回保留的结尾部分，不超过上限减去开头部分的长度.
+func (b *outputBuffer) keptTail() []byte {
+	if b.limit <= 0 {
+		return b.tail
+	}
+
+	if tailCap := int(b.limit) - len(b.head); len(b.tail) > tailCap {
+		return b.tail[len(b.tail)-tailCap:]
+	}
+
+	return b.tail
+}
+
+// finish 关闭输出文件，未截断时删除文件；返回保留的完整输出文件路径.
+func (b *outputBuffer) finish() string {
+	if b.spill == nil {
+		return ""
+	}
+
+	if err := b.spill.Close(); err != nil && b.spillErr == nil {
+		b.spillErr = err
+	}
+
+	if !b.Truncated() || b.spillErr != nil {
+		_ = os.Remove(b.spillPath)
+		return ""
+	}
+
+	return b.spillPath
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/HJH0924/agent-sandbox/internal/sandbox"
+)
+
+func TestOutputBuffer_WithinLimit(t *testing.T) {
+	b, err := newOutputBuffer(10, "", "", nil)
+	if err != nil {
+		t.Fatalf("Failed to create buffer: %v", err)
+	}
+
+	for _, chunk := range []string{"abc", "def", "ghij"} {
+		if _, err := b.Write([]byte(chunk)); err != nil {
+			t.Fatalf("Failed to write: %v", err)
+		}
+	}
+
+	if b.Truncated() {
+		t.Fatal("Buffer should not be truncated")
+	}
+
+	if got := string(b.Bytes()); got != "abcdefghij" {
+		t.Fatalf("Expected %q, got %q", "abcdefghij", got)
+	}
+}
+
+func TestOutputBuffer_KeepsHeadAndTail(t *testing.T) {
+	b, err := newOutputBuffer(10, "", "", nil)
+	if err != nil {
+		t.Fatalf("Failed to create buffer: %v", err)
+	}
+
+	// 分多次写入，覆盖结尾部分的压缩逻辑
+	data := "0123456789abcdefghijklmnopqrstuvwxyz"
+	for i := 0; i < len(data); i += 3 {
+		if _, err := b.Write([]byte(data[i:min(i+3, len(data))])); err != nil {
+			t.Fatalf("Failed to write: %v", err)
+		}
+	}
+
+	if !b.Truncated() {
+		t.Fatal("Buffer should be truncated")
+	}
+
+	if b.Total() != int64(len(data)) {
+		t.Fatalf("Expected total %d, got %d", len(data), b.Total())
+	}
+
+	expected := "01234\n... [26 bytes truncated] ...\nvwxyz"
+	if got := string(b.Text()); got != expected {
+		t.Fatalf("Expected %q, got %q", expected, got)
+	}
+
+	// 原始字节不插入截断说明
+	if got := string(b.Bytes()); got != "01234vwxyz" {
+		t.Fatalf("Expected raw head and tail, got %q", got)
+	}
+}
+
+func TestOutputBuffer_Unlimited(t *testing.T) {
+	b, err := newOutputBuffer(0, "", "", nil)
+	if err != nil {
+		t.Fatalf("Failed to create buffer: %v", err)
+	}
+
+	data := strings.Repeat("x", 1000)
+	if _, err := b.Write([]byte(data)); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+
+	if b.Truncated() || string(b.Bytes()) != data {
+		t.Fatal("Unlimited buffer should keep all output")
+	}
+}
+
+func TestOutputBuffer_Spill(t *testing.T) {
+	dir := t.TempDir()
+
+	truncated, err := newOutputBuffer(4, dir, filepath.Join("out", "a.stdout"), nil)
+	if err != nil {
+		t.Fatalf("Failed to create buffer: %v", err)
+	}
+
+	_, _ = truncated.Write([]byte("full output"))
+
+	path := truncated.finish()
+
+	content, err := os.ReadFile(path) // #nosec G304 -- test file
+	if err != nil {
+		t.Fatalf("Expected spilled file to exist: %v", err)
+	}
+
+	if string(content) != "full output" {
+		t.Fatalf("Expected full output in file, got %q", content)
+	}
+
+	// 未截断时不保留文件
+	small, err := newOutputBuffer(100, dir, filepath.Join("out", "b.stdout"), nil)
+	if err != nil {
+		t.Fatalf("Failed to create buffer: %v", err)
+	}
+
+	_, _ = small.Write([]byte("small"))
+
+	if path := small.finish(); path != "" {
+		t.Fatalf("Expected no spilled file, got %q", path)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "out", "b.stdout")); !os.IsNotExist(err) {
+		t.Fatal("Spill file should be removed when output is not truncated")
+	}
+}
+
+func TestOutputBuffer_SpillSymlink(t *testing.T) {
+	dir, outside := t.TempDir(), t.TempDir()
+
+	// 沙箱将输出目录替换为指向宿主机目录的符号链接
+	if err := os.Symlink(outside, filepath.Join(dir, "out")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	if _, err := newOutputBuffer(4, dir, filepath.Join("out", "a.stdout"), nil); !errors.Is(err, sandbox.ErrUnsafePath) {
+		t.Fatalf("Expected ErrUnsafePath, got %v", err)
+	}
+
+	if entries, _ := os.ReadDir(outside); len(entries) != 0 {
+		t.Fatalf("Expected nothing to be created outside the workspace, got %v", entries)
+	}
+}
+
+func TestCopyUntilMarker(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		exitCode int
+	}{
+		{name: "trailing newline", input: "hello\n\nEND 0\n", expected: "hello\n", exitCode: 0},
+		{name: "no trailing newline", input: "hello\nEND 2\n", expected: "hello", exitCode: 2},
+		{name: "empty output", input: "\nEND 1\n", expected: "", exitCode: 1},
+		{name: "marker not at line start", input: "xEND 5\n\nEND 3\n", expected: "xEND 5\n", exitCode: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			exitCode, err := CopyUntilMarker(bufio.NewReader(strings.NewReader(tt.input)), &out, "END")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if out.String() != tt.expected || exitCode != tt.exitCode {
+				t.Fatalf("Expected (%q, %d), got (%q, %d)", tt.expected, tt.exitCode, out.String(), exitCode)
+			}
+		})
+	}
+}
+
+func TestShellService_TruncatedOutput(t *testing.T) {
+	tmpDir := t.TempDir()
+	service := NewService(30, tmpDir, WithMaxOutputSize(100), WithOutputSpill(true))
+
+	result, err := service.Execute(context.Background(), &ExecuteRequest{Command: "seq 1 10000"})
+	if err != nil {
+		t.Fatalf("Failed to execute command: %v", err)
+	}
+
+	if !result.Truncated {
+		t.Fatal("Expected output to be truncated")
+	}
+
+	if !strings.HasPrefix(result.Output, "1\n2\n") || !strings.HasSuffix(result.Output, "9999\n10000\n") {
+		t.Fatalf("Expected head and tail of output, got %q", result.Output)
+	}
+
+	if result.StdoutBytes != 48894 {
+		t.Fatalf("Expected 48894 stdout bytes, got %d", result.StdoutBytes)
+	}
+
+	if result.StdoutFile == "" || result.StderrFile != "" {
+		t.Fatalf("Expected only stdout to be spilled, got %q and %q", result.StdoutFile, result.StderrFile)
+	}
+
+	info, err := os.Stat(filepath.Join(tmpDir, result.StdoutFile))
+	if err != nil {
+		t.Fatalf("Expected spilled output file: %v", err)
+	}
+
+	if info.Size() != result.StdoutBytes {
+		t.Fatalf("Expected spilled file size %d, got %d", result.StdoutBytes, info.Size())
+	}
+}
+
+func TestShellService_SessionTruncatedOutput(t *testing.T) {
+	service := NewService(30, t.TempDir(), WithMaxOutputSize(100))
+
+	session, err := service.CreateSession("sandbox-1")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	defer func() {
+		_ = service.CloseSession("sandbox-1", session.ID)
+	}()
+
+	result, err := service.ExecuteInSession(context.Background(), "sandbox-1", session.ID, "seq 1 10000 | tr -d '\\n'")
+	if err != nil {
+		t.Fatalf("Failed to execute command: %v", err)
+	}
+
+	if !result.Truncated || result.StdoutBytes != 38894 {
+		t.Fatalf("Expected truncated output of 38894 bytes, got %v/%d", result.Truncated, result.StdoutBytes)
+	}
+
+	if !strings.HasSuffix(result.Output, "999910000") {
+		t.Fatalf("Expected tail of output, got %q", result.Output)
+	}
+}
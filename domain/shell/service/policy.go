@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/HJH0924/agent-sandbox/internal/approval"
+	"github.com/HJH0924/agent-sandbox/internal/policy"
+)
+
+var (
+	// ErrCommandDenied 命令被策略拒绝.
+	ErrCommandDenied = errors.New("command denied by policy")
+	// ErrApprovalRequired 命令需要审批后才能执行，但服务没有启用审批.
+	ErrApprovalRequired = errors.New("command requires approval")
+	// ErrApprovalMismatch 审批请求属于另一条命令.
+	ErrApprovalMismatch = errors.New("approval does not match command")
+)
+
+// WithPolicy 设置服务端的命令策略，沙箱创建时指定的策略只能收紧它.
+func WithPolicy(p *policy.Policy) Option {
+	return func(s *Service) {
+		s.policy = p
+	}
+}
+
+// WithApprovals 将需要审批的命令提交到审批队列，审批通过后再执行.
+// 未设置时需要审批的命令直接被拒绝.
+func WithApprovals(approvals *approval.Queue) Option {
+	return func(s *Service) {
+		s.approvals = approvals
+	}
+}
+
+// authorize 在启动命令前按服务端策略和沙箱策略判定命令.
+// 需要审批时提交（或继续）审批请求并等待结果，PollApproval 为 true 且尚未处理时返回 pending.
+func (s *Service) authorize(ctx context.Context, req *ExecuteRequest) (approvalID string, pending bool, err error) {
+	command := req.commandLine()
+	action, rule := s.decide(req.SandboxID, command)
+
+	switch action {
+	case policy.ActionDeny:
+		return "", false, fmt.Errorf("%w: rule %q", ErrCommandDenied, rule)
+	case policy.ActionApprove:
+		if s.approvals == nil {
+			return "", false, fmt.Errorf("%w: rule %q", ErrApprovalRequired, rule)
+		}
+	default:
+		return "", false, nil
+	}
+
+	pendingReq, err := s.approvalRequest(req.SandboxID, command, req.ApprovalID, rule)
+	if err != nil {
+		return "", false, err
+	}
+
+	if req.PollApproval && s.approvals.Status(pendingReq) == approval.StatusPending {
+		return pendingReq.ID, true, nil
+	}
+
+	if err := s.approvals.Wait(ctx, pendingReq); err != nil {
+		return pendingReq.ID, false, fmt.Errorf("approval %s (rule %q): %w", pendingReq.ID, rule, err)
+	}
+
+	return pendingReq.ID, false, nil
+}
+
+// CheckCommand 检查无人值守执行的命令（如定时任务）是否被策略允许，需要审批的命令同样视为不允许.
+func (s *Service) CheckCommand(sandboxID, command string) error {
+	switch action, rule := s.decide(sandboxID, command); action {
+	case policy.ActionDeny:
+		return fmt.Errorf("%w: rule %q", ErrCommandDenied, rule)
+	case policy.ActionApprove:
+		return fmt.Errorf("%w: rule %q", ErrApprovalRequired, rule)
+	default:
+		return nil
+	}
+}
+
+// decide 按服务端策略和沙箱策略判定命令，返回处理方式和命中的规则名称（未命中时为 "default"）.
+func (s *Service) decide(sandboxID, command string) (policy.Action, string) {
+	p := s.policy
+
+	if s.registry != nil {
+		if info, err := s.registry.Get(sandboxID); err == nil {
+			p = p.Override(info.Policy)
+		}
+	}
+
+	decision := p.Match(command)
+
+	rule := decision.Rule
+	if rule == "" {
+		rule = "default"
+	}
+
+	return decision.Action, rule
+}
+
+// approvalRequest 查找调用方指定的审批请求，未指定时提交新的审批请求.
+func (s *Service) approvalRequest(sandboxID, command, approvalID, rule string) (*approval.Request, error) {
+	if approvalID == "" {
+		return s.approvals.Submit(sandboxID, command, rule), nil
+	}
+
+	pendingReq, err := s.approvals.Get(approvalID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 不暴露其他沙箱的审批请求
+	if pendingReq.SandboxID != sandboxID {
+		return nil, approval.ErrNotFound
+	}
+
+	if pendingReq.Command != command {
+		return nil, ErrApprovalMismatch
+	}
+
+	return pendingReq, nil
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"errors"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// defaultKillGracePeriod 发送 SIGTERM 后等待进程退出的默认时间.
+const defaultKillGracePeriod = 5 * time.Second
+
+// startNewProcessGroup 让命令在独立的进程组中运行，便于终止其所有子孙进程.
+func startNewProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+
+	cmd.SysProcAttr.Setpgid = true
+}
+
+// terminateOnCancel 在 context 结束时终止整个进程组：先发送 SIGTERM，宽限期后发送 SIGKILL.
+// 返回的函数必须在 cmd.Wait 返回后调用：它取消尚未触发的 SIGKILL 定时器，并立即结束进程组中剩下的进程，
+// 避免进程组全部退出后其 ID 被其他进程组复用、宽限期结束时误杀无关的进程.
+func terminateOnCancel(cmd *exec.Cmd, grace time.Duration) func() {
+	var (
+		mu    sync.Mutex
+		timer *time.Timer
+	)
+
+	cmd.Cancel = func() error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		timer = terminateGroup(cmd.Process.Pid, grace)
+
+		return nil
+	}
+
+	// 宽限期过后仍未关闭的输出管道会被强制关闭，
+	// 避免后台任务持有管道导致 Wait 永远阻塞
+	cmd.WaitDelay = grace + time.Second
+
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		// Wait 返回后不会再调用 Cancel；进程组已为空时 kill 返回 ESRCH
+		if timer != nil && timer.Stop() {
+			killGroup(cmd.Process.Pid)
+		}
+	}
+}
+
+// terminateGroup 向进程组发送 SIGTERM，返回宽限期后发送 SIGKILL 的定时器.
+func terminateGroup(pgid int, grace time.Duration) *time.Timer {
+	_ = syscall.Kill(-pgid, syscall.SIGTERM)
+
+	return time.AfterFunc(grace, func() {
+		_ = syscall.Kill(-pgid, syscall.SIGKILL)
+	})
+}
+
+// killGroup 立即向进程组发送 SIGKILL.
+func killGroup(pgid int) {
+	_ = syscall.Kill(-pgid, syscall.SIGKILL)
+}
+
+// terminationSignal 返回导致进程结束的信号名称，进程正常退出时返回空字符串.
+func terminationSignal(err error) string {
+	sig := terminationSignalNumber(err)
+	if sig == 0 {
+		return ""
+	}
+
+	return unix.SignalName(sig)
+}
+
+// terminationSignalNumber 返回导致进程结束的信号，进程正常退出时返回 0.
+func terminationSignalNumber(err error) syscall.Signal {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return 0
+	}
+
+	status, ok := exitErr.Sys().(syscall.WaitStatus)
+	if !ok || !status.Signaled() {
+		return 0
+	}
+
+	return status.Signal()
+}
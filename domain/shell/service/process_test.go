@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// processAlive 判断进程是否仍然存在.
+func processAlive(pid int) bool {
+	return syscall.Kill(pid, 0) == nil
+}
+
+func TestShellService_TimeoutKillsProcessGroup(t *testing.T) {
+	tmpDir := t.TempDir()
+	service := NewService(1, tmpDir, WithKillGracePeriod(200*time.Millisecond))
+
+	start := time.Now()
+
+	result, err := service.Execute(context.Background(), &ExecuteRequest{Command: "sleep 30 & echo $! > bg.pid; echo started; wait"})
+	if err == nil {
+		t.Fatal("Expected timeout error")
+	}
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Execute should return shortly after timeout, took %s", elapsed)
+	}
+
+	if !result.TimedOut || result.Signal != "SIGTERM" {
+		t.Fatalf("Expected timeout with SIGTERM, got timed_out=%v signal=%q", result.TimedOut, result.Signal)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "bg.pid")) // #nosec G304 -- test file
+	if err != nil {
+		t.Fatalf("Failed to read background pid: %v", err)
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err != nil {
+		t.Fatalf("Invalid background pid: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for processAlive(pid) && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	if processAlive(pid) {
+		t.Fatalf("Background process %d should have been killed", pid)
+	}
+}
+
+func TestShellService_TimeoutEscalatesToSIGKILL(t *testing.T) {
+	service := NewService(1, t.TempDir(), WithKillGracePeriod(200*time.Millisecond))
+
+	result, err := service.Execute(context.Background(), &ExecuteRequest{Command: "trap '' TERM; echo ignoring; sleep 30"})
+	if err == nil {
+		t.Fatal("Expected timeout error")
+	}
+
+	if result.Signal != "SIGKILL" {
+		t.Fatalf("Expected SIGKILL, got %q", result.Signal)
+	}
+}
+
+func TestShellService_CancelKillsProcessGroup(t *testing.T) {
+	service := NewService(30, t.TempDir(), WithKillGracePeriod(200*time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+
+	result, err := service.Execute(ctx, &ExecuteRequest{Command: "echo running; sleep 30"})
+	if err == nil {
+		t.Fatal("Expected cancellation error")
+	}
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Execute should return shortly after cancellation, took %s", elapsed)
+	}
+
+	if result.Signal != "SIGTERM" {
+		t.Fatalf("Expected SIGTERM, got %q", result.Signal)
+	}
+
+	// 调用方的截止时间不是服务端超时
+	if result.TimedOut || !errors.Is(err, context.DeadlineExceeded) || strings.Contains(err.Error(), "timed out after") {
+		t.Fatalf("Expected caller deadline to be reported, got timed_out=%v err=%v", result.TimedOut, err)
+	}
+}
+
+func TestShellService_BackgroundJobDoesNotBlock(t *testing.T) {
+	service := NewService(30, t.TempDir(), WithKillGracePeriod(200*time.Millisecond))
+
+	start := time.Now()
+
+	result, err := service.Execute(context.Background(), &ExecuteRequest{Command: "sleep 30 & echo done"})
+	if err != nil {
+		t.Fatalf("Failed to execute command: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Background job should not block Execute, took %s", elapsed)
+	}
+
+	if !strings.Contains(result.Output, "done") || result.Signal != "" {
+		t.Fatalf("Unexpected result: %+v", result)
+	}
+}
+
+func TestTerminateOnCancel_Finish(t *testing.T) {
+	dir := t.TempDir()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// 后台进程忽略 SIGTERM，命令本身收到 SIGTERM 后退出
+	cmd := exec.CommandContext(ctx, "sh", "-c", "(trap '' TERM; exec sleep 30) & echo $! > bg.pid; wait")
+	cmd.Dir = dir
+	startNewProcessGroup(cmd)
+	finish := terminateOnCancel(cmd, time.Minute)
+
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	pidFile := filepath.Join(dir, "bg.pid")
+
+	var pid int
+
+	for deadline := time.Now().Add(5 * time.Second); pid == 0 && time.Now().Before(deadline); {
+		content, _ := os.ReadFile(pidFile) // #nosec G304 -- test file
+		pid, _ = strconv.Atoi(strings.TrimSpace(string(content)))
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if pid == 0 {
+		t.Fatal("Background process did not start")
+	}
+
+	cancel()
+	_ = cmd.Wait()
+	finish()
+
+	// 剩下的进程立即被结束，而不是等到宽限期后由定时器结束
+	deadline := time.Now().Add(2 * time.Second)
+	for processAlive(pid) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if processAlive(pid) {
+		t.Fatalf("Background process %d should have been killed when finishing", pid)
+	}
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"errors"
+	"os"
+
+	"github.com/HJH0924/agent-sandbox/internal/recording"
+)
+
+// ErrRecordingDisabled 服务没有开启录制.
+var ErrRecordingDisabled = errors.New("recording is disabled")
+
+// WithRecordings 将命令执行和终端会话的输出录制为 asciicast 文件.
+func WithRecordings(store *recording.Store) Option {
+	return func(s *Service) {
+		s.recordings = store
+	}
+}
+
+// StartRecording 开始录制，width/height 为 0 时使用默认的终端大小，命令和输出中的密钥被替换.
+// 未开启录制或录制文件创建失败时返回 nil，录制失败不影响命令执行；返回值的方法都可以在 nil 上调用.
+func (s *Service) StartRecording(sandboxID string, kind recording.Kind, command string, width, height int) *recording.Recording {
+	if s.recordings == nil {
+		return nil
+	}
+
+	rec, err := s.recordings.Start(sandboxID, kind, s.Redact(sandboxID, command), width, height)
+	if err != nil {
+		return nil
+	}
+
+	rec.Redact(s.NewRedactor(sandboxID))
+
+	return rec
+}
+
+// ListRecordings 按从新到旧的顺序返回沙箱的录制.
+func (s *Service) ListRecordings(sandboxID string) ([]recording.Info, error) {
+	if s.recordings == nil {
+		return nil, ErrRecordingDisabled
+	}
+
+	return s.recordings.List(sandboxID), nil
+}
+
+// OpenRecording 打开沙箱的录制文件，调用方负责关闭返回的文件.
+func (s *Service) OpenRecording(sandboxID, recordingID string) (*os.File, recording.Info, error) {
+	if s.recordings == nil {
+		return nil, recording.Info{}, ErrRecordingDisabled
+	}
+
+	return s.recordings.Open(sandboxID, recordingID)
+}
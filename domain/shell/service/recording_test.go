@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HJH0924/agent-sandbox/internal/history"
+	"github.com/HJH0924/agent-sandbox/internal/recording"
+)
+
+func newRecordingService(t *testing.T, opts ...Option) *Service {
+	t.Helper()
+
+	store, err := recording.NewStore(t.TempDir(), 10, 0)
+	if err != nil {
+		t.Fatalf("recording.NewStore failed: %v", err)
+	}
+
+	return NewService(30, t.TempDir(), append(opts, WithRecordings(store))...)
+}
+
+// readRecording 返回录制文件的内容.
+func readRecording(t *testing.T, s *Service, sandboxID, recordingID string) string {
+	t.Helper()
+
+	f, _, err := s.OpenRecording(sandboxID, recordingID)
+	if err != nil {
+		t.Fatalf("OpenRecording failed: %v", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	content, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatalf("Failed to read recording: %v", err)
+	}
+
+	return string(content)
+}
+
+func TestExecute_Recording(t *testing.T) {
+	service := newRecordingService(t, WithHistory(history.NewStore(10, false)))
+	ctx := context.Background()
+
+	result, err := service.Execute(ctx, &ExecuteRequest{SandboxID: "sandbox-1", Command: "echo out; echo err >&2"})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if result.RecordingID == "" {
+		t.Fatal("Expected a recording ID")
+	}
+
+	content := readRecording(t, service, "sandbox-1", result.RecordingID)
+	for _, want := range []string{`"command":"echo out; echo err >&2"`, `"$ echo out; echo err >&2\r\n"`, `"out\r\n"`, `"err\r\n"`} {
+		if !strings.Contains(content, want) {
+			t.Fatalf("Expected recording to contain %s, got:\n%s", want, content)
+		}
+	}
+
+	// 执行历史中记录录制 ID
+	page, err := service.ListExecutions("sandbox-1", 10, "", false)
+	if err != nil {
+		t.Fatalf("ListExecutions failed: %v", err)
+	}
+
+	if page.Entries[0].RecordingID != result.RecordingID {
+		t.Fatalf("Expected history entry to reference recording %s, got %q", result.RecordingID, page.Entries[0].RecordingID)
+	}
+
+	// 持久会话中的命令同样被录制
+	session, err := service.CreateSession("sandbox-1")
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	defer func() {
+		_ = service.CloseSession("sandbox-1", session.ID)
+	}()
+
+	result, err = service.ExecuteInSession(ctx, "sandbox-1", session.ID, "echo in-session")
+	if err != nil {
+		t.Fatalf("ExecuteInSession failed: %v", err)
+	}
+
+	if content := readRecording(t, service, "sandbox-1", result.RecordingID); !strings.Contains(content, `"in-session"`) {
+		t.Fatalf("Expected session output in recording, got:\n%s", content)
+	}
+
+	recordings, err := service.ListRecordings("sandbox-1")
+	if err != nil {
+		t.Fatalf("ListRecordings failed: %v", err)
+	}
+
+	if len(recordings) != 2 || recordings[0].ID != result.RecordingID || recordings[0].Kind != recording.KindExecute {
+		t.Fatalf("Unexpected recordings: %+v", recordings)
+	}
+}
+
+func TestTerminal_Recording(t *testing.T) {
+	service := newRecordingService(t)
+
+	term, err := service.StartTerminal("sandbox-1", "", 80, 24)
+	if err != nil {
+		t.Fatalf("Failed to start terminal: %v", err)
+	}
+
+	_, output, unsubscribe := term.Subscribe()
+	defer unsubscribe()
+
+	if err := term.Resize(100, 30); err != nil {
+		t.Fatalf("Failed to resize terminal: %v", err)
+	}
+
+	if err := term.Write([]byte("echo $((40 + 2))\nexit\n")); err != nil {
+		t.Fatalf("Failed to write to terminal: %v", err)
+	}
+
+	readTerminalUntil(t, output, "42")
+
+	select {
+	case <-term.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Terminal did not exit")
+	}
+
+	content := readRecording(t, service, "sandbox-1", term.RecordingID)
+	for _, want := range []string{`"width":80`, `"TERM":"xterm-256color"`, `"r", "100x30"`, `42`} {
+		if !strings.Contains(content, want) {
+			t.Fatalf("Expected recording to contain %s, got:\n%s", want, content)
+		}
+	}
+
+	recordings, _ := service.ListRecordings("sandbox-1")
+	if len(recordings) != 1 || recordings[0].EndedAt.IsZero() {
+		t.Fatalf("Expected a finished terminal recording, got %+v", recordings)
+	}
+}
+
+func TestRecording_Disabled(t *testing.T) {
+	service := NewService(30, t.TempDir())
+
+	result, err := service.Execute(context.Background(), &ExecuteRequest{SandboxID: "sandbox-1", Command: "true"})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if result.RecordingID != "" {
+		t.Fatalf("Expected no recording, got %s", result.RecordingID)
+	}
+
+	if _, err := service.ListRecordings("sandbox-1"); !errors.Is(err, ErrRecordingDisabled) {
+		t.Fatalf("Expected ErrRecordingDisabled, got %v", err)
+	}
+
+	if _, _, err := service.OpenRecording("sandbox-1", "any"); !errors.Is(err, ErrRecordingDisabled) {
+		t.Fatalf("Expected ErrRecordingDisabled, got %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/HJH0924/agent-sandbox/internal/secret"
+)
+
+// ErrSecretsDisabled 服务没有配置密钥存储.
+var ErrSecretsDisabled = errors.New("secrets are disabled")
+
+// WithSecrets 将沙箱的密钥作为环境变量注入命令，并从返回的输出中替换密钥值.
+func WithSecrets(store *secret.Store) Option {
+	return func(s *Service) {
+		s.secrets = store
+	}
+}
+
+// SetSecrets 设置沙箱的密钥，只对之后启动的命令、会话、终端和内核生效.
+func (s *Service) SetSecrets(sandboxID string, secrets map[string]string) error {
+	if s.secrets == nil {
+		return ErrSecretsDisabled
+	}
+
+	return s.secrets.Set(sandboxID, secrets)
+}
+
+// DeleteSecrets 删除沙箱的密钥，不存在的名称被忽略.
+func (s *Service) DeleteSecrets(sandboxID string, names []string) error {
+	if s.secrets == nil {
+		return ErrSecretsDisabled
+	}
+
+	s.secrets.Delete(sandboxID, names)
+
+	return nil
+}
+
+// ListSecrets 按名称排序返回沙箱的密钥名称，不返回密钥值.
+func (s *Service) ListSecrets(sandboxID string) ([]secret.Info, error) {
+	if s.secrets == nil {
+		return nil, ErrSecretsDisabled
+	}
+
+	return s.secrets.List(sandboxID), nil
+}
+
+// Redact 将文本中沙箱的密钥值替换为 [REDACTED:NAME].
+func (s *Service) Redact(sandboxID, text string) string {
+	return s.secrets.Redact(sandboxID, text)
+}
+
+// NewRedactor 创建替换沙箱输出流中密钥值的替换器，跨越多次写入的密钥值同样被替换.
+func (s *Service) NewRedactor(sandboxID string) *secret.Redactor {
+	return s.secrets.NewRedactor(sandboxID)
+}
+
+// redactResult 替换执行结果输出中的密钥值.
+func (s *Service) redactResult(sandboxID string, result *ExecuteResult) {
+	result.Output = s.Redact(sandboxID, result.Output)
+	result.Stdout = s.Redact(sandboxID, result.Stdout)
+	result.Stderr = s.Redact(sandboxID, result.Stderr)
+	result.StdoutData = []byte(s.Redact(sandboxID, string(result.StdoutData)))
+	result.StderrData = []byte(s.Redact(sandboxID, string(result.StderrData)))
+}
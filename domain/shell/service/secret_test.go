@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HJH0924/agent-sandbox/internal/history"
+	"github.com/HJH0924/agent-sandbox/internal/secret"
+)
+
+func TestExecute_Secrets(t *testing.T) {
+	store := secret.NewStore()
+	executions := history.NewStore(10, true)
+	service := newRecordingService(t, WithSecrets(store), WithHistory(executions))
+	ctx := context.Background()
+
+	if err := service.SetSecrets("sandbox-1", map[string]string{"API_TOKEN": "tok-123456"}); err != nil {
+		t.Fatalf("SetSecrets failed: %v", err)
+	}
+
+	result, err := service.Execute(ctx, &ExecuteRequest{
+		SandboxID: "sandbox-1",
+		Command:   `test "$API_TOKEN" = tok-123456 && echo "token: $API_TOKEN" && echo "$API_TOKEN" >&2`,
+	})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if result.Output != "token: [REDACTED:API_TOKEN]\n\n[REDACTED:API_TOKEN]\n" {
+		t.Errorf("Unexpected output: %q", result.Output)
+	}
+
+	if strings.Contains(result.Stdout+result.Stderr, "tok-123456") {
+		t.Errorf("Expected raw output to be redacted, got %q and %q", result.Stdout, result.Stderr)
+	}
+
+	if content := readRecording(t, service, "sandbox-1", result.RecordingID); strings.Contains(content, "tok-123456") {
+		t.Errorf("Expected recording to be redacted, got %s", content)
+	}
+
+	// 命令中直接出现的密钥值同样不会进入执行历史
+	_, _ = service.Execute(ctx, &ExecuteRequest{SandboxID: "sandbox-1", Command: "echo tok-123456"})
+
+	page, err := executions.List("sandbox-1", 10, "", true)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+
+	for _, e := range page.Entries {
+		if strings.Contains(e.Command+e.Output, "tok-123456") {
+			t.Errorf("Expected history entry to be redacted, got %+v", e)
+		}
+	}
+
+	// 其他沙箱看不到密钥
+	result, err = service.Execute(ctx, &ExecuteRequest{SandboxID: "sandbox-2", Command: `echo "[$API_TOKEN]"`})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if result.Output != "[]\n" {
+		t.Errorf("Expected no secret in another sandbox, got %q", result.Output)
+	}
+
+	if err := service.DeleteSecrets("sandbox-1", []string{"API_TOKEN"}); err != nil {
+		t.Fatalf("DeleteSecrets failed: %v", err)
+	}
+
+	result, err = service.Execute(ctx, &ExecuteRequest{SandboxID: "sandbox-1", Command: `echo "[$API_TOKEN]"`})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if result.Output != "[]\n" {
+		t.Errorf("Expected deleted secret to be gone, got %q", result.Output)
+	}
+}
+
+func TestSession_Secrets(t *testing.T) {
+	store := secret.NewStore()
+	service := NewService(30, t.TempDir(), WithSecrets(store))
+
+	if err := store.Set("sandbox-1", map[string]string{"DB_PASSWORD": "hunter22"}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	session, err := service.CreateSession("sandbox-1")
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	defer func() {
+		_ = service.CloseSession("sandbox-1", session.ID)
+	}()
+
+	result, err := service.Execute(context.Background(), &ExecuteRequest{
+		SandboxID: "sandbox-1",
+		SessionID: session.ID,
+		Command:   `echo "pw=$DB_PASSWORD"`,
+	})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if result.Output != "pw=[REDACTED:DB_PASSWORD]\n" {
+		t.Errorf("Unexpected output: %q", result.Output)
+	}
+}
+
+func TestSecrets_Disabled(t *testing.T) {
+	service := NewService(30, t.TempDir())
+
+	if err := service.SetSecrets("sandbox-1", map[string]string{"TOKEN": "tok-123456"}); !errors.Is(err, ErrSecretsDisabled) {
+		t.Errorf("Expected ErrSecretsDisabled, got %v", err)
+	}
+
+	if _, err := service.ListSecrets("sandbox-1"); !errors.Is(err, ErrSecretsDisabled) {
+		t.Errorf("Expected ErrSecretsDisabled, got %v", err)
+	}
+
+	if got := service.Redact("sandbox-1", "tok-123456"); got != "tok-123456" {
+		t.Errorf("Expected text unchanged, got %q", got)
+	}
+}
+
+// splitSecretCommand 分两次输出 "token: tok-123456 end"，密钥值被拆分在两次写入中.
+const splitSecretCommand = `printf 'token: tok-\061\062'; sleep 0.2; printf '\063456 end'`
+
+func TestTerminal_SecretSplitAcrossReads(t *testing.T) {
+	store := secret.NewStore()
+	service := newRecordingService(t, WithSecrets(store))
+
+	if err := store.Set("sandbox-1", map[string]string{"API_TOKEN": "tok-123456"}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	// 密钥值分两次写入，终端分两次读取；用转义写出密钥值，录制的命令中不出现它的片段
+	term, err := service.StartTerminal("sandbox-1", splitSecretCommand, 80, 24)
+	if err != nil {
+		t.Fatalf("Failed to start terminal: %v", err)
+	}
+
+	select {
+	case <-term.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Terminal did not exit")
+	}
+
+	backlog, _, unsubscribe := term.Subscribe()
+	defer unsubscribe()
+
+	if !strings.Contains(string(backlog), "token: [REDACTED:API_TOKEN] end") || strings.Contains(string(backlog), "tok-12") {
+		t.Errorf("Expected split secret to be redacted, got %q", backlog)
+	}
+
+	if content := readRecording(t, service, "sandbox-1", term.RecordingID); strings.Contains(content, "tok-12") {
+		t.Errorf("Expected recording to be redacted, got %s", content)
+	}
+}
+
+func TestExecute_SecretSplitAcrossWrites(t *testing.T) {
+	store := secret.NewStore()
+	service := newRecordingService(t, WithSecrets(store))
+
+	if err := store.Set("sandbox-1", map[string]string{"API_TOKEN": "tok-123456"}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	result, err := service.Execute(context.Background(), &ExecuteRequest{
+		SandboxID: "sandbox-1",
+		Command:   splitSecretCommand,
+	})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if result.Output != "token: [REDACTED:API_TOKEN] end" {
+		t.Errorf("Unexpected output: %q", result.Output)
+	}
+
+	if content := readRecording(t, service, "sandbox-1", result.RecordingID); strings.Contains(content, "tok-12") {
+		t.Errorf("Expected recording to be redacted, got %s", content)
+	}
+}
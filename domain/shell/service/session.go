@@ -0,0 +1,420 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/HJH0924/agent-sandbox/internal/recording"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// maxSessionsPerSandbox 每个沙箱允许同时存在的持久会话数.
+	maxSessionsPerSandbox = 16
+	// sessionIdleTimeout 持久会话的最长空闲时间.
+	sessionIdleTimeout = 30 * time.Minute
+	// sentinelPrefix 命令结束标记的前缀.
+	sentinelPrefix = "__AGENT_SANDBOX_DONE_"
+)
+
+var (
+	// ErrSessionNotFound 会话不存在或不属于当前沙箱.
+	ErrSessionNotFound = errors.New("shell session not found")
+	// ErrTooManySessions 沙箱的持久会话数已达上限.
+	ErrTooManySessions = fmt.Errorf("too many shell sessions (max: %d)", maxSessionsPerSandbox)
+	// ErrSessionTerminated 会话的 shell 进程已退出.
+	ErrSessionTerminated = errors.New("shell session terminated")
+)
+
+// Session 持久 shell 会话，同一会话内的命令依次在同一个 shell 进程中执行.
+type Session struct {
+	ID        string
+	SandboxID string
+
+	cmd    *exec.Cmd
+	dir    string
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+	stderr *bufio.Reader
+
+	// cwd shell 当前的工作目录（命令看到的路径），每条命令结束后更新
+	cwd string
+
+	// newCapture 创建每条命令的输出缓冲区
+	newCapture func(dir string) (*capture, error)
+
+	// mu 保证同一时间只有一条命令在会话中执行
+	mu        sync.Mutex
+	idleTimer *time.Timer
+	closeOnce sync.Once
+}
+
+// CreateSession 为沙箱创建持久 shell 会话.
+func (s *Service) CreateSession(sandboxID string) (*Session, error) {
+	// 在锁内占用名额，启动 shell 和读走登录 profile 的输出期间不持有锁，以免阻塞其他沙箱的会话
+	if err := s.reserveSession(sandboxID); err != nil {
+		return nil, err
+	}
+
+	session, err := s.startSession(sandboxID)
+
+	s.sessionsMu.Lock()
+	s.releaseSessionLocked(sandboxID)
+
+	if err == nil {
+		s.sessions[session.ID] = session
+	}
+	s.sessionsMu.Unlock()
+
+	return session, err
+}
+
+// reserveSession 为沙箱占用一个会话名额，已启动和启动中的会话数达到上限时返回 ErrTooManySessions.
+func (s *Service) reserveSession(sandboxID string) error {
+	s.sessionsMu.Lock()
+	defer s.sessionsMu.Unlock()
+
+	count := s.sessionsStarting[sandboxID]
+
+	for _, session := range s.sessions {
+		if session.SandboxID == sandboxID {
+			count++
+		}
+	}
+
+	if count >= maxSessionsPerSandbox {
+		return ErrTooManySessions
+	}
+
+	s.sessionsStarting[sandboxID]++
+
+	return nil
+}
+
+// releaseSessionLocked 归还 reserveSession 占用的名额，调用方必须持有 sessionsMu.
+func (s *Service) releaseSessionLocked(sandboxID string) {
+	if s.sessionsStarting[sandboxID]--; s.sessionsStarting[sandboxID] <= 0 {
+		delete(s.sessionsStarting, sandboxID)
+	}
+}
+
+// startSession 启动会话的 shell 进程，调用方负责将会话加入会话表.
+func (s *Service) startSession(sandboxID string) (*Session, error) {
+	// 与 Execute 和终端使用同一个配置的 shell，从 stdin 读取命令
+	cmd := exec.Command(s.shell.Path, s.shell.args("")...)
+	startNewProcessGroup(cmd)
+
+	dir, user, err := s.prepareCommand(cmd, sandboxID)
+	if err != nil {
+		return nil, err
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdin pipe: %w", err)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stderr pipe: %w", err)
+	}
+
+	limits, err := s.applySandbox(cmd, sandboxID, dir, true)
+	if err != nil {
+		return nil, err
+	}
+
+	err = limits.start(cmd.Start)
+	limits.release()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to start shell session: %w", err)
+	}
+
+	// 在会话的 shell 中加载 profile，其输出被丢弃，函数和别名在之后的命令中可用
+	if profile := s.profile(); profile != "" {
+		if _, err := io.WriteString(stdin, ". "+quoteArg(profilePath(profile))+" >/dev/null 2>&1 </dev/null\n"); err != nil {
+			killGroup(cmd.Process.Pid)
+			_ = cmd.Wait()
+
+			return nil, fmt.Errorf("failed to load profile: %w", err)
+		}
+	}
+
+	session := &Session{
+		ID:        uuid.New().String(),
+		SandboxID: sandboxID,
+		cmd:       cmd,
+		dir:       dir,
+		cwd:       s.visibleDir(dir),
+		stdin:     stdin,
+		stdout:    bufio.NewReader(stdout),
+		stderr:    bufio.NewReader(stderr),
+		newCapture: func(dir string) (*capture, error) {
+			return s.newCapture(dir, user)
+		},
+	}
+	session.idleTimer = time.AfterFunc(sessionIdleTimeout, func() {
+		_ = s.CloseSession(sandboxID, session.ID)
+	})
+
+	// 登录 shell 启动时加载的 profile 可能有输出，先执行一条空命令将其读走，以免混入第一条命令的输出
+	if s.shell.Login {
+		ctx, cancel := context.WithTimeout(context.Background(), s.defaultTimeout)
+		_, err := session.run(ctx, ":", nil)
+		cancel()
+
+		if err != nil {
+			session.close()
+			return nil, fmt.Errorf("failed to start shell session: %w", err)
+		}
+	}
+
+	return session, nil
+}
+
+// CloseSession 关闭沙箱的持久 shell 会话.
+func (s *Service) CloseSession(sandboxID, sessionID string) error {
+	s.sessionsMu.Lock()
+
+	session, ok := s.sessions[sessionID]
+	if !ok || session.SandboxID != sandboxID {
+		s.sessionsMu.Unlock()
+		return ErrSessionNotFound
+	}
+
+	delete(s.sessions, sessionID)
+	s.sessionsMu.Unlock()
+
+	session.close()
+
+	return nil
+}
+
+// CloseSandbox 关闭沙箱的所有持久会话和终端，结束 cgroup 中仍在运行的进程（包括后台命令）并删除 cgroup，用于销毁沙箱.
+// 沙箱用户的其他进程在释放用户时结束.
+func (s *Service) CloseSandbox(sandboxID string) {
+	var sessions []*Session
+
+	s.sessionsMu.Lock()
+	for id, session := range s.sessions {
+		if session.SandboxID == sandboxID {
+			sessions = append(sessions, session)
+			delete(s.sessions, id)
+		}
+	}
+	s.sessionsMu.Unlock()
+
+	for _, session := range sessions {
+		session.close()
+	}
+
+	var terminals []*Terminal
+
+	s.terminalsMu.Lock()
+	for _, t := range s.terminals {
+		if t.SandboxID == sandboxID {
+			terminals = append(terminals, t)
+		}
+	}
+	s.terminalsMu.Unlock()
+
+	// 终端在进程退出后自行从列表中移除
+	for _, t := range terminals {
+		t.Close()
+	}
+
+	// cgroup 中仍有进程时无法删除，此时保留它
+	if s.cgroup != nil && s.cgroup.Kill(sandboxID) == nil {
+		_ = s.cgroup.Remove(sandboxID)
+	}
+}
+
+// lookupSession 查找属于沙箱的会话.
+func (s *Service) lookupSession(sandboxID, sessionID string) (*Session, error) {
+	s.sessionsMu.Lock()
+	session, ok := s.sessions[sessionID]
+	s.sessionsMu.Unlock()
+
+	if !ok || session.SandboxID != sandboxID {
+		return nil, ErrSessionNotFound
+	}
+
+	return session, nil
+}
+
+// executeInSession 在会话中执行已通过策略检查的命令，输出同时写入 rec.
+func (s *Service) executeInSession(ctx context.Context, session *Session, command string, rec *recording.Recording) (*ExecuteResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.defaultTimeout)
+	defer cancel()
+
+	result, err := session.run(ctx, command, rec)
+	if err != nil {
+		// 会话状态已不可知（超时或 shell 退出），直接关闭
+		_ = s.CloseSession(session.SandboxID, session.ID)
+		return nil, err
+	}
+
+	session.idleTimer.Reset(sessionIdleTimeout)
+
+	return result, nil
+}
+
+// run 向 shell 写入命令，并读取 stdout/stderr 直到出现结束标记，输出同时写入 rec.
+func (sess *Session) run(ctx context.Context, command string, rec *recording.Recording) (*ExecuteResult, error) {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+
+	marker := sentinelPrefix + uuid.New().String()
+
+	// 命令的标准输入重定向到 /dev/null，避免读取到后续写入的脚本；
+	// 花括号保证 cd、export 等在当前 shell 中生效. stderr 的结束标记后附带命令结束时的工作目录
+	script := fmt.Sprintf("{\n%s\n} </dev/null\n__rc=$?\nprintf '\\n%s %%d\\n' \"$__rc\"\nprintf '\\n%s %%s\\n' \"$PWD\" >&2\n",
+		command, marker, marker)
+
+	if _, err := io.WriteString(sess.stdin, script); err != nil {
+		return nil, ErrSessionTerminated
+	}
+
+	out, err := sess.newCapture(sess.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	out.record(rec)
+
+	stdoutCh := make(chan error, 1)
+	stderrCh := make(chan error, 1)
+
+	var (
+		exitCode int
+		cwd      string
+	)
+
+	go func() {
+		var err error
+
+		exitCode, err = CopyUntilMarker(sess.stdout, out.stdout, marker)
+		stdoutCh <- err
+	}()
+
+	go func() {
+		var err error
+
+		cwd, err = readUntilMarker(sess.stderr, out.stderr, marker)
+		stderrCh <- err
+	}()
+
+	var stdoutErr, stderrErr error
+
+	for received := 0; received < 2; received++ {
+		select {
+		case stdoutErr = <-stdoutCh:
+		case stderrErr = <-stderrCh:
+		case <-ctx.Done():
+			// 会话随后会被关闭，等读取结束后再释放输出文件
+			go func(pending int) {
+				for ; pending > 0; pending-- {
+					select {
+					case <-stdoutCh:
+					case <-stderrCh:
+					}
+				}
+
+				out.discard()
+			}(2 - received)
+
+			return nil, fmt.Errorf("command execution failed: %w", ctx.Err())
+		}
+	}
+
+	result := out.result(sess.dir)
+
+	if stdoutErr != nil || stderrErr != nil {
+		return nil, ErrSessionTerminated
+	}
+
+	result.ExitCode = exitCode
+	result.Cwd = sess.cwd
+
+	if cwd != "" {
+		sess.cwd = cwd
+	}
+
+	return result, nil
+}
+
+// close 终止会话的 shell 进程.
+func (sess *Session) close() {
+	sess.closeOnce.Do(func() {
+		sess.idleTimer.Stop()
+		_ = sess.stdin.Close()
+
+		// 终止 shell 及其启动的所有进程
+		if sess.cmd.Process != nil {
+			killGroup(sess.cmd.Process.Pid)
+		}
+
+		go func() {
+			_ = sess.cmd.Wait()
+		}()
+	})
+}
+
+// CopyUntilMarker 将输出写入 w，直到读到以结束标记开头的行，返回标记后的退出码.
+// 写入标记前额外添加的换行不会写入 w. 代码解释器等同样按结束标记划分输出的进程也使用它.
+func CopyUntilMarker(r *bufio.Reader, w io.Writer, marker string) (int, error) {
+	rest, err := readUntilMarker(r, w, marker)
+	exitCode, _ := strconv.Atoi(rest)
+
+	return exitCode, err
+}
+
+// readUntilMarker 与 CopyUntilMarker 相同，返回标记行中标记之后的内容（去掉首尾空白）.
+func readUntilMarker(r *bufio.Reader, w io.Writer, marker string) (string, error) {
+	prefix := []byte(marker)
+	atLineStart := true
+	pendingNewline := false
+
+	for {
+		chunk, err := r.ReadSlice('\n')
+		if atLineStart && err == nil && bytes.HasPrefix(chunk, prefix) {
+			return string(bytes.TrimSpace(chunk[len(prefix):])), nil
+		}
+
+		if pendingNewline {
+			_, _ = w.Write([]byte("\n"))
+			pendingNewline = false
+		}
+
+		// 行尾的换行先暂存，确认下一行不是结束标记后再写入
+		if err == nil {
+			_, _ = w.Write(chunk[:len(chunk)-1])
+			pendingNewline = true
+			atLineStart = true
+
+			continue
+		}
+
+		_, _ = w.Write(chunk)
+		atLineStart = false
+
+		if !errors.Is(err, bufio.ErrBufferFull) {
+			return "", err
+		}
+	}
+}
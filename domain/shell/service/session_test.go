@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestShellService_SessionKeepsState(t *testing.T) {
+	tmpDir := t.TempDir()
+	service := NewService(30, tmpDir)
+	ctx := context.Background()
+
+	session, err := service.CreateSession("sandbox-1")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	defer func() {
+		_ = service.CloseSession("sandbox-1", session.ID)
+	}()
+
+	commands := []string{"mkdir -p sub && cd sub", "export GREETING=hello"}
+	for _, command := range commands {
+		result, err := service.ExecuteInSession(ctx, "sandbox-1", session.ID, command)
+		if err != nil {
+			t.Fatalf("Failed to execute %q: %v", command, err)
+		}
+
+		if result.ExitCode != 0 {
+			t.Fatalf("Expected exit code 0 for %q, got %d", command, result.ExitCode)
+		}
+	}
+
+	result, err := service.ExecuteInSession(ctx, "sandbox-1", session.ID, "pwd; echo $GREETING")
+	if err != nil {
+		t.Fatalf("Failed to execute command: %v", err)
+	}
+
+	expected := tmpDir + "/sub\nhello\n"
+	if result.Output != expected {
+		t.Fatalf("Expected output %q, got %q", expected, result.Output)
+	}
+}
+
+func TestShellService_SessionExitCodeAndStderr(t *testing.T) {
+	service := NewService(30, t.TempDir())
+	ctx := context.Background()
+
+	session, err := service.CreateSession("sandbox-1")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	defer func() {
+		_ = service.CloseSession("sandbox-1", session.ID)
+	}()
+
+	result, err := service.ExecuteInSession(ctx, "sandbox-1", session.ID, "printf out; echo err >&2; false")
+	if err != nil {
+		t.Fatalf("Failed to execute command: %v", err)
+	}
+
+	if result.ExitCode != 1 {
+		t.Fatalf("Expected exit code 1, got %d", result.ExitCode)
+	}
+
+	if result.Output != "out\nerr\n" {
+		t.Fatalf("Expected combined output, got %q", result.Output)
+	}
+
+	// 命令不会读取到会话的标准输入
+	result, err = service.ExecuteInSession(ctx, "sandbox-1", session.ID, "cat; echo after")
+	if err != nil {
+		t.Fatalf("Failed to execute command: %v", err)
+	}
+
+	if strings.TrimSpace(result.Output) != "after" {
+		t.Fatalf("Expected output %q, got %q", "after", result.Output)
+	}
+}
+
+func TestShellService_SessionOwnership(t *testing.T) {
+	service := NewService(30, t.TempDir())
+
+	session, err := service.CreateSession("sandbox-1")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	_, err = service.ExecuteInSession(context.Background(), "sandbox-2", session.ID, "pwd")
+	if !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("Expected ErrSessionNotFound, got %v", err)
+	}
+
+	if err := service.CloseSession("sandbox-2", session.ID); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("Expected ErrSessionNotFound, got %v", err)
+	}
+
+	if err := service.CloseSession("sandbox-1", session.ID); err != nil {
+		t.Fatalf("Failed to close session: %v", err)
+	}
+
+	_, err = service.ExecuteInSession(context.Background(), "sandbox-1", session.ID, "pwd")
+	if !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("Expected ErrSessionNotFound after close, got %v", err)
+	}
+}
+
+func TestShellService_SessionTerminated(t *testing.T) {
+	service := NewService(30, t.TempDir())
+
+	session, err := service.CreateSession("sandbox-1")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	_, err = service.ExecuteInSession(context.Background(), "sandbox-1", session.ID, "exit 0")
+	if !errors.Is(err, ErrSessionTerminated) {
+		t.Fatalf("Expected ErrSessionTerminated, got %v", err)
+	}
+
+	_, err = service.ExecuteInSession(context.Background(), "sandbox-1", session.ID, "pwd")
+	if !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("Expected terminated session to be removed, got %v", err)
+	}
+}
+
+func TestShellService_SessionTimeout(t *testing.T) {
+	service := NewService(1, t.TempDir())
+
+	session, err := service.CreateSession("sandbox-1")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	_, err = service.ExecuteInSession(context.Background(), "sandbox-1", session.ID, "sleep 5")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected timeout error, got %v", err)
+	}
+}
+
+func TestShellService_SessionStartDoesNotBlock(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not available")
+	}
+
+	workspace := t.TempDir()
+
+	// HOME 为工作空间，登录 shell 在 slow 文件存在时加载 profile 很慢
+	if err := os.WriteFile(filepath.Join(workspace, ".bash_profile"), []byte("[ -f slow ] && sleep 2\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write profile: %v", err)
+	}
+
+	service := NewService(30, workspace, WithShell(Shell{Path: bash, Login: true}), WithEnvironment(Environment{}))
+
+	session, err := service.CreateSession("sandbox-1")
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	defer func() {
+		_ = service.CloseSession("sandbox-1", session.ID)
+	}()
+
+	if err := os.WriteFile(filepath.Join(workspace, "slow"), nil, 0o600); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	created := make(chan error, 1)
+
+	go func() {
+		slow, err := service.CreateSession("sandbox-2")
+		if err == nil {
+			_ = service.CloseSession("sandbox-2", slow.ID)
+		}
+
+		created <- err
+	}()
+
+	time.Sleep(200 * time.Millisecond)
+
+	// 其他会话中的命令不等待正在启动的会话
+	start := time.Now()
+
+	result, err := service.Execute(context.Background(), &ExecuteRequest{SandboxID: "sandbox-1", SessionID: session.ID, Command: "echo ok"})
+	if err != nil || result.Output != "ok\n" {
+		t.Fatalf("Execute failed: %+v, %v", result, err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Execute waited for another session to start: %s", elapsed)
+	}
+
+	if err := <-created; err != nil {
+		t.Fatalf("Failed to create slow session: %v", err)
+	}
+}
+
+func TestShellService_SessionCountLimit(t *testing.T) {
+	service := NewService(30, t.TempDir())
+
+	// 并发创建时启动中的会话同样计入上限
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		created []*Session
+		limited int
+	)
+
+	for range 2 * maxSessionsPerSandbox {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			session, err := service.CreateSession("sandbox-1")
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			switch {
+			case err == nil:
+				created = append(created, session)
+			case errors.Is(err, ErrTooManySessions):
+				limited++
+			default:
+				t.Errorf("CreateSession failed: %v", err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if len(created) != maxSessionsPerSandbox || limited != maxSessionsPerSandbox {
+		t.Fatalf("Expected %d sessions and %d rejections, got %d and %d", maxSessionsPerSandbox, maxSessionsPerSandbox, len(created), limited)
+	}
+
+	for _, session := range created {
+		_ = service.CloseSession("sandbox-1", session.ID)
+	}
+
+	// 关闭后名额被归还
+	session, err := service.CreateSession("sandbox-1")
+	if err != nil {
+		t.Fatalf("Expected a free slot after closing sessions, got %v", err)
+	}
+
+	_ = service.CloseSession("sandbox-1", session.ID)
+}
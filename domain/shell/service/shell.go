@@ -2,82 +2,467 @@
 package service
 
 import (
-	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 	"path/filepath"
+	"sync"
+	"syscall"
 	"time"
+
+	"github.com/HJH0924/agent-sandbox/internal/approval"
+	"github.com/HJH0924/agent-sandbox/internal/history"
+	"github.com/HJH0924/agent-sandbox/internal/policy"
+	"github.com/HJH0924/agent-sandbox/internal/recording"
+	"github.com/HJH0924/agent-sandbox/internal/sandbox"
+	"github.com/HJH0924/agent-sandbox/internal/secret"
+
+	"github.com/google/uuid"
 )
 
+// errExecuteTimeout 命令运行超过服务端配置的超时时间.
+var errExecuteTimeout = errors.New("command timed out")
+
 // Service Shell 服务.
 type Service struct {
 	defaultTimeout time.Duration
 	workspaceDir   string
+	maxOutputSize  int64
+	spillOutput    bool
+	killGrace      time.Duration
+	limits         ResourceLimits
+	cgroup         *Cgroup
+	users          *sandbox.Users
+	isolation      *Isolation
+	registry       *sandbox.Registry
+	policy         *policy.Policy
+	approvals      *approval.Queue
+	limiter        *limiter
+	shell          Shell
+	history        *history.Store
+	recordings     *recording.Store
+	secrets        *secret.Store
+	env            *Environment
+
+	terminalsMu sync.Mutex
+	terminals   map[string]*Terminal
+
+	sessionsMu sync.Mutex
+	sessions   map[string]*Session
+	// sessionsStarting 每个沙箱正在启动的会话数，与已启动的会话一起计入上限
+	sessionsStarting map[string]int
+}
+
+// Option Shell 服务的可选配置.
+type Option func(*Service)
+
+// WithMaxOutputSize 设置每个输出流（stdout/stderr）保留的最大字节数，<= 0 表示不限制.
+func WithMaxOutputSize(size int64) Option {
+	return func(s *Service) {
+		s.maxOutputSize = size
+	}
+}
+
+// WithOutputSpill 设置输出被截断时是否将完整输出保存到工作空间中的文件.
+func WithOutputSpill(enabled bool) Option {
+	return func(s *Service) {
+		s.spillOutput = enabled
+	}
+}
+
+// WithKillGracePeriod 设置超时或取消时从 SIGTERM 到 SIGKILL 的宽限期.
+func WithKillGracePeriod(grace time.Duration) Option {
+	return func(s *Service) {
+		s.killGrace = grace
+	}
+}
+
+// WithUsers 以沙箱专属的非特权用户身份运行命令，工作目录为该沙箱的工作空间.
+func WithUsers(users *sandbox.Users) Option {
+	return func(s *Service) {
+		s.users = users
+	}
 }
 
 // NewService 创建 Shell 服务实例.
-func NewService(defaultTimeout int, workspaceDir string) *Service {
-	return &Service{
-		defaultTimeout: time.Duration(defaultTimeout) * time.Second,
-		workspaceDir:   workspaceDir,
+func NewService(defaultTimeout int, workspaceDir string, opts ...Option) *Service {
+	s := &Service{
+		defaultTimeout:   time.Duration(defaultTimeout) * time.Second,
+		workspaceDir:     workspaceDir,
+		killGrace:        defaultKillGracePeriod,
+		shell:            defaultShell,
+		terminals:        make(map[string]*Terminal),
+		sessions:         make(map[string]*Session),
+		sessionsStarting: make(map[string]int),
+	}
+
+	for _, opt := range opts {
+		opt(s)
 	}
+
+	return s
+}
+
+// ExecuteRequest 执行请求.
+type ExecuteRequest struct {
+	SandboxID string
+	// Command 通过 shell 执行的命令字符串
+	Command string
+	// Argv 不经过 shell 直接执行的程序和参数，与 Command 互斥
+	Argv []string
+	// SessionID 非空时在指定的持久会话中执行
+	SessionID string
+	// ApprovalID 继续等待之前提交的审批请求，命令必须与提交时相同
+	ApprovalID string
+	// PollApproval 为 true 时命令需要审批则立即返回审批请求，不等待审批结果
+	PollApproval bool
+	// CallerKeyID 调用方 API Key 的标识，记录在执行历史中
+	CallerKeyID string
 }
 
 // ExecuteResult 执行结果.
 type ExecuteResult struct {
+	// Output 合并后的 stdout 和 stderr 的 UTF-8 文本形式，无效的 UTF-8 字节被替换为 U+FFFD
 	Output string
+	// Stdout/Stderr 分别保留的 stdout 和 stderr，被截断时插入截断说明，字节未做编码转换
+	Stdout string
+	Stderr string
+	// StdoutData/StderrData 保留的原始字节，被截断时为开头和结尾的字节直接拼接
+	StdoutData []byte
+	StderrData []byte
+	// StdoutEncoding/StderrEncoding 检测到的 stdout 和 stderr 编码
+	StdoutEncoding Encoding
+	StderrEncoding Encoding
+	ExitCode       int
+	// Cwd 命令开始执行时的工作目录（命令看到的路径）
+	Cwd string
+	// Truncated 表示 stdout 或 stderr 超过上限被截断
+	Truncated   bool
+	StdoutBytes int64
+	StderrBytes int64
+	// StdoutFile/StderrFile 被截断时保存完整输出的文件（相对工作空间的路径）
+	StdoutFile string
+	StderrFile string
+	// Signal 导致命令结束的信号（如 SIGTERM、SIGKILL），正常退出时为空
+	Signal string
+	// TimedOut 表示命令因超时被终止
+	TimedOut bool
+	// LimitsExceeded 命令运行期间触发的资源限制
+	LimitsExceeded []string
+	// ApprovalID 命令经过的审批请求
+	ApprovalID string
+	// ApprovalPending 表示命令正在等待审批，尚未执行
+	ApprovalPending bool
+	// QueueWait 命令因并发限制排队等待的时间
+	QueueWait time.Duration
+	// RecordingID 命令输出的录制，未开启录制时为空
+	RecordingID string
 }
 
-// Execute 执行 Shell 命令.
-func (s *Service) Execute(ctx context.Context, command string) (*ExecuteResult, error) {
-	// 创建带超时的 context
-	ctx, cancel := context.WithTimeout(ctx, s.defaultTimeout)
-	defer cancel()
+// Execute 按命令策略检查并执行 Shell 命令，需要审批的命令在审批通过后执行.
+// 开启执行历史时，通过校验的请求（包括被拒绝的命令）都会被记录.
+func (s *Service) Execute(ctx context.Context, req *ExecuteRequest) (result *ExecuteResult, err error) {
+	if req.Command != "" && len(req.Argv) > 0 {
+		return nil, ErrInvalidCommand
+	}
 
-	// 创建命令
-	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	var session *Session
 
-	// 设置工作目录
-	if s.workspaceDir != "" {
-		// 确保工作目录存在
-		absPath, err := filepath.Abs(s.workspaceDir)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	if req.SessionID != "" {
+		if session, err = s.lookupSession(req.SandboxID, req.SessionID); err != nil {
+			return nil, err
 		}
+	}
+
+	startedAt := time.Now()
+	defer func() {
+		s.record(req, startedAt, result, err)
+	}()
+
+	approvalID, pending, err := s.authorize(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if pending {
+		return &ExecuteResult{ApprovalID: approvalID, ApprovalPending: true}, nil
+	}
+
+	// 审批通过后再占用执行名额，等待审批不占用并发数
+	release, queueWait, err := s.limiter.acquire(ctx, req.SandboxID)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// 录制中先显示命令，与在终端中输入命令时看到的一致
+	rec := s.StartRecording(req.SandboxID, recording.KindExecute, req.commandLine(), 0, 0)
+	defer rec.Close()
+
+	_, _ = fmt.Fprintf(rec, "$ %s\n", req.commandLine())
+
+	if session != nil {
+		result, err = s.executeInSession(ctx, session, req.commandLine(), rec)
+	} else {
+		result, err = s.execute(ctx, req, rec)
+	}
+
+	if result != nil {
+		result.ApprovalID = approvalID
+		result.QueueWait = queueWait
+		result.RecordingID = rec.ID()
+		s.redactResult(req.SandboxID, result)
+	}
+
+	return result, err
+}
+
+// execute 执行已通过策略检查的命令，输出同时写入 rec.
+func (s *Service) execute(ctx context.Context, req *ExecuteRequest, rec *recording.Recording) (*ExecuteResult, error) {
+	// 创建带超时的 context，调用方更早的截止时间或取消不视为服务端超时
+	ctx, cancel := context.WithTimeoutCause(ctx, s.defaultTimeout, errExecuteTimeout)
+	defer cancel()
+
+	// 创建命令，超时或取消时终止整个进程组
+	cmd, err := s.newCommand(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	startNewProcessGroup(cmd)
+	finish := terminateOnCancel(cmd, s.killGrace)
+
+	// 设置工作目录和运行用户
+	dir, user, err := s.prepareCommand(cmd, req.SandboxID)
+	if err != nil {
+		return nil, err
+	}
 
-		cmd.Dir = absPath
+	// 应用资源限制
+	limits, err := s.applySandbox(cmd, req.SandboxID, dir, false)
+	if err != nil {
+		return nil, err
 	}
 
 	// 捕获输出
-	var stdout, stderr bytes.Buffer
+	out, err := s.newCapture(dir, user)
+	if err != nil {
+		limits.release()
+		return nil, err
+	}
 
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	out.record(rec)
+
+	cmd.Stdout = out.stdout
+	cmd.Stderr = out.stderr
 
 	// 执行命令
-	err := cmd.Run()
+	err = limits.start(cmd.Start)
+	limits.release()
+
+	if err == nil {
+		err = cmd.Wait()
+		finish()
+	}
+
+	// 命令已退出但后台任务仍持有输出管道，不视为失败
+	if errors.Is(err, exec.ErrWaitDelay) {
+		err = nil
+	}
 
 	// 合并 stdout 和 stderr
-	output := stdout.String()
-	if stderr.Len() > 0 {
-		if len(output) > 0 {
-			output += "\n"
-		}
+	result := out.result(dir)
+	result.Cwd = s.visibleDir(dir)
+	result.Signal = terminationSignal(err)
+	result.TimedOut = errors.Is(context.Cause(ctx), errExecuteTimeout)
+	result.LimitsExceeded = limits.exceeded(err)
 
-		output += stderr.String()
+	switch {
+	case result.TimedOut:
+		err = fmt.Errorf("timed out after %s: %w", s.defaultTimeout, err)
+	case ctx.Err() != nil:
+		// 调用方取消或调用方的截止时间已到
+		err = fmt.Errorf("%w: %w", context.Cause(ctx), err)
 	}
 
 	if err != nil {
-		// 如果命令执行失败，返回带输出的错误
-		if output != "" {
-			return &ExecuteResult{Output: output}, fmt.Errorf("command execution failed: %w", err)
+		// 命令已经运行时返回带输出和退出码的错误
+		if result.Output != "" || cmd.ProcessState != nil {
+			result.ExitCode = exitCode(err)
+			return result, fmt.Errorf("command execution failed: %w", err)
 		}
 
 		return nil, fmt.Errorf("command execution failed: %w", err)
 	}
 
-	return &ExecuteResult{
-		Output: output,
-	}, nil
+	return result, nil
+}
+
+// capture 一条命令的 stdout/stderr 输出缓冲区.
+type capture struct {
+	stdout *outputBuffer
+	stderr *outputBuffer
+}
+
+// newCapture 按配置创建输出缓冲区，user 非空时输出文件归属该沙箱用户.
+func (s *Service) newCapture(dir string, user *sandbox.User) (*capture, error) {
+	var stdoutPath, stderrPath string
+
+	if s.spillOutput && s.maxOutputSize > 0 && dir != "" {
+		name := uuid.New().String()
+		stdoutPath = filepath.Join(outputDir, name+".stdout")
+		stderrPath = filepath.Join(outputDir, name+".stderr")
+	}
+
+	stdout, err := newOutputBuffer(s.maxOutputSize, dir, stdoutPath, user)
+	if err != nil {
+		return nil, err
+	}
+
+	stderr, err := newOutputBuffer(s.maxOutputSize, dir, stderrPath, user)
+	if err != nil {
+		stdout.finish()
+		return nil, err
+	}
+
+	return &capture{stdout: stdout, stderr: stderr}, nil
+}
+
+// result 结束捕获并生成执行结果，dir 用于计算输出文件的相对路径.
+func (c *capture) result(dir string) *ExecuteResult {
+	stdout, stderr := c.stdout.Text(), c.stderr.Text()
+	stdoutText, stdoutEncoding := RenderText(stdout)
+	stderrText, stderrEncoding := RenderText(stderr)
+
+	result := &ExecuteResult{
+		Output:         combineOutput([]byte(stdoutText), []byte(stderrText)),
+		Stdout:         string(stdout),
+		Stderr:         string(stderr),
+		StdoutData:     c.stdout.Bytes(),
+		StderrData:     c.stderr.Bytes(),
+		StdoutEncoding: stdoutEncoding,
+		StderrEncoding: stderrEncoding,
+		Truncated:      c.stdout.Truncated() || c.stderr.Truncated(),
+		StdoutBytes:    c.stdout.Total(),
+		StderrBytes:    c.stderr.Total(),
+	}
+
+	result.StdoutFile = relativeTo(dir, c.stdout.finish())
+	result.StderrFile = relativeTo(dir, c.stderr.finish())
+
+	return result
+}
+
+// record 将两个输出流同时写入录制，rec 为 nil 时不录制.
+func (c *capture) record(rec *recording.Recording) {
+	if rec == nil {
+		return
+	}
+
+	c.stdout.tee = rec
+	c.stderr.tee = rec
+}
+
+// discard 结束捕获并删除输出文件.
+func (c *capture) discard() {
+	for _, b := range []*outputBuffer{c.stdout, c.stderr} {
+		if path := b.finish(); path != "" {
+			_ = os.Remove(path)
+		}
+	}
+}
+
+// relativeTo 返回 path 相对于 dir 的路径，path 为空时返回空字符串.
+func relativeTo(dir, path string) string {
+	if path == "" {
+		return ""
+	}
+
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return path
+	}
+
+	return rel
+}
+
+// workDir 返回沙箱命令的工作目录（绝对路径），未配置工作空间时返回空字符串.
+// 启用沙箱用户时返回该沙箱的专属工作空间.
+func (s *Service) workDir(sandboxID string) (string, *sandbox.User, error) {
+	if s.users != nil {
+		user, err := s.users.Lookup(sandboxID)
+		if err != nil {
+			return "", nil, err
+		}
+
+		return user.Workspace, user, nil
+	}
+
+	if s.workspaceDir == "" {
+		return "", nil, nil
+	}
+
+	absPath, err := filepath.Abs(s.workspaceDir)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	return absPath, nil, nil
+}
+
+// Workspace 返回沙箱命令的工作目录（绝对路径）和运行用户，未启用沙箱用户时用户为 nil.
+func (s *Service) Workspace(sandboxID string) (string, *sandbox.User, error) {
+	return s.workDir(sandboxID)
+}
+
+// prepareCommand 设置命令的工作目录和环境变量，启用沙箱用户时以该用户身份运行命令.
+// 环境变量为基础环境加上 cmd.Env 中调用方设置的变量（如终端的 TERM）和沙箱的密钥，
+// 必须在应用资源限制之前调用.
+func (s *Service) prepareCommand(cmd *exec.Cmd, sandboxID string) (string, *sandbox.User, error) {
+	dir, user, err := s.workDir(sandboxID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	cmd.Dir = dir
+	cmd.Env = append(append(s.environ(dir), cmd.Env...), s.secrets.Env(sandboxID)...)
+
+	if user == nil {
+		return dir, nil, nil
+	}
+
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+
+	cmd.SysProcAttr.Credential = &syscall.Credential{Uid: user.UID, Gid: user.GID}
+	cmd.Env = setEnv(cmd.Env, "HOME", user.Workspace)
+
+	return dir, user, nil
+}
+
+// exitCode 从命令执行错误中提取退出码，无法获取时返回 -1.
+func exitCode(err error) int {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+
+	return -1
+}
+
+// combineOutput 合并 stdout 和 stderr，两者都非空时以换行分隔.
+func combineOutput(stdout, stderr []byte) string {
+	output := string(stdout)
+	if len(stderr) > 0 {
+		if len(output) > 0 {
+			output += "\n"
+		}
+
+		output += string(stderr)
+	}
+
+	return output
 }
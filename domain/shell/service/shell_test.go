@@ -24,7 +24,7 @@ func TestShellService_Execute(t *testing.T) {
 	// Test simple command
 	ctx := context.Background()
 
-	result, err := service.Execute(ctx, "echo 'Hello, World!'")
+	result, err := service.Execute(ctx, &ExecuteRequest{Command: "echo 'Hello, World!'"})
 	if err != nil {
 		t.Fatalf("Failed to execute command: %v", err)
 	}
@@ -52,7 +52,7 @@ func TestShellService_WorkingDir(t *testing.T) {
 	// Execute pwd command to check working directory
 	ctx := context.Background()
 
-	result, err := service.Execute(ctx, "pwd")
+	result, err := service.Execute(ctx, &ExecuteRequest{Command: "pwd"})
 	if err != nil {
 		t.Fatalf("Failed to execute command: %v", err)
 	}
@@ -80,7 +80,7 @@ func TestShellService_FailedCommand(t *testing.T) {
 	// Execute a command that will fail
 	ctx := context.Background()
 
-	_, err = service.Execute(ctx, "exit 1")
+	_, err = service.Execute(ctx, &ExecuteRequest{Command: "exit 1"})
 	if err == nil {
 		t.Fatal("Expected error for failed command")
 	}
@@ -103,7 +103,7 @@ func TestShellService_Timeout(t *testing.T) {
 	// Execute a command that takes longer than timeout
 	ctx := context.Background()
 
-	_, err = service.Execute(ctx, "sleep 5")
+	_, err = service.Execute(ctx, &ExecuteRequest{Command: "sleep 5"})
 	if err == nil {
 		t.Fatal("Expected timeout error")
 	}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"syscall"
+)
+
+// Process 在沙箱中启动的长期运行进程（如代码解释器），调用方通过标准输入输出与其交互.
+type Process struct {
+	// Workspace 进程看到的工作空间路径，启用隔离时为 /workspace
+	Workspace string
+	Stdin     io.WriteCloser
+	Stdout    *bufio.Reader
+	Stderr    *bufio.Reader
+
+	cmd      *exec.Cmd
+	pipes    []io.Closer
+	done     chan struct{}
+	exitCode int
+}
+
+// Spawn 按命令策略检查 argv 后，在沙箱中启动长期运行的进程.
+// 进程与持久会话一样以沙箱用户身份在工作空间中运行，并应用隔离、网络策略和资源限制.
+func (s *Service) Spawn(ctx context.Context, sandboxID string, argv []string) (*Process, error) {
+	if len(argv) == 0 {
+		return nil, ErrInvalidCommand
+	}
+
+	req := &ExecuteRequest{SandboxID: sandboxID, Argv: argv}
+
+	if _, _, err := s.authorize(ctx, req); err != nil {
+		return nil, err
+	}
+
+	cmd, err := s.newCommand(context.Background(), req)
+	if err != nil {
+		return nil, err
+	}
+
+	startNewProcessGroup(cmd)
+
+	dir, _, err := s.prepareCommand(cmd, sandboxID)
+	if err != nil {
+		return nil, err
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdin pipe: %w", err)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stderr pipe: %w", err)
+	}
+
+	limits, err := s.applySandbox(cmd, sandboxID, dir, true)
+	if err != nil {
+		return nil, err
+	}
+
+	err = limits.start(cmd.Start)
+	limits.release()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to start process: %w", err)
+	}
+
+	p := &Process{
+		Workspace: s.visibleDir(dir),
+		Stdin:     stdin,
+		Stdout:    bufio.NewReader(stdout),
+		Stderr:    bufio.NewReader(stderr),
+		cmd:       cmd,
+		pipes:     []io.Closer{stdin, stdout, stderr},
+		done:      make(chan struct{}),
+	}
+
+	go func() {
+		// 不使用 cmd.Wait，以免进程退出时管道被关闭、剩余的输出无法读取
+		state, _ := cmd.Process.Wait()
+		if state != nil {
+			p.exitCode = state.ExitCode()
+		}
+
+		close(p.done)
+	}()
+
+	return p, nil
+}
+
+// Signal 向进程所在的进程组发送信号.
+func (p *Process) Signal(sig syscall.Signal) error {
+	return syscall.Kill(-p.cmd.Process.Pid, sig)
+}
+
+// Close 终止进程及其启动的所有进程，并关闭标准输入输出.
+func (p *Process) Close() {
+	killGroup(p.cmd.Process.Pid)
+
+	for _, pipe := range p.pipes {
+		_ = pipe.Close()
+	}
+}
+
+// Done 返回在进程退出后关闭的通道.
+func (p *Process) Done() <-chan struct{} {
+	return p.done
+}
+
+// ExitCode 返回进程的退出码，被信号终止时为 -1（启用隔离时为 128+N），仅在 Done 关闭后有效.
+func (p *Process) ExitCode() int {
+	return p.exitCode
+}
@@ -0,0 +1,309 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/HJH0924/agent-sandbox/internal/recording"
+	"github.com/HJH0924/agent-sandbox/internal/secret"
+
+	"github.com/creack/pty"
+	"github.com/google/uuid"
+)
+
+const (
+	// terminalBacklogSize 每个终端会话保留的最近输出字节数，用于重连时回放.
+	terminalBacklogSize = 64 * 1024
+	// terminalIdleTimeout 终端会话在无客户端连接时的最长保留时间.
+	terminalIdleTimeout = 10 * time.Minute
+	// terminalSubscriberBuffer 每个订阅者的输出缓冲区消息数.
+	terminalSubscriberBuffer = 256
+	// maxTerminalsPerSandbox 每个沙箱允许同时存在的终端会话数.
+	maxTerminalsPerSandbox = 16
+)
+
+var (
+	// ErrTerminalNotFound 终端会话不存在或不属于当前沙箱.
+	ErrTerminalNotFound = errors.New("terminal session not found")
+	// ErrTooManyTerminals 沙箱的终端会话数已达上限.
+	ErrTooManyTerminals = fmt.Errorf("too many terminal sessions (max: %d)", maxTerminalsPerSandbox)
+)
+
+// Terminal 交互式伪终端会话.
+type Terminal struct {
+	ID        string
+	SandboxID string
+	// RecordingID 终端输出的录制，未开启录制时为空
+	RecordingID string
+
+	cmd  *exec.Cmd
+	pty  *os.File
+	rec  *recording.Recording
+	done chan struct{}
+	// redactor 替换输出中的密钥值，跨越两次读取的密钥值同样被替换
+	redactor *secret.Redactor
+
+	mu          sync.Mutex
+	backlog     []byte
+	subscribers map[chan []byte]struct{}
+	idleTimer   *time.Timer
+	exitCode    int
+}
+
+// StartTerminal 在工作空间中启动新的伪终端会话. 指定的启动命令与 Execute 一样按命令策略检查，
+// 终端无法等待审批，需要审批的命令同样被拒绝.
+func (s *Service) StartTerminal(sandboxID, command string, cols, rows uint16) (*Terminal, error) {
+	if command != "" {
+		if err := s.CheckCommand(sandboxID, command); err != nil {
+			return nil, err
+		}
+	}
+
+	// 启动期间持有锁，避免并发请求超过每个沙箱的终端数上限
+	s.terminalsMu.Lock()
+	defer s.terminalsMu.Unlock()
+
+	count := 0
+
+	for _, t := range s.terminals {
+		if t.SandboxID == sandboxID {
+			count++
+		}
+	}
+
+	if count >= maxTerminalsPerSandbox {
+		return nil, ErrTooManyTerminals
+	}
+
+	cmd := exec.Command(s.shell.Path, s.shellArgs(command)...)
+	cmd.Env = []string{"TERM=xterm-256color"}
+
+	dir, _, err := s.prepareCommand(cmd, sandboxID)
+	if err != nil {
+		return nil, err
+	}
+
+	limits, err := s.applySandbox(cmd, sandboxID, dir, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var f *os.File
+
+	err = limits.start(func() (err error) {
+		f, err = pty.StartWithSize(cmd, &pty.Winsize{Cols: cols, Rows: rows})
+		return err
+	})
+	limits.release()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to start terminal: %w", err)
+	}
+
+	// 交互式 shell 没有命令，录制中记录 shell 的路径
+	recorded := command
+	if recorded == "" {
+		recorded = s.shell.Path
+	}
+
+	rec := s.StartRecording(sandboxID, recording.KindTerminal, recorded, int(cols), int(rows))
+
+	t := &Terminal{
+		ID:          uuid.New().String(),
+		SandboxID:   sandboxID,
+		RecordingID: rec.ID(),
+		cmd:         cmd,
+		pty:         f,
+		rec:         rec,
+		done:        make(chan struct{}),
+		subscribers: make(map[chan []byte]struct{}),
+		redactor:    s.NewRedactor(sandboxID),
+	}
+
+	// 客户端在空闲超时内未连接时自动回收
+	t.idleTimer = time.AfterFunc(terminalIdleTimeout, t.Close)
+
+	s.terminals[t.ID] = t
+
+	go t.pump(func() {
+		s.terminalsMu.Lock()
+		delete(s.terminals, t.ID)
+		s.terminalsMu.Unlock()
+	})
+
+	return t, nil
+}
+
+// AttachTerminal 查找属于指定沙箱的终端会话.
+func (s *Service) AttachTerminal(sandboxID, sessionID string) (*Terminal, error) {
+	s.terminalsMu.Lock()
+	defer s.terminalsMu.Unlock()
+
+	t, ok := s.terminals[sessionID]
+	if !ok || t.SandboxID != sandboxID {
+		return nil, ErrTerminalNotFound
+	}
+
+	return t, nil
+}
+
+// Write 向终端写入输入.
+func (t *Terminal) Write(p []byte) error {
+	if _, err := t.pty.Write(p); err != nil {
+		return fmt.Errorf("failed to write to terminal: %w", err)
+	}
+
+	return nil
+}
+
+// Resize 调整终端窗口大小.
+func (t *Terminal) Resize(cols, rows uint16) error {
+	if err := pty.Setsize(t.pty, &pty.Winsize{Cols: cols, Rows: rows}); err != nil {
+		return fmt.Errorf("failed to resize terminal: %w", err)
+	}
+
+	t.rec.Resize(int(cols), int(rows))
+
+	return nil
+}
+
+// Subscribe 订阅终端输出，返回最近的输出回放和后续输出通道.
+// 进程退出且输出读取完毕后通道被关闭；调用方结束时必须调用返回的取消函数.
+func (t *Terminal) Subscribe() ([]byte, <-chan []byte, func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	backlog := append([]byte(nil), t.backlog...)
+	ch := make(chan []byte, terminalSubscriberBuffer)
+
+	select {
+	case <-t.done:
+		close(ch)
+		return backlog, ch, func() {}
+	default:
+	}
+
+	t.subscribers[ch] = struct{}{}
+
+	if t.idleTimer != nil {
+		t.idleTimer.Stop()
+		t.idleTimer = nil
+	}
+
+	return backlog, ch, func() { t.unsubscribe(ch) }
+}
+
+// Done 返回在终端进程退出后关闭的通道.
+func (t *Terminal) Done() <-chan struct{} {
+	return t.done
+}
+
+// ExitCode 返回终端进程的退出码，仅在 Done 关闭后有效.
+func (t *Terminal) ExitCode() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.exitCode
+}
+
+// Close 终止终端进程及其启动的所有进程（pty 会为终端创建独立的会话和进程组）.
+func (t *Terminal) Close() {
+	if t.cmd.Process != nil {
+		killGroup(t.cmd.Process.Pid)
+	}
+}
+
+// unsubscribe 取消订阅.
+func (t *Terminal) unsubscribe(ch chan []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.subscribers[ch]; ok {
+		t.removeLocked(ch)
+	}
+}
+
+// removeLocked 移除订阅者并关闭其通道，最后一个订阅者离开后开始空闲计时.
+func (t *Terminal) removeLocked(ch chan []byte) {
+	close(ch)
+	delete(t.subscribers, ch)
+
+	if len(t.subscribers) == 0 && t.idleTimer == nil {
+		t.idleTimer = time.AfterFunc(terminalIdleTimeout, t.Close)
+	}
+}
+
+// pump 持续读取终端输出并分发给订阅者，进程退出后执行清理.
+func (t *Terminal) pump(cleanup func()) {
+	buf := make([]byte, 32*1024)
+
+	for {
+		n, err := t.pty.Read(buf)
+		if n > 0 {
+			if data := t.redactor.Redact(buf[:n]); len(data) > 0 {
+				t.broadcast(data)
+			}
+		}
+
+		// Linux 上子进程退出后读取 pty 会返回 EIO
+		if err != nil {
+			break
+		}
+	}
+
+	// 输出结束，发送可能是密钥值开头而保留的字节
+	if data := t.redactor.Flush(); len(data) > 0 {
+		t.broadcast(data)
+	}
+
+	code := 0
+	if err := t.cmd.Wait(); err != nil {
+		code = exitCode(err)
+	}
+
+	_ = t.pty.Close()
+	t.rec.Close()
+
+	// 先从会话表移除，保证 Done 关闭后无法再重新连接
+	cleanup()
+
+	t.mu.Lock()
+	t.exitCode = code
+
+	if t.idleTimer != nil {
+		t.idleTimer.Stop()
+	}
+
+	for ch := range t.subscribers {
+		close(ch)
+		delete(t.subscribers, ch)
+	}
+
+	close(t.done)
+	t.mu.Unlock()
+}
+
+// broadcast 记录输出并发送给所有订阅者，跟不上的订阅者会被断开.
+func (t *Terminal) broadcast(data []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	_, _ = t.rec.Write(data)
+
+	t.backlog = append(t.backlog, data...)
+	if over := len(t.backlog) - terminalBacklogSize; over > 0 {
+		t.backlog = append([]byte(nil), t.backlog[over:]...)
+	}
+
+	for ch := range t.subscribers {
+		select {
+		case ch <- data:
+		default:
+			t.removeLocked(ch)
+		}
+	}
+}
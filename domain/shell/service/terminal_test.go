@@ -0,0 +1,197 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// readTerminalUntil 从输出通道读取数据直到包含期望内容或超时.
+func readTerminalUntil(t *testing.T, output <-chan []byte, want string) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	timeout := time.After(5 * time.Second)
+
+	for !bytes.Contains(buf.Bytes(), []byte(want)) {
+		select {
+		case data, ok := <-output:
+			if !ok {
+				t.Fatalf("Terminal output closed before %q appeared, got %q", want, buf.String())
+			}
+
+			buf.Write(data)
+		case <-timeout:
+			t.Fatalf("Timed out waiting for %q, got %q", want, buf.String())
+		}
+	}
+
+	return buf.String()
+}
+
+func TestShellService_TerminalInteractive(t *testing.T) {
+	service := NewService(30, t.TempDir())
+
+	term, err := service.StartTerminal("sandbox-1", "", 80, 24)
+	if err != nil {
+		t.Fatalf("Failed to start terminal: %v", err)
+	}
+
+	defer term.Close()
+
+	_, output, unsubscribe := term.Subscribe()
+	defer unsubscribe()
+
+	if err := term.Write([]byte("echo $((40 + 2))\n")); err != nil {
+		t.Fatalf("Failed to write to terminal: %v", err)
+	}
+
+	readTerminalUntil(t, output, "42")
+
+	if err := term.Resize(120, 40); err != nil {
+		t.Fatalf("Failed to resize terminal: %v", err)
+	}
+
+	if err := term.Write([]byte("stty size\n")); err != nil {
+		t.Fatalf("Failed to write to terminal: %v", err)
+	}
+
+	readTerminalUntil(t, output, "40 120")
+
+	if err := term.Write([]byte("exit 3\n")); err != nil {
+		t.Fatalf("Failed to write to terminal: %v", err)
+	}
+
+	select {
+	case <-term.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Terminal did not exit")
+	}
+
+	if term.ExitCode() != 3 {
+		t.Fatalf("Expected exit code 3, got %d", term.ExitCode())
+	}
+}
+
+func TestShellService_TerminalReattach(t *testing.T) {
+	service := NewService(30, t.TempDir())
+
+	term, err := service.StartTerminal("sandbox-1", "", 80, 24)
+	if err != nil {
+		t.Fatalf("Failed to start terminal: %v", err)
+	}
+
+	defer term.Close()
+
+	_, output, unsubscribe := term.Subscribe()
+
+	if err := term.Write([]byte("echo first-client\n")); err != nil {
+		t.Fatalf("Failed to write to terminal: %v", err)
+	}
+
+	readTerminalUntil(t, output, "first-client")
+	unsubscribe()
+
+	// 其他沙箱不能连接
+	if _, err := service.AttachTerminal("sandbox-2", term.ID); !errors.Is(err, ErrTerminalNotFound) {
+		t.Fatalf("Expected ErrTerminalNotFound, got %v", err)
+	}
+
+	attached, err := service.AttachTerminal("sandbox-1", term.ID)
+	if err != nil {
+		t.Fatalf("Failed to reattach terminal: %v", err)
+	}
+
+	backlog, _, unsubscribe := attached.Subscribe()
+	defer unsubscribe()
+
+	if !bytes.Contains(backlog, []byte("first-client")) {
+		t.Fatalf("Expected backlog to contain previous output, got %q", backlog)
+	}
+}
+
+func TestShellService_TerminalCommandExit(t *testing.T) {
+	service := NewService(30, t.TempDir())
+
+	term, err := service.StartTerminal("sandbox-1", "echo done", 80, 24)
+	if err != nil {
+		t.Fatalf("Failed to start terminal: %v", err)
+	}
+
+	select {
+	case <-term.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Terminal did not exit")
+	}
+
+	backlog, output, unsubscribe := term.Subscribe()
+	defer unsubscribe()
+
+	if !bytes.Contains(backlog, []byte("done")) {
+		t.Fatalf("Expected backlog to contain command output, got %q", backlog)
+	}
+
+	if _, ok := <-output; ok {
+		t.Fatal("Expected output channel to be closed after exit")
+	}
+
+	if _, err := service.AttachTerminal("sandbox-1", term.ID); !errors.Is(err, ErrTerminalNotFound) {
+		t.Fatalf("Expected exited terminal to be removed, got %v", err)
+	}
+}
+
+func TestShellService_TerminalProfile(t *testing.T) {
+	workspace := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(workspace, "profile.sh"), []byte("export GREETING=hello\n"), 0o600); err != nil {
+		t.Fatalf("Failed to create profile: %v", err)
+	}
+
+	service := NewService(30, workspace, WithEnvironment(Environment{Profile: "profile.sh"}))
+
+	// 交互式终端加载 profile 后替换为交互式 shell，导出的变量保留
+	term, err := service.StartTerminal("sandbox-1", "", 80, 24)
+	if err != nil {
+		t.Fatalf("Failed to start terminal: %v", err)
+	}
+
+	defer term.Close()
+
+	_, output, unsubscribe := term.Subscribe()
+	defer unsubscribe()
+
+	if err := term.Write([]byte("echo \"$GREETING-$TERM\"\n")); err != nil {
+		t.Fatalf("Failed to write to terminal: %v", err)
+	}
+
+	readTerminalUntil(t, output, "hello-xterm-256color")
+}
+
+func TestShellService_TerminalLimit(t *testing.T) {
+	service := NewService(30, t.TempDir())
+
+	for i := 0; i < maxTerminalsPerSandbox; i++ {
+		term, err := service.StartTerminal("sandbox-1", "", 80, 24)
+		if err != nil {
+			t.Fatalf("Failed to start terminal %d: %v", i, err)
+		}
+
+		defer term.Close()
+	}
+
+	if _, err := service.StartTerminal("sandbox-1", "", 80, 24); !errors.Is(err, ErrTooManyTerminals) {
+		t.Fatalf("Expected ErrTooManyTerminals, got %v", err)
+	}
+
+	// 其他沙箱不受影响
+	term, err := service.StartTerminal("sandbox-2", "", 80, 24)
+	if err != nil {
+		t.Fatalf("Failed to start terminal in another sandbox: %v", err)
+	}
+
+	term.Close()
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/HJH0924/agent-sandbox/internal/sandbox"
+)
+
+// newTestUsers 创建沙箱用户分配器，工作空间根目录允许沙箱用户进入.
+func newTestUsers(t *testing.T) *sandbox.Users {
+	t.Helper()
+
+	if os.Geteuid() != 0 {
+		t.Skip("running commands as another user requires root")
+	}
+
+	root := t.TempDir()
+
+	// t.TempDir 的父目录权限为 0700，沙箱用户无法进入
+	for _, dir := range []string{filepath.Dir(root), root} {
+		if err := os.Chmod(dir, 0o711); err != nil {
+			t.Fatalf("Failed to chmod %s: %v", dir, err)
+		}
+	}
+
+	return sandbox.NewUsers(root, 20000, 10)
+}
+
+func TestShellService_RunsAsSandboxUser(t *testing.T) {
+	users := newTestUsers(t)
+	service := NewService(10, "", WithUsers(users))
+
+	user, err := users.Create("sandbox-1")
+	if err != nil {
+		t.Fatalf("Failed to create sandbox user: %v", err)
+	}
+
+	result, err := service.Execute(context.Background(), &ExecuteRequest{
+		SandboxID: "sandbox-1",
+		Command:   "id -u; id -g; pwd; echo $HOME",
+	})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	uid := strconv.FormatUint(uint64(user.UID), 10)
+	expected := strings.Join([]string{uid, uid, user.Workspace, user.Workspace}, "\n") + "\n"
+
+	if result.Output != expected {
+		t.Fatalf("Expected %q, got %q", expected, result.Output)
+	}
+}
+
+func TestShellService_SandboxUserHome(t *testing.T) {
+	users := newTestUsers(t)
+
+	user, err := users.Create("sandbox-1")
+	if err != nil {
+		t.Fatalf("Failed to create sandbox user: %v", err)
+	}
+
+	// 继承服务进程的 HOME 和基础环境设置的 HOME 都被沙箱用户的工作空间替换
+	for _, service := range []*Service{
+		NewService(10, "", WithUsers(users)),
+		NewService(10, "", WithUsers(users), WithEnvironment(Environment{Vars: []string{"HOME=/root"}})),
+	} {
+		cmd := exec.Command("true")
+
+		if _, _, err := service.prepareCommand(cmd, "sandbox-1"); err != nil {
+			t.Fatalf("prepareCommand failed: %v", err)
+		}
+
+		var homes []string
+
+		for _, kv := range cmd.Env {
+			if strings.HasPrefix(kv, "HOME=") {
+				homes = append(homes, kv)
+			}
+		}
+
+		if len(homes) != 1 || homes[0] != "HOME="+user.Workspace {
+			t.Fatalf("Expected a single HOME=%s, got %v", user.Workspace, homes)
+		}
+	}
+}
+
+func TestShellService_SandboxUsersIsolated(t *testing.T) {
+	users := newTestUsers(t)
+	service := NewService(10, "", WithUsers(users))
+
+	other, err := users.Create("sandbox-1")
+	if err != nil {
+		t.Fatalf("Failed to create sandbox user: %v", err)
+	}
+
+	if _, err := users.Create("sandbox-2"); err != nil {
+		t.Fatalf("Failed to create sandbox user: %v", err)
+	}
+
+	_, err = service.Execute(context.Background(), &ExecuteRequest{
+		SandboxID: "sandbox-1",
+		Command:   "echo secret > secret.txt",
+	})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	result, err := service.Execute(context.Background(), &ExecuteRequest{
+		SandboxID: "sandbox-2",
+		Command:   "cat " + filepath.Join(other.Workspace, "secret.txt"),
+	})
+	if err == nil {
+		t.Fatalf("Expected permission error, got output %q", result.Output)
+	}
+
+	if result == nil || !strings.Contains(result.Output, "Permission denied") {
+		t.Fatalf("Expected permission denied, got %+v", result)
+	}
+}
+
+func TestShellService_UnknownSandboxUser(t *testing.T) {
+	users := newTestUsers(t)
+	service := NewService(10, "", WithUsers(users))
+
+	if _, err := service.Execute(context.Background(), &ExecuteRequest{SandboxID: "unknown", Command: "true"}); err == nil {
+		t.Fatalf("Expected error for sandbox without user")
+	}
+
+	if _, err := service.CreateSession("unknown"); err == nil {
+		t.Fatalf("Expected error for sandbox without user")
+	}
+}
+
+func TestShellService_SandboxUserSession(t *testing.T) {
+	users := newTestUsers(t)
+	service := NewService(10, "", WithUsers(users), WithMaxOutputSize(16), WithOutputSpill(true))
+
+	user, err := users.Create("sandbox-1")
+	if err != nil {
+		t.Fatalf("Failed to create sandbox user: %v", err)
+	}
+
+	session, err := service.CreateSession("sandbox-1")
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	defer func() {
+		_ = service.CloseSession("sandbox-1", session.ID)
+	}()
+
+	result, err := service.ExecuteInSession(context.Background(), "sandbox-1", session.ID, "id -u; seq 1 100")
+	if err != nil {
+		t.Fatalf("ExecuteInSession failed: %v", err)
+	}
+
+	if !strings.HasPrefix(result.Output, strconv.FormatUint(uint64(user.UID), 10)+"\n") {
+		t.Fatalf("Expected command to run as %d, got %q", user.UID, result.Output)
+	}
+
+	// 截断输出保存的文件应可被沙箱用户读取
+	result, err = service.ExecuteInSession(context.Background(), "sandbox-1", session.ID, "wc -l < "+result.StdoutFile)
+	if err != nil {
+		t.Fatalf("ExecuteInSession failed: %v", err)
+	}
+
+	if strings.TrimSpace(result.Output) != "101" {
+		t.Fatalf("Expected sandbox user to read spilled output, got %q", result.Output)
+	}
+}
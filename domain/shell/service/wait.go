@@ -0,0 +1,366 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// waitInterval 检查等待条件的间隔.
+	waitInterval = 200 * time.Millisecond
+	// maxWaitTimeout 等待的最长时间.
+	maxWaitTimeout = 10 * time.Minute
+	// waitHTTPTimeout 单次 HTTP 请求的超时时间.
+	waitHTTPTimeout = 5 * time.Second
+	// maxLogRead 每次检查从日志文件中读取的最大字节数.
+	maxLogRead = 1 << 20
+	// maxLogLine 日志中未结束的行保留的最大长度，超过后丢弃.
+	maxLogLine = 64 << 10
+)
+
+// ErrInvalidCondition 等待条件无效.
+var ErrInvalidCondition = errors.New("invalid wait condition")
+
+// WaitCondition 等待条件的类型.
+type WaitCondition string
+
+const (
+	// WaitPort 沙箱中的 TCP 端口接受连接.
+	WaitPort WaitCondition = "port"
+	// WaitHTTP HTTP 地址返回期望的状态码.
+	WaitHTTP WaitCondition = "http"
+	// WaitFile 工作空间中的文件存在.
+	WaitFile WaitCondition = "file"
+	// WaitLog 工作空间中的日志文件出现匹配正则表达式的行.
+	WaitLog WaitCondition = "log"
+)
+
+// WaitRequest 等待请求.
+type WaitRequest struct {
+	SandboxID string
+	Condition WaitCondition
+	// Port 等待的端口（WaitPort），连接沙箱的回环地址
+	Port int
+	// URL 请求的地址（WaitHTTP），从沙箱的网络中访问
+	URL string
+	// Status 期望的状态码（WaitHTTP），为 0 时接受任意 2xx
+	Status int
+	// Path 文件路径（WaitFile、WaitLog），相对工作空间或为命令看到的工作空间下的绝对路径
+	Path string
+	// Pattern 匹配日志行的正则表达式（WaitLog）
+	Pattern string
+	// Timeout 最长等待时间，为 0 时使用默认命令超时，最长 10 分钟
+	Timeout time.Duration
+}
+
+// WaitResult 等待结果，超时不视为错误.
+type WaitResult struct {
+	// Ready 条件在超时前满足
+	Ready    bool
+	Elapsed  time.Duration
+	Attempts int
+	// LastError 最后一次检查失败的原因（如 connection refused）
+	LastError string
+	// Status 最后一次 HTTP 请求返回的状态码
+	Status int
+	// Match 日志中匹配的行
+	Match string
+}
+
+// checkFunc 检查一次等待条件，条件满足时返回 true.
+type checkFunc func(ctx context.Context, result *WaitResult) (bool, error)
+
+// WaitFor 每隔 200 毫秒检查一次条件，直到条件满足或超时.
+func (s *Service) WaitFor(ctx context.Context, req *WaitRequest) (*WaitResult, error) {
+	check, err := s.waitCheck(req)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := req.Timeout
+	if timeout <= 0 {
+		timeout = s.defaultTimeout
+	}
+
+	if timeout <= 0 || timeout > maxWaitTimeout {
+		timeout = maxWaitTimeout
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(waitInterval)
+	defer ticker.Stop()
+
+	result := &WaitResult{}
+	start := time.Now()
+
+	for {
+		result.Attempts++
+
+		ready, err := check(waitCtx, result)
+		if ready {
+			result.Ready = true
+			result.LastError = ""
+			result.Elapsed = time.Since(start)
+			result.Match = s.Redact(req.SandboxID, result.Match)
+
+			return result, nil
+		}
+
+		if err != nil {
+			result.LastError = err.Error()
+		}
+
+		select {
+		case <-waitCtx.Done():
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+
+			result.Elapsed = time.Since(start)
+
+			return result, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// waitCheck 校验等待条件并返回检查函数.
+func (s *Service) waitCheck(req *WaitRequest) (checkFunc, error) {
+	switch req.Condition {
+	case WaitPort:
+		if req.Port < 1 || req.Port > 65535 {
+			return nil, fmt.Errorf("%w: port must be between 1 and 65535", ErrInvalidCondition)
+		}
+
+		return s.portCheck(req.SandboxID, req.Port), nil
+	case WaitHTTP:
+		u, err := url.Parse(req.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidCondition)
+		}
+
+		return s.httpCheck(req.SandboxID, u.String(), req.Status), nil
+	case WaitFile:
+		path, root, err := s.waitPath(req.SandboxID, req.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		return fileCheck(path, root), nil
+	case WaitLog:
+		path, root, err := s.waitPath(req.SandboxID, req.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		re, err := regexp.Compile(req.Pattern)
+		if err != nil || req.Pattern == "" {
+			return nil, fmt.Errorf("%w: invalid pattern %q", ErrInvalidCondition, req.Pattern)
+		}
+
+		return logCheck(path, root, re), nil
+	default:
+		return nil, fmt.Errorf("%w: unknown condition %q", ErrInvalidCondition, req.Condition)
+	}
+}
+
+// portCheck 检查沙箱中的端口是否接受连接.
+func (s *Service) portCheck(sandboxID string, port int) checkFunc {
+	return func(ctx context.Context, _ *WaitResult) (bool, error) {
+		conn, err := s.registry.DialPort(ctx, sandboxID, port)
+		if err != nil {
+			return false, err
+		}
+
+		_ = conn.Close()
+
+		return true, nil
+	}
+}
+
+// httpCheck 检查 HTTP 地址是否返回期望的状态码，不跟随重定向.
+func (s *Service) httpCheck(sandboxID, rawURL string, status int) checkFunc {
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, addr string) (net.Conn, error) {
+				return s.registry.Dial(ctx, sandboxID, addr)
+			},
+			DisableKeepAlives: true,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	return func(ctx context.Context, result *WaitResult) (bool, error) {
+		ctx, cancel := context.WithTimeout(ctx, waitHTTPTimeout)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+		if err != nil {
+			return false, err
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return false, err
+		}
+
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxLogRead))
+		_ = resp.Body.Close()
+
+		result.Status = resp.StatusCode
+
+		if (status == 0 && resp.StatusCode >= 200 && resp.StatusCode < 300) || resp.StatusCode == status {
+			return true, nil
+		}
+
+		return false, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+}
+
+// fileCheck 检查文件是否存在.
+func fileCheck(path, root string) checkFunc {
+	return func(context.Context, *WaitResult) (bool, error) {
+		if err := checkWithin(path, root); err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+}
+
+// logCheck 增量读取日志文件，检查新增的行是否匹配正则表达式.
+// 未以换行结尾的最后一行同样参与匹配；文件被截断时从头读取.
+func logCheck(path, root string, re *regexp.Regexp) checkFunc {
+	var (
+		offset  int64
+		pending []byte
+	)
+
+	return func(_ context.Context, result *WaitResult) (bool, error) {
+		if err := checkWithin(path, root); err != nil {
+			return false, err
+		}
+
+		f, err := os.Open(path) // #nosec G304 -- path is validated to be within the workspace
+		if err != nil {
+			return false, err
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+
+		if info, err := f.Stat(); err == nil && info.Size() < offset {
+			offset, pending = 0, nil
+		}
+
+		data, err := io.ReadAll(io.NewSectionReader(f, offset, maxLogRead))
+		if err != nil {
+			return false, err
+		}
+
+		offset += int64(len(data))
+		pending = append(pending, data...)
+
+		for {
+			line, rest, ok := bytes.Cut(pending, []byte("\n"))
+			if !ok {
+				break
+			}
+
+			pending = rest
+
+			if re.Match(line) {
+				result.Match = strings.TrimSuffix(string(line), "\r")
+				return true, nil
+			}
+		}
+
+		if re.Match(pending) {
+			result.Match = string(pending)
+			return true, nil
+		}
+
+		if len(pending) > maxLogLine {
+			pending = nil
+		}
+
+		return false, fmt.Errorf("no line matching %q in %s", re.String(), filepath.Base(path))
+	}
+}
+
+// waitPath 返回等待条件中的文件在宿主机上的路径和沙箱的工作空间，路径必须位于工作空间内.
+func (s *Service) waitPath(sandboxID, path string) (string, string, error) {
+	if path == "" {
+		return "", "", fmt.Errorf("%w: path is required", ErrInvalidCondition)
+	}
+
+	dir, _, err := s.workDir(sandboxID)
+	if err != nil {
+		return "", "", err
+	}
+
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	// 命令看到的工作空间路径（隔离时为 /workspace）下的绝对路径转换为相对路径
+	if filepath.IsAbs(path) {
+		rel, err := filepath.Rel(s.visibleDir(root), path)
+		if err != nil {
+			return "", "", fmt.Errorf("%w: path is outside the workspace", ErrInvalidCondition)
+		}
+
+		path = rel
+	}
+
+	fullPath := filepath.Join(root, path)
+	if !isWithin(root, fullPath) {
+		return "", "", fmt.Errorf("%w: path is outside the workspace", ErrInvalidCondition)
+	}
+
+	return fullPath, root, nil
+}
+
+// checkWithin 检查文件存在，且解析符号链接后仍位于工作空间内.
+func checkWithin(path, root string) error {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return err
+	}
+
+	if realRoot, err := filepath.EvalSymlinks(root); err == nil {
+		root = realRoot
+	}
+
+	if !isWithin(root, resolved) {
+		return fmt.Errorf("%s resolves outside the workspace", filepath.Base(path))
+	}
+
+	return nil
+}
+
+// isWithin 判断 path 是否位于 root 之内.
+func isWithin(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWaitFor_Port(t *testing.T) {
+	service := NewService(10, t.TempDir())
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+
+	port := listener.Addr().(*net.TCPAddr).Port
+	if err := listener.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	result, err := service.WaitFor(context.Background(), &WaitRequest{
+		SandboxID: "sandbox-1",
+		Condition: WaitPort,
+		Port:      port,
+		Timeout:   500 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("WaitFor failed: %v", err)
+	}
+
+	if result.Ready || result.Attempts < 2 || result.LastError == "" {
+		t.Fatalf("Expected timeout with the last error, got %+v", result)
+	}
+
+	// 端口稍后开始监听
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+
+		if l, err := net.Listen("tcp", listener.Addr().String()); err == nil {
+			t.Cleanup(func() { _ = l.Close() })
+		}
+	}()
+
+	result, err = service.WaitFor(context.Background(), &WaitRequest{
+		SandboxID: "sandbox-1",
+		Condition: WaitPort,
+		Port:      port,
+		Timeout:   5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("WaitFor failed: %v", err)
+	}
+
+	if !result.Ready || result.LastError != "" {
+		t.Fatalf("Expected port to become ready, got %+v", result)
+	}
+}
+
+func TestWaitFor_HTTP(t *testing.T) {
+	service := NewService(10, t.TempDir())
+
+	started := time.Now()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/redirect":
+			http.Redirect(w, r, "/health", http.StatusFound)
+		case time.Since(started) < 300*time.Millisecond:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	result, err := service.WaitFor(context.Background(), &WaitRequest{
+		SandboxID: "sandbox-1",
+		Condition: WaitHTTP,
+		URL:       server.URL + "/health",
+		Timeout:   5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("WaitFor failed: %v", err)
+	}
+
+	if !result.Ready || result.Status != http.StatusNoContent || result.Attempts < 2 {
+		t.Fatalf("Expected health check to become ready, got %+v", result)
+	}
+
+	// 不跟随重定向，可以等待指定的状态码
+	result, err = service.WaitFor(context.Background(), &WaitRequest{
+		SandboxID: "sandbox-1",
+		Condition: WaitHTTP,
+		URL:       server.URL + "/redirect",
+		Status:    http.StatusFound,
+	})
+	if err != nil || !result.Ready {
+		t.Fatalf("Expected redirect status, got %+v, %v", result, err)
+	}
+}
+
+func TestWaitFor_FileAndLog(t *testing.T) {
+	workspace := t.TempDir()
+	service := NewService(10, workspace)
+
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+
+		f, err := os.Create(filepath.Join(workspace, "server.log"))
+		if err != nil {
+			return
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+
+		_, _ = f.WriteString("starting\n")
+		time.Sleep(300 * time.Millisecond)
+		_, _ = f.WriteString("listening on :3000\r\nready")
+	}()
+
+	result, err := service.WaitFor(context.Background(), &WaitRequest{
+		SandboxID: "sandbox-1",
+		Condition: WaitFile,
+		Path:      "server.log",
+		Timeout:   5 * time.Second,
+	})
+	if err != nil || !result.Ready {
+		t.Fatalf("Expected file to appear, got %+v, %v", result, err)
+	}
+
+	result, err = service.WaitFor(context.Background(), &WaitRequest{
+		SandboxID: "sandbox-1",
+		Condition: WaitLog,
+		Path:      filepath.Join(workspace, "server.log"),
+		Pattern:   `listening on :\d+`,
+		Timeout:   5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("WaitFor failed: %v", err)
+	}
+
+	if !result.Ready || result.Match != "listening on :3000" {
+		t.Fatalf("Expected log line to match, got %+v", result)
+	}
+
+	// 未以换行结尾的最后一行同样参与匹配
+	result, err = service.WaitFor(context.Background(), &WaitRequest{
+		SandboxID: "sandbox-1",
+		Condition: WaitLog,
+		Path:      "server.log",
+		Pattern:   `^ready$`,
+		Timeout:   5 * time.Second,
+	})
+	if err != nil || !result.Ready || result.Match != "ready" {
+		t.Fatalf("Expected partial line to match, got %+v, %v", result, err)
+	}
+}
+
+func TestWaitFor_Invalid(t *testing.T) {
+	workspace := t.TempDir()
+	service := NewService(10, workspace)
+
+	if err := os.Symlink("/etc/hostname", filepath.Join(workspace, "link")); err != nil {
+		t.Fatalf("Symlink failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		req  *WaitRequest
+	}{
+		{name: "no condition", req: &WaitRequest{}},
+		{name: "invalid port", req: &WaitRequest{Condition: WaitPort, Port: 70000}},
+		{name: "relative url", req: &WaitRequest{Condition: WaitHTTP, URL: "localhost:3000"}},
+		{name: "ftp url", req: &WaitRequest{Condition: WaitHTTP, URL: "ftp://localhost/"}},
+		{name: "no path", req: &WaitRequest{Condition: WaitFile}},
+		{name: "outside workspace", req: &WaitRequest{Condition: WaitFile, Path: "../etc/passwd"}},
+		{name: "absolute outside workspace", req: &WaitRequest{Condition: WaitFile, Path: "/etc/passwd"}},
+		{name: "invalid pattern", req: &WaitRequest{Condition: WaitLog, Path: "a.log", Pattern: "("}},
+		{name: "no pattern", req: &WaitRequest{Condition: WaitLog, Path: "a.log"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.WaitFor(context.Background(), tt.req); !errors.Is(err, ErrInvalidCondition) {
+				t.Fatalf("Expected ErrInvalidCondition, got %v", err)
+			}
+		})
+	}
+
+	// 指向工作空间之外的符号链接不视为存在
+	result, err := service.WaitFor(context.Background(), &WaitRequest{
+		SandboxID: "sandbox-1",
+		Condition: WaitFile,
+		Path:      "link",
+		Timeout:   300 * time.Millisecond,
+	})
+	if err != nil || result.Ready {
+		t.Fatalf("Expected symlink outside the workspace to be rejected, got %+v, %v", result, err)
+	}
+}
+
+func TestWaitFor_Canceled(t *testing.T) {
+	service := NewService(10, t.TempDir())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	_, err := service.WaitFor(ctx, &WaitRequest{SandboxID: "sandbox-1", Condition: WaitFile, Path: "missing"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected caller's context error, got %v", err)
+	}
+}
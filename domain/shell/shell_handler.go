@@ -3,12 +3,24 @@ package shell
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
+	"math"
+	"sort"
+	"time"
 
 	"github.com/HJH0924/agent-sandbox/domain/shell/service"
+	"github.com/HJH0924/agent-sandbox/internal/approval"
+	"github.com/HJH0924/agent-sandbox/internal/history"
+	"github.com/HJH0924/agent-sandbox/internal/middleware"
+	"github.com/HJH0924/agent-sandbox/internal/recording"
+	"github.com/HJH0924/agent-sandbox/internal/secret"
 	shellv1 "github.com/HJH0924/agent-sandbox/sdk/go/shell/v1"
 
 	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // Handler Shell 服务处理器.
@@ -32,32 +44,642 @@ func (h *Handler) Execute(
 ) (*connect.Response[shellv1.ExecuteResponse], error) {
 	command := req.Msg.GetCommand()
 
+	if sessionID := req.Msg.GetSessionId(); sessionID != "" {
+		return h.executeInSession(ctx, sessionID, req.Msg)
+	}
+
 	h.logger.InfoContext(ctx, "executing shell command",
-		slog.String("command", command))
+		slog.String("command", command),
+		slog.Any("argv", req.Msg.GetArgv()))
 
 	// 调用 service 层执行命令
-	result, err := h.shellService.Execute(ctx, command)
+	sandboxID, _ := middleware.GetSandboxIDFromContext(ctx)
+	keyID, _ := middleware.GetAPIKeyIDFromContext(ctx)
+
+	result, err := h.shellService.Execute(ctx, &service.ExecuteRequest{
+		SandboxID:    sandboxID,
+		Command:      command,
+		Argv:         req.Msg.GetArgv(),
+		ApprovalID:   req.Msg.GetApprovalId(),
+		PollApproval: req.Msg.GetPollApproval(),
+		CallerKeyID:  keyID,
+	})
 	if err != nil {
 		h.logger.ErrorContext(ctx, "failed to execute shell command",
 			slog.String("command", command),
 			slog.Any("error", err))
 
-		// 即使命令执行失败，也返回输出（如果有的话）
-		if result != nil && result.Output != "" {
-			return connect.NewResponse(&shellv1.ExecuteResponse{
-				Output: result.Output,
-			}), connect.NewError(connect.CodeInternal, err)
+		// 命令已经运行（非零退出、被信号终止或超时）时通过响应返回结果，
+		// connect 在返回错误时会丢弃响应，客户端将看不到退出码、信号和超时
+		if result == nil {
+			return nil, connect.NewError(ErrorCode(err), err)
 		}
-
-		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 
-	h.logger.InfoContext(ctx, "shell command executed successfully",
+	h.logger.InfoContext(ctx, "shell command executed",
 		slog.String("command", command),
-		slog.Int("output_length", len(result.Output)))
+		slog.Int("exit_code", result.ExitCode),
+		slog.String("signal", result.Signal),
+		slog.Bool("timed_out", result.TimedOut),
+		slog.Int("output_length", len(result.Output)),
+		slog.Bool("truncated", result.Truncated))
 
 	// 返回响应
-	return connect.NewResponse(&shellv1.ExecuteResponse{
-		Output: result.Output,
+	return connect.NewResponse(toExecuteResponse(result, req.Msg.GetRawOutput())), nil
+}
+
+// executeInSession 在持久会话中执行命令.
+func (h *Handler) executeInSession(
+	ctx context.Context,
+	sessionID string,
+	msg *shellv1.ExecuteRequest,
+) (*connect.Response[shellv1.ExecuteResponse], error) {
+	sandboxID, _ := middleware.GetSandboxIDFromContext(ctx)
+	keyID, _ := middleware.GetAPIKeyIDFromContext(ctx)
+	command := msg.GetCommand()
+
+	h.logger.InfoContext(ctx, "executing shell command in session",
+		slog.String("session_id", sessionID),
+		slog.String("command", command),
+		slog.Any("argv", msg.GetArgv()))
+
+	result, err := h.shellService.Execute(ctx, &service.ExecuteRequest{
+		SandboxID:    sandboxID,
+		SessionID:    sessionID,
+		Command:      command,
+		Argv:         msg.GetArgv(),
+		ApprovalID:   msg.GetApprovalId(),
+		PollApproval: msg.GetPollApproval(),
+		CallerKeyID:  keyID,
+	})
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to execute shell command in session",
+			slog.String("session_id", sessionID),
+			slog.String("command", command),
+			slog.Any("error", err))
+
+		if errors.Is(err, service.ErrSessionNotFound) {
+			return nil, connect.NewError(connect.CodeNotFound, err)
+		}
+
+		return nil, connect.NewError(ErrorCode(err), err)
+	}
+
+	h.logger.InfoContext(ctx, "shell command executed in session",
+		slog.String("session_id", sessionID),
+		slog.Int("exit_code", result.ExitCode),
+		slog.Int("output_length", len(result.Output)),
+		slog.Bool("truncated", result.Truncated))
+
+	return connect.NewResponse(toExecuteResponse(result, msg.GetRawOutput())), nil
+}
+
+// toExecuteResponse 将执行结果转换为响应，raw 为 true 时返回原始字节而不是文本.
+func toExecuteResponse(result *service.ExecuteResult, raw bool) *shellv1.ExecuteResponse {
+	resp := &shellv1.ExecuteResponse{
+		ExitCode:        int32(result.ExitCode), // #nosec G115 -- exit codes fit in int32
+		Truncated:       result.Truncated,
+		StdoutBytes:     result.StdoutBytes,
+		StderrBytes:     result.StderrBytes,
+		StdoutFile:      result.StdoutFile,
+		StderrFile:      result.StderrFile,
+		Signal:          result.Signal,
+		TimedOut:        result.TimedOut,
+		LimitsExceeded:  result.LimitsExceeded,
+		ApprovalId:      result.ApprovalID,
+		ApprovalPending: result.ApprovalPending,
+		QueueWaitMs:     result.QueueWait.Milliseconds(),
+		StdoutEncoding:  string(result.StdoutEncoding),
+		StderrEncoding:  string(result.StderrEncoding),
+		RecordingId:     result.RecordingID,
+	}
+
+	if raw {
+		resp.StdoutData = result.StdoutData
+		resp.StderrData = result.StderrData
+	} else {
+		resp.Output = result.Output
+	}
+
+	return resp
+}
+
+// ErrorCode 将命令执行错误映射为 RPC 错误码，其他执行命令的服务也使用这一映射.
+func ErrorCode(err error) connect.Code {
+	switch {
+	case errors.Is(err, service.ErrCommandDenied),
+		errors.Is(err, service.ErrApprovalRequired),
+		errors.Is(err, approval.ErrRejected):
+		return connect.CodePermissionDenied
+	case errors.Is(err, approval.ErrExpired):
+		return connect.CodeDeadlineExceeded
+	case errors.Is(err, approval.ErrNotFound):
+		return connect.CodeNotFound
+	case errors.Is(err, service.ErrApprovalMismatch),
+		errors.Is(err, service.ErrInvalidCommand),
+		errors.Is(err, secret.ErrInvalidSecret),
+		errors.Is(err, service.ErrInvalidCondition),
+		errors.Is(err, history.ErrInvalidPageToken):
+		return connect.CodeInvalidArgument
+	case errors.Is(err, service.ErrHistoryDisabled),
+		errors.Is(err, service.ErrRecordingDisabled),
+		errors.Is(err, service.ErrSecretsDisabled):
+		return connect.CodeFailedPrecondition
+	case errors.Is(err, service.ErrProgramNotFound),
+		errors.Is(err, recording.ErrNotFound):
+		return connect.CodeNotFound
+	case errors.Is(err, service.ErrQueueFull),
+		errors.Is(err, service.ErrTooManyTerminals),
+		errors.Is(err, secret.ErrTooManySecrets):
+		return connect.CodeResourceExhausted
+	case errors.Is(err, context.Canceled):
+		return connect.CodeCanceled
+	default:
+		return connect.CodeInternal
+	}
+}
+
+// ListExecutions 分页返回沙箱的命令执行历史.
+func (h *Handler) ListExecutions(
+	ctx context.Context,
+	req *connect.Request[shellv1.ListExecutionsRequest],
+) (*connect.Response[shellv1.ListExecutionsResponse], error) {
+	sandboxID, _ := middleware.GetSandboxIDFromContext(ctx)
+
+	page, err := h.shellService.ListExecutions(sandboxID, int(req.Msg.GetPageSize()), req.Msg.GetPageToken(), req.Msg.GetIncludeOutput())
+	if err != nil {
+		return nil, connect.NewError(ErrorCode(err), err)
+	}
+
+	executions := make([]*shellv1.Execution, 0, len(page.Entries))
+	for _, e := range page.Entries {
+		executions = append(executions, &shellv1.Execution{
+			Id:          e.ID,
+			SessionId:   e.SessionID,
+			Command:     e.Command,
+			Cwd:         e.Cwd,
+			StartedAt:   timestamppb.New(e.StartedAt),
+			EndedAt:     timestamppb.New(e.EndedAt),
+			ExitCode:    int32(e.ExitCode), // #nosec G115 -- exit codes fit in int32
+			OutputBytes: e.OutputBytes,
+			CallerKeyId: e.CallerKeyID,
+			Error:       e.Error,
+			Output:      e.Output,
+			RecordingId: e.RecordingID,
+		})
+	}
+
+	return connect.NewResponse(&shellv1.ListExecutionsResponse{
+		Executions:    executions,
+		NextPageToken: page.NextPageToken,
+	}), nil
+}
+
+// ListRecordings 返回沙箱的录制.
+func (h *Handler) ListRecordings(
+	ctx context.Context,
+	_ *connect.Request[shellv1.ListRecordingsRequest],
+) (*connect.Response[shellv1.ListRecordingsResponse], error) {
+	sandboxID, _ := middleware.GetSandboxIDFromContext(ctx)
+
+	infos, err := h.shellService.ListRecordings(sandboxID)
+	if err != nil {
+		return nil, connect.NewError(ErrorCode(err), err)
+	}
+
+	recordings := make([]*shellv1.Recording, 0, len(infos))
+	for _, info := range infos {
+		recordings = append(recordings, toRecording(info))
+	}
+
+	return connect.NewResponse(&shellv1.ListRecordingsResponse{
+		Recordings: recordings,
+	}), nil
+}
+
+// DownloadRecording 分块发送录制文件.
+func (h *Handler) DownloadRecording(
+	ctx context.Context,
+	req *connect.Request[shellv1.DownloadRecordingRequest],
+	stream *connect.ServerStream[shellv1.DownloadRecordingResponse],
+) error {
+	sandboxID, _ := middleware.GetSandboxIDFromContext(ctx)
+
+	f, _, err := h.shellService.OpenRecording(sandboxID, req.Msg.GetRecordingId())
+	if err != nil {
+		return connect.NewError(ErrorCode(err), err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	buf := make([]byte, 64*1024)
+
+	for {
+		n, err := f.Read(buf)
+		if n > 0 {
+			if sendErr := stream.Send(&shellv1.DownloadRecordingResponse{Data: buf[:n]}); sendErr != nil {
+				return sendErr
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return connect.NewError(connect.CodeInternal, fmt.Errorf("failed to read recording: %w", err))
+		}
+	}
+}
+
+// toRecording 将录制的元数据转换为响应消息.
+func toRecording(info recording.Info) *shellv1.Recording {
+	msg := &shellv1.Recording{
+		Id:        info.ID,
+		Kind:      string(info.Kind),
+		Command:   info.Command,
+		Width:     int32(info.Width),  // #nosec G115 -- terminal sizes fit in int32
+		Height:    int32(info.Height), // #nosec G115 -- terminal sizes fit in int32
+		StartedAt: timestamppb.New(info.StartedAt),
+		Size:      info.Size,
+		Truncated: info.Truncated,
+	}
+
+	if !info.EndedAt.IsZero() {
+		msg.EndedAt = timestamppb.New(info.EndedAt)
+	}
+
+	return msg
+}
+
+// WaitFor 等待沙箱中的条件满足，超时通过响应中的 ready 返回.
+func (h *Handler) WaitFor(
+	ctx context.Context,
+	req *connect.Request[shellv1.WaitForRequest],
+) (*connect.Response[shellv1.WaitForResponse], error) {
+	sandboxID, _ := middleware.GetSandboxIDFromContext(ctx)
+
+	waitReq := &service.WaitRequest{
+		SandboxID: sandboxID,
+		Timeout:   time.Duration(req.Msg.GetTimeoutSeconds()) * time.Second,
+	}
+
+	switch condition := req.Msg.GetCondition().(type) {
+	case *shellv1.WaitForRequest_Port:
+		waitReq.Condition = service.WaitPort
+		waitReq.Port = int(condition.Port.GetPort())
+	case *shellv1.WaitForRequest_Http:
+		waitReq.Condition = service.WaitHTTP
+		waitReq.URL = condition.Http.GetUrl()
+		waitReq.Status = int(condition.Http.GetStatus())
+	case *shellv1.WaitForRequest_File:
+		waitReq.Condition = service.WaitFile
+		waitReq.Path = condition.File.GetPath()
+	case *shellv1.WaitForRequest_Log:
+		waitReq.Condition = service.WaitLog
+		waitReq.Path = condition.Log.GetPath()
+		waitReq.Pattern = condition.Log.GetPattern()
+	}
+
+	result, err := h.shellService.WaitFor(ctx, waitReq)
+	if err != nil {
+		return nil, connect.NewError(ErrorCode(err), err)
+	}
+
+	h.logger.InfoContext(ctx, "wait finished",
+		slog.String("condition", string(waitReq.Condition)),
+		slog.Bool("ready", result.Ready),
+		slog.Duration("elapsed", result.Elapsed))
+
+	return connect.NewResponse(&shellv1.WaitForResponse{
+		Ready:     result.Ready,
+		ElapsedMs: result.Elapsed.Milliseconds(),
+		Attempts:  int32(result.Attempts), // #nosec G115 -- attempts are bounded by the wait timeout
+		LastError: result.LastError,
+		Status:    int32(result.Status), // #nosec G115 -- HTTP status codes fit in int32
+		Match:     result.Match,
+	}), nil
+}
+
+// CreateSession 创建持久 shell 会话.
+func (h *Handler) CreateSession(
+	ctx context.Context,
+	_ *connect.Request[shellv1.CreateSessionRequest],
+) (*connect.Response[shellv1.CreateSessionResponse], error) {
+	sandboxID, _ := middleware.GetSandboxIDFromContext(ctx)
+
+	session, err := h.shellService.CreateSession(sandboxID)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to create shell session",
+			slog.Any("error", err))
+
+		if errors.Is(err, service.ErrTooManySessions) {
+			return nil, connect.NewError(connect.CodeResourceExhausted, err)
+		}
+
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	h.logger.InfoContext(ctx, "shell session created",
+		slog.String("session_id", session.ID))
+
+	return connect.NewResponse(&shellv1.CreateSessionResponse{
+		SessionId: session.ID,
+	}), nil
+}
+
+// CloseSession 关闭持久 shell 会话.
+func (h *Handler) CloseSession(
+	ctx context.Context,
+	req *connect.Request[shellv1.CloseSessionRequest],
+) (*connect.Response[shellv1.CloseSessionResponse], error) {
+	sandboxID, _ := middleware.GetSandboxIDFromContext(ctx)
+	sessionID := req.Msg.GetSessionId()
+
+	if err := h.shellService.CloseSession(sandboxID, sessionID); err != nil {
+		h.logger.WarnContext(ctx, "failed to close shell session",
+			slog.String("session_id", sessionID),
+			slog.Any("error", err))
+
+		return nil, connect.NewError(connect.CodeNotFound, err)
+	}
+
+	h.logger.InfoContext(ctx, "shell session closed",
+		slog.String("session_id", sessionID))
+
+	return connect.NewResponse(&shellv1.CloseSessionResponse{}), nil
+}
+
+// Terminal 处理交互式伪终端会话.
+func (h *Handler) Terminal(
+	ctx context.Context,
+	stream *connect.BidiStream[shellv1.TerminalRequest, shellv1.TerminalResponse],
+) error {
+	sandboxID, _ := middleware.GetSandboxIDFromContext(ctx)
+
+	// 第一条消息必须是 start
+	first, err := stream.Receive()
+	if err != nil {
+		return err
+	}
+
+	start := first.GetStart()
+	if start == nil {
+		return connect.NewError(connect.CodeInvalidArgument, errors.New("first terminal message must be start"))
+	}
+
+	term, reattached, err := h.openTerminal(ctx, sandboxID, start)
+	if err != nil {
+		return err
+	}
+
+	if err := stream.Send(&shellv1.TerminalResponse{
+		Event: &shellv1.TerminalResponse_Started{Started: &shellv1.TerminalStarted{
+			SessionId:   term.ID,
+			Reattached:  reattached,
+			RecordingId: term.RecordingID,
+		}},
+	}); err != nil {
+		return err
+	}
+
+	backlog, output, unsubscribe := term.Subscribe()
+	defer unsubscribe()
+
+	if len(backlog) > 0 {
+		if err := sendTerminalOutput(stream, backlog); err != nil {
+			return err
+		}
+	}
+
+	// 在独立 goroutine 中接收客户端输入
+	recvErr := make(chan error, 1)
+
+	go func() {
+		recvErr <- h.forwardTerminalInput(stream, term)
+	}()
+
+	for {
+		select {
+		case data, ok := <-output:
+			if !ok {
+				return h.finishTerminal(ctx, stream, term)
+			}
+
+			if err := sendTerminalOutput(stream, data); err != nil {
+				return err
+			}
+		case err := <-recvErr:
+			// 客户端关闭发送方向视为断开连接，会话保留以便重连
+			h.logger.InfoContext(ctx, "terminal client detached",
+				slog.String("session_id", term.ID))
+
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// openTerminal 创建新终端或重新连接到已有终端.
+func (h *Handler) openTerminal(
+	ctx context.Context,
+	sandboxID string,
+	start *shellv1.TerminalStart,
+) (*service.Terminal, bool, error) {
+	if start.GetSessionId() != "" {
+		term, err := h.shellService.AttachTerminal(sandboxID, start.GetSessionId())
+		if err != nil {
+			return nil, false, connect.NewError(connect.CodeNotFound, err)
+		}
+
+		if size := start.GetSize(); size != nil {
+			cols, rows, err := terminalSize(size)
+			if err != nil {
+				return nil, false, err
+			}
+
+			if err := term.Resize(cols, rows); err != nil {
+				return nil, false, connect.NewError(connect.CodeInternal, err)
+			}
+		}
+
+		h.logger.InfoContext(ctx, "terminal reattached",
+			slog.String("session_id", term.ID))
+
+		return term, true, nil
+	}
+
+	cols, rows, err := terminalSize(start.GetSize())
+	if err != nil {
+		return nil, false, err
+	}
+
+	term, err := h.shellService.StartTerminal(sandboxID, start.GetCommand(), cols, rows)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to start terminal",
+			slog.String("command", start.GetCommand()),
+			slog.Any("error", err))
+
+		return nil, false, connect.NewError(ErrorCode(err), err)
+	}
+
+	h.logger.InfoContext(ctx, "terminal started",
+		slog.String("session_id", term.ID),
+		slog.String("command", start.GetCommand()))
+
+	return term, false, nil
+}
+
+// terminalSize 检查终端大小并转换为 pty 使用的 uint16，超出范围时返回 InvalidArgument.
+func terminalSize(size *shellv1.TerminalSize) (uint16, uint16, error) {
+	if size.GetCols() > math.MaxUint16 || size.GetRows() > math.MaxUint16 {
+		return 0, 0, connect.NewError(connect.CodeInvalidArgument,
+			fmt.Errorf("terminal size %dx%d out of range (max: %d)", size.GetCols(), size.GetRows(), math.MaxUint16))
+	}
+
+	return uint16(size.GetCols()), uint16(size.GetRows()), nil // #nosec G115 -- checked against math.MaxUint16 above
+}
+
+// forwardTerminalInput 将客户端的输入和窗口大小变化转发给终端.
+func (h *Handler) forwardTerminalInput(
+	stream *connect.BidiStream[shellv1.TerminalRequest, shellv1.TerminalResponse],
+	term *service.Terminal,
+) error {
+	for {
+		msg, err := stream.Receive()
+		if err != nil {
+			return err
+		}
+
+		switch event := msg.GetEvent().(type) {
+		case *shellv1.TerminalRequest_Input:
+			if err := term.Write(event.Input); err != nil {
+				return connect.NewError(connect.CodeInternal, err)
+			}
+		case *shellv1.TerminalRequest_Resize:
+			cols, rows, err := terminalSize(event.Resize)
+			if err != nil {
+				return err
+			}
+
+			if err := term.Resize(cols, rows); err != nil {
+				return connect.NewError(connect.CodeInternal, err)
+			}
+		default:
+			return connect.NewError(connect.CodeInvalidArgument, errors.New("terminal already started"))
+		}
+	}
+}
+
+// finishTerminal 在输出通道关闭后发送退出事件.
+func (h *Handler) finishTerminal(
+	ctx context.Context,
+	stream *connect.BidiStream[shellv1.TerminalRequest, shellv1.TerminalResponse],
+	term *service.Terminal,
+) error {
+	select {
+	case <-term.Done():
+	default:
+		// 进程仍在运行，说明客户端读取过慢被断开，可以重新连接
+		return connect.NewError(connect.CodeResourceExhausted,
+			fmt.Errorf("terminal client too slow, reattach to session %s", term.ID))
+	}
+
+	h.logger.InfoContext(ctx, "terminal exited",
+		slog.String("session_id", term.ID),
+		slog.Int("exit_code", term.ExitCode()))
+
+	return stream.Send(&shellv1.TerminalResponse{
+		Event: &shellv1.TerminalResponse_Exited{Exited: &shellv1.TerminalExited{
+			ExitCode: int32(term.ExitCode()), // #nosec G115 -- exit codes fit in int32
+		}},
+	})
+}
+
+// sendTerminalOutput 发送终端输出.
+func sendTerminalOutput(
+	stream *connect.BidiStream[shellv1.TerminalRequest, shellv1.TerminalResponse],
+	data []byte,
+) error {
+	return stream.Send(&shellv1.TerminalResponse{
+		Event: &shellv1.TerminalResponse_Output{Output: data},
+	})
+}
+
+// SetSecrets 设置沙箱的密钥，日志中只记录密钥名称.
+func (h *Handler) SetSecrets(
+	ctx context.Context,
+	req *connect.Request[shellv1.SetSecretsRequest],
+) (*connect.Response[shellv1.SetSecretsResponse], error) {
+	sandboxID, _ := middleware.GetSandboxIDFromContext(ctx)
+	secrets := req.Msg.GetSecrets()
+
+	names := make([]string, 0, len(secrets))
+	for name := range secrets {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	if err := h.shellService.SetSecrets(sandboxID, secrets); err != nil {
+		h.logger.ErrorContext(ctx, "failed to set secrets",
+			slog.Any("names", names),
+			slog.Any("error", err))
+
+		return nil, connect.NewError(ErrorCode(err), err)
+	}
+
+	h.logger.InfoContext(ctx, "secrets set",
+		slog.Any("names", names))
+
+	return connect.NewResponse(&shellv1.SetSecretsResponse{}), nil
+}
+
+// ListSecrets 返回沙箱的密钥名称.
+func (h *Handler) ListSecrets(
+	ctx context.Context,
+	_ *connect.Request[shellv1.ListSecretsRequest],
+) (*connect.Response[shellv1.ListSecretsResponse], error) {
+	sandboxID, _ := middleware.GetSandboxIDFromContext(ctx)
+
+	infos, err := h.shellService.ListSecrets(sandboxID)
+	if err != nil {
+		return nil, connect.NewError(ErrorCode(err), err)
+	}
+
+	secrets := make([]*shellv1.Secret, 0, len(infos))
+	for _, info := range infos {
+		secrets = append(secrets, &shellv1.Secret{
+			Name:      info.Name,
+			UpdatedAt: timestamppb.New(info.UpdatedAt),
+		})
+	}
+
+	return connect.NewResponse(&shellv1.ListSecretsResponse{
+		Secrets: secrets,
 	}), nil
 }
+
+// DeleteSecrets 删除沙箱的密钥.
+func (h *Handler) DeleteSecrets(
+	ctx context.Context,
+	req *connect.Request[shellv1.DeleteSecretsRequest],
+) (*connect.Response[shellv1.DeleteSecretsResponse], error) {
+	sandboxID, _ := middleware.GetSandboxIDFromContext(ctx)
+
+	if err := h.shellService.DeleteSecrets(sandboxID, req.Msg.GetNames()); err != nil {
+		return nil, connect.NewError(ErrorCode(err), err)
+	}
+
+	h.logger.InfoContext(ctx, "secrets deleted",
+		slog.Any("names", req.Msg.GetNames()))
+
+	return connect.NewResponse(&shellv1.DeleteSecretsResponse{}), nil
+}
@@ -2,19 +2,36 @@ package shell
 
 import (
 	"context"
-	"errors"
 	"log/slog"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/HJH0924/agent-sandbox/domain/shell/service"
+	"github.com/HJH0924/agent-sandbox/internal/history"
+	"github.com/HJH0924/agent-sandbox/internal/launcher"
+	"github.com/HJH0924/agent-sandbox/internal/middleware"
+	"github.com/HJH0924/agent-sandbox/internal/policy"
+	"github.com/HJH0924/agent-sandbox/internal/recording"
+	"github.com/HJH0924/agent-sandbox/internal/secret"
 	shellv1 "github.com/HJH0924/agent-sandbox/sdk/go/shell/v1"
+	"github.com/HJH0924/agent-sandbox/sdk/go/shell/v1/shellv1connect"
 
 	"connectrpc.com/connect"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+func TestMain(m *testing.M) {
+	// 资源限制通过重新执行测试二进制应用
+	launcher.Init()
+	os.Exit(m.Run())
+}
+
 func TestNewHandler(t *testing.T) {
 	shellService := service.NewService(30, "/tmp")
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
@@ -61,38 +78,55 @@ func TestHandler_Execute_CommandFailed_WithOutput(t *testing.T) {
 
 	resp, err := handler.Execute(ctx, req)
 
-	// 应该返回输出和错误
-	assert.Error(t, err)
-	assert.NotNil(t, resp)
+	// 非零退出码通过响应返回，不视为错误
+	require.NoError(t, err)
 	assert.NotEmpty(t, resp.Msg.GetOutput())
-
-	// 验证错误类型
-	var connectErr *connect.Error
-	assert.True(t, errors.As(err, &connectErr))
-	assert.Equal(t, connect.CodeInternal, connectErr.Code())
+	assert.NotZero(t, resp.Msg.GetExitCode())
 }
 
 func TestHandler_Execute_CommandFailed_NoOutput(t *testing.T) {
+	tmpDir := t.TempDir()
+	shellService := service.NewService(30, tmpDir)
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	handler := NewHandler(shellService, logger)
+
+	resp, err := handler.Execute(context.Background(), connect.NewRequest(&shellv1.ExecuteRequest{
+		Command: "exit 3",
+	}))
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), resp.Msg.GetExitCode())
+	assert.Empty(t, resp.Msg.GetOutput())
+}
+
+func TestHandler_Execute_TimedOut(t *testing.T) {
 	tmpDir := t.TempDir()
 	shellService := service.NewService(1, tmpDir) // 1秒超时
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
 	handler := NewHandler(shellService, logger)
 
-	ctx := context.Background()
-	command := "sleep 5" // 会超时
+	resp, err := handler.Execute(context.Background(), connect.NewRequest(&shellv1.ExecuteRequest{
+		Command: "echo started; sleep 5",
+	}))
 
-	req := connect.NewRequest(&shellv1.ExecuteRequest{
-		Command: command,
-	})
+	// 超时被终止的命令通过响应返回信号和输出
+	require.NoError(t, err)
+	assert.True(t, resp.Msg.GetTimedOut())
+	assert.Equal(t, "SIGTERM", resp.Msg.GetSignal())
+	assert.Equal(t, "started\n", resp.Msg.GetOutput())
+}
 
-	_, err := handler.Execute(ctx, req)
+func TestHandler_Execute_LimitsExceeded(t *testing.T) {
+	shellService := service.NewService(10, t.TempDir(), service.WithResourceLimits(service.ResourceLimits{FileSize: 4096}))
+	handler := NewHandler(shellService, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
 
-	// 超时错误，可能没有输出
-	assert.Error(t, err)
+	resp, err := handler.Execute(context.Background(), connect.NewRequest(&shellv1.ExecuteRequest{
+		Command: "exec head -c 100000 /dev/zero > big",
+	}))
 
-	var connectErr *connect.Error
-	assert.True(t, errors.As(err, &connectErr))
-	assert.Equal(t, connect.CodeInternal, connectErr.Code())
+	require.NoError(t, err)
+	assert.Equal(t, []string{service.LimitFileSize}, resp.Msg.GetLimitsExceeded())
+	assert.Equal(t, "SIGXFSZ", resp.Msg.GetSignal())
 }
 
 func TestHandler_Execute_EmptyCommand(t *testing.T) {
@@ -140,3 +174,421 @@ func TestHandler_Execute_WorkingDirectory(t *testing.T) {
 	assert.NotNil(t, resp)
 	assert.Contains(t, resp.Msg.GetOutput(), testContent)
 }
+
+func TestHandler_Execute_NonUTF8Output(t *testing.T) {
+	client := newTerminalClient(t)
+	ctx := context.Background()
+
+	// 经过真实的序列化，非 UTF-8 输出不能导致响应无法编码
+	resp, err := client.Execute(ctx, connect.NewRequest(&shellv1.ExecuteRequest{Command: `printf 'caf\351\000'`}))
+	require.NoError(t, err)
+	assert.Equal(t, "caf\uFFFD\x00", resp.Msg.GetOutput())
+	assert.Equal(t, "binary", resp.Msg.GetStdoutEncoding())
+	assert.Equal(t, "utf-8", resp.Msg.GetStderrEncoding())
+	assert.Empty(t, resp.Msg.GetStdoutData())
+
+	resp, err = client.Execute(ctx, connect.NewRequest(&shellv1.ExecuteRequest{
+		Command:   `printf 'caf\351'`,
+		RawOutput: true,
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("caf\xe9"), resp.Msg.GetStdoutData())
+	assert.Equal(t, "unknown", resp.Msg.GetStdoutEncoding())
+	assert.Empty(t, resp.Msg.GetOutput())
+}
+
+func TestHandler_Execute_RawOutputTruncated(t *testing.T) {
+	shellService := service.NewService(30, t.TempDir(), service.WithMaxOutputSize(10))
+	handler := NewHandler(shellService, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
+	resp, err := handler.Execute(context.Background(), connect.NewRequest(&shellv1.ExecuteRequest{
+		Command:   `printf '0123\351\351\351\351wxyz'`,
+		RawOutput: true,
+	}))
+
+	// 原始字节为开头和结尾的字节，截断只通过 truncated 报告
+	require.NoError(t, err)
+	assert.True(t, resp.Msg.GetTruncated())
+	assert.Equal(t, []byte("0123\xe9\xe9wxyz"), resp.Msg.GetStdoutData())
+	assert.Equal(t, int64(12), resp.Msg.GetStdoutBytes())
+}
+
+// newTerminalClient 启动支持 HTTP/2 的测试服务器并返回 Shell 客户端.
+func newTerminalClient(t *testing.T) shellv1connect.ShellServiceClient {
+	t.Helper()
+
+	shellService := service.NewService(30, t.TempDir())
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	handler := NewHandler(shellService, logger)
+
+	mux := http.NewServeMux()
+	mux.Handle(shellv1connect.NewShellServiceHandler(handler))
+
+	server := httptest.NewUnstartedServer(mux)
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	return shellv1connect.NewShellServiceClient(server.Client(), server.URL)
+}
+
+func TestHandler_Terminal(t *testing.T) {
+	client := newTerminalClient(t)
+
+	stream := client.Terminal(context.Background())
+
+	err := stream.Send(&shellv1.TerminalRequest{
+		Event: &shellv1.TerminalRequest_Start{Start: &shellv1.TerminalStart{
+			Size: &shellv1.TerminalSize{Cols: 80, Rows: 24},
+		}},
+	})
+	require.NoError(t, err)
+
+	resp, err := stream.Receive()
+	require.NoError(t, err)
+	require.NotNil(t, resp.GetStarted())
+	assert.NotEmpty(t, resp.GetStarted().GetSessionId())
+	assert.False(t, resp.GetStarted().GetReattached())
+
+	err = stream.Send(&shellv1.TerminalRequest{
+		Event: &shellv1.TerminalRequest_Input{Input: []byte("echo terminal-$((1 + 1))\nexit 7\n")},
+	})
+	require.NoError(t, err)
+
+	var output strings.Builder
+
+	for {
+		resp, err := stream.Receive()
+		require.NoError(t, err)
+
+		if exited := resp.GetExited(); exited != nil {
+			assert.Equal(t, int32(7), exited.GetExitCode())
+			break
+		}
+
+		output.Write(resp.GetOutput())
+	}
+
+	assert.Contains(t, output.String(), "terminal-2")
+	require.NoError(t, stream.CloseRequest())
+	require.NoError(t, stream.CloseResponse())
+}
+
+func TestHandler_Terminal_InvalidFirstMessage(t *testing.T) {
+	client := newTerminalClient(t)
+
+	stream := client.Terminal(context.Background())
+
+	err := stream.Send(&shellv1.TerminalRequest{
+		Event: &shellv1.TerminalRequest_Input{Input: []byte("ls\n")},
+	})
+	require.NoError(t, err)
+
+	_, err = stream.Receive()
+	require.Error(t, err)
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+}
+
+func TestHandler_Terminal_ReattachUnknownSession(t *testing.T) {
+	client := newTerminalClient(t)
+
+	stream := client.Terminal(context.Background())
+
+	err := stream.Send(&shellv1.TerminalRequest{
+		Event: &shellv1.TerminalRequest_Start{Start: &shellv1.TerminalStart{SessionId: "missing"}},
+	})
+	require.NoError(t, err)
+
+	_, err = stream.Receive()
+	require.Error(t, err)
+	assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+}
+
+func TestHandler_Terminal_InvalidSize(t *testing.T) {
+	client := newTerminalClient(t)
+
+	stream := client.Terminal(context.Background())
+
+	err := stream.Send(&shellv1.TerminalRequest{
+		Event: &shellv1.TerminalRequest_Start{Start: &shellv1.TerminalStart{
+			Size: &shellv1.TerminalSize{Cols: 70000, Rows: 24},
+		}},
+	})
+	require.NoError(t, err)
+
+	_, err = stream.Receive()
+	require.Error(t, err)
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+}
+
+func TestHandler_Session(t *testing.T) {
+	tmpDir := t.TempDir()
+	shellService := service.NewService(30, tmpDir)
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	handler := NewHandler(shellService, logger)
+
+	ctx := context.WithValue(context.Background(), middleware.SandboxIDKey, "sandbox-1")
+
+	createResp, err := handler.CreateSession(ctx, connect.NewRequest(&shellv1.CreateSessionRequest{}))
+	require.NoError(t, err)
+
+	sessionID := createResp.Msg.GetSessionId()
+	assert.NotEmpty(t, sessionID)
+
+	_, err = handler.Execute(ctx, connect.NewRequest(&shellv1.ExecuteRequest{
+		Command:   "export NAME=agent",
+		SessionId: sessionID,
+	}))
+	require.NoError(t, err)
+
+	resp, err := handler.Execute(ctx, connect.NewRequest(&shellv1.ExecuteRequest{
+		Command:   "echo $NAME; exit_code() { return 4; }; exit_code",
+		SessionId: sessionID,
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "agent\n", resp.Msg.GetOutput())
+	assert.Equal(t, int32(4), resp.Msg.GetExitCode())
+
+	_, err = handler.CloseSession(ctx, connect.NewRequest(&shellv1.CloseSessionRequest{SessionId: sessionID}))
+	require.NoError(t, err)
+
+	_, err = handler.Execute(ctx, connect.NewRequest(&shellv1.ExecuteRequest{
+		Command:   "pwd",
+		SessionId: sessionID,
+	}))
+	assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+
+	_, err = handler.CloseSession(ctx, connect.NewRequest(&shellv1.CloseSessionRequest{SessionId: sessionID}))
+	assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+}
+
+func TestHandler_ListExecutions(t *testing.T) {
+	shellService := service.NewService(30, t.TempDir(), service.WithHistory(history.NewStore(10, true)))
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	handler := NewHandler(shellService, logger)
+
+	ctx := context.WithValue(context.Background(), middleware.SandboxIDKey, "sandbox-1")
+	ctx = context.WithValue(ctx, middleware.APIKeyIDKey, "abc123")
+
+	for _, command := range []string{"echo one", "echo two", "echo three"} {
+		_, err := handler.Execute(ctx, connect.NewRequest(&shellv1.ExecuteRequest{Command: command}))
+		require.NoError(t, err)
+	}
+
+	resp, err := handler.ListExecutions(ctx, connect.NewRequest(&shellv1.ListExecutionsRequest{PageSize: 2}))
+	require.NoError(t, err)
+	require.Len(t, resp.Msg.GetExecutions(), 2)
+
+	latest := resp.Msg.GetExecutions()[0]
+	assert.Equal(t, "echo three", latest.GetCommand())
+	assert.Equal(t, "abc123", latest.GetCallerKeyId())
+	assert.Equal(t, int64(6), latest.GetOutputBytes())
+	assert.Empty(t, latest.GetOutput())
+	assert.False(t, latest.GetEndedAt().AsTime().Before(latest.GetStartedAt().AsTime()))
+
+	resp, err = handler.ListExecutions(ctx, connect.NewRequest(&shellv1.ListExecutionsRequest{
+		PageSize:      2,
+		PageToken:     resp.Msg.GetNextPageToken(),
+		IncludeOutput: true,
+	}))
+	require.NoError(t, err)
+	require.Len(t, resp.Msg.GetExecutions(), 1)
+	assert.Equal(t, "one\n", resp.Msg.GetExecutions()[0].GetOutput())
+	assert.Empty(t, resp.Msg.GetNextPageToken())
+
+	_, err = handler.ListExecutions(ctx, connect.NewRequest(&shellv1.ListExecutionsRequest{PageToken: "bogus"}))
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+
+	// 未开启执行历史
+	handler = NewHandler(service.NewService(30, t.TempDir()), logger)
+
+	_, err = handler.ListExecutions(ctx, connect.NewRequest(&shellv1.ListExecutionsRequest{}))
+	assert.Equal(t, connect.CodeFailedPrecondition, connect.CodeOf(err))
+}
+
+func TestHandler_Execute_PolicyDenied(t *testing.T) {
+	commandPolicy, err := policy.New([]policy.Rule{
+		{Name: "rm-root", Action: policy.ActionDeny, Prefix: []string{"rm", "-rf", "/"}},
+	}, policy.ActionAllow)
+	require.NoError(t, err)
+
+	shellService := service.NewService(30, t.TempDir(), service.WithPolicy(commandPolicy))
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	handler := NewHandler(shellService, logger)
+
+	_, err = handler.Execute(context.Background(), connect.NewRequest(&shellv1.ExecuteRequest{
+		Command: "rm -rf /",
+	}))
+
+	require.Error(t, err)
+	assert.Equal(t, connect.CodePermissionDenied, connect.CodeOf(err))
+	assert.Contains(t, err.Error(), "rm-root")
+}
+
+func TestHandler_Execute_QueueFull(t *testing.T) {
+	shellService := service.NewService(30, t.TempDir(), service.WithConcurrency(service.Concurrency{MaxExecutions: 1}))
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	handler := NewHandler(shellService, logger)
+
+	ctx := context.Background()
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		_, _ = handler.Execute(ctx, connect.NewRequest(&shellv1.ExecuteRequest{Command: "sleep 0.5"}))
+	}()
+
+	// 没有排队空间，名额被占用后立即拒绝
+	assert.Eventually(t, func() bool {
+		_, err := handler.Execute(ctx, connect.NewRequest(&shellv1.ExecuteRequest{Command: "true"}))
+		return connect.CodeOf(err) == connect.CodeResourceExhausted
+	}, time.Second, 10*time.Millisecond)
+
+	<-done
+}
+
+func TestHandler_Execute_Argv(t *testing.T) {
+	shellService := service.NewService(30, t.TempDir())
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	handler := NewHandler(shellService, logger)
+
+	ctx := context.Background()
+
+	resp, err := handler.Execute(ctx, connect.NewRequest(&shellv1.ExecuteRequest{
+		Argv: []string{"echo", "a  b", "$HOME"},
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "a  b $HOME\n", resp.Msg.GetOutput())
+
+	_, err = handler.Execute(ctx, connect.NewRequest(&shellv1.ExecuteRequest{
+		Command: "echo",
+		Argv:    []string{"echo"},
+	}))
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+
+	_, err = handler.Execute(ctx, connect.NewRequest(&shellv1.ExecuteRequest{
+		Argv: []string{"agent-sandbox-no-such-program"},
+	}))
+	assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+}
+
+func TestHandler_WaitFor(t *testing.T) {
+	workspace := t.TempDir()
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	handler := NewHandler(service.NewService(30, workspace), logger)
+	ctx := context.WithValue(context.Background(), middleware.SandboxIDKey, "sandbox-1")
+
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "app.log"), []byte("booting\nserver ready on 8080\n"), 0o600))
+
+	resp, err := handler.WaitFor(ctx, connect.NewRequest(&shellv1.WaitForRequest{
+		Condition: &shellv1.WaitForRequest_Log{Log: &shellv1.WaitForLog{Path: "app.log", Pattern: `ready on \d+`}},
+	}))
+	require.NoError(t, err)
+	assert.True(t, resp.Msg.GetReady())
+	assert.Equal(t, "server ready on 8080", resp.Msg.GetMatch())
+	assert.Equal(t, int32(1), resp.Msg.GetAttempts())
+
+	// 超时通过 ready 返回
+	resp, err = handler.WaitFor(ctx, connect.NewRequest(&shellv1.WaitForRequest{
+		Condition:      &shellv1.WaitForRequest_File{File: &shellv1.WaitForFile{Path: "missing"}},
+		TimeoutSeconds: 1,
+	}))
+	require.NoError(t, err)
+	assert.False(t, resp.Msg.GetReady())
+	assert.NotEmpty(t, resp.Msg.GetLastError())
+
+	_, err = handler.WaitFor(ctx, connect.NewRequest(&shellv1.WaitForRequest{}))
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+}
+
+func TestHandler_Recordings(t *testing.T) {
+	store, err := recording.NewStore(t.TempDir(), 10, 0)
+	require.NoError(t, err)
+
+	handler := NewHandler(service.NewService(30, t.TempDir(), service.WithRecordings(store)), slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
+	mux := http.NewServeMux()
+	mux.Handle(shellv1connect.NewShellServiceHandler(handler))
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	client := shellv1connect.NewShellServiceClient(server.Client(), server.URL)
+	ctx := context.Background()
+
+	executed, err := client.Execute(ctx, connect.NewRequest(&shellv1.ExecuteRequest{Command: "echo recorded"}))
+	require.NoError(t, err)
+	require.NotEmpty(t, executed.Msg.GetRecordingId())
+
+	listed, err := client.ListRecordings(ctx, connect.NewRequest(&shellv1.ListRecordingsRequest{}))
+	require.NoError(t, err)
+	require.Len(t, listed.Msg.GetRecordings(), 1)
+
+	rec := listed.Msg.GetRecordings()[0]
+	assert.Equal(t, executed.Msg.GetRecordingId(), rec.GetId())
+	assert.Equal(t, "execute", rec.GetKind())
+	assert.Equal(t, "echo recorded", rec.GetCommand())
+	assert.NotNil(t, rec.GetEndedAt())
+
+	stream, err := client.DownloadRecording(ctx, connect.NewRequest(&shellv1.DownloadRecordingRequest{RecordingId: rec.GetId()}))
+	require.NoError(t, err)
+
+	var content strings.Builder
+	for stream.Receive() {
+		content.Write(stream.Msg().GetData())
+	}
+
+	require.NoError(t, stream.Err())
+	assert.Equal(t, rec.GetSize(), int64(content.Len()))
+	assert.Contains(t, content.String(), `"recorded\r\n"`)
+
+	stream, err = client.DownloadRecording(ctx, connect.NewRequest(&shellv1.DownloadRecordingRequest{RecordingId: "missing"}))
+	require.NoError(t, err)
+	assert.False(t, stream.Receive())
+	assert.Equal(t, connect.CodeNotFound, connect.CodeOf(stream.Err()))
+}
+
+func TestHandler_Recordings_Disabled(t *testing.T) {
+	client := newTerminalClient(t)
+
+	_, err := client.ListRecordings(context.Background(), connect.NewRequest(&shellv1.ListRecordingsRequest{}))
+	require.Error(t, err)
+	assert.Equal(t, connect.CodeFailedPrecondition, connect.CodeOf(err))
+}
+
+func TestHandler_Secrets(t *testing.T) {
+	handler := NewHandler(service.NewService(30, t.TempDir(), service.WithSecrets(secret.NewStore())), slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+	ctx := context.WithValue(context.Background(), middleware.SandboxIDKey, "sandbox-1")
+
+	_, err := handler.SetSecrets(ctx, connect.NewRequest(&shellv1.SetSecretsRequest{
+		Secrets: map[string]string{"API_TOKEN": "tok-123456", "DB_PASSWORD": "hunter22"},
+	}))
+	require.NoError(t, err)
+
+	listed, err := handler.ListSecrets(ctx, connect.NewRequest(&shellv1.ListSecretsRequest{}))
+	require.NoError(t, err)
+	require.Len(t, listed.Msg.GetSecrets(), 2)
+	assert.Equal(t, "API_TOKEN", listed.Msg.GetSecrets()[0].GetName())
+	assert.NotNil(t, listed.Msg.GetSecrets()[0].GetUpdatedAt())
+	assert.NotContains(t, listed.Msg.String(), "tok-123456")
+
+	executed, err := handler.Execute(ctx, connect.NewRequest(&shellv1.ExecuteRequest{Command: `echo "$API_TOKEN"`}))
+	require.NoError(t, err)
+	assert.Equal(t, "[REDACTED:API_TOKEN]\n", executed.Msg.GetOutput())
+
+	_, err = handler.DeleteSecrets(ctx, connect.NewRequest(&shellv1.DeleteSecretsRequest{Names: []string{"API_TOKEN"}}))
+	require.NoError(t, err)
+
+	listed, err = handler.ListSecrets(ctx, connect.NewRequest(&shellv1.ListSecretsRequest{}))
+	require.NoError(t, err)
+	require.Len(t, listed.Msg.GetSecrets(), 1)
+	assert.Equal(t, "DB_PASSWORD", listed.Msg.GetSecrets()[0].GetName())
+
+	_, err = handler.SetSecrets(ctx, connect.NewRequest(&shellv1.SetSecretsRequest{
+		Secrets: map[string]string{"BAD-NAME": "tok-123456"},
+	}))
+	require.Error(t, err)
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+	assert.NotContains(t, err.Error(), "tok-123456")
+}
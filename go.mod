@@ -4,10 +4,12 @@ go 1.24.7
 
 require (
 	connectrpc.com/connect v1.17.0
+	github.com/creack/pty v1.1.24
 	github.com/google/uuid v1.4.0
 	github.com/spf13/cobra v1.8.1
 	github.com/spf13/viper v1.19.0
 	github.com/stretchr/testify v1.9.0
+	golang.org/x/sys v0.18.0
 	google.golang.org/protobuf v1.35.2
 )
 
@@ -31,7 +33,6 @@ require (
 	go.uber.org/atomic v1.9.0 // indirect
 	go.uber.org/multierr v1.9.0 // indirect
 	golang.org/x/exp v0.0.0-20230905200255-921286631fa9 // indirect
-	golang.org/x/sys v0.18.0 // indirect
 	golang.org/x/text v0.14.0 // indirect
 	gopkg.in/ini.v1 v1.67.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
@@ -0,0 +1,207 @@
+// Package approval parks commands that need human approval until an operator
+// approves or rejects them, or until they expire.
+package approval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrNotFound 审批请求不存在或已被使用.
+	ErrNotFound = errors.New("approval not found")
+	// ErrRejected 命令被拒绝.
+	ErrRejected = errors.New("command rejected")
+	// ErrExpired 审批请求在超时前没有得到处理.
+	ErrExpired = errors.New("approval expired")
+	// ErrDecided 审批请求已经处理过.
+	ErrDecided = errors.New("approval already decided")
+)
+
+// Status 审批请求的状态.
+type Status string
+
+const (
+	// StatusPending 等待审批.
+	StatusPending Status = "pending"
+	// StatusApproved 已批准.
+	StatusApproved Status = "approved"
+	// StatusRejected 已拒绝.
+	StatusRejected Status = "rejected"
+	// StatusExpired 已过期.
+	StatusExpired Status = "expired"
+)
+
+// Request 等待审批的命令.
+type Request struct {
+	ID        string
+	SandboxID string
+	Command   string
+	// Rule 要求审批的策略规则名称
+	Rule      string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+
+	status Status
+	reason string
+	done   chan struct{}
+}
+
+// Queue 审批队列，请求在超时后过期，处理结果保留一个超时周期供调用方查询.
+type Queue struct {
+	mu       sync.Mutex
+	timeout  time.Duration
+	requests map[string]*Request
+}
+
+// NewQueue 创建审批队列，timeout 为请求等待审批的最长时间.
+func NewQueue(timeout time.Duration) *Queue {
+	return &Queue{
+		timeout:  timeout,
+		requests: make(map[string]*Request),
+	}
+}
+
+// Submit 提交等待审批的命令.
+func (q *Queue) Submit(sandboxID, command, rule string) *Request {
+	now := time.Now()
+	req := &Request{
+		ID:        uuid.New().String(),
+		SandboxID: sandboxID,
+		Command:   command,
+		Rule:      rule,
+		CreatedAt: now,
+		ExpiresAt: now.Add(q.timeout),
+		status:    StatusPending,
+		done:      make(chan struct{}),
+	}
+
+	q.mu.Lock()
+	q.requests[req.ID] = req
+	q.mu.Unlock()
+
+	time.AfterFunc(q.timeout, func() {
+		q.decide(req.ID, StatusExpired, "")
+	})
+
+	return req
+}
+
+// Get 查找审批请求.
+func (q *Queue) Get(id string) (*Request, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	req, ok := q.requests[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return req, nil
+}
+
+// Pending 返回所有等待审批的请求，按提交时间排序.
+func (q *Queue) Pending() []Request {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	pending := make([]Request, 0, len(q.requests))
+
+	for _, req := range q.requests {
+		if req.status == StatusPending {
+			pending = append(pending, *req)
+		}
+	}
+
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].CreatedAt.Before(pending[j].CreatedAt)
+	})
+
+	return pending
+}
+
+// Approve 批准命令.
+func (q *Queue) Approve(id string) error {
+	return q.decide(id, StatusApproved, "")
+}
+
+// Reject 拒绝命令，reason 会返回给调用方.
+func (q *Queue) Reject(id, reason string) error {
+	return q.decide(id, StatusRejected, reason)
+}
+
+// decide 记录处理结果并唤醒等待的调用方，结果保留一个超时周期后删除.
+func (q *Queue) decide(id string, status Status, reason string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	req, ok := q.requests[id]
+	if !ok {
+		return ErrNotFound
+	}
+
+	if req.status != StatusPending {
+		return fmt.Errorf("%w: %s", ErrDecided, req.status)
+	}
+
+	req.status = status
+	req.reason = reason
+	close(req.done)
+
+	time.AfterFunc(q.timeout, func() {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+
+		if q.requests[id] == req {
+			delete(q.requests, id)
+		}
+	})
+
+	return nil
+}
+
+// Status 返回审批请求当前的状态.
+func (q *Queue) Status(req *Request) Status {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return req.status
+}
+
+// Wait 等待审批结果，批准后请求被使用并删除，之后无法再次用于执行命令.
+// 请求被拒绝或过期时返回 ErrRejected 或 ErrExpired；ctx 结束时请求保持等待状态.
+func (q *Queue) Wait(ctx context.Context, req *Request) error {
+	select {
+	case <-req.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	switch req.status {
+	case StatusApproved:
+		if q.requests[req.ID] != req {
+			return ErrNotFound
+		}
+
+		delete(q.requests, req.ID)
+
+		return nil
+	case StatusRejected:
+		if req.reason != "" {
+			return fmt.Errorf("%w: %s", ErrRejected, req.reason)
+		}
+
+		return ErrRejected
+	default:
+		return ErrExpired
+	}
+}
@@ -0,0 +1,79 @@
+package approval
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueue_Approve(t *testing.T) {
+	queue := NewQueue(time.Minute)
+
+	req := queue.Submit("sandbox-1", "git push", "git-push")
+
+	pending := queue.Pending()
+	require.Len(t, pending, 1)
+	assert.Equal(t, req.ID, pending[0].ID)
+	assert.Equal(t, "git push", pending[0].Command)
+	assert.Equal(t, "git-push", pending[0].Rule)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- queue.Wait(context.Background(), req)
+	}()
+
+	require.NoError(t, queue.Approve(req.ID))
+	require.NoError(t, <-done)
+
+	assert.Empty(t, queue.Pending())
+
+	// 批准只能使用一次
+	_, err := queue.Get(req.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorIs(t, queue.Approve(req.ID), ErrNotFound)
+}
+
+func TestQueue_Reject(t *testing.T) {
+	queue := NewQueue(time.Minute)
+
+	req := queue.Submit("sandbox-1", "git push", "git-push")
+	require.NoError(t, queue.Reject(req.ID, "not on Fridays"))
+
+	err := queue.Wait(context.Background(), req)
+	require.ErrorIs(t, err, ErrRejected)
+	assert.Contains(t, err.Error(), "not on Fridays")
+
+	// 处理结果保留，重复处理失败
+	assert.Equal(t, StatusRejected, queue.Status(req))
+	assert.ErrorIs(t, queue.Approve(req.ID), ErrDecided)
+}
+
+func TestQueue_Expire(t *testing.T) {
+	queue := NewQueue(50 * time.Millisecond)
+
+	req := queue.Submit("sandbox-1", "git push", "git-push")
+
+	require.ErrorIs(t, queue.Wait(context.Background(), req), ErrExpired)
+	assert.Equal(t, StatusExpired, queue.Status(req))
+	assert.ErrorIs(t, queue.Approve(req.ID), ErrDecided)
+	assert.Empty(t, queue.Pending())
+}
+
+func TestQueue_WaitCanceled(t *testing.T) {
+	queue := NewQueue(time.Minute)
+
+	req := queue.Submit("sandbox-1", "git push", "git-push")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, queue.Wait(ctx, req), context.DeadlineExceeded)
+
+	// 调用方放弃等待后请求仍可被处理
+	assert.Equal(t, StatusPending, queue.Status(req))
+	require.NoError(t, queue.Approve(req.ID))
+	require.NoError(t, queue.Wait(context.Background(), req))
+}
@@ -21,6 +21,20 @@ type ServerConfig struct {
 	Port         int           `mapstructure:"port"`
 	ReadTimeout  time.Duration `mapstructure:"read_timeout"`
 	WriteTimeout time.Duration `mapstructure:"write_timeout"`
+	// AdminAPIKey 管理接口（如命令审批）使用的 API Key，为空时禁用管理接口
+	AdminAPIKey string `mapstructure:"admin_api_key"`
+	// Preview 沙箱端口的 HTTP 预览代理
+	Preview PreviewConfig `mapstructure:"preview"`
+}
+
+// PreviewConfig HTTP 预览代理配置.
+type PreviewConfig struct {
+	// Enabled 是否启用 /sandboxes/{id}/ports/{port}/ 预览代理和 PreviewService（包括端口转发）
+	Enabled bool `mapstructure:"enabled"`
+	// Secret 签名预览令牌的密钥，为空时启动时随机生成（重启后已签发的令牌失效）
+	Secret string `mapstructure:"secret"`
+	// TokenTTL 预览令牌的默认及最长有效期（秒）
+	TokenTTL int `mapstructure:"token_ttl"`
 }
 
 // SandboxConfig 沙箱配置.
@@ -28,6 +42,181 @@ type SandboxConfig struct {
 	WorkspaceDir string `mapstructure:"workspace_dir"`
 	MaxFileSize  int64  `mapstructure:"max_file_size"`
 	ShellTimeout int    `mapstructure:"shell_timeout"`
+	// Shell 执行命令字符串和终端使用的 shell（如 sh、bash 或绝对路径）
+	Shell string `mapstructure:"shell"`
+	// LoginShell 以登录 shell 方式（-l）启动，会先加载 profile
+	LoginShell bool `mapstructure:"login_shell"`
+	// Environment 命令的基础环境，替代服务进程的环境变量
+	Environment EnvironmentConfig `mapstructure:"environment"`
+	// MaxOutputSize 命令每个输出流保留的最大字节数，超出部分只保留开头和结尾
+	MaxOutputSize int64 `mapstructure:"max_output_size"`
+	// SpillOutput 输出被截断时是否将完整输出保存到工作空间
+	SpillOutput bool `mapstructure:"spill_output"`
+	// KillGracePeriod 命令超时或取消时从 SIGTERM 到 SIGKILL 的宽限期（秒）
+	KillGracePeriod int `mapstructure:"kill_grace_period"`
+	// Limits 命令资源限制
+	Limits LimitsConfig `mapstructure:"limits"`
+	// Users 沙箱专属用户
+	Users UsersConfig `mapstructure:"users"`
+	// Isolation 命名空间隔离
+	Isolation IsolationConfig `mapstructure:"isolation"`
+	// DefaultNetwork 创建沙箱时未指定网络策略时使用的策略（none、loopback、full）
+	DefaultNetwork string `mapstructure:"default_network"`
+	// Policy 服务端命令策略
+	Policy PolicyConfig `mapstructure:"policy"`
+	// ApprovalTimeout 需要审批的命令等待审批的最长时间（秒）
+	ApprovalTimeout int `mapstructure:"approval_timeout"`
+	// Concurrency 命令并发执行限制
+	Concurrency ConcurrencyConfig `mapstructure:"concurrency"`
+	// History 命令执行历史
+	History HistoryConfig `mapstructure:"history"`
+	// Recording 命令和终端输出的 asciicast 录制
+	Recording RecordingConfig `mapstructure:"recording"`
+	// Languages RunCode 支持的语言，键为语言名称
+	Languages map[string]LanguageConfig `mapstructure:"languages"`
+	// TestRunners RunTests 支持的测试运行器，键为运行器名称
+	TestRunners map[string]TestRunnerConfig `mapstructure:"test_runners"`
+	// Checkers Diagnostics 支持的编译器和 linter，键为检查器名称
+	Checkers map[string]CheckerConfig `mapstructure:"checkers"`
+}
+
+// LanguageConfig 运行代码片段的解释器配置.
+type LanguageConfig struct {
+	// Command 解释器及其参数，代码文件的路径追加在最后
+	Command []string `mapstructure:"command"`
+	// Extension 代码文件的扩展名（如 .py）
+	Extension string `mapstructure:"extension"`
+	// Repl 内核使用的交互式解释器命令，为空时该语言不支持内核
+	Repl []string `mapstructure:"repl"`
+	// ReplRun 让解释器运行单元文件（{file}）并输出结束标记（{marker}）的语句
+	ReplRun string `mapstructure:"repl_run"`
+}
+
+// TestRunnerConfig 运行测试的命令配置.
+type TestRunnerConfig struct {
+	// Command 运行测试的程序及参数，请求中的参数追加在最后
+	Command []string `mapstructure:"command"`
+	// DefaultArgs 请求没有指定参数时追加的参数
+	DefaultArgs []string `mapstructure:"default_args"`
+	// Format 报告格式（go、junit、tap）
+	Format string `mapstructure:"format"`
+	// Report 运行器写入的报告文件（相对工作空间的路径），为空时解析 stdout
+	Report string `mapstructure:"report"`
+}
+
+// CheckerConfig 编译器或 linter 检查器配置.
+type CheckerConfig struct {
+	// Command 运行检查的程序及参数，请求中的参数追加在最后
+	Command []string `mapstructure:"command"`
+	// DefaultArgs 请求没有指定参数时追加的参数
+	DefaultArgs []string `mapstructure:"default_args"`
+	// Format 输出格式（go、gcc、golangci-lint）
+	Format string `mapstructure:"format"`
+}
+
+// ConcurrencyConfig 命令并发执行限制配置，0 表示不限制.
+type ConcurrencyConfig struct {
+	// MaxExecutions 整个服务同时执行的命令数
+	MaxExecutions int `mapstructure:"max_executions"`
+	// MaxPerSandbox 每个沙箱同时执行的命令数
+	MaxPerSandbox int `mapstructure:"max_per_sandbox"`
+	// QueueSize 达到上限后排队等待的命令数，队列满时拒绝新命令，0 表示不排队
+	QueueSize int `mapstructure:"queue_size"`
+}
+
+// HistoryConfig 命令执行历史配置.
+type HistoryConfig struct {
+	// MaxEntries 每个沙箱保留的执行记录数，超出时丢弃最早的记录；0 表示不记录
+	MaxEntries int `mapstructure:"max_entries"`
+	// StoreOutput 是否同时保存命令输出（每条最多 2 * max_output_size 字节）
+	StoreOutput bool `mapstructure:"store_output"`
+}
+
+// EnvironmentConfig 沙箱命令的基础环境配置. 命令不继承服务进程的环境变量，HOME 为沙箱的工作空间.
+type EnvironmentConfig struct {
+	// Path 命令的 PATH，为空时使用服务进程的 PATH
+	Path string `mapstructure:"path"`
+	// Lang LANG 的值，为空时不设置
+	Lang string `mapstructure:"lang"`
+	// TZ 时区，为空时不设置
+	TZ string `mapstructure:"tz"`
+	// Passthrough 从服务进程继承的环境变量名称（如 HTTP_PROXY）
+	Passthrough []string `mapstructure:"passthrough"`
+	// Vars 额外的环境变量（NAME=value）
+	Vars []string `mapstructure:"vars"`
+	// Profile 在每个命令之前加载的 shell 脚本，相对路径相对沙箱的工作空间，为空时不加载
+	Profile string `mapstructure:"profile"`
+}
+
+// RecordingConfig 命令、终端和代码单元输出的 asciicast 录制配置.
+type RecordingConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Dir 保存录制文件的目录，每个沙箱一个子目录；应位于工作空间之外，以免被沙箱中的命令修改
+	Dir string `mapstructure:"dir"`
+	// MaxPerSandbox 每个沙箱保留的录制数，超出时删除最早的录制；0 表示不限制
+	MaxPerSandbox int `mapstructure:"max_per_sandbox"`
+	// MaxSize 每个录制文件的最大字节数，超出部分不再记录；0 表示不限制
+	MaxSize int64 `mapstructure:"max_size"`
+}
+
+// PolicyConfig 命令策略配置，规则按顺序匹配，第一条命中的规则决定处理方式.
+type PolicyConfig struct {
+	// Default 没有规则命中时的处理方式（allow、deny、approve）
+	Default string `mapstructure:"default"`
+	// Rules 命令规则
+	Rules []PolicyRuleConfig `mapstructure:"rules"`
+}
+
+// PolicyRuleConfig 命令规则配置，pattern 和 prefix 至少设置一个.
+type PolicyRuleConfig struct {
+	Name string `mapstructure:"name"`
+	// Action 命中后的处理方式（allow、deny、approve）
+	Action string `mapstructure:"action"`
+	// Pattern 与完整命令字符串匹配的正则表达式
+	Pattern string `mapstructure:"pattern"`
+	// Prefix 与命令中任意一条简单命令的参数前缀匹配
+	Prefix []string `mapstructure:"prefix"`
+}
+
+// IsolationConfig 命名空间隔离配置.
+type IsolationConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Required 为 true 时内核不支持隔离则拒绝启动，否则记录警告并以非隔离模式运行
+	Required bool `mapstructure:"required"`
+	// ReadOnlyPaths 以只读方式提供给命令的系统目录，为空时使用默认列表
+	ReadOnlyPaths []string `mapstructure:"read_only_paths"`
+	// Hostname 隔离环境中的主机名
+	Hostname string `mapstructure:"hostname"`
+}
+
+// UsersConfig 沙箱专属用户配置.
+// 启用后每个沙箱以范围内独立的 UID/GID 运行命令，工作空间为 workspace_dir/<sandbox_id>.
+type UsersConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// UIDStart 分配给沙箱的第一个 UID（GID 与 UID 相同）
+	UIDStart uint32 `mapstructure:"uid_start"`
+	// Count 可分配的 UID 数量，即同时存在的沙箱上限
+	Count uint32 `mapstructure:"count"`
+}
+
+// LimitsConfig 命令资源限制配置，0 表示不限制.
+type LimitsConfig struct {
+	// CPUSeconds 单条命令的 CPU 时间（秒）
+	CPUSeconds uint64 `mapstructure:"cpu_seconds"`
+	// AddressSpace 虚拟地址空间上限（字节）
+	AddressSpace uint64 `mapstructure:"address_space"`
+	// OpenFiles 可打开的文件描述符数量
+	OpenFiles uint64 `mapstructure:"open_files"`
+	// MaxProcesses 所属用户可拥有的进程数（RLIMIT_NPROC）
+	MaxProcesses uint64 `mapstructure:"max_processes"`
+	// FileSize 单个文件大小上限（字节）
+	FileSize uint64 `mapstructure:"file_size"`
+	// Memory 每个沙箱的内存上限（字节），需要 cgroup v2
+	Memory int64 `mapstructure:"memory"`
+	// Pids 每个沙箱的进程数上限，需要 cgroup v2
+	Pids int64 `mapstructure:"pids"`
+	// CgroupRoot 沙箱 cgroup 的父目录
+	CgroupRoot string `mapstructure:"cgroup_root"`
 }
 
 // LogConfig 日志配置.
@@ -36,15 +225,78 @@ type LogConfig struct {
 	Format string `mapstructure:"format"`
 }
 
+// pythonReplRun Python 内核运行单元的默认语句：通过 sys.last_value 判断单元是否抛出异常.
+const pythonReplRun = `__import__('sys').last_value = None
+exec(compile(open('{file}').read(), '{file}', 'exec'))
+print('\n{marker}', int(__import__('sys').last_value is not None)); print('\n{marker}', file=__import__('sys').stderr)`
+
 // setDefaults 设置配置的默认值.
 func setDefaults() {
 	viper.SetDefault("server.host", "0.0.0.0")
 	viper.SetDefault("server.port", 8080)
 	viper.SetDefault("server.read_timeout", "30s")
 	viper.SetDefault("server.write_timeout", "30s")
+	viper.SetDefault("server.preview.enabled", true)
+	viper.SetDefault("server.preview.secret", "")
+	viper.SetDefault("server.preview.token_ttl", 900)
 	viper.SetDefault("sandbox.workspace_dir", "/tmp/agent-sandbox")
 	viper.SetDefault("sandbox.max_file_size", 104857600)
 	viper.SetDefault("sandbox.shell_timeout", 300)
+	viper.SetDefault("sandbox.shell", "sh")
+	viper.SetDefault("sandbox.login_shell", false)
+	viper.SetDefault("sandbox.environment.path", "")
+	viper.SetDefault("sandbox.environment.lang", "C.UTF-8")
+	viper.SetDefault("sandbox.environment.tz", "UTC")
+	viper.SetDefault("sandbox.environment.passthrough", []string{})
+	viper.SetDefault("sandbox.environment.vars", []string{})
+	viper.SetDefault("sandbox.environment.profile", "")
+	viper.SetDefault("sandbox.max_output_size", 1048576)
+	viper.SetDefault("sandbox.spill_output", false)
+	viper.SetDefault("sandbox.kill_grace_period", 5)
+	viper.SetDefault("sandbox.limits.cpu_seconds", 0)
+	viper.SetDefault("sandbox.limits.address_space", 0)
+	viper.SetDefault("sandbox.limits.open_files", 0)
+	viper.SetDefault("sandbox.limits.max_processes", 0)
+	viper.SetDefault("sandbox.limits.file_size", 0)
+	viper.SetDefault("sandbox.limits.memory", 0)
+	viper.SetDefault("sandbox.limits.pids", 0)
+	viper.SetDefault("sandbox.limits.cgroup_root", "/sys/fs/cgroup/agent-sandbox")
+	viper.SetDefault("sandbox.users.enabled", false)
+	viper.SetDefault("sandbox.users.uid_start", 10000)
+	viper.SetDefault("sandbox.users.count", 1000)
+	viper.SetDefault("sandbox.default_network", "full")
+	viper.SetDefault("sandbox.policy.default", "allow")
+	viper.SetDefault("sandbox.approval_timeout", 600)
+	viper.SetDefault("sandbox.concurrency.max_executions", 0)
+	viper.SetDefault("sandbox.concurrency.max_per_sandbox", 0)
+	viper.SetDefault("sandbox.concurrency.queue_size", 100)
+	viper.SetDefault("sandbox.history.max_entries", 1000)
+	viper.SetDefault("sandbox.history.store_output", false)
+	viper.SetDefault("sandbox.recording.enabled", false)
+	viper.SetDefault("sandbox.recording.dir", "/tmp/agent-sandbox-recordings")
+	viper.SetDefault("sandbox.recording.max_per_sandbox", 100)
+	viper.SetDefault("sandbox.recording.max_size", 10485760)
+	viper.SetDefault("sandbox.languages.python.command", []string{"python3"})
+	viper.SetDefault("sandbox.languages.python.extension", ".py")
+	viper.SetDefault("sandbox.languages.python.repl", []string{"python3", "-u", "-i", "-q", "-c", "import sys; sys.ps1 = sys.ps2 = ''"})
+	viper.SetDefault("sandbox.languages.python.repl_run", pythonReplRun)
+	viper.SetDefault("sandbox.languages.javascript.command", []string{"node"})
+	viper.SetDefault("sandbox.languages.javascript.extension", ".js")
+	viper.SetDefault("sandbox.languages.bash.command", []string{"bash"})
+	viper.SetDefault("sandbox.languages.bash.extension", ".sh")
+	viper.SetDefault("sandbox.test_runners.go.command", []string{"go", "test", "-json"})
+	viper.SetDefault("sandbox.test_runners.go.default_args", []string{"./..."})
+	viper.SetDefault("sandbox.test_runners.go.format", "go")
+	viper.SetDefault("sandbox.checkers.go-vet.command", []string{"go", "vet"})
+	viper.SetDefault("sandbox.checkers.go-vet.default_args", []string{"./..."})
+	viper.SetDefault("sandbox.checkers.go-vet.format", "go")
+	viper.SetDefault("sandbox.checkers.go-build.command", []string{"go", "build", "-o", "/dev/null"})
+	viper.SetDefault("sandbox.checkers.go-build.default_args", []string{"./..."})
+	viper.SetDefault("sandbox.checkers.go-build.format", "go")
+	viper.SetDefault("sandbox.isolation.enabled", false)
+	viper.SetDefault("sandbox.isolation.required", false)
+	viper.SetDefault("sandbox.isolation.read_only_paths", []string{})
+	viper.SetDefault("sandbox.isolation.hostname", "sandbox")
 	viper.SetDefault("log.level", "info")
 	viper.SetDefault("log.format", "json")
 }
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/HJH0924/agent-sandbox/internal/policy"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -29,11 +30,88 @@ host = "localhost"
 port = 9090
 read_timeout = "60s"
 write_timeout = "60s"
+admin_api_key = "admin-secret"
+
+[server.preview]
+enabled = false
+secret = "preview-secret"
+token_ttl = 60
 
 [sandbox]
 workspace_dir = "/var/sandbox"
 max_file_size = 52428800
 shell_timeout = 600
+shell = "bash"
+login_shell = true
+max_output_size = 4096
+spill_output = true
+kill_grace_period = 2
+default_network = "none"
+approval_timeout = 30
+
+[sandbox.environment]
+path = "/usr/local/bin:/usr/bin:/bin"
+lang = "en_US.UTF-8"
+passthrough = ["HTTP_PROXY"]
+vars = ["EDITOR=vi", "GOFLAGS=-mod=mod"]
+profile = "/etc/agent-sandbox/profile.sh"
+
+[sandbox.limits]
+cpu_seconds = 10
+file_size = 1048576
+memory = 536870912
+pids = 64
+
+[sandbox.concurrency]
+max_executions = 8
+max_per_sandbox = 2
+queue_size = 16
+
+[sandbox.history]
+max_entries = 50
+store_output = true
+
+[sandbox.recording]
+enabled = true
+dir = "/var/lib/agent-sandbox/recordings"
+max_per_sandbox = 20
+max_size = 1048576
+
+[sandbox.languages.ruby]
+command = ["ruby", "-W0"]
+extension = ".rb"
+
+[sandbox.test_runners.pytest]
+command = ["pytest", "--junitxml=.agent-sandbox/tests/pytest.xml"]
+format = "junit"
+report = ".agent-sandbox/tests/pytest.xml"
+
+[sandbox.checkers.golangci-lint]
+command = ["golangci-lint", "run", "--output.json.path=stdout"]
+format = "golangci-lint"
+
+[sandbox.users]
+enabled = true
+uid_start = 20000
+count = 10
+
+[sandbox.isolation]
+enabled = true
+required = true
+read_only_paths = ["/usr", "/etc"]
+
+[sandbox.policy]
+default = "approve"
+
+[[sandbox.policy.rules]]
+name = "pipe-to-shell"
+action = "deny"
+pattern = 'curl.*\|\s*sh'
+
+[[sandbox.policy.rules]]
+name = "ls"
+action = "allow"
+prefix = ["ls"]
 
 [log]
 level = "debug"
@@ -50,11 +128,61 @@ format = "text"
 	assert.Equal(t, 9090, cfg.Server.Port)
 	assert.Equal(t, 60*time.Second, cfg.Server.ReadTimeout)
 	assert.Equal(t, 60*time.Second, cfg.Server.WriteTimeout)
+	assert.Equal(t, "admin-secret", cfg.Server.AdminAPIKey)
+	assert.Equal(t, PreviewConfig{Secret: "preview-secret", TokenTTL: 60}, cfg.Server.Preview)
 
 	// 验证沙箱配置
 	assert.Equal(t, "/var/sandbox", cfg.Sandbox.WorkspaceDir)
 	assert.Equal(t, int64(52428800), cfg.Sandbox.MaxFileSize)
 	assert.Equal(t, 600, cfg.Sandbox.ShellTimeout)
+	assert.Equal(t, "bash", cfg.Sandbox.Shell)
+	assert.True(t, cfg.Sandbox.LoginShell)
+	assert.Equal(t, EnvironmentConfig{
+		Path:        "/usr/local/bin:/usr/bin:/bin",
+		Lang:        "en_US.UTF-8",
+		TZ:          "UTC",
+		Passthrough: []string{"HTTP_PROXY"},
+		Vars:        []string{"EDITOR=vi", "GOFLAGS=-mod=mod"},
+		Profile:     "/etc/agent-sandbox/profile.sh",
+	}, cfg.Sandbox.Environment)
+	assert.Equal(t, int64(4096), cfg.Sandbox.MaxOutputSize)
+	assert.True(t, cfg.Sandbox.SpillOutput)
+	assert.Equal(t, 2, cfg.Sandbox.KillGracePeriod)
+	assert.Equal(t, "none", cfg.Sandbox.DefaultNetwork)
+	assert.Equal(t, 30, cfg.Sandbox.ApprovalTimeout)
+	assert.Equal(t, ConcurrencyConfig{MaxExecutions: 8, MaxPerSandbox: 2, QueueSize: 16}, cfg.Sandbox.Concurrency)
+	assert.Equal(t, HistoryConfig{MaxEntries: 50, StoreOutput: true}, cfg.Sandbox.History)
+	assert.Equal(t, RecordingConfig{Enabled: true, Dir: "/var/lib/agent-sandbox/recordings", MaxPerSandbox: 20, MaxSize: 1048576}, cfg.Sandbox.Recording)
+	assert.Equal(t, uint64(10), cfg.Sandbox.Limits.CPUSeconds)
+	assert.Equal(t, uint64(1048576), cfg.Sandbox.Limits.FileSize)
+	assert.Equal(t, int64(536870912), cfg.Sandbox.Limits.Memory)
+	assert.Equal(t, int64(64), cfg.Sandbox.Limits.Pids)
+	assert.Equal(t, "/sys/fs/cgroup/agent-sandbox", cfg.Sandbox.Limits.CgroupRoot)
+	assert.True(t, cfg.Sandbox.Users.Enabled)
+	assert.Equal(t, uint32(20000), cfg.Sandbox.Users.UIDStart)
+	assert.Equal(t, uint32(10), cfg.Sandbox.Users.Count)
+	assert.True(t, cfg.Sandbox.Isolation.Enabled)
+	assert.True(t, cfg.Sandbox.Isolation.Required)
+	assert.Equal(t, []string{"/usr", "/etc"}, cfg.Sandbox.Isolation.ReadOnlyPaths)
+	assert.Equal(t, "sandbox", cfg.Sandbox.Isolation.Hostname)
+	assert.Equal(t, "approve", cfg.Sandbox.Policy.Default)
+	assert.Equal(t, []PolicyRuleConfig{
+		{Name: "pipe-to-shell", Action: "deny", Pattern: `curl.*\|\s*sh`},
+		{Name: "ls", Action: "allow", Prefix: []string{"ls"}},
+	}, cfg.Sandbox.Policy.Rules)
+	assert.Equal(t, LanguageConfig{Command: []string{"ruby", "-W0"}, Extension: ".rb"}, cfg.Sandbox.Languages["ruby"])
+	assert.Contains(t, cfg.Sandbox.Languages, "python")
+	assert.Equal(t, TestRunnerConfig{
+		Command: []string{"pytest", "--junitxml=.agent-sandbox/tests/pytest.xml"},
+		Format:  "junit",
+		Report:  ".agent-sandbox/tests/pytest.xml",
+	}, cfg.Sandbox.TestRunners["pytest"])
+	assert.Contains(t, cfg.Sandbox.TestRunners, "go")
+	assert.Equal(t, CheckerConfig{
+		Command: []string{"golangci-lint", "run", "--output.json.path=stdout"},
+		Format:  "golangci-lint",
+	}, cfg.Sandbox.Checkers["golangci-lint"])
+	assert.Contains(t, cfg.Sandbox.Checkers, "go-vet")
 
 	// 验证日志配置
 	assert.Equal(t, "debug", cfg.Log.Level)
@@ -75,6 +203,38 @@ func TestLoad_DefaultValues(t *testing.T) {
 	assert.Equal(t, "/tmp/agent-sandbox", cfg.Sandbox.WorkspaceDir)
 	assert.Equal(t, int64(104857600), cfg.Sandbox.MaxFileSize)
 	assert.Equal(t, 300, cfg.Sandbox.ShellTimeout)
+	assert.Equal(t, "sh", cfg.Sandbox.Shell)
+	assert.False(t, cfg.Sandbox.LoginShell)
+	assert.Equal(t, EnvironmentConfig{Lang: "C.UTF-8", TZ: "UTC", Passthrough: []string{}, Vars: []string{}}, cfg.Sandbox.Environment)
+	assert.Equal(t, int64(1048576), cfg.Sandbox.MaxOutputSize)
+	assert.False(t, cfg.Sandbox.SpillOutput)
+	assert.Equal(t, 5, cfg.Sandbox.KillGracePeriod)
+	assert.Equal(t, uint64(0), cfg.Sandbox.Limits.CPUSeconds)
+	assert.Equal(t, int64(0), cfg.Sandbox.Limits.Memory)
+	assert.False(t, cfg.Sandbox.Users.Enabled)
+	assert.Equal(t, uint32(10000), cfg.Sandbox.Users.UIDStart)
+	assert.False(t, cfg.Sandbox.Isolation.Enabled)
+	assert.Equal(t, "full", cfg.Sandbox.DefaultNetwork)
+	assert.Equal(t, "allow", cfg.Sandbox.Policy.Default)
+	assert.Equal(t, 600, cfg.Sandbox.ApprovalTimeout)
+	assert.Equal(t, ConcurrencyConfig{QueueSize: 100}, cfg.Sandbox.Concurrency)
+	assert.Equal(t, PreviewConfig{Enabled: true, TokenTTL: 900}, cfg.Server.Preview)
+	assert.Equal(t, HistoryConfig{MaxEntries: 1000}, cfg.Sandbox.History)
+	assert.Equal(t, RecordingConfig{Dir: "/tmp/agent-sandbox-recordings", MaxPerSandbox: 100, MaxSize: 10485760}, cfg.Sandbox.Recording)
+	assert.Empty(t, cfg.Server.AdminAPIKey)
+	assert.Empty(t, cfg.Sandbox.Policy.Rules)
+	assert.Equal(t, []string{"python3"}, cfg.Sandbox.Languages["python"].Command)
+	assert.Equal(t, ".py", cfg.Sandbox.Languages["python"].Extension)
+	assert.Equal(t, pythonReplRun, cfg.Sandbox.Languages["python"].ReplRun)
+	assert.Empty(t, cfg.Sandbox.Languages["bash"].Repl)
+	assert.Len(t, cfg.Sandbox.Languages, 3)
+	assert.Equal(t, map[string]TestRunnerConfig{
+		"go": {Command: []string{"go", "test", "-json"}, DefaultArgs: []string{"./..."}, Format: "go"},
+	}, cfg.Sandbox.TestRunners)
+	assert.Equal(t, map[string]CheckerConfig{
+		"go-vet":   {Command: []string{"go", "vet"}, DefaultArgs: []string{"./..."}, Format: "go"},
+		"go-build": {Command: []string{"go", "build", "-o", "/dev/null"}, DefaultArgs: []string{"./..."}, Format: "go"},
+	}, cfg.Sandbox.Checkers)
 	assert.Equal(t, "info", cfg.Log.Level)
 	assert.Equal(t, "json", cfg.Log.Format)
 }
@@ -237,6 +397,19 @@ func TestSetDefaults(t *testing.T) {
 	assert.Equal(t, "/tmp/agent-sandbox", cfg.Sandbox.WorkspaceDir)
 	assert.Equal(t, int64(104857600), cfg.Sandbox.MaxFileSize)
 	assert.Equal(t, 300, cfg.Sandbox.ShellTimeout)
+	assert.Equal(t, int64(1048576), cfg.Sandbox.MaxOutputSize)
+	assert.False(t, cfg.Sandbox.SpillOutput)
+	assert.Equal(t, 5, cfg.Sandbox.KillGracePeriod)
+	assert.Equal(t, uint64(0), cfg.Sandbox.Limits.CPUSeconds)
+	assert.Equal(t, int64(0), cfg.Sandbox.Limits.Memory)
+	assert.False(t, cfg.Sandbox.Users.Enabled)
+	assert.Equal(t, uint32(10000), cfg.Sandbox.Users.UIDStart)
+	assert.False(t, cfg.Sandbox.Isolation.Enabled)
+	assert.Equal(t, "full", cfg.Sandbox.DefaultNetwork)
+	assert.Equal(t, "allow", cfg.Sandbox.Policy.Default)
+	assert.Equal(t, 600, cfg.Sandbox.ApprovalTimeout)
+	assert.Empty(t, cfg.Server.AdminAPIKey)
+	assert.Empty(t, cfg.Sandbox.Policy.Rules)
 	assert.Equal(t, "info", cfg.Log.Level)
 	assert.Equal(t, "json", cfg.Log.Format)
 }
@@ -277,3 +450,56 @@ format = "json"
 	assert.Equal(t, "/tmp/agent-sandbox", cfg.Sandbox.WorkspaceDir)
 	assert.Equal(t, "info", cfg.Log.Level)
 }
+
+func TestLoad_ShippedConfig(t *testing.T) {
+	cfg, err := Load(filepath.Join("..", "..", "configs", "config.toml"))
+	require.NoError(t, err)
+
+	// 示例配置中的 Python 内核与默认值一致
+	assert.Equal(t, pythonReplRun, cfg.Sandbox.Languages["python"].ReplRun)
+	assert.NotEmpty(t, cfg.Sandbox.Languages["python"].Repl)
+}
+
+func TestLoad_ShippedPolicy(t *testing.T) {
+	// 仓库自带配置中的默认规则
+	cfg, err := Load(filepath.Join("..", "..", "configs", "config.toml"))
+	require.NoError(t, err)
+
+	rules := make([]policy.Rule, 0, len(cfg.Sandbox.Policy.Rules))
+	for _, r := range cfg.Sandbox.Policy.Rules {
+		rules = append(rules, policy.Rule{Name: r.Name, Action: policy.Action(r.Action), Pattern: r.Pattern, Prefix: r.Prefix})
+	}
+
+	p, err := policy.New(rules, policy.Action(cfg.Sandbox.Policy.Default))
+	require.NoError(t, err)
+
+	denied := []string{
+		"rm -rf /",
+		"rm -fr /",
+		"rm -Rf /*",
+		"rm -r -f /",
+		"rm -f -r /",
+		"rm --recursive --force /",
+		"rm --force --recursive /",
+		"rm -rf -- /",
+		"rm -r --force -- /",
+		"rm --no-preserve-root -rf /",
+		"rm -rf / --no-preserve-root",
+		"cd /tmp && sudo /bin/rm -r -f /; echo done",
+	}
+	for _, command := range denied {
+		assert.Equal(t, policy.Decision{Action: policy.ActionDeny, Rule: "rm-root"}, p.Match(command), command)
+	}
+
+	allowed := []string{
+		"rm -rf /tmp/build",
+		"rm -r -f build",
+		"rm -rf -- ./",
+		"rm -f /tmp/x",
+		"rm -r build && ls /",
+		"echo 'rm -rf /'",
+	}
+	for _, command := range allowed {
+		assert.Equal(t, policy.Decision{Action: policy.ActionAllow}, p.Match(command), command)
+	}
+}
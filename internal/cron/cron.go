@@ -0,0 +1,230 @@
+// Package cron parses standard five-field cron expressions and computes
+// their next activation time.
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidExpression cron 表达式无效.
+var ErrInvalidExpression = errors.New("invalid cron expression")
+
+// maxSearchYears 查找下一次触发时间时最多向后搜索的年数，用于排除永远不会触发的表达式（如 2 月 30 日）.
+const maxSearchYears = 5
+
+// field 字段的取值范围和名称.
+type field struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = field{name: "minute", min: 0, max: 59}
+	hourField   = field{name: "hour", min: 0, max: 23}
+	domField    = field{name: "day of month", min: 1, max: 31}
+	monthField  = field{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 星期中 0 和 7 都表示星期日
+	dowField = field{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// descriptors 预定义的表达式.
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Schedule 解析后的 cron 表达式，每个字段用位图记录允许的取值.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	// domStar/dowStar 日期和星期字段为 *，两者都不是 * 时满足其一即可（与 Vixie cron 一致）
+	domStar, dowStar bool
+}
+
+// Parse 解析 "分 时 日 月 周" 形式的 cron 表达式，支持 *、范围（1-5）、步长（*/15、1-30/5）、
+// 列表（1,15）、月份和星期的英文缩写（jan、mon）以及 @hourly、@daily 等预定义表达式.
+func Parse(spec string) (*Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if expanded, ok := descriptors[strings.ToLower(spec)]; ok {
+		spec = expanded
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w: expected 5 fields, got %d", ErrInvalidExpression, len(fields))
+	}
+
+	s := &Schedule{
+		domStar: fields[2] == "*" || fields[2] == "?",
+		dowStar: fields[4] == "*" || fields[4] == "?",
+	}
+
+	var err error
+
+	for i, target := range []struct {
+		bits *uint64
+		f    field
+	}{
+		{&s.minute, minuteField},
+		{&s.hour, hourField},
+		{&s.dom, domField},
+		{&s.month, monthField},
+		{&s.dow, dowField},
+	} {
+		if *target.bits, err = parseField(fields[i], target.f); err != nil {
+			return nil, err
+		}
+	}
+
+	// 7 与 0 同为星期日
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+
+	return s, nil
+}
+
+// parseField 解析逗号分隔的字段.
+func parseField(expr string, f field) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(expr, ",") {
+		b, err := parseRange(part, f)
+		if err != nil {
+			return 0, err
+		}
+
+		bits |= b
+	}
+
+	return bits, nil
+}
+
+// parseRange 解析单个 *、数值、范围以及可选的步长.
+func parseRange(expr string, f field) (uint64, error) {
+	rangeExpr, stepExpr, hasStep := strings.Cut(expr, "/")
+
+	step := 1
+
+	if hasStep {
+		n, err := strconv.Atoi(stepExpr)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("%w: invalid step %q in %s field", ErrInvalidExpression, stepExpr, f.name)
+		}
+
+		step = n
+	}
+
+	var start, end int
+
+	switch {
+	case rangeExpr == "*" || rangeExpr == "?":
+		start, end = f.min, f.max
+	case strings.Contains(rangeExpr, "-"):
+		lo, hi, _ := strings.Cut(rangeExpr, "-")
+
+		var err error
+		if start, err = parseValue(lo, f); err != nil {
+			return 0, err
+		}
+
+		if end, err = parseValue(hi, f); err != nil {
+			return 0, err
+		}
+
+		if start > end {
+			return 0, fmt.Errorf("%w: invalid range %q in %s field", ErrInvalidExpression, rangeExpr, f.name)
+		}
+	default:
+		v, err := parseValue(rangeExpr, f)
+		if err != nil {
+			return 0, err
+		}
+
+		// "5/10" 表示从 5 开始每隔 10
+		start, end = v, v
+		if hasStep {
+			end = f.max
+		}
+	}
+
+	var bits uint64
+	for v := start; v <= end; v += step {
+		bits |= 1 << uint(v)
+	}
+
+	return bits, nil
+}
+
+// parseValue 解析数值或名称.
+func parseValue(s string, f field) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("%w: %q is not a valid %s (%d-%d)", ErrInvalidExpression, s, f.name, f.min, f.max)
+	}
+
+	return v, nil
+}
+
+// Next 返回 t 之后（不含 t 所在的分钟）第一次触发的时间，使用 t 的时区.
+// 表达式在 5 年内都不会触发时返回零值.
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxSearchYears, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Truncate(time.Minute).Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+// dayMatches 判断日期是否同时满足日期和星期字段.
+func (s *Schedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+
+	return dom || dow
+}
@@ -0,0 +1,70 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNext(t *testing.T) {
+	// 2025-01-15 是星期三
+	from := time.Date(2025, 1, 15, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{spec: "* * * * *", want: time.Date(2025, 1, 15, 10, 8, 0, 0, time.UTC)},
+		{spec: "*/15 * * * *", want: time.Date(2025, 1, 15, 10, 15, 0, 0, time.UTC)},
+		{spec: "5/20 * * * *", want: time.Date(2025, 1, 15, 10, 25, 0, 0, time.UTC)},
+		{spec: "0 9-17 * * mon-fri", want: time.Date(2025, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{spec: "30 2 * * *", want: time.Date(2025, 1, 16, 2, 30, 0, 0, time.UTC)},
+		{spec: "0 0 1,15 * *", want: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 12 * feb *", want: time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)},
+		{spec: "0 0 * * 7", want: time.Date(2025, 1, 19, 0, 0, 0, 0, time.UTC)},
+		// 日期和星期都指定时满足其一即可
+		{spec: "0 0 20 * fri", want: time.Date(2025, 1, 17, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 29 2 *", want: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{spec: "@hourly", want: time.Date(2025, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{spec: "@daily", want: time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC)},
+		{spec: "@weekly", want: time.Date(2025, 1, 19, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			s, err := Parse(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, s.Next(from))
+		})
+	}
+}
+
+func TestNext_Never(t *testing.T) {
+	s, err := Parse("0 0 30 2 *")
+	require.NoError(t, err)
+	assert.True(t, s.Next(time.Now()).IsZero())
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"@reboot",
+	} {
+		t.Run(spec, func(t *testing.T) {
+			_, err := Parse(spec)
+			assert.ErrorIs(t, err, ErrInvalidExpression)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+// Package diagnostic parses the output of compilers and linters (go build,
+// go vet, gcc-style tools, golangci-lint) into file/line/column findings, so
+// that agents can fix errors without regex-parsing free text.
+package diagnostic
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrUnknownFormat 没有注册该格式的解析器.
+var ErrUnknownFormat = errors.New("unknown diagnostic format")
+
+// Severity 诊断的严重程度.
+type Severity string
+
+const (
+	// SeverityError 错误，如编译失败.
+	SeverityError Severity = "error"
+	// SeverityWarning 警告.
+	SeverityWarning Severity = "warning"
+	// SeverityInfo 补充说明，如 gcc 的 note.
+	SeverityInfo Severity = "info"
+)
+
+// Diagnostic 一条编译器或 linter 的诊断.
+type Diagnostic struct {
+	// File 文件路径，保持工具输出的形式（通常相对工作目录）
+	File string
+	Line int
+	// Column 列号，工具没有报告时为 0
+	Column   int
+	Severity Severity
+	Message  string
+	// Rule 产生诊断的规则或 linter（如 -Wunused-variable、errcheck），没有时为空
+	Rule string
+}
+
+// Parser 将工具的输出解析为诊断.
+type Parser func(data []byte) ([]Diagnostic, error)
+
+var (
+	parsersMu sync.RWMutex
+	parsers   = map[string]Parser{
+		"go":            ParseGo,
+		"gcc":           ParseGCC,
+		"golangci-lint": ParseGolangCILint,
+	}
+)
+
+// Register 注册格式的解析器，已存在的同名解析器被替换.
+func Register(format string, parser Parser) {
+	parsersMu.Lock()
+	defer parsersMu.Unlock()
+
+	parsers[format] = parser
+}
+
+// Lookup 返回格式的解析器.
+func Lookup(format string) (Parser, error) {
+	parsersMu.RLock()
+	defer parsersMu.RUnlock()
+
+	parser, ok := parsers[format]
+	if !ok {
+		return nil, fmt.Errorf("%w %q (supported: %s)", ErrUnknownFormat, format, strings.Join(formatsLocked(), ", "))
+	}
+
+	return parser, nil
+}
+
+// Formats 返回已注册的格式，按名称排序.
+func Formats() []string {
+	parsersMu.RLock()
+	defer parsersMu.RUnlock()
+
+	return formatsLocked()
+}
+
+// formatsLocked 返回已注册的格式. 调用方必须持有 parsersMu.
+func formatsLocked() []string {
+	formats := make([]string, 0, len(parsers))
+	for format := range parsers {
+		formats = append(formats, format)
+	}
+
+	sort.Strings(formats)
+
+	return formats
+}
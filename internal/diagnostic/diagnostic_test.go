@@ -0,0 +1,114 @@
+package diagnostic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// goVetOutput 是 go vet ./... 的真实输出：根包有类型检查错误，sub 包有 vet 诊断.
+const goVetOutput = `# example.com/dg
+# [example.com/dg]
+vet: ./main.go:5:2: "os" imported and not used
+# example.com/dg/sub
+sub/a.go:8:14: fmt.Printf format %d has arg "x" of wrong type string
+`
+
+// goBuildOutput 是 go build ./... 的真实输出，包括 tab 缩进的续行.
+const goBuildOutput = `# example.com/dg/sub
+sub/a.go:4:9: undefined: undefinedThing
+sub/a.go:7:31: cannot use a (variable of type int) as string value in return statement
+./main.go:9:12: not enough arguments in call to f
+	have ()
+	want (int)
+go: some unrelated message
+`
+
+// gccOutput 是 gcc -c a.c 的真实输出.
+const gccOutput = `a.c: In function 'main':
+a.c:1:21: warning: initialization of 'int' from 'char *' makes integer from pointer without a cast [-Wint-conversion]
+    1 | int main(){ int x = "a"; return y; }
+      |                     ^~~
+a.c:1:33: error: 'y' undeclared (first use in this function)
+    1 | int main(){ int x = "a"; return y; }
+      |                                 ^
+a.c:1:33: note: each undeclared identifier is reported only once for each function it appears in
+src/main.rs:2:5: error[E0425]: cannot find value ` + "`x`" + ` in this scope
+`
+
+const golangciOutput = `level=warning msg="[config_reader] The configuration option is deprecated"
+{"Issues":[{"FromLinter":"errcheck","Text":"Error return value of ` + "`f.Close`" + ` is not checked","Severity":"","SourceLines":["\tf.Close()"],"Replacement":null,"Pos":{"Filename":"main.go","Offset":120,"Line":12,"Column":9},"ExpectNoLint":false,"ExpectedNoLintLinter":""},{"FromLinter":"revive","Text":"exported function Foo should have comment","Severity":"warning","SourceLines":[],"Pos":{"Filename":"pkg/foo.go","Offset":0,"Line":3,"Column":1}}],"Report":{"Linters":[{"Name":"errcheck","Enabled":true}]}}
+`
+
+func TestParseGo(t *testing.T) {
+	diagnostics, err := ParseGo([]byte(goVetOutput))
+	require.NoError(t, err)
+	assert.Equal(t, []Diagnostic{
+		{File: "./main.go", Line: 5, Column: 2, Severity: SeverityError, Message: `"os" imported and not used`},
+		{File: "sub/a.go", Line: 8, Column: 14, Severity: SeverityError, Message: `fmt.Printf format %d has arg "x" of wrong type string`},
+	}, diagnostics)
+
+	diagnostics, err = ParseGo([]byte(goBuildOutput))
+	require.NoError(t, err)
+	require.Len(t, diagnostics, 3)
+	assert.Equal(t, "undefined: undefinedThing", diagnostics[0].Message)
+	assert.Equal(t, "./main.go", diagnostics[2].File)
+	assert.Equal(t, "not enough arguments in call to f\nhave ()\nwant (int)", diagnostics[2].Message)
+
+	diagnostics, err = ParseGo([]byte("go: go.mod file not found in current directory or any parent directory\n"))
+	require.NoError(t, err)
+	assert.Empty(t, diagnostics)
+}
+
+func TestParseGCC(t *testing.T) {
+	diagnostics, err := ParseGCC([]byte(gccOutput))
+	require.NoError(t, err)
+	assert.Equal(t, []Diagnostic{
+		{File: "a.c", Line: 1, Column: 21, Severity: SeverityWarning, Message: "initialization of 'int' from 'char *' makes integer from pointer without a cast", Rule: "-Wint-conversion"},
+		{File: "a.c", Line: 1, Column: 33, Severity: SeverityError, Message: "'y' undeclared (first use in this function)"},
+		{File: "a.c", Line: 1, Column: 33, Severity: SeverityInfo, Message: "each undeclared identifier is reported only once for each function it appears in"},
+		{File: "src/main.rs", Line: 2, Column: 5, Severity: SeverityError, Message: "cannot find value `x` in this scope", Rule: "E0425"},
+	}, diagnostics)
+}
+
+func TestParseGolangCILint(t *testing.T) {
+	diagnostics, err := ParseGolangCILint([]byte(golangciOutput))
+	require.NoError(t, err)
+	assert.Equal(t, []Diagnostic{
+		{File: "main.go", Line: 12, Column: 9, Severity: SeverityError, Message: "Error return value of `f.Close` is not checked", Rule: "errcheck"},
+		{File: "pkg/foo.go", Line: 3, Column: 1, Severity: SeverityWarning, Message: "exported function Foo should have comment", Rule: "revive"},
+	}, diagnostics)
+
+	// 没有问题时返回空列表
+	diagnostics, err = ParseGolangCILint([]byte(`{"Issues":null,"Report":{}}`))
+	require.NoError(t, err)
+	assert.Empty(t, diagnostics)
+
+	_, err = ParseGolangCILint([]byte(`{"Issues":null,"Report":{"Error":"can't load config"}}`))
+	require.ErrorContains(t, err, "can't load config")
+
+	_, err = ParseGolangCILint([]byte("level=error msg=\"Running error\"\n"))
+	require.Error(t, err)
+}
+
+func TestLookup(t *testing.T) {
+	parser, err := Lookup("go")
+	require.NoError(t, err)
+	assert.NotNil(t, parser)
+
+	_, err = Lookup("eslint")
+	require.ErrorIs(t, err, ErrUnknownFormat)
+	assert.Contains(t, err.Error(), "gcc, go, golangci-lint")
+
+	Register("eslint", func([]byte) ([]Diagnostic, error) { return nil, nil })
+	t.Cleanup(func() {
+		parsersMu.Lock()
+		delete(parsers, "eslint")
+		parsersMu.Unlock()
+	})
+
+	_, err = Lookup("eslint")
+	require.NoError(t, err)
+	assert.Contains(t, Formats(), "eslint")
+}
@@ -0,0 +1,69 @@
+package diagnostic
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// golangciReport golangci-lint JSON 输出（v1 的 --out-format=json，v2 的 --output.json.path=stdout）.
+type golangciReport struct {
+	Issues []struct {
+		FromLinter string
+		Text       string
+		Severity   string
+		Pos        struct {
+			Filename string
+			Line     int
+			Column   int
+		}
+	}
+	Report struct {
+		// Error 运行失败的原因（如无法加载包）
+		Error string
+	}
+}
+
+// ParseGolangCILint 解析 golangci-lint 的 JSON 输出. JSON 之前的非 JSON 行（如日志）被忽略；
+// 没有设置严重程度的问题记录为错误，Rule 为报告问题的 linter.
+func ParseGolangCILint(data []byte) ([]Diagnostic, error) {
+	start := bytes.Index(data, []byte("{\""))
+	if start < 0 {
+		return nil, errors.New("failed to parse golangci-lint output: no JSON report found")
+	}
+
+	var report golangciReport
+	if err := json.NewDecoder(bytes.NewReader(data[start:])).Decode(&report); err != nil {
+		return nil, fmt.Errorf("failed to parse golangci-lint output: %w", err)
+	}
+
+	if len(report.Issues) == 0 && report.Report.Error != "" {
+		return nil, fmt.Errorf("golangci-lint failed: %s", report.Report.Error)
+	}
+
+	diagnostics := make([]Diagnostic, 0, len(report.Issues))
+
+	for _, issue := range report.Issues {
+		d := Diagnostic{
+			File:     issue.Pos.Filename,
+			Line:     issue.Pos.Line,
+			Column:   issue.Pos.Column,
+			Severity: SeverityError,
+			Message:  issue.Text,
+			Rule:     issue.FromLinter,
+		}
+
+		switch strings.ToLower(issue.Severity) {
+		case "warning", "warn":
+			d.Severity = SeverityWarning
+		case "info", "note", "hint":
+			d.Severity = SeverityInfo
+		}
+
+		diagnostics = append(diagnostics, d)
+	}
+
+	return diagnostics, nil
+}
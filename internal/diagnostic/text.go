@@ -0,0 +1,111 @@
+package diagnostic
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// goPattern 匹配 go build/go vet 的 "file.go:line:col: message"，go vet 的类型检查错误带有 "vet: " 前缀.
+	goPattern = regexp.MustCompile(`^(?:vet: )?(\S.*?\.\w+):(\d+)(?::(\d+))?: (.+)$`)
+	// gccPattern 匹配 gcc/clang/rustc --error-format=short 的 "file:line:col: severity[code]: message".
+	gccPattern = regexp.MustCompile(`^(\S.*?):(\d+):(?:(\d+):)?\s*(fatal error|error|warning|note|remark|help|info)(?:\[([^\]]+)\])?:\s*(.*)$`)
+	// gccFlagPattern 匹配 gcc/clang 在消息末尾标注的警告选项，如 "[-Wunused-variable]".
+	gccFlagPattern = regexp.MustCompile(`\s+\[(-W[^\]]+)\]$`)
+)
+
+// ParseGo 解析 go build 和 go vet 的输出. "# package" 标题和没有位置的行被忽略；
+// 以 tab 缩进的行（如 have/want 类型）追加到上一条诊断的消息中. 所有诊断都是错误.
+func ParseGo(data []byte) ([]Diagnostic, error) {
+	var diagnostics []Diagnostic
+
+	// continued 表示上一行是诊断，tab 缩进的续行属于它
+	continued := false
+
+	err := scanLines(data, func(line string) {
+		if continued && strings.HasPrefix(line, "\t") {
+			last := &diagnostics[len(diagnostics)-1]
+			last.Message += "\n" + strings.TrimSpace(line)
+
+			return
+		}
+
+		m := goPattern.FindStringSubmatch(line)
+
+		continued = m != nil
+		if m == nil {
+			return
+		}
+
+		diagnostics = append(diagnostics, Diagnostic{
+			File:     m[1],
+			Line:     atoi(m[2]),
+			Column:   atoi(m[3]),
+			Severity: SeverityError,
+			Message:  m[4],
+		})
+	})
+
+	return diagnostics, err
+}
+
+// ParseGCC 解析 gcc 风格的 "file:line:col: severity: message" 输出（gcc、clang、
+// rustc --error-format=short 等）. note/help 等补充说明记录为 info，源码摘录行被忽略.
+func ParseGCC(data []byte) ([]Diagnostic, error) {
+	var diagnostics []Diagnostic
+
+	err := scanLines(data, func(line string) {
+		m := gccPattern.FindStringSubmatch(line)
+		if m == nil {
+			return
+		}
+
+		d := Diagnostic{
+			File:    m[1],
+			Line:    atoi(m[2]),
+			Column:  atoi(m[3]),
+			Message: m[6],
+			Rule:    m[5],
+		}
+
+		switch m[4] {
+		case "fatal error", "error":
+			d.Severity = SeverityError
+		case "warning":
+			d.Severity = SeverityWarning
+		default:
+			d.Severity = SeverityInfo
+		}
+
+		if flag := gccFlagPattern.FindStringSubmatch(d.Message); flag != nil && d.Rule == "" {
+			d.Rule = flag[1]
+			d.Message = strings.TrimSuffix(d.Message, flag[0])
+		}
+
+		diagnostics = append(diagnostics, d)
+	})
+
+	return diagnostics, err
+}
+
+// scanLines 逐行调用 fn，去掉行尾的 \r.
+func scanLines(data []byte, fn func(line string)) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		fn(strings.TrimRight(scanner.Text(), "\r"))
+	}
+
+	return scanner.Err()
+}
+
+// atoi 解析行号或列号，为空或无效时返回 0.
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+
+	return n
+}
@@ -0,0 +1,149 @@
+// Package history keeps a bounded, per-sandbox record of executed commands so
+// that what an agent did during a run can be reconstructed afterwards.
+package history
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultPageSize 未指定分页大小时每页返回的记录数.
+	DefaultPageSize = 50
+	// MaxPageSize 每页最多返回的记录数.
+	MaxPageSize = 500
+)
+
+// ErrInvalidPageToken 分页令牌无效.
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// Entry 一次命令执行的记录.
+type Entry struct {
+	ID        string
+	SandboxID string
+	// SessionID 在持久会话中执行时的会话 ID
+	SessionID string
+	Command   string
+	// Cwd 命令开始执行时的工作目录（命令看到的路径）
+	Cwd       string
+	StartedAt time.Time
+	EndedAt   time.Time
+	ExitCode  int
+	// OutputBytes stdout 和 stderr 的总字节数（包括被截断的部分）
+	OutputBytes int64
+	// CallerKeyID 调用方 API Key 的标识
+	CallerKeyID string
+	// Error 命令被拒绝或执行失败时的错误信息
+	Error string
+	// Output 保留的合并输出，仅在开启输出存储时记录
+	Output string
+	// RecordingID 命令输出的录制，未开启录制时为空
+	RecordingID string
+
+	seq uint64
+}
+
+// Page 一页执行记录.
+type Page struct {
+	Entries []Entry
+	// NextPageToken 获取下一页的令牌，为空表示没有更多记录
+	NextPageToken string
+}
+
+// Store 执行历史，每个沙箱最多保留 maxEntries 条记录，超出时丢弃最早的记录.
+type Store struct {
+	mu          sync.Mutex
+	maxEntries  int
+	storeOutput bool
+	seq         uint64
+	entries     map[string][]Entry
+}
+
+// NewStore 创建执行历史，storeOutput 为 true 时同时保存命令输出.
+func NewStore(maxEntries int, storeOutput bool) *Store {
+	return &Store{
+		maxEntries:  maxEntries,
+		storeOutput: storeOutput,
+		entries:     make(map[string][]Entry),
+	}
+}
+
+// Add 记录一次执行，返回记录的 ID.
+func (s *Store) Add(entry Entry) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seq++
+	entry.seq = s.seq
+	entry.ID = uuid.New().String()
+
+	if !s.storeOutput {
+		entry.Output = ""
+	}
+
+	entries := append(s.entries[entry.SandboxID], entry)
+	if len(entries) > s.maxEntries {
+		entries = append([]Entry(nil), entries[len(entries)-s.maxEntries:]...)
+	}
+
+	s.entries[entry.SandboxID] = entries
+
+	return entry.ID
+}
+
+// List 按从新到旧的顺序返回沙箱的一页执行记录，includeOutput 为 false 时不返回输出.
+func (s *Store) List(sandboxID string, pageSize int, pageToken string, includeOutput bool) (*Page, error) {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	pageSize = min(pageSize, MaxPageSize)
+
+	// 令牌为上一页最后一条记录的序号，下一页从更早的记录开始
+	before := uint64(0)
+
+	if pageToken != "" {
+		var err error
+		if before, err = strconv.ParseUint(pageToken, 10, 64); err != nil {
+			return nil, ErrInvalidPageToken
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := s.entries[sandboxID]
+	page := &Page{}
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if before != 0 && entry.seq >= before {
+			continue
+		}
+
+		if len(page.Entries) == pageSize {
+			page.NextPageToken = strconv.FormatUint(page.Entries[len(page.Entries)-1].seq, 10)
+			break
+		}
+
+		if !includeOutput {
+			entry.Output = ""
+		}
+
+		page.Entries = append(page.Entries, entry)
+	}
+
+	return page, nil
+}
+
+// Remove 删除沙箱的全部执行记录.
+func (s *Store) Remove(sandboxID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, sandboxID)
+}
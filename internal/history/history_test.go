@@ -0,0 +1,72 @@
+package history
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_List(t *testing.T) {
+	store := NewStore(100, true)
+
+	for i := range 5 {
+		store.Add(Entry{SandboxID: "sandbox-1", Command: fmt.Sprintf("echo %d", i), Output: "out"})
+	}
+
+	store.Add(Entry{SandboxID: "sandbox-2", Command: "ls"})
+
+	// 从新到旧分页
+	page, err := store.List("sandbox-1", 2, "", false)
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 2)
+	assert.Equal(t, "echo 4", page.Entries[0].Command)
+	assert.Equal(t, "echo 3", page.Entries[1].Command)
+	assert.Empty(t, page.Entries[0].Output)
+	assert.NotEmpty(t, page.Entries[0].ID)
+	require.NotEmpty(t, page.NextPageToken)
+
+	page, err = store.List("sandbox-1", 2, page.NextPageToken, true)
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 2)
+	assert.Equal(t, "echo 2", page.Entries[0].Command)
+	assert.Equal(t, "out", page.Entries[0].Output)
+
+	page, err = store.List("sandbox-1", 2, page.NextPageToken, false)
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 1)
+	assert.Equal(t, "echo 0", page.Entries[0].Command)
+	assert.Empty(t, page.NextPageToken)
+
+	// 其他沙箱的记录互不可见
+	page, err = store.List("sandbox-2", 0, "", false)
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 1)
+	assert.Equal(t, "ls", page.Entries[0].Command)
+
+	_, err = store.List("sandbox-1", 2, "not-a-token", false)
+	assert.ErrorIs(t, err, ErrInvalidPageToken)
+
+	store.Remove("sandbox-1")
+
+	page, err = store.List("sandbox-1", 0, "", false)
+	require.NoError(t, err)
+	assert.Empty(t, page.Entries)
+}
+
+func TestStore_Limits(t *testing.T) {
+	store := NewStore(3, false)
+
+	for i := range 5 {
+		store.Add(Entry{SandboxID: "sandbox-1", Command: fmt.Sprintf("echo %d", i), Output: "out"})
+	}
+
+	// 只保留最新的记录，未开启输出存储时不保存输出
+	page, err := store.List("sandbox-1", 0, "", true)
+	require.NoError(t, err)
+	require.Len(t, page.Entries, 3)
+	assert.Equal(t, "echo 4", page.Entries[0].Command)
+	assert.Equal(t, "echo 2", page.Entries[2].Command)
+	assert.Empty(t, page.Entries[0].Output)
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package launcher
+
+import "os"
+
+// executable 返回重新执行自身使用的路径.
+func executable() (string, error) {
+	return os.Executable()
+}
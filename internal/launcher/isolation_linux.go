@@ -0,0 +1,437 @@
+package launcher
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// namespaceFlags 隔离模式下创建的命名空间.
+const namespaceFlags = syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS | syscall.CLONE_NEWPID |
+	syscall.CLONE_NEWIPC | syscall.CLONE_NEWUTS
+
+// stagingDir 启动器在其中构建新的根文件系统，挂载 tmpfs 后只在新的 mount 命名空间内可见.
+const stagingDir = "/tmp"
+
+// forwardedSignals 启动器转发给目标命令的信号.
+var forwardedSignals = []os.Signal{syscall.SIGTERM, syscall.SIGINT, syscall.SIGHUP, syscall.SIGQUIT}
+
+// devices 绑定挂载到隔离环境中的设备.
+var devices = []string{"null", "zero", "full", "random", "urandom", "tty"}
+
+// isolate 让启动器在新的命名空间中启动，命名空间内的 root 映射为命令原本的运行用户.
+func isolate(cmd *exec.Cmd) error {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+
+	attr := cmd.SysProcAttr
+
+	uid, gid := os.Geteuid(), os.Getegid()
+	if attr.Credential != nil {
+		uid, gid = int(attr.Credential.Uid), int(attr.Credential.Gid)
+	}
+
+	// 启动器需要以命名空间内的 root 身份完成挂载，执行命令前再放弃所有 capability.
+	// 映射不会改变子进程的身份，需要在命名空间内切换到 root（即映射到的宿主机用户）
+	attr.Credential = &syscall.Credential{Uid: 0, Gid: 0, NoSetGroups: true}
+	attr.Cloneflags |= namespaceFlags
+	attr.UidMappings = []syscall.SysProcIDMap{{ContainerID: 0, HostID: uid, Size: 1}}
+	attr.GidMappings = []syscall.SysProcIDMap{{ContainerID: 0, HostID: gid, Size: 1}}
+	attr.GidMappingsEnableSetgroups = false
+
+	return nil
+}
+
+// setupIsolation 在启动器所在的新命名空间中构建根文件系统并切换过去.
+func setupIsolation(iso *Isolation) error {
+	// 挂载变化不传播回宿主机
+	if err := unix.Mount("", "/", "", unix.MS_REC|unix.MS_PRIVATE, ""); err != nil {
+		return fmt.Errorf("failed to make mounts private: %w", err)
+	}
+
+	// 工作空间可能位于 stagingDir 下，挂载 tmpfs 之前先持有它
+	workspace, err := unix.Open(iso.Workspace, unix.O_PATH|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open workspace: %w", err)
+	}
+
+	defer func() {
+		_ = unix.Close(workspace)
+	}()
+
+	if err := unix.Mount("tmpfs", stagingDir, "tmpfs", unix.MS_NOSUID|unix.MS_NODEV, "mode=0755"); err != nil {
+		return fmt.Errorf("failed to mount staging tmpfs: %w", err)
+	}
+
+	root := filepath.Join(stagingDir, "root")
+	if err := os.Mkdir(root, 0o755); err != nil {
+		return fmt.Errorf("failed to create root: %w", err)
+	}
+
+	if err := unix.Mount(root, root, "", unix.MS_BIND, ""); err != nil {
+		return fmt.Errorf("failed to bind root: %w", err)
+	}
+
+	for _, dir := range iso.ReadOnly {
+		if err := mountReadOnly(root, dir); err != nil {
+			return err
+		}
+	}
+
+	workspacePath := "/proc/self/fd/" + strconv.Itoa(workspace)
+	if err := bindMount(workspacePath, filepath.Join(root, WorkspacePath), true); err != nil {
+		return err
+	}
+
+	if err := setupDev(root); err != nil {
+		return err
+	}
+
+	if err := mountFS(root, "/proc", "proc", unix.MS_NOSUID|unix.MS_NODEV|unix.MS_NOEXEC, ""); err != nil {
+		return err
+	}
+
+	if err := mountFS(root, "/tmp", "tmpfs", unix.MS_NOSUID|unix.MS_NODEV, "mode=1777"); err != nil {
+		return err
+	}
+
+	if err := pivotRoot(root); err != nil {
+		return err
+	}
+
+	if iso.Hostname != "" {
+		if err := unix.Sethostname([]byte(iso.Hostname)); err != nil {
+			return fmt.Errorf("failed to set hostname: %w", err)
+		}
+	}
+
+	if err := os.Chdir(WorkspacePath); err != nil {
+		return fmt.Errorf("failed to enter workspace: %w", err)
+	}
+
+	for key, value := range map[string]string{"HOME": WorkspacePath, "PWD": WorkspacePath} {
+		if err := os.Setenv(key, value); err != nil {
+			return err
+		}
+	}
+
+	return dropCapabilities()
+}
+
+// mountReadOnly 将宿主机目录只读地挂载到新根文件系统的相同位置，符号链接原样复制.
+func mountReadOnly(root, dir string) error {
+	info, err := os.Lstat(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to stat %s: %w", dir, err)
+	}
+
+	target := filepath.Join(root, dir)
+
+	// 如 /bin -> usr/bin
+	if info.Mode()&os.ModeSymlink != 0 {
+		link, err := os.Readlink(dir)
+		if err != nil {
+			return fmt.Errorf("failed to read link %s: %w", dir, err)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(target), err)
+		}
+
+		return os.Symlink(link, target)
+	}
+
+	if err := bindMount(dir, target, info.IsDir()); err != nil {
+		return err
+	}
+
+	// 递归绑定会带上子挂载（如容器中的 /etc/resolv.conf），它们也需要设为只读
+	mounts, err := mountsUnder(target)
+	if err != nil {
+		return err
+	}
+
+	for _, mount := range mounts {
+		if err := remountReadOnly(mount); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// bindMount 递归绑定挂载 source 到 target，按需创建挂载点.
+func bindMount(source, target string, dir bool) error {
+	if err := makeMountPoint(target, dir); err != nil {
+		return err
+	}
+
+	if err := unix.Mount(source, target, "", unix.MS_BIND|unix.MS_REC, ""); err != nil {
+		return fmt.Errorf("failed to bind %s: %w", source, err)
+	}
+
+	return nil
+}
+
+// makeMountPoint 创建目录或空文件作为挂载点.
+func makeMountPoint(target string, dir bool) error {
+	if dir {
+		if err := os.MkdirAll(target, 0o755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", target, err)
+		}
+
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(target), err)
+	}
+
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY, 0o644) // #nosec G304 -- target is inside the staging root
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", target, err)
+	}
+
+	return f.Close()
+}
+
+// remountReadOnly 将挂载点重新挂载为只读，保留已锁定的挂载标志.
+func remountReadOnly(target string) error {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(target, &stat); err != nil {
+		return fmt.Errorf("failed to stat %s: %w", target, err)
+	}
+
+	// 在 user 命名空间中重新挂载时，从宿主机继承的 nosuid/nodev/noexec 等标志不能被清除
+	flags := uintptr(unix.MS_BIND | unix.MS_REMOUNT | unix.MS_RDONLY)
+	for st, ms := range map[int64]uintptr{
+		unix.ST_NOSUID:     unix.MS_NOSUID,
+		unix.ST_NODEV:      unix.MS_NODEV,
+		unix.ST_NOEXEC:     unix.MS_NOEXEC,
+		unix.ST_NOATIME:    unix.MS_NOATIME,
+		unix.ST_NODIRATIME: unix.MS_NODIRATIME,
+		unix.ST_RELATIME:   unix.MS_RELATIME,
+	} {
+		if stat.Flags&st != 0 {
+			flags |= ms
+		}
+	}
+
+	if err := unix.Mount("", target, "", flags, ""); err != nil {
+		return fmt.Errorf("failed to remount %s read-only: %w", target, err)
+	}
+
+	return nil
+}
+
+// mountsUnder 返回位于 dir（包括 dir 本身）之下的挂载点.
+func mountsUnder(dir string) ([]string, error) {
+	f, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mountinfo: %w", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	var mounts []string
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// 第 5 列为挂载点
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 {
+			continue
+		}
+
+		mount := unescapeMountPath(fields[4])
+		if mount == dir || strings.HasPrefix(mount, dir+"/") {
+			mounts = append(mounts, mount)
+		}
+	}
+
+	return mounts, scanner.Err()
+}
+
+// unescapeMountPath 还原 mountinfo 中以八进制转义的空白字符.
+func unescapeMountPath(path string) string {
+	return strings.NewReplacer(`\040`, " ", `\011`, "\t", `\012`, "\n", `\134`, `\`).Replace(path)
+}
+
+// setupDev 创建只包含常用设备的 /dev.
+func setupDev(root string) error {
+	if err := mountFS(root, "/dev", "tmpfs", unix.MS_NOSUID|unix.MS_NOEXEC, "mode=0755"); err != nil {
+		return err
+	}
+
+	for _, name := range devices {
+		source := filepath.Join("/dev", name)
+		if _, err := os.Stat(source); err != nil {
+			continue
+		}
+
+		if err := bindMount(source, filepath.Join(root, source), false); err != nil {
+			return err
+		}
+	}
+
+	for name, target := range map[string]string{
+		"fd":     "/proc/self/fd",
+		"stdin":  "/proc/self/fd/0",
+		"stdout": "/proc/self/fd/1",
+		"stderr": "/proc/self/fd/2",
+	} {
+		if err := os.Symlink(target, filepath.Join(root, "dev", name)); err != nil {
+			return fmt.Errorf("failed to create /dev/%s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// mountFS 在新根文件系统中挂载一个文件系统.
+func mountFS(root, target, fstype string, flags uintptr, data string) error {
+	path := filepath.Join(root, target)
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", target, err)
+	}
+
+	if err := unix.Mount(fstype, path, fstype, flags, data); err != nil {
+		return fmt.Errorf("failed to mount %s on %s: %w", fstype, target, err)
+	}
+
+	return nil
+}
+
+// pivotRoot 切换到新的根文件系统并卸载旧的根.
+func pivotRoot(root string) error {
+	if err := os.Chdir(root); err != nil {
+		return fmt.Errorf("failed to enter new root: %w", err)
+	}
+
+	// 以 "." 同时作为新根和旧根的挂载点，避免在新根中创建额外目录
+	if err := unix.PivotRoot(".", "."); err != nil {
+		return fmt.Errorf("failed to pivot root: %w", err)
+	}
+
+	if err := unix.Unmount(".", unix.MNT_DETACH); err != nil {
+		return fmt.Errorf("failed to detach old root: %w", err)
+	}
+
+	return os.Chdir("/")
+}
+
+// dropCapabilities 放弃命名空间内 root 的全部 capability，命令无法再修改挂载.
+func dropCapabilities() error {
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("failed to set no_new_privs: %w", err)
+	}
+
+	// 清空 bounding set 后，execve 不会再为 root 授予 capability
+	for capability := 0; capability <= lastCapability(); capability++ {
+		if err := unix.Prctl(unix.PR_CAPBSET_DROP, uintptr(capability), 0, 0, 0); err != nil && err != unix.EINVAL {
+			return fmt.Errorf("failed to drop capability %d: %w", capability, err)
+		}
+	}
+
+	header := unix.CapUserHeader{Version: unix.LINUX_CAPABILITY_VERSION_3}
+	data := [2]unix.CapUserData{}
+
+	if err := unix.Capset(&header, &data[0]); err != nil {
+		return fmt.Errorf("failed to clear capabilities: %w", err)
+	}
+
+	return nil
+}
+
+// lastCapability 返回内核支持的最大 capability 编号.
+func lastCapability() int {
+	data, err := os.ReadFile("/proc/sys/kernel/cap_last_cap")
+	if err != nil {
+		return unix.CAP_LAST_CAP
+	}
+
+	last, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return unix.CAP_LAST_CAP
+	}
+
+	return last
+}
+
+// superviseTarget 以子进程方式启动目标命令，转发终止信号并回收孤儿进程，目标命令退出后以相同的退出码退出.
+// 内核只向设置了处理函数的 1 号进程投递信号，目标命令如果直接作为 1 号进程运行会忽略 SIGTERM.
+// 目标命令在独立的进程组中运行，发往启动器进程组的信号只经启动器转发一次.
+// 1 号进程无法被自己发送的信号终止，目标命令被信号 N 终止时启动器以 128+N 退出.
+func superviseTarget(spec Spec) error {
+	path, env, err := targetCommand(spec)
+	if err != nil {
+		return err
+	}
+
+	// 在启动目标命令之前注册，期间收到的信号在启动后转发
+	signals := make(chan os.Signal, len(forwardedSignals))
+	signal.Notify(signals, forwardedSignals...)
+
+	attr := &syscall.SysProcAttr{Setpgid: true}
+
+	// 在终端中运行时让目标命令成为前台进程组，终端产生的信号直接发送给它
+	if _, err := unix.IoctlGetInt(0, unix.TIOCGPGRP); err == nil {
+		attr.Foreground = true
+		attr.Ctty = 0
+	}
+
+	pid, err := syscall.ForkExec(path, spec.Args, &syscall.ProcAttr{ // #nosec G204 -- executing the sandbox command is the purpose of the launcher
+		Env:   env,
+		Files: []uintptr{0, 1, 2},
+		Sys:   attr,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to exec %s: %w", path, err)
+	}
+
+	go func() {
+		for sig := range signals {
+			_ = unix.Kill(-pid, sig.(syscall.Signal))
+		}
+	}()
+
+	for {
+		var status unix.WaitStatus
+
+		wpid, err := unix.Wait4(-1, &status, 0, nil)
+		if errors.Is(err, unix.EINTR) {
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to wait for %s: %w", path, err)
+		}
+
+		// 命名空间中的孤儿进程由 1 号进程收养，回收后继续等待目标命令
+		if wpid != pid {
+			continue
+		}
+
+		if status.Signaled() {
+			os.Exit(128 + int(status.Signal()))
+		}
+
+		os.Exit(status.ExitStatus())
+	}
+}
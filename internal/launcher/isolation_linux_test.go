@@ -0,0 +1,94 @@
+package launcher
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// defaultReadOnly 测试使用的只读系统目录.
+var defaultReadOnly = []string{"/bin", "/sbin", "/usr", "/lib", "/lib64", "/etc"}
+
+// runIsolated 在隔离环境中执行 shell 脚本，内核不支持时跳过测试.
+func runIsolated(t *testing.T, workspace, script string) (string, error) {
+	t.Helper()
+
+	iso := Isolation{
+		Workspace: workspace,
+		ReadOnly:  defaultReadOnly,
+		Hostname:  "sandbox",
+	}
+
+	if err := CheckIsolation(iso); err != nil {
+		t.Skipf("namespace isolation not available: %v", err)
+	}
+
+	cmd := exec.Command("sh", "-c", script)
+	cmd.Dir = workspace
+	require.NoError(t, Wrap(cmd, Spec{Isolation: &iso}))
+
+	output, err := cmd.CombinedOutput()
+
+	return string(output), err
+}
+
+func TestIsolation_Environment(t *testing.T) {
+	workspace := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "hello.txt"), []byte("hello"), 0o600))
+
+	output, err := runIsolated(t, workspace, `pwd; hostname; echo $PPID; id -u; echo $HOME; cat hello.txt; echo; ls /`)
+	require.NoError(t, err, output)
+
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	require.GreaterOrEqual(t, len(lines), 7, output)
+	assert.Equal(t, WorkspacePath, lines[0])
+	assert.Equal(t, "sandbox", lines[1])
+	assert.Equal(t, "1", lines[2], "launcher should be PID 1 in its own PID namespace")
+	assert.Equal(t, "0", lines[3])
+	assert.Equal(t, WorkspacePath, lines[4])
+	assert.Equal(t, "hello", lines[5])
+
+	// 根目录只包含工作空间和挂载的系统目录
+	assert.NotContains(t, lines[6:], "root")
+	assert.NotContains(t, lines[6:], "home")
+	assert.Contains(t, lines[6:], "workspace")
+}
+
+func TestIsolation_Filesystem(t *testing.T) {
+	workspace := t.TempDir()
+
+	output, err := runIsolated(t, workspace, `echo data > out.txt && touch /tmp/scratch && ! touch /usr/should-fail 2>/dev/null && ! touch /etc/should-fail 2>/dev/null && echo ok`)
+	require.NoError(t, err, output)
+	assert.Equal(t, "ok\n", output)
+
+	// 工作空间中的修改对宿主机可见
+	data, err := os.ReadFile(filepath.Join(workspace, "out.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "data\n", string(data))
+
+	_, err = os.Stat("/usr/should-fail")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestIsolation_ProcessTable(t *testing.T) {
+	output, err := runIsolated(t, t.TempDir(), `ls /proc | grep -c '^[0-9]'`)
+	require.NoError(t, err, output)
+
+	// 只能看到启动器和自己（sh、ls 和 grep）
+	count, err := strconv.Atoi(strings.TrimSpace(output))
+	require.NoError(t, err)
+	assert.LessOrEqual(t, count, 4)
+}
+
+func TestIsolation_NoCapabilities(t *testing.T) {
+	output, err := runIsolated(t, t.TempDir(), `grep CapEff /proc/self/status; mount -o remount,rw /usr 2>/dev/null && echo remounted; true`)
+	require.NoError(t, err, output)
+	assert.Contains(t, output, "CapEff:\t0000000000000000")
+	assert.NotContains(t, output, "remounted")
+}
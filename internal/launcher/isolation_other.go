@@ -0,0 +1,26 @@
+//go:build !linux
+
+package launcher
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// errIsolationUnsupported 非 Linux 平台不支持命名空间隔离.
+var errIsolationUnsupported = errors.New("namespace isolation is only supported on Linux")
+
+// isolate 在非 Linux 平台上始终返回错误.
+func isolate(_ *exec.Cmd) error {
+	return errIsolationUnsupported
+}
+
+// setupIsolation 在非 Linux 平台上始终返回错误.
+func setupIsolation(_ *Isolation) error {
+	return errIsolationUnsupported
+}
+
+// superviseTarget 在非 Linux 平台上始终返回错误.
+func superviseTarget(_ Spec) error {
+	return errIsolationUnsupported
+}
@@ -0,0 +1,231 @@
+// Package launcher starts sandbox commands through a small re-exec shim that
+// applies process settings (such as resource limits) before exec'ing the target.
+package launcher
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// Arg0 启动器进程的 argv[0]，用于识别重新执行的子进程.
+	Arg0 = "agent-sandbox-init"
+	// specEnv 传递启动参数的环境变量，启动器在执行目标命令前会移除它.
+	specEnv = "AGENT_SANDBOX_INIT_SPEC"
+	// exitCodeLaunchFailed 启动器自身失败时的退出码.
+	exitCodeLaunchFailed = 127
+)
+
+// Rlimit 单项资源限制.
+type Rlimit struct {
+	Resource int    `json:"resource"`
+	Cur      uint64 `json:"cur"`
+	Max      uint64 `json:"max"`
+}
+
+// Spec 启动器执行目标命令前需要应用的设置.
+type Spec struct {
+	Path      string     `json:"path"`
+	Args      []string   `json:"args"`
+	Rlimits   []Rlimit   `json:"rlimits,omitempty"`
+	Isolation *Isolation `json:"isolation,omitempty"`
+	// KillAll 不执行目标命令，向启动器有权发送信号的所有进程发送 SIGKILL
+	KillAll bool `json:"kill_all,omitempty"`
+}
+
+// Isolation 在新的 user、mount、PID、IPC 和 UTS 命名空间中运行命令.
+// 命令只能看到工作空间（挂载为 WorkspacePath）和只读的系统目录.
+type Isolation struct {
+	// Workspace 宿主机上的工作空间目录
+	Workspace string `json:"workspace"`
+	// ReadOnly 以只读方式挂载的宿主机目录，不存在的目录会被忽略
+	ReadOnly []string `json:"read_only"`
+	// Hostname 命名空间内的主机名
+	Hostname string `json:"hostname"`
+}
+
+// WorkspacePath 隔离模式下工作空间在命令中的路径.
+const WorkspacePath = "/workspace"
+
+// Wrap 改写 cmd，使其先启动启动器，再由启动器应用 spec 并执行原命令.
+// cmd 的工作目录、环境变量和标准输入输出保持不变；启用隔离时会在 SysProcAttr 中设置命名空间，
+// 并将 SysProcAttr.Credential 指定的用户映射为命名空间内的 root.
+func Wrap(cmd *exec.Cmd, spec Spec) error {
+	self, err := executable()
+	if err != nil {
+		return fmt.Errorf("failed to resolve executable: %w", err)
+	}
+
+	spec.Path = cmd.Path
+	spec.Args = cmd.Args
+
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return fmt.Errorf("failed to encode launch spec: %w", err)
+	}
+
+	env := cmd.Env
+	if env == nil {
+		env = os.Environ()
+	}
+
+	if spec.Isolation != nil {
+		if err := isolate(cmd); err != nil {
+			return err
+		}
+	}
+
+	cmd.Path = self
+	cmd.Args = []string{Arg0}
+	cmd.Env = append(env, specEnv+"="+string(data))
+
+	return nil
+}
+
+// CheckIsolation 在隔离环境中执行一条空命令，检查内核是否允许创建所需的命名空间.
+func CheckIsolation(iso Isolation) error {
+	cmd := exec.Command("true")
+	cmd.Dir = iso.Workspace
+
+	if err := Wrap(cmd, Spec{Isolation: &iso}); err != nil {
+		return err
+	}
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(output)); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// KillUser 结束用户的所有进程：以该用户身份启动启动器，由它调用 kill(-1, SIGKILL).
+// 内核在一次调用中向所有进程发送信号，进程无法通过不断 fork 逃过.
+func KillUser(uid, gid uint32) error {
+	self, err := executable()
+	if err != nil {
+		return fmt.Errorf("failed to resolve executable: %w", err)
+	}
+
+	data, err := json.Marshal(Spec{KillAll: true})
+	if err != nil {
+		return fmt.Errorf("failed to encode launch spec: %w", err)
+	}
+
+	cmd := exec.Command(self) // #nosec G204 -- re-executing the server binary as the launcher
+	cmd.Args = []string{Arg0}
+	cmd.Env = []string{specEnv + "=" + string(data)}
+	cmd.SysProcAttr = &syscall.SysProcAttr{Credential: &syscall.Credential{Uid: uid, Gid: gid}}
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(output)); msg != "" {
+			return fmt.Errorf("failed to kill processes of uid %d: %w: %s", uid, err, msg)
+		}
+
+		return fmt.Errorf("failed to kill processes of uid %d: %w", uid, err)
+	}
+
+	return nil
+}
+
+// Init 如果当前进程是启动器则应用设置并执行目标命令，永不返回；否则立即返回.
+// 必须在 main（以及测试的 TestMain）开头调用.
+func Init() {
+	if len(os.Args) == 0 || os.Args[0] != Arg0 {
+		return
+	}
+
+	// capability 和 no_new_privs 按线程生效，放弃它们和启动目标命令必须在同一个线程中进行
+	runtime.LockOSThread()
+
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", Arg0, err)
+		os.Exit(exitCodeLaunchFailed)
+	}
+
+	// 只有 KillAll 不执行目标命令
+	os.Exit(0)
+}
+
+// run 解析启动参数、应用资源限制并执行目标命令.
+func run() error {
+	var spec Spec
+	if err := json.Unmarshal([]byte(os.Getenv(specEnv)), &spec); err != nil {
+		return fmt.Errorf("invalid launch spec: %w", err)
+	}
+
+	if spec.KillAll {
+		// kill(-1) 不会向调用者自身发送信号；没有其他进程时返回 ESRCH
+		if err := unix.Kill(-1, unix.SIGKILL); err != nil && !errors.Is(err, unix.ESRCH) {
+			return fmt.Errorf("failed to kill processes: %w", err)
+		}
+
+		return nil
+	}
+
+	for _, limit := range spec.Rlimits {
+		rlimit := unix.Rlimit{Cur: limit.Cur, Max: limit.Max}
+		if err := unix.Setrlimit(limit.Resource, &rlimit); err != nil {
+			return fmt.Errorf("failed to set rlimit %d: %w", limit.Resource, err)
+		}
+	}
+
+	if spec.Isolation != nil {
+		if err := setupIsolation(spec.Isolation); err != nil {
+			return err
+		}
+
+		// 启动器是 PID 命名空间的 1 号进程，留下来代为处理信号
+		return superviseTarget(spec)
+	}
+
+	return execTarget(spec)
+}
+
+// execTarget 用目标命令替换当前进程.
+func execTarget(spec Spec) error {
+	path, env, err := targetCommand(spec)
+	if err != nil {
+		return err
+	}
+
+	if err := syscall.Exec(path, spec.Args, env); err != nil { // #nosec G204 -- executing the sandbox command is the purpose of the launcher
+		return fmt.Errorf("failed to exec %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// targetCommand 返回目标命令的可执行文件路径和去掉启动参数后的环境变量.
+func targetCommand(spec Spec) (string, []string, error) {
+	path := spec.Path
+	if !strings.Contains(path, "/") {
+		resolved, err := exec.LookPath(path)
+		if err != nil {
+			return "", nil, fmt.Errorf("command not found: %w", err)
+		}
+
+		path = resolved
+	}
+
+	env := make([]string, 0, len(os.Environ()))
+
+	for _, kv := range os.Environ() {
+		if !strings.HasPrefix(kv, specEnv+"=") {
+			env = append(env, kv)
+		}
+	}
+
+	return path, env, nil
+}
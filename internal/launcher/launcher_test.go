@@ -0,0 +1,48 @@
+package launcher
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func TestMain(m *testing.M) {
+	Init()
+	os.Exit(m.Run())
+}
+
+func TestWrap(t *testing.T) {
+	cmd := exec.Command("sh", "-c", `ulimit -n; echo "$AGENT_SANDBOX_INIT_SPEC"; echo "$0"`)
+
+	err := Wrap(cmd, Spec{
+		Rlimits: []Rlimit{{Resource: unix.RLIMIT_NOFILE, Cur: 64, Max: 64}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, Arg0, cmd.Args[0])
+
+	output, err := cmd.Output()
+	require.NoError(t, err)
+
+	lines := strings.Split(string(output), "\n")
+	assert.Equal(t, "64", lines[0])
+	assert.Empty(t, lines[1], "spec env should not leak into the target command")
+	assert.Equal(t, "sh", lines[2])
+}
+
+func TestWrap_ExecFailed(t *testing.T) {
+	cmd := exec.Command("/nonexistent/agent-sandbox-command")
+	require.NoError(t, Wrap(cmd, Spec{}))
+
+	output, err := cmd.CombinedOutput()
+	require.Error(t, err)
+
+	var exitErr *exec.ExitError
+	require.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, exitCodeLaunchFailed, exitErr.ExitCode())
+	assert.Contains(t, string(output), "failed to exec")
+}
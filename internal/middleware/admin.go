@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"connectrpc.com/connect"
+)
+
+// AdminAPIKeyHeader 管理员 API Key 请求头.
+const AdminAPIKeyHeader = "X-Admin-Api-Key" // #nosec G101 -- This is a header name, not a credential
+
+var (
+	errAdminDisabled      = connect.NewError(connect.CodePermissionDenied, errors.New("admin API is disabled"))
+	errMissingAdminAPIKey = connect.NewError(connect.CodeUnauthenticated, fmt.Errorf("missing admin API key in header %s", AdminAPIKeyHeader))
+	errInvalidAdminAPIKey = connect.NewError(connect.CodeUnauthenticated, errors.New("invalid admin API key"))
+)
+
+// AdminInterceptor 管理员认证拦截器，未配置管理员 API Key 时拒绝所有请求.
+type AdminInterceptor struct {
+	apiKey string
+	logger *slog.Logger
+}
+
+// NewAdminInterceptor 创建管理员认证拦截器.
+func NewAdminInterceptor(apiKey string, logger *slog.Logger) *AdminInterceptor {
+	return &AdminInterceptor{
+		apiKey: apiKey,
+		logger: logger,
+	}
+}
+
+// WrapUnary 拦截 Unary 调用.
+func (i *AdminInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if err := i.authenticate(ctx, req.Spec().Procedure, req.Header()); err != nil {
+			return nil, err
+		}
+
+		return next(ctx, req)
+	}
+}
+
+// WrapStreamingClient 拦截流式客户端调用（暂不支持）.
+func (i *AdminInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+// WrapStreamingHandler 拦截流式服务端调用.
+func (i *AdminInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if err := i.authenticate(ctx, conn.Spec().Procedure, conn.RequestHeader()); err != nil {
+			return err
+		}
+
+		return next(ctx, conn)
+	}
+}
+
+// authenticate 校验管理员 API Key.
+func (i *AdminInterceptor) authenticate(ctx context.Context, procedure string, header http.Header) error {
+	if i.apiKey == "" {
+		return errAdminDisabled
+	}
+
+	apiKey := header.Get(AdminAPIKeyHeader)
+	if apiKey == "" {
+		i.logger.WarnContext(ctx, "admin authentication failed: missing API key",
+			slog.String("procedure", procedure))
+
+		return errMissingAdminAPIKey
+	}
+
+	if subtle.ConstantTimeCompare([]byte(apiKey), []byte(i.apiKey)) != 1 {
+		i.logger.WarnContext(ctx, "admin authentication failed: invalid API key",
+			slog.String("procedure", procedure),
+			slog.String("api_key_prefix", maskAPIKey(apiKey)))
+
+		return errInvalidAdminAPIKey
+	}
+
+	i.logger.DebugContext(ctx, "admin authentication successful",
+		slog.String("procedure", procedure))
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"os"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminInterceptor_Authenticate(t *testing.T) {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		apiKey   string
+		header   string
+		expected connect.Code
+	}{
+		{name: "Valid key", apiKey: "admin-secret", header: "admin-secret"},
+		{name: "Missing key", apiKey: "admin-secret", expected: connect.CodeUnauthenticated},
+		{name: "Invalid key", apiKey: "admin-secret", header: "sk_sandbox", expected: connect.CodeUnauthenticated},
+		{name: "Disabled", header: "anything", expected: connect.CodePermissionDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interceptor := NewAdminInterceptor(tt.apiKey, logger)
+
+			header := http.Header{}
+			if tt.header != "" {
+				header.Set(AdminAPIKeyHeader, tt.header)
+			}
+
+			err := interceptor.authenticate(ctx, "/admin.v1.AdminService/Approve", header)
+			if tt.expected == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.Equal(t, tt.expected, connect.CodeOf(err))
+		})
+	}
+}
@@ -3,8 +3,11 @@ package middleware
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"strings"
 
 	"github.com/HJH0924/agent-sandbox/domain/core/service"
@@ -20,6 +23,8 @@ const (
 	APIKeyHeader = "X-Sandbox-Api-Key" // #nosec G101 -- This is a header name, not a credential
 	// SandboxIDKey 上下文中的 Sandbox ID key.
 	SandboxIDKey contextKey = "sandbox_id"
+	// APIKeyIDKey 上下文中调用方 API Key 的标识.
+	APIKeyIDKey contextKey = "api_key_id"
 )
 
 var (
@@ -51,18 +56,19 @@ func NewAuthInterceptor(store service.APIKeyStore, logger *slog.Logger) *AuthInt
 func (i *AuthInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
 	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
 		// 跳过特定接口的认证
-		if i.shouldSkipAuth(req) {
+		if shouldSkipAuth(req.Spec().Procedure) {
 			return next(ctx, req)
 		}
 
 		// 执行认证
-		sandboxID, err := i.authenticate(ctx, req)
+		sandboxID, err := i.authenticate(ctx, req.Spec().Procedure, req.Header())
 		if err != nil {
 			return nil, err
 		}
 
-		// 将 Sandbox ID 存入上下文
+		// 将 Sandbox ID 和 API Key 标识存入上下文
 		ctx = context.WithValue(ctx, SandboxIDKey, sandboxID)
+		ctx = context.WithValue(ctx, APIKeyIDKey, APIKeyID(req.Header().Get(APIKeyHeader)))
 
 		i.logger.DebugContext(ctx, "authentication successful",
 			slog.String("procedure", req.Spec().Procedure),
@@ -77,14 +83,33 @@ func (i *AuthInterceptor) WrapStreamingClient(next connect.StreamingClientFunc)
 	return next
 }
 
-// WrapStreamingHandler 拦截流式服务端调用（暂不支持）.
+// WrapStreamingHandler 拦截流式服务端调用.
 func (i *AuthInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
-	return next
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		procedure := conn.Spec().Procedure
+
+		if shouldSkipAuth(procedure) {
+			return next(ctx, conn)
+		}
+
+		sandboxID, err := i.authenticate(ctx, procedure, conn.RequestHeader())
+		if err != nil {
+			return err
+		}
+
+		ctx = context.WithValue(ctx, SandboxIDKey, sandboxID)
+		ctx = context.WithValue(ctx, APIKeyIDKey, APIKeyID(conn.RequestHeader().Get(APIKeyHeader)))
+
+		i.logger.DebugContext(ctx, "authentication successful",
+			slog.String("procedure", procedure),
+			slog.String("sandbox_id", sandboxID))
+
+		return next(ctx, conn)
+	}
 }
 
 // shouldSkipAuth 判断是否需要跳过认证.
-func (i *AuthInterceptor) shouldSkipAuth(req connect.AnyRequest) bool {
-	procedure := req.Spec().Procedure
+func shouldSkipAuth(procedure string) bool {
 	for _, suffix := range skipAuthSuffixes {
 		if strings.HasSuffix(procedure, suffix) {
 			return true
@@ -95,11 +120,9 @@ func (i *AuthInterceptor) shouldSkipAuth(req connect.AnyRequest) bool {
 }
 
 // authenticate 执行认证逻辑.
-func (i *AuthInterceptor) authenticate(ctx context.Context, req connect.AnyRequest) (string, error) {
-	procedure := req.Spec().Procedure
-
+func (i *AuthInterceptor) authenticate(ctx context.Context, procedure string, header http.Header) (string, error) {
 	// 从请求头获取 API Key
-	apiKey := req.Header().Get(APIKeyHeader)
+	apiKey := header.Get(APIKeyHeader)
 	if apiKey == "" {
 		i.logger.WarnContext(ctx, "authentication failed: missing API key",
 			slog.String("procedure", procedure))
@@ -134,3 +157,15 @@ func GetSandboxIDFromContext(ctx context.Context) (string, bool) {
 	sandboxID, ok := ctx.Value(SandboxIDKey).(string)
 	return sandboxID, ok
 }
+
+// APIKeyID 返回 API Key 的标识（SHA-256 摘要的前 12 位），可以写入日志和执行历史而不泄露 Key 本身.
+func APIKeyID(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// GetAPIKeyIDFromContext 从上下文中获取调用方 API Key 的标识.
+func GetAPIKeyIDFromContext(ctx context.Context) (string, bool) {
+	keyID, ok := ctx.Value(APIKeyIDKey).(string)
+	return keyID, ok
+}
@@ -93,6 +93,24 @@ func TestGetSandboxIDFromContext(t *testing.T) {
 	})
 }
 
+func TestAPIKeyID(t *testing.T) {
+	id := APIKeyID("1234567890abcdef")
+
+	assert.Len(t, id, 12)
+	assert.Equal(t, id, APIKeyID("1234567890abcdef"))
+	assert.NotEqual(t, id, APIKeyID("1234567890abcdeg"))
+	assert.NotContains(t, id, "12345678")
+
+	ctx := context.WithValue(context.Background(), APIKeyIDKey, id)
+
+	keyID, ok := GetAPIKeyIDFromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, id, keyID)
+
+	_, ok = GetAPIKeyIDFromContext(context.Background())
+	assert.False(t, ok)
+}
+
 func TestAPIKeyStore_Integration(t *testing.T) {
 	// 测试 APIKeyStore 的集成
 	store := service.NewMemoryAPIKeyStore()
@@ -140,7 +158,14 @@ func TestSkipAuthSuffixes(t *testing.T) {
 	assert.Contains(t, skipAuthSuffixes, "/InitSandbox")
 }
 
+func TestShouldSkipAuth(t *testing.T) {
+	assert.True(t, shouldSkipAuth("/core.v1.CoreService/InitSandbox"))
+	assert.False(t, shouldSkipAuth("/shell.v1.ShellService/Execute"))
+	assert.False(t, shouldSkipAuth("/shell.v1.ShellService/Terminal"))
+}
+
 func TestContextKey(t *testing.T) {
 	// 验证上下文键定义
 	assert.Equal(t, contextKey("sandbox_id"), SandboxIDKey)
+	assert.Equal(t, contextKey("api_key_id"), APIKeyIDKey)
 }
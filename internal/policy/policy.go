@@ -0,0 +1,224 @@
+// Package policy decides whether a shell command may run by matching it
+// against ordered allow, deny and approval rules.
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+)
+
+// Action 规则匹配后的处理方式.
+type Action string
+
+const (
+	// ActionAllow 允许执行.
+	ActionAllow Action = "allow"
+	// ActionDeny 拒绝执行.
+	ActionDeny Action = "deny"
+	// ActionApprove 需要人工审批后才能执行.
+	ActionApprove Action = "approve"
+)
+
+// ParseAction 解析处理方式名称.
+func ParseAction(name string) (Action, error) {
+	switch action := Action(name); action {
+	case ActionAllow, ActionDeny, ActionApprove:
+		return action, nil
+	default:
+		return "", fmt.Errorf("unknown policy action %q", name)
+	}
+}
+
+// ErrInvalidRule 规则配置无效.
+var ErrInvalidRule = errors.New("invalid policy rule")
+
+// Rule 命令规则，Pattern 和 Prefix 至少设置一个，都设置时两者都匹配才算命中.
+type Rule struct {
+	// Name 规则名称，拒绝命令时返回给调用方
+	Name string
+	// Action 命中后的处理方式
+	Action Action
+	// Pattern 与完整命令字符串匹配的正则表达式
+	Pattern string
+	// Prefix 与命令中任意一条简单命令的参数前缀匹配，第一个参数同时与程序的文件名比较
+	Prefix []string
+}
+
+// rule 编译后的规则.
+type rule struct {
+	Rule
+
+	re *regexp.Regexp
+}
+
+// Policy 有序的命令规则集合.
+type Policy struct {
+	rules         []rule
+	defaultAction Action
+	// overlay 通过 Override 追加的策略，只能使判定更严格
+	overlay *Policy
+}
+
+// Decision 命令的判定结果.
+type Decision struct {
+	Action Action
+	// Rule 命中的规则名称，使用默认处理方式时为空
+	Rule string
+}
+
+// New 编译规则并创建策略，defaultAction 为没有规则命中时的处理方式，为空时允许执行.
+func New(rules []Rule, defaultAction Action) (*Policy, error) {
+	if defaultAction != "" {
+		if _, err := ParseAction(string(defaultAction)); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidRule, err)
+		}
+	}
+
+	compiled := make([]rule, 0, len(rules))
+
+	for i, r := range rules {
+		c, err := compile(r)
+		if err != nil {
+			return nil, fmt.Errorf("%w: rule %d (%s): %w", ErrInvalidRule, i, r.Name, err)
+		}
+
+		compiled = append(compiled, c)
+	}
+
+	return &Policy{rules: compiled, defaultAction: defaultAction}, nil
+}
+
+// compile 校验并编译单条规则.
+func compile(r Rule) (rule, error) {
+	if _, err := ParseAction(string(r.Action)); err != nil {
+		return rule{}, err
+	}
+
+	if r.Pattern == "" && len(r.Prefix) == 0 {
+		return rule{}, errors.New("pattern or prefix is required")
+	}
+
+	c := rule{Rule: r}
+
+	if r.Pattern != "" {
+		re, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			return rule{}, fmt.Errorf("invalid pattern: %w", err)
+		}
+
+		c.re = re
+	}
+
+	return c, nil
+}
+
+// Match 返回第一条命中命令的规则的判定结果，没有规则命中时返回默认处理方式（未设置时允许执行）.
+// 通过 Override 追加的策略单独判定，取两者中更严格的结果.
+func (p *Policy) Match(command string) Decision {
+	if p == nil {
+		return Decision{Action: ActionAllow}
+	}
+
+	decision := p.match(command)
+
+	if p.overlay != nil {
+		// 同样严格时优先使用命中了规则的判定，便于调用方了解原因
+		if o := p.overlay.Match(command); strictness(o.Action) > strictness(decision.Action) ||
+			(o.Action == decision.Action && decision.Rule == "") {
+			decision = o
+		}
+	}
+
+	return decision
+}
+
+// match 只按 p 自己的规则和默认处理方式判定.
+func (p *Policy) match(command string) Decision {
+	var commands [][]string
+
+	for _, r := range p.rules {
+		if r.re != nil && !r.re.MatchString(command) {
+			continue
+		}
+
+		if len(r.Prefix) > 0 {
+			if commands == nil {
+				commands = Split(command)
+			}
+
+			if !matchAnyPrefix(commands, r.Prefix) {
+				continue
+			}
+		}
+
+		return Decision{Action: r.Action, Rule: r.Name}
+	}
+
+	if p.defaultAction == "" {
+		return Decision{Action: ActionAllow}
+	}
+
+	return Decision{Action: p.defaultAction}
+}
+
+// strictness 返回处理方式的严格程度：deny > approve > allow.
+func strictness(action Action) int {
+	switch action {
+	case ActionDeny:
+		return 2
+	case ActionApprove:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Override 返回追加了 other 的策略. 命令分别按 p 和 other 判定并取更严格的结果，
+// 因此 other 只能收紧 p：既不能放行 p 拒绝或需要审批的命令，也不能用更宽松的默认处理方式或 allow 规则
+// 放行 p 默认拒绝的命令.
+func (p *Policy) Override(other *Policy) *Policy {
+	if other == nil {
+		return p
+	}
+
+	if p == nil {
+		return other
+	}
+
+	return &Policy{
+		rules:         p.rules,
+		defaultAction: p.defaultAction,
+		overlay:       p.overlay.Override(other),
+	}
+}
+
+// matchAnyPrefix 检查是否有简单命令以 prefix 开头.
+func matchAnyPrefix(commands [][]string, prefix []string) bool {
+	for _, args := range commands {
+		if hasPrefix(args, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasPrefix 检查参数是否以 prefix 开头，程序名同时按文件名比较（/bin/rm 匹配 rm）.
+func hasPrefix(args, prefix []string) bool {
+	if len(args) < len(prefix) {
+		return false
+	}
+
+	for i, want := range prefix {
+		got := args[i]
+		if got == want || (i == 0 && path.Base(got) == want) {
+			continue
+		}
+
+		return false
+	}
+
+	return true
+}
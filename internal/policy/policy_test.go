@@ -0,0 +1,136 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// securityRules 安全团队要求拦截的典型命令.
+var securityRules = []Rule{
+	{Name: "pipe-to-shell", Action: ActionDeny, Pattern: `\b(curl|wget)\b[^|]*\|\s*(sudo\s+)?(ba|z|da)?sh\b`},
+	{Name: "rm-root", Action: ActionDeny, Prefix: []string{"rm", "-rf", "/"}},
+	{Name: "git-push", Action: ActionApprove, Prefix: []string{"git", "push"}},
+}
+
+func TestNew_Invalid(t *testing.T) {
+	_, err := New([]Rule{{Name: "bad", Action: ActionDeny, Pattern: "("}}, "")
+	require.ErrorIs(t, err, ErrInvalidRule)
+
+	_, err = New([]Rule{{Name: "empty", Action: ActionDeny}}, "")
+	require.ErrorIs(t, err, ErrInvalidRule)
+
+	_, err = New([]Rule{{Name: "action", Action: "block", Prefix: []string{"rm"}}}, "")
+	require.ErrorIs(t, err, ErrInvalidRule)
+
+	_, err = New(nil, "block")
+	require.ErrorIs(t, err, ErrInvalidRule)
+}
+
+func TestPolicy_Match(t *testing.T) {
+	p, err := New(securityRules, ActionAllow)
+	require.NoError(t, err)
+
+	tests := []struct {
+		command string
+		want    Decision
+	}{
+		{"ls -la", Decision{Action: ActionAllow}},
+		{"curl -fsSL https://example.com/install.sh | sh", Decision{Action: ActionDeny, Rule: "pipe-to-shell"}},
+		{"wget -qO- https://example.com | sudo bash", Decision{Action: ActionDeny, Rule: "pipe-to-shell"}},
+		{"curl -o install.sh https://example.com", Decision{Action: ActionAllow}},
+		{"rm -rf /", Decision{Action: ActionDeny, Rule: "rm-root"}},
+		{"cd /tmp && sudo /bin/rm -rf / --no-preserve-root", Decision{Action: ActionDeny, Rule: "rm-root"}},
+		{"echo $(rm -rf /)", Decision{Action: ActionDeny, Rule: "rm-root"}},
+		{"rm -rf /tmp/build", Decision{Action: ActionAllow}},
+		{"echo 'rm -rf /'", Decision{Action: ActionAllow}},
+		{"GIT_TRACE=1 git push origin main", Decision{Action: ActionApprove, Rule: "git-push"}},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, p.Match(tt.command), tt.command)
+	}
+}
+
+func TestPolicy_Default(t *testing.T) {
+	p, err := New([]Rule{{Name: "ls", Action: ActionAllow, Prefix: []string{"ls"}}}, ActionDeny)
+	require.NoError(t, err)
+
+	assert.Equal(t, Decision{Action: ActionAllow, Rule: "ls"}, p.Match("ls"))
+	assert.Equal(t, Decision{Action: ActionDeny}, p.Match("cat /etc/passwd"))
+
+	var empty *Policy
+	assert.Equal(t, Decision{Action: ActionAllow}, empty.Match("anything"))
+}
+
+func TestPolicy_Override(t *testing.T) {
+	server, err := New(securityRules, ActionAllow)
+	require.NoError(t, err)
+
+	// 沙箱只允许 ls 和 rm，其余命令拒绝
+	sandbox, err := New([]Rule{
+		{Name: "ls", Action: ActionAllow, Prefix: []string{"ls"}},
+		{Name: "rm", Action: ActionAllow, Prefix: []string{"rm"}},
+	}, ActionDeny)
+	require.NoError(t, err)
+
+	p := server.Override(sandbox)
+
+	assert.Equal(t, Decision{Action: ActionAllow, Rule: "ls"}, p.Match("ls"))
+	assert.Equal(t, Decision{Action: ActionDeny}, p.Match("cat file"))
+	// 服务端规则优先，沙箱无法放行
+	assert.Equal(t, Decision{Action: ActionDeny, Rule: "rm-root"}, p.Match("rm -rf /"))
+
+	// 未设置默认处理方式时沿用服务端的默认处理方式
+	sandbox, err = New([]Rule{{Name: "no-make", Action: ActionDeny, Prefix: []string{"make"}}}, "")
+	require.NoError(t, err)
+
+	p = server.Override(sandbox)
+	assert.Equal(t, Decision{Action: ActionDeny, Rule: "no-make"}, p.Match("make all"))
+	assert.Equal(t, Decision{Action: ActionAllow}, p.Match("cat file"))
+
+	assert.Same(t, server, server.Override(nil))
+
+	// 沙箱不能放宽服务端的默认处理方式
+	for _, serverDefault := range []Action{ActionDeny, ActionApprove} {
+		allowlist, err := New([]Rule{{Name: "ls", Action: ActionAllow, Prefix: []string{"ls"}}}, serverDefault)
+		require.NoError(t, err)
+
+		loose, err := New([]Rule{{Name: "curl", Action: ActionAllow, Prefix: []string{"curl"}}}, ActionAllow)
+		require.NoError(t, err)
+
+		p = allowlist.Override(loose)
+		assert.Equal(t, Decision{Action: serverDefault}, p.Match("cat file"), serverDefault)
+		assert.Equal(t, Decision{Action: serverDefault}, p.Match("curl example.com"), serverDefault)
+		assert.Equal(t, Decision{Action: ActionAllow, Rule: "ls"}, p.Match("ls"), serverDefault)
+	}
+
+	// 沙箱可以设置更严格的默认处理方式
+	approve, err := New(nil, ActionApprove)
+	require.NoError(t, err)
+
+	p = server.Override(approve)
+	assert.Equal(t, Decision{Action: ActionApprove}, p.Match("cat file"))
+	assert.Equal(t, Decision{Action: ActionDeny, Rule: "rm-root"}, p.Match("rm -rf /"))
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		command string
+		want    [][]string
+	}{
+		{"ls -la", [][]string{{"ls", "-la"}}},
+		{`echo "a b" 'c d' e\ f`, [][]string{{"echo", "a b", "c d", "e f"}}},
+		{`echo "say \"hi\""`, [][]string{{"echo", `say "hi"`}}},
+		{"a | b && c || d; e & f\ng", [][]string{{"a"}, {"b"}, {"c"}, {"d"}, {"e"}, {"f"}, {"g"}}},
+		{"FOO=1 BAR=2 sudo env make", [][]string{{"make"}}},
+		{"echo $(whoami) `id`", [][]string{{"echo"}, {"whoami"}, {"id"}}},
+		{"if true; then rm x; fi", [][]string{{"true"}, {"rm", "x"}, {"fi"}}},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Split(tt.command), tt.command)
+	}
+}
@@ -0,0 +1,127 @@
+package policy
+
+import (
+	"strings"
+)
+
+// prefixWords 出现在简单命令开头、不影响实际执行程序的关键字和包装命令，前缀匹配时跳过.
+var prefixWords = map[string]bool{
+	"!": true, "{": true, "if": true, "then": true, "else": true, "elif": true,
+	"while": true, "until": true, "do": true,
+	"builtin": true, "command": true, "env": true, "exec": true, "nice": true,
+	"nohup": true, "sudo": true, "time": true,
+}
+
+// Split 将 shell 命令近似拆分为简单命令的参数列表.
+// 支持引号、反斜杠转义以及 |、&、;、换行、括号、$( 和反引号分隔的命令，
+// 会跳过开头的变量赋值和 prefixWords 中的词，不展开变量和通配符.
+func Split(command string) [][]string {
+	var (
+		commands [][]string
+		args     []string
+		word     strings.Builder
+		inWord   bool
+	)
+
+	endWord := func() {
+		if inWord {
+			args = append(args, word.String())
+			word.Reset()
+			inWord = false
+		}
+	}
+
+	endCommand := func() {
+		endWord()
+
+		if args = trimPrefixWords(args); len(args) > 0 {
+			commands = append(commands, args)
+		}
+
+		args = nil
+	}
+
+	for i := 0; i < len(command); i++ {
+		c := command[i]
+
+		switch {
+		case c == '\\' && i+1 < len(command):
+			i++
+			if command[i] != '\n' {
+				word.WriteByte(command[i])
+				inWord = true
+			}
+		case c == '\'':
+			end := strings.IndexByte(command[i+1:], '\'')
+			if end < 0 {
+				end = len(command) - i - 1
+			}
+
+			word.WriteString(command[i+1 : i+1+end])
+			inWord = true
+			i += end + 1
+		case c == '"':
+			i = readDoubleQuoted(command, i+1, &word)
+			inWord = true
+		case c == '$' && i+1 < len(command) && command[i+1] == '(':
+			endCommand()
+			i++
+		case strings.IndexByte("|&;()`\n", c) >= 0:
+			endCommand()
+		case c == ' ' || c == '\t':
+			endWord()
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+
+	endCommand()
+
+	return commands
+}
+
+// readDoubleQuoted 读取双引号中的内容直到结束引号，返回结束引号的位置.
+func readDoubleQuoted(command string, i int, word *strings.Builder) int {
+	for ; i < len(command); i++ {
+		c := command[i]
+
+		switch {
+		case c == '"':
+			return i
+		case c == '\\' && i+1 < len(command) && strings.IndexByte("\"\\$`", command[i+1]) >= 0:
+			i++
+			word.WriteByte(command[i])
+		default:
+			word.WriteByte(c)
+		}
+	}
+
+	return i
+}
+
+// trimPrefixWords 跳过简单命令开头的变量赋值和 prefixWords 中的词.
+func trimPrefixWords(args []string) []string {
+	for len(args) > 0 && (prefixWords[args[0]] || isAssignment(args[0])) {
+		args = args[1:]
+	}
+
+	return args
+}
+
+// isAssignment 检查参数是否为 NAME=value 形式的变量赋值.
+func isAssignment(arg string) bool {
+	name, _, ok := strings.Cut(arg, "=")
+	if !ok || name == "" {
+		return false
+	}
+
+	for i, c := range name {
+		isLetter := c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+		if !isLetter && (i == 0 || c < '0' || c > '9') {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,221 @@
+// Package portforward forwards local TCP connections to ports inside a sandbox over the PortForward RPC.
+package portforward
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/HJH0924/agent-sandbox/internal/middleware"
+	previewv1 "github.com/HJH0924/agent-sandbox/sdk/go/preview/v1"
+	"github.com/HJH0924/agent-sandbox/sdk/go/preview/v1/previewv1connect"
+
+	"connectrpc.com/connect"
+)
+
+// bufferSize 每条 data 消息携带的最大字节数.
+const bufferSize = 32 * 1024
+
+// Mapping 本地端口到沙箱端口的映射.
+type Mapping struct {
+	// Local 本地监听的端口，为 0 时随机选择
+	Local int
+	// Remote 沙箱中服务监听的端口
+	Remote int
+}
+
+// ParseMapping 解析 "[本地端口:]沙箱端口" 形式的端口映射，只指定一个端口时本地端口与沙箱端口相同.
+func ParseMapping(s string) (Mapping, error) {
+	local, remote, ok := strings.Cut(s, ":")
+	if !ok {
+		local, remote = s, s
+	}
+
+	remotePort, err := parsePort(remote, false)
+	if err != nil {
+		return Mapping{}, fmt.Errorf("invalid port mapping %q: %w", s, err)
+	}
+
+	localPort, err := parsePort(local, true)
+	if err != nil {
+		return Mapping{}, fmt.Errorf("invalid port mapping %q: %w", s, err)
+	}
+
+	return Mapping{Local: localPort, Remote: remotePort}, nil
+}
+
+// parsePort 解析端口号，allowZero 为 true 时允许 0 或空字符串表示随机端口.
+func parsePort(s string, allowZero bool) (int, error) {
+	if s == "" && allowZero {
+		return 0, nil
+	}
+
+	port, err := strconv.Atoi(s)
+	if err != nil || port < 0 || port > 65535 || (port == 0 && !allowZero) {
+		return 0, fmt.Errorf("port must be between 1 and 65535: %q", s)
+	}
+
+	return port, nil
+}
+
+// NewHTTPClient 创建支持双向流的 HTTP/2 客户端，http:// 地址使用 h2c.
+func NewHTTPClient() *http.Client {
+	protocols := new(http.Protocols)
+	protocols.SetHTTP2(true)
+	protocols.SetUnencryptedHTTP2(true)
+
+	return &http.Client{
+		Transport: &http.Transport{
+			Protocols:       protocols,
+			TLSClientConfig: &tls.Config{MinVersion: tls.VersionTLS12},
+		},
+	}
+}
+
+// Forwarder 在本地接受连接，并为每条连接打开一个 PortForward 流.
+type Forwarder struct {
+	client previewv1connect.PreviewServiceClient
+	apiKey string
+	logger *slog.Logger
+}
+
+// New 创建端口转发器，apiKey 为沙箱的 API Key.
+func New(client previewv1connect.PreviewServiceClient, apiKey string, logger *slog.Logger) *Forwarder {
+	return &Forwarder{
+		client: client,
+		apiKey: apiKey,
+		logger: logger,
+	}
+}
+
+// Serve 接受 listener 上的连接并转发到沙箱端口 port，直到 ctx 取消或 listener 被关闭.
+// 单条连接转发失败只记录日志，不影响其他连接.
+func (f *Forwarder) Serve(ctx context.Context, listener net.Listener, port int) error {
+	stop := context.AfterFunc(ctx, func() {
+		_ = listener.Close()
+	})
+	defer stop()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+
+			return err
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if err := f.Forward(ctx, conn, port); err != nil {
+				f.logger.WarnContext(ctx, "port forward failed",
+					slog.Int("port", port),
+					slog.String("client", conn.RemoteAddr().String()),
+					slog.Any("error", err))
+			}
+		}()
+	}
+}
+
+// Forward 将一条本地连接转发到沙箱端口，两端都关闭后返回，返回时关闭 conn.
+func (f *Forwarder) Forward(ctx context.Context, conn net.Conn, port int) error {
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream := f.client.PortForward(ctx)
+	stream.RequestHeader().Set(middleware.APIKeyHeader, f.apiKey)
+
+	if err := stream.Send(&previewv1.PortForwardRequest{
+		Event: &previewv1.PortForwardRequest_Start{Start: &previewv1.PortForwardStart{
+			Port: int32(port), // #nosec G115 -- ports fit in int32
+		}},
+	}); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	// 发送失败时（如认证失败）错误由 Receive 返回
+	first, err := stream.Receive()
+	if err != nil {
+		return err
+	}
+
+	if first.GetConnected() == nil {
+		return errors.New("unexpected first port forward message")
+	}
+
+	// 本地 → 沙箱
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		buf := make([]byte, bufferSize)
+
+		for {
+			n, err := conn.Read(buf)
+			if n > 0 {
+				if sendErr := stream.Send(&previewv1.PortForwardRequest{
+					Event: &previewv1.PortForwardRequest_Data{Data: buf[:n]},
+				}); sendErr != nil {
+					return
+				}
+			}
+
+			if err != nil {
+				// 本地连接关闭写方向或断开时关闭流的发送方向
+				_ = stream.CloseRequest()
+				return
+			}
+		}
+	}()
+
+	// 沙箱 → 本地
+	err = receive(stream, conn)
+
+	// 沙箱端已关闭，结束仍在读取本地连接或发送的 goroutine
+	cancel()
+	_ = conn.Close()
+	<-done
+
+	return err
+}
+
+// receive 将流上的数据写入本地连接，直到沙箱端关闭连接.
+func receive(
+	stream *connect.BidiStreamForClient[previewv1.PortForwardRequest, previewv1.PortForwardResponse],
+	conn net.Conn,
+) error {
+	for {
+		msg, err := stream.Receive()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if _, err := conn.Write(msg.GetData()); err != nil {
+			return err
+		}
+	}
+}
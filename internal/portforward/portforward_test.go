@@ -0,0 +1,202 @@
+package portforward
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	coreService "github.com/HJH0924/agent-sandbox/domain/core/service"
+	"github.com/HJH0924/agent-sandbox/domain/preview"
+	previewService "github.com/HJH0924/agent-sandbox/domain/preview/service"
+	"github.com/HJH0924/agent-sandbox/internal/middleware"
+	"github.com/HJH0924/agent-sandbox/internal/sandbox"
+	"github.com/HJH0924/agent-sandbox/sdk/go/preview/v1/previewv1connect"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMapping(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Mapping
+		wantErr bool
+	}{
+		{input: "5432", want: Mapping{Local: 5432, Remote: 5432}},
+		{input: "15432:5432", want: Mapping{Local: 15432, Remote: 5432}},
+		{input: ":9229", want: Mapping{Local: 0, Remote: 9229}},
+		{input: "0:9229", want: Mapping{Local: 0, Remote: 9229}},
+		{input: "0", wantErr: true},
+		{input: "8080:", wantErr: true},
+		{input: "70000", wantErr: true},
+		{input: "http", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseMapping(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// newTestClient 启动使用 h2c 的预览服务，sandbox-1 的 API Key 为 key-1.
+func newTestClient(t *testing.T) previewv1connect.PreviewServiceClient {
+	t.Helper()
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	store := coreService.NewMemoryAPIKeyStore()
+	require.NoError(t, store.Store("sandbox-1", "key-1"))
+
+	registry := sandbox.NewRegistry()
+	registry.Add(&sandbox.Sandbox{ID: "sandbox-1", CreatedAt: time.Now(), Network: sandbox.NetworkFull})
+
+	handler := preview.NewHandler(previewService.NewService(registry, []byte("secret")), logger)
+
+	mux := http.NewServeMux()
+	mux.Handle(previewv1connect.NewPreviewServiceHandler(handler,
+		connect.WithInterceptors(middleware.NewAuthInterceptor(store, logger)),
+	))
+
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetUnencryptedHTTP2(true)
+
+	server := httptest.NewUnstartedServer(mux)
+	server.Config.Protocols = protocols
+	server.Start()
+	t.Cleanup(server.Close)
+
+	return previewv1connect.NewPreviewServiceClient(NewHTTPClient(), server.URL)
+}
+
+// newEchoServer 启动回显数据的 TCP 服务，收到 EOF 后回复 "bye" 并关闭连接，返回监听端口.
+func newEchoServer(t *testing.T) int {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer func() {
+					_ = conn.Close()
+				}()
+
+				_, _ = io.Copy(conn, conn)
+				_, _ = conn.Write([]byte("bye"))
+			}()
+		}
+	}()
+
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestForwarder(t *testing.T) {
+	client := newTestClient(t)
+	port := newEchoServer(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	served := make(chan error, 1)
+
+	go func() {
+		served <- New(client, "key-1", slog.New(slog.NewJSONHandler(os.Stdout, nil))).Serve(ctx, listener, port)
+	}()
+
+	// 依次转发多条连接
+	for range 2 {
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		require.NoError(t, err)
+
+		require.NoError(t, conn.SetDeadline(time.Now().Add(10*time.Second)))
+
+		_, err = conn.Write([]byte("ping"))
+		require.NoError(t, err)
+
+		buf := make([]byte, 4)
+		_, err = io.ReadFull(conn, buf)
+		require.NoError(t, err)
+		assert.Equal(t, "ping", string(buf))
+
+		// 关闭写方向后仍能收到沙箱端的剩余数据
+		require.NoError(t, conn.(*net.TCPConn).CloseWrite())
+
+		rest, err := io.ReadAll(conn)
+		require.NoError(t, err)
+		assert.Equal(t, "bye", string(rest))
+		require.NoError(t, conn.Close())
+	}
+
+	cancel()
+	require.NoError(t, <-served)
+}
+
+func TestForwarder_Errors(t *testing.T) {
+	client := newTestClient(t)
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	tests := []struct {
+		name   string
+		apiKey string
+		port   int
+		code   connect.Code
+	}{
+		{name: "wrong api key", apiKey: "wrong", port: newEchoServer(t), code: connect.CodeUnauthenticated},
+		{name: "invalid port", apiKey: "key-1", port: 70000, code: connect.CodeInvalidArgument},
+		{name: "nothing listening", apiKey: "key-1", port: closedPort(t), code: connect.CodeUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			local, remote := net.Pipe()
+			defer func() {
+				_ = remote.Close()
+			}()
+
+			err := New(client, tt.apiKey, logger).Forward(context.Background(), local, tt.port)
+			require.Error(t, err)
+			assert.Equal(t, tt.code, connect.CodeOf(err))
+
+			// 转发失败时关闭本地连接
+			_, err = remote.Read(make([]byte, 1))
+			assert.ErrorIs(t, err, io.EOF)
+		})
+	}
+}
+
+func closedPort(t *testing.T) int {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	port := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+
+	return port
+}
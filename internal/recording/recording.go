@@ -0,0 +1,468 @@
+// Package recording stores what commands and terminals printed as asciicast v2
+// files, so that a sandbox session can be replayed with asciinema afterwards.
+package recording
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultWidth 非终端输出（命令、代码单元）录制使用的终端宽度.
+	DefaultWidth = 80
+	// DefaultHeight 非终端输出录制使用的终端高度.
+	DefaultHeight = 24
+	// fileExtension 录制文件的扩展名.
+	fileExtension = ".cast"
+)
+
+// ErrNotFound 录制不存在或不属于当前沙箱.
+var ErrNotFound = errors.New("recording not found")
+
+// Kind 录制的来源.
+type Kind string
+
+const (
+	// KindExecute 一次命令执行.
+	KindExecute Kind = "execute"
+	// KindTerminal 一个交互式终端会话.
+	KindTerminal Kind = "terminal"
+	// KindCell 内核中运行的一个代码单元.
+	KindCell Kind = "cell"
+)
+
+// Info 录制的元数据.
+type Info struct {
+	ID        string
+	SandboxID string
+	Kind      Kind
+	// Command 执行的命令、终端启动的命令或单元的代码
+	Command string
+	Width   int
+	Height  int
+	// StartedAt 开始录制的时间
+	StartedAt time.Time
+	// EndedAt 结束录制的时间，仍在录制时为零值
+	EndedAt time.Time
+	// Size 录制文件的字节数
+	Size int64
+	// Truncated 录制超过大小上限，之后的输出没有记录
+	Truncated bool
+}
+
+// header asciicast v2 文件的首行.
+type header struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Command   string            `json:"command,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Store 按沙箱保存录制文件，每个沙箱最多保留 maxPerSandbox 个录制，超出时删除最早结束的录制.
+type Store struct {
+	dir           string
+	maxPerSandbox int
+	maxSize       int64
+
+	mu         sync.Mutex
+	recordings map[string][]*Recording
+}
+
+// NewStore 创建录制存储，录制文件保存在 dir/<sandbox_id>/ 下.
+// maxSize 为每个录制文件的最大字节数，<= 0 表示不限制.
+func NewStore(dir string, maxPerSandbox int, maxSize int64) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create recording directory: %w", err)
+	}
+
+	return &Store{
+		dir:           dir,
+		maxPerSandbox: maxPerSandbox,
+		maxSize:       maxSize,
+		recordings:    make(map[string][]*Recording),
+	}, nil
+}
+
+// Start 开始一个新的录制. 除终端外的输出来自管道，换行会被转换为 \r\n 以便按终端回放.
+func (s *Store) Start(sandboxID string, kind Kind, command string, width, height int) (*Recording, error) {
+	if width <= 0 || height <= 0 {
+		width, height = DefaultWidth, DefaultHeight
+	}
+
+	dir := filepath.Join(s.dir, sandboxID)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create recording directory: %w", err)
+	}
+
+	id := uuid.New().String()
+	path := filepath.Join(dir, id+fileExtension)
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o600) // #nosec G304 -- path is built from the store directory and a generated ID
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recording: %w", err)
+	}
+
+	now := time.Now()
+	r := &Recording{
+		info: Info{
+			ID:        id,
+			SandboxID: sandboxID,
+			Kind:      kind,
+			Command:   command,
+			Width:     width,
+			Height:    height,
+			StartedAt: now,
+		},
+		path:    path,
+		file:    f,
+		maxSize: s.maxSize,
+		crlf:    kind != KindTerminal,
+	}
+
+	h := header{
+		Version:   2,
+		Width:     width,
+		Height:    height,
+		Timestamp: now.Unix(),
+		Command:   command,
+		Title:     string(kind),
+	}
+	if kind == KindTerminal {
+		h.Env = map[string]string{"TERM": "xterm-256color"}
+	}
+
+	line, err := marshal(h)
+	if err != nil {
+		_ = f.Close()
+		_ = os.Remove(path)
+
+		return nil, fmt.Errorf("failed to encode recording header: %w", err)
+	}
+
+	if err := r.writeLine(line); err != nil {
+		_ = f.Close()
+		_ = os.Remove(path)
+
+		return nil, fmt.Errorf("failed to write recording header: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.recordings[sandboxID] = append(s.recordings[sandboxID], r)
+	s.evictLocked(sandboxID)
+
+	return r, nil
+}
+
+// evictLocked 删除超出数量上限的最早录制，仍在录制的不删除. 调用方必须持有 s.mu.
+func (s *Store) evictLocked(sandboxID string) {
+	recordings := s.recordings[sandboxID]
+	excess := len(recordings) - s.maxPerSandbox
+
+	if s.maxPerSandbox <= 0 || excess <= 0 {
+		return
+	}
+
+	kept := recordings[:0]
+
+	for _, r := range recordings {
+		if excess > 0 && r.ended() {
+			_ = os.Remove(r.path)
+			excess--
+
+			continue
+		}
+
+		kept = append(kept, r)
+	}
+
+	s.recordings[sandboxID] = kept
+}
+
+// List 按从新到旧的顺序返回沙箱的录制.
+func (s *Store) List(sandboxID string) []Info {
+	s.mu.Lock()
+	recordings := slices.Clone(s.recordings[sandboxID])
+	s.mu.Unlock()
+
+	infos := make([]Info, 0, len(recordings))
+
+	for i := len(recordings) - 1; i >= 0; i-- {
+		infos = append(infos, recordings[i].Info())
+	}
+
+	return infos
+}
+
+// Open 打开沙箱的录制文件，调用方负责关闭返回的文件.
+// 仍在录制时返回已写入的部分.
+func (s *Store) Open(sandboxID, id string) (*os.File, Info, error) {
+	s.mu.Lock()
+
+	var found *Recording
+
+	for _, r := range s.recordings[sandboxID] {
+		if r.info.ID == id {
+			found = r
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	if found == nil {
+		return nil, Info{}, ErrNotFound
+	}
+
+	f, err := os.Open(found.path)
+	if err != nil {
+		return nil, Info{}, fmt.Errorf("failed to open recording: %w", err)
+	}
+
+	return f, found.Info(), nil
+}
+
+// Remove 结束并删除沙箱的全部录制.
+func (s *Store) Remove(sandboxID string) {
+	s.mu.Lock()
+	recordings := s.recordings[sandboxID]
+	delete(s.recordings, sandboxID)
+	s.mu.Unlock()
+
+	for _, r := range recordings {
+		r.Close()
+	}
+
+	_ = os.RemoveAll(filepath.Join(s.dir, sandboxID))
+}
+
+// Redactor 替换输出流中的敏感内容，可以保留末尾的字节等待后续输出，以替换跨越多次写入的内容.
+type Redactor interface {
+	// Redact 返回可以记录的替换后的输出
+	Redact(data []byte) []byte
+	// Flush 返回输出结束时保留的替换后的字节
+	Flush() []byte
+}
+
+// Recording 进行中的录制，可以并发写入. 所有方法都可以在 nil 上调用，此时不做任何事.
+type Recording struct {
+	path    string
+	maxSize int64
+	// crlf 将 \n 转换为 \r\n
+	crlf bool
+	// redactor 记录前替换输出中的敏感内容，为 nil 时原样记录
+	redactor Redactor
+
+	mu      sync.Mutex
+	info    Info
+	file    *os.File
+	pending []byte
+}
+
+// ID 返回录制的 ID，r 为 nil 时返回空字符串.
+func (r *Recording) ID() string {
+	if r == nil {
+		return ""
+	}
+
+	return r.info.ID
+}
+
+// Info 返回录制的元数据.
+func (r *Recording) Info() Info {
+	if r == nil {
+		return Info{}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.info
+}
+
+// Redact 设置记录输出前对输出流的替换（如去除密钥），必须在写入输出之前调用.
+func (r *Recording) Redact(redactor Redactor) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.redactor = redactor
+}
+
+// Write 记录一段输出，始终返回成功以免影响命令.
+// 被截断的 UTF-8 字符会等到下一次写入补全后再记录.
+func (r *Recording) Write(p []byte) (int, error) {
+	if r == nil {
+		return len(p), nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.redactor != nil {
+		p = r.redactor.Redact(p)
+	}
+
+	data := append(r.pending, p...)
+	data, r.pending = splitIncomplete(data)
+
+	r.outputLocked(data)
+
+	return len(p), nil
+}
+
+// Resize 记录终端窗口大小的变化.
+func (r *Recording) Resize(width, height int) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.eventLocked("r", fmt.Sprintf("%dx%d", width, height))
+}
+
+// Close 结束录制，可以重复调用.
+func (r *Recording) Close() {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return
+	}
+
+	data := r.pending
+	if r.redactor != nil {
+		data = append(data, r.redactor.Flush()...)
+	}
+
+	r.outputLocked(data)
+	r.pending = nil
+
+	_ = r.file.Close()
+	r.file = nil
+	r.info.EndedAt = time.Now()
+}
+
+// ended 返回录制是否已经结束.
+func (r *Recording) ended() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.file == nil
+}
+
+// outputLocked 记录输出事件. 调用方必须持有 r.mu.
+func (r *Recording) outputLocked(data []byte) {
+	if len(data) == 0 {
+		return
+	}
+
+	if r.crlf {
+		data = bytes.ReplaceAll(data, []byte("\n"), []byte("\r\n"))
+	}
+
+	r.eventLocked("o", string(data))
+}
+
+// eventLocked 追加一个事件，超过大小上限时记录截断说明并停止录制. 调用方必须持有 r.mu.
+func (r *Recording) eventLocked(code, data string) {
+	if r.file == nil || r.info.Truncated {
+		return
+	}
+
+	line := r.encodeLocked(code, data)
+
+	if r.maxSize > 0 && r.info.Size+int64(len(line))+1 > r.maxSize {
+		r.info.Truncated = true
+		line = r.encodeLocked("o", "\r\n[recording truncated]\r\n")
+	}
+
+	if err := r.writeLine(line); err != nil {
+		// 磁盘写满等错误后不再继续录制
+		r.info.Truncated = true
+	}
+}
+
+// encodeLocked 将事件编码为 [time, code, data]，时间为距开始录制的秒数（单调时钟，不会倒退）.
+// 调用方必须持有 r.mu.
+func (r *Recording) encodeLocked(code, data string) []byte {
+	elapsed := strconv.FormatFloat(time.Since(r.info.StartedAt).Seconds(), 'f', 6, 64)
+
+	// 编码字符串不会失败，无效的 UTF-8 字节被替换为 U+FFFD
+	codeJSON, _ := marshal(code)
+	dataJSON, _ := marshal(data)
+
+	line := make([]byte, 0, len(elapsed)+len(codeJSON)+len(dataJSON)+6)
+	line = append(line, '[')
+	line = append(line, elapsed...)
+	line = append(line, ", "...)
+	line = append(line, codeJSON...)
+	line = append(line, ", "...)
+	line = append(line, dataJSON...)
+	line = append(line, ']')
+
+	return line
+}
+
+// writeLine 写入一行.
+func (r *Recording) writeLine(line []byte) error {
+	n, err := r.file.Write(append(line, '\n'))
+	r.info.Size += int64(n)
+
+	return err
+}
+
+// marshal 编码为 JSON，不转义 <、>、& 以便录制文件保持可读.
+func marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// splitIncomplete 将末尾不完整的 UTF-8 字符与前面的数据分开.
+func splitIncomplete(data []byte) ([]byte, []byte) {
+	for i := 1; i < utf8.UTFMax && i <= len(data); i++ {
+		start := len(data) - i
+		if !utf8.RuneStart(data[start]) {
+			continue
+		}
+
+		if utf8.FullRune(data[start:]) {
+			return data, nil
+		}
+
+		return data[:start], append([]byte(nil), data[start:]...)
+	}
+
+	return data, nil
+}
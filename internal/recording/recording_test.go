@@ -0,0 +1,196 @@
+package recording
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readCast 读取录制文件，返回头部和事件.
+func readCast(t *testing.T, s *Store, sandboxID, id string) (map[string]any, [][]any) {
+	t.Helper()
+
+	f, _, err := s.Open(sandboxID, id)
+	require.NoError(t, err)
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	scanner := bufio.NewScanner(f)
+	require.True(t, scanner.Scan())
+
+	var h map[string]any
+	require.NoError(t, json.Unmarshal(scanner.Bytes(), &h))
+
+	var events [][]any
+
+	for scanner.Scan() {
+		var event []any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		require.Len(t, event, 3)
+
+		events = append(events, event)
+	}
+
+	require.NoError(t, scanner.Err())
+
+	return h, events
+}
+
+// eventData 返回事件的类型和数据.
+func eventData(events [][]any) [][2]string {
+	data := make([][2]string, 0, len(events))
+	for _, e := range events {
+		data = append(data, [2]string{e[1].(string), e[2].(string)})
+	}
+
+	return data
+}
+
+func TestRecording_Execute(t *testing.T) {
+	s, err := NewStore(t.TempDir(), 10, 0)
+	require.NoError(t, err)
+
+	r, err := s.Start("sandbox-1", KindExecute, "echo héllo", 0, 0)
+	require.NoError(t, err)
+
+	_, _ = r.Write([]byte("line one\nh\xc3"))
+	_, _ = r.Write([]byte("\xa9llo\n"))
+	r.Close()
+	r.Close()
+
+	_, _ = r.Write([]byte("after close"))
+
+	h, events := readCast(t, s, "sandbox-1", r.ID())
+	assert.InDelta(t, 2, h["version"], 0)
+	assert.InDelta(t, DefaultWidth, h["width"], 0)
+	assert.InDelta(t, DefaultHeight, h["height"], 0)
+	assert.Equal(t, "echo héllo", h["command"])
+	assert.Nil(t, h["env"])
+
+	// 管道输出的换行转换为 \r\n，被拆开的 UTF-8 字符补全后再记录
+	assert.Equal(t, [][2]string{{"o", "line one\r\nh"}, {"o", "éllo\r\n"}}, eventData(events))
+	assert.LessOrEqual(t, events[0][0].(float64), events[1][0].(float64))
+
+	info := r.Info()
+	assert.False(t, info.EndedAt.IsZero())
+	assert.Positive(t, info.Size)
+}
+
+func TestRecording_Terminal(t *testing.T) {
+	s, err := NewStore(t.TempDir(), 10, 0)
+	require.NoError(t, err)
+
+	r, err := s.Start("sandbox-1", KindTerminal, "bash", 120, 40)
+	require.NoError(t, err)
+
+	_, _ = r.Write([]byte("$ ls\r\n"))
+	r.Resize(100, 30)
+	r.Close()
+
+	h, events := readCast(t, s, "sandbox-1", r.ID())
+	assert.InDelta(t, 120, h["width"], 0)
+	assert.Equal(t, map[string]any{"TERM": "xterm-256color"}, h["env"])
+	assert.Equal(t, [][2]string{{"o", "$ ls\r\n"}, {"r", "100x30"}}, eventData(events))
+}
+
+func TestRecording_MaxSize(t *testing.T) {
+	s, err := NewStore(t.TempDir(), 10, 512)
+	require.NoError(t, err)
+
+	r, err := s.Start("sandbox-1", KindExecute, "yes", 0, 0)
+	require.NoError(t, err)
+
+	for range 100 {
+		_, _ = r.Write([]byte("y\n"))
+	}
+
+	r.Close()
+
+	info := r.Info()
+	assert.True(t, info.Truncated)
+
+	_, events := readCast(t, s, "sandbox-1", r.ID())
+	last := eventData(events)[len(events)-1]
+	assert.Equal(t, [2]string{"o", "\r\n[recording truncated]\r\n"}, last)
+	assert.Less(t, len(events), 100)
+}
+
+func TestRecording_Nil(t *testing.T) {
+	var r *Recording
+
+	n, err := r.Write([]byte("ignored"))
+	require.NoError(t, err)
+	assert.Equal(t, 7, n)
+
+	r.Resize(80, 24)
+	r.Close()
+	assert.Empty(t, r.ID())
+	assert.Empty(t, r.Info().ID)
+}
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+
+	s, err := NewStore(dir, 2, 0)
+	require.NoError(t, err)
+
+	// 仍在录制的不会被删除
+	active, err := s.Start("sandbox-1", KindTerminal, "sh", 80, 24)
+	require.NoError(t, err)
+
+	var ids []string
+
+	for _, command := range []string{"one", "two", "three"} {
+		r, err := s.Start("sandbox-1", KindExecute, command, 0, 0)
+		require.NoError(t, err)
+		r.Close()
+
+		ids = append(ids, r.ID())
+	}
+
+	infos := s.List("sandbox-1")
+	require.Len(t, infos, 2)
+	assert.Equal(t, "three", infos[0].Command)
+	assert.Equal(t, active.ID(), infos[1].ID)
+	assert.True(t, infos[1].EndedAt.IsZero())
+
+	for _, id := range ids[:2] {
+		_, _, err = s.Open("sandbox-1", id)
+		require.ErrorIs(t, err, ErrNotFound)
+
+		_, err = os.Stat(filepath.Join(dir, "sandbox-1", id+fileExtension))
+		assert.True(t, os.IsNotExist(err))
+	}
+
+	// 其他沙箱无法访问
+	_, _, err = s.Open("sandbox-2", ids[2])
+	require.ErrorIs(t, err, ErrNotFound)
+	assert.Empty(t, s.List("sandbox-2"))
+
+	// 仍在录制时可以下载已写入的部分
+	_, _ = active.Write([]byte("partial"))
+
+	f, _, err := s.Open("sandbox-1", active.ID())
+	require.NoError(t, err)
+
+	content, err := io.ReadAll(f)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	assert.Contains(t, string(content), "partial")
+
+	s.Remove("sandbox-1")
+
+	assert.Empty(t, s.List("sandbox-1"))
+	assert.False(t, active.Info().EndedAt.IsZero())
+
+	_, err = os.Stat(filepath.Join(dir, "sandbox-1"))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -4,14 +4,23 @@ package router
 import (
 	"log/slog"
 	"net/http"
+	"time"
 
+	"github.com/HJH0924/agent-sandbox/domain/admin"
+	"github.com/HJH0924/agent-sandbox/domain/code"
 	"github.com/HJH0924/agent-sandbox/domain/core"
 	"github.com/HJH0924/agent-sandbox/domain/core/service"
 	"github.com/HJH0924/agent-sandbox/domain/file"
+	"github.com/HJH0924/agent-sandbox/domain/preview"
+	"github.com/HJH0924/agent-sandbox/domain/schedule"
 	"github.com/HJH0924/agent-sandbox/domain/shell"
 	"github.com/HJH0924/agent-sandbox/internal/middleware"
+	adminv1connect "github.com/HJH0924/agent-sandbox/sdk/go/admin/v1/adminv1connect"
+	codev1connect "github.com/HJH0924/agent-sandbox/sdk/go/code/v1/codev1connect"
 	corev1connect "github.com/HJH0924/agent-sandbox/sdk/go/core/v1/corev1connect"
 	filev1connect "github.com/HJH0924/agent-sandbox/sdk/go/file/v1/filev1connect"
+	previewv1connect "github.com/HJH0924/agent-sandbox/sdk/go/preview/v1/previewv1connect"
+	schedulev1connect "github.com/HJH0924/agent-sandbox/sdk/go/schedule/v1/schedulev1connect"
 	shellv1connect "github.com/HJH0924/agent-sandbox/sdk/go/shell/v1/shellv1connect"
 
 	"connectrpc.com/connect"
@@ -22,8 +31,20 @@ type Config struct {
 	CoreHandler  *core.Handler
 	FileHandler  *file.Handler
 	ShellHandler *shell.Handler
-	APIKeyStore  service.APIKeyStore
-	Logger       *slog.Logger
+	// CodeHandler 代码运行接口处理器，为空时不注册代码运行接口
+	CodeHandler *code.Handler
+	// PreviewHandler 预览链接接口处理器，为空时不注册预览链接接口
+	PreviewHandler *preview.Handler
+	// PreviewProxy 沙箱端口的 HTTP 预览代理，为空时不注册 /sandboxes/{id}/ports/{port}/ 路由
+	PreviewProxy *preview.Proxy
+	// ScheduleHandler 定时任务接口处理器，为空时不注册定时任务接口
+	ScheduleHandler *schedule.Handler
+	// AdminHandler 管理接口处理器，为空时不注册管理接口
+	AdminHandler *admin.Handler
+	// AdminAPIKey 管理员 API Key，为空时拒绝所有管理接口请求
+	AdminAPIKey string
+	APIKeyStore service.APIKeyStore
+	Logger      *slog.Logger
 }
 
 // Setup 设置路由.
@@ -39,21 +60,27 @@ func Setup(cfg *Config) http.Handler {
 	// 注册受保护路由（需要认证）
 	registerProtectedRoutes(mux, cfg, authInterceptor)
 
+	// 注册管理路由（需要管理员认证）
+	registerAdminRoutes(mux, cfg)
+
 	return mux
 }
 
 // registerPublicRoutes 注册不需要认证的路由.
 func registerPublicRoutes(mux *http.ServeMux, cfg *Config) {
-	// CoreService - 不需要认证
-	corePath, coreHandler := corev1connect.NewCoreServiceHandler(cfg.CoreHandler)
-	mux.Handle(corePath, coreHandler)
-
 	// 健康检查
 	mux.HandleFunc("/health", healthCheckHandler(cfg.Logger))
 }
 
 // registerProtectedRoutes 注册需要认证的路由.
 func registerProtectedRoutes(mux *http.ServeMux, cfg *Config, authInterceptor connect.Interceptor) {
+	// CoreService - 除 InitSandbox 外需要认证
+	corePath, coreHandler := corev1connect.NewCoreServiceHandler(
+		cfg.CoreHandler,
+		connect.WithInterceptors(authInterceptor),
+	)
+	mux.Handle(corePath, coreHandler)
+
 	// FileService - 需要认证
 	filePath, fileHandler := filev1connect.NewFileServiceHandler(
 		cfg.FileHandler,
@@ -66,7 +93,82 @@ func registerProtectedRoutes(mux *http.ServeMux, cfg *Config, authInterceptor co
 		cfg.ShellHandler,
 		connect.WithInterceptors(authInterceptor),
 	)
-	mux.Handle(shellPath, shellHandler)
+	// 命令执行、审批等待和条件等待可能超过服务器的写超时
+	mux.Handle(shellPath, withoutDeadlines(shellHandler,
+		shellv1connect.ShellServiceExecuteProcedure,
+		shellv1connect.ShellServiceTerminalProcedure,
+		shellv1connect.ShellServiceWaitForProcedure,
+	))
+
+	// CodeService - 需要认证
+	if cfg.CodeHandler != nil {
+		codePath, codeHandler := codev1connect.NewCodeServiceHandler(
+			cfg.CodeHandler,
+			connect.WithInterceptors(authInterceptor),
+		)
+		mux.Handle(codePath, withoutDeadlines(codeHandler,
+			codev1connect.CodeServiceRunCodeProcedure,
+			codev1connect.CodeServiceExecuteCellProcedure,
+			codev1connect.CodeServiceRunTestsProcedure,
+			codev1connect.CodeServiceDiagnosticsProcedure,
+		))
+	}
+
+	// PreviewService - 需要认证
+	if cfg.PreviewHandler != nil {
+		previewPath, previewHandler := previewv1connect.NewPreviewServiceHandler(
+			cfg.PreviewHandler,
+			connect.WithInterceptors(authInterceptor),
+		)
+		// 端口转发的连接可能长时间保持
+		mux.Handle(previewPath, withoutDeadlines(previewHandler,
+			previewv1connect.PreviewServicePortForwardProcedure,
+		))
+	}
+
+	// ScheduleService - 需要认证
+	if cfg.ScheduleHandler != nil {
+		schedulePath, scheduleHandler := schedulev1connect.NewScheduleServiceHandler(
+			cfg.ScheduleHandler,
+			connect.WithInterceptors(authInterceptor),
+		)
+		mux.Handle(schedulePath, scheduleHandler)
+	}
+
+	// 预览代理 - 自行通过 API Key 或预览令牌认证
+	if cfg.PreviewProxy != nil {
+		mux.Handle(preview.Pattern, cfg.PreviewProxy)
+	}
+}
+
+// registerAdminRoutes 注册需要管理员认证的路由.
+func registerAdminRoutes(mux *http.ServeMux, cfg *Config) {
+	if cfg.AdminHandler == nil {
+		return
+	}
+
+	adminPath, adminHandler := adminv1connect.NewAdminServiceHandler(
+		cfg.AdminHandler,
+		connect.WithInterceptors(middleware.NewAdminInterceptor(cfg.AdminAPIKey, cfg.Logger)),
+	)
+	mux.Handle(adminPath, adminHandler)
+}
+
+// withoutDeadlines 为长连接的流式接口取消服务器的读写超时.
+func withoutDeadlines(next http.Handler, procedures ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, procedure := range procedures {
+			if r.URL.Path == procedure {
+				rc := http.NewResponseController(w)
+				_ = rc.SetReadDeadline(time.Time{})
+				_ = rc.SetWriteDeadline(time.Time{})
+
+				break
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
 }
 
 // healthCheckHandler 健康检查处理器.
@@ -154,3 +154,20 @@ func TestRegisterProtectedRoutes(t *testing.T) {
 	// 验证不会 panic
 	assert.NotNil(t, mux)
 }
+
+func TestWithoutDeadlines(t *testing.T) {
+	var called []string
+
+	next := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		called = append(called, r.URL.Path)
+	})
+
+	handler := withoutDeadlines(next, "/shell.v1.ShellService/Terminal")
+
+	for _, path := range []string{"/shell.v1.ShellService/Terminal", "/shell.v1.ShellService/Execute"} {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	assert.Equal(t, []string{"/shell.v1.ShellService/Terminal", "/shell.v1.ShellService/Execute"}, called)
+}
@@ -0,0 +1,90 @@
+package sandbox
+
+import (
+	"context"
+	"net"
+	"strconv"
+)
+
+// loopbackAddresses 连接沙箱端口时依次尝试的回环地址.
+var loopbackAddresses = []string{"127.0.0.1", "::1"}
+
+// DialPort 连接沙箱中监听在回环地址上的 TCP 端口.
+// 沙箱有独立的网络命名空间时在其中建立连接；策略为 full 时连接宿主机的回环地址，此时各沙箱共享端口.
+// 沙箱未注册（如已销毁）时返回 ErrSandboxNotFound；注册表为 nil 时连接宿主机.
+func (r *Registry) DialPort(ctx context.Context, sandboxID string, port int) (net.Conn, error) {
+	addrs := make([]string, 0, len(loopbackAddresses))
+	for _, host := range loopbackAddresses {
+		addrs = append(addrs, net.JoinHostPort(host, strconv.Itoa(port)))
+	}
+
+	return r.dial(ctx, sandboxID, addrs)
+}
+
+// Dial 从沙箱的网络中连接 TCP 地址（host:port），主机名在宿主机上解析后依次尝试各个 IP.
+func (r *Registry) Dial(ctx context.Context, sandboxID, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	ips, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		addrs = append(addrs, net.JoinHostPort(ip.String(), port))
+	}
+
+	return r.dial(ctx, sandboxID, addrs)
+}
+
+// dial 依次连接 IP 地址，返回第一个成功的连接.
+func (r *Registry) dial(ctx context.Context, sandboxID string, addrs []string) (net.Conn, error) {
+	var netns *NetNS
+
+	// 未注册的沙箱不能回退到宿主机的网络，否则过期或伪造的沙箱 ID 可以访问宿主机上的服务
+	if r != nil {
+		info, err := r.Get(sandboxID)
+		if err != nil {
+			return nil, err
+		}
+
+		netns = info.NetNS
+	}
+
+	var (
+		dialer   net.Dialer
+		conn     net.Conn
+		firstErr error
+	)
+
+	dial := func() error {
+		// 地址都是 IP，逐个在当前线程上连接，不会在其他线程（其他网络命名空间）中建立连接
+		for _, addr := range addrs {
+			var err error
+			if conn, err = dialer.DialContext(ctx, "tcp", addr); err == nil {
+				return nil
+			}
+
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+
+		return firstErr
+	}
+
+	// 先完成连接再读取 conn：return conn, dial() 中 conn 的求值可能早于 dial 的赋值
+	if netns == nil {
+		err := dial()
+		return conn, err
+	}
+
+	// 套接字在创建它的线程所在的网络命名空间中，建立后可以在任意线程使用
+	err := netns.Do(dial)
+
+	return conn, err
+}
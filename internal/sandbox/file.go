@@ -0,0 +1,82 @@
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// ErrUnsafePath 路径中的目录不是真实目录（如沙箱放置的符号链接）.
+var ErrUnsafePath = errors.New("unsafe path in sandbox workspace")
+
+// CreateFile 在 root 下创建新文件 rel，沿途缺少的目录以 0o750 创建.
+// 服务以 root 运行而工作空间由沙箱用户控制，因此每一级都以 O_NOFOLLOW 相对上一级目录的 fd 打开，
+// 不跟随沙箱放置的符号链接；文件以 O_EXCL 创建，不会截断已有的文件.
+// user 非空时 root 之下的目录和新文件通过 fd 改为该用户所有.
+func CreateFile(root, rel string, perm os.FileMode, user *User) (*os.File, error) {
+	if !filepath.IsLocal(rel) {
+		return nil, fmt.Errorf("%w: %s", ErrUnsafePath, rel)
+	}
+
+	dirfd, err := unix.Open(root, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", root, err)
+	}
+
+	parts := strings.Split(filepath.Clean(rel), string(filepath.Separator))
+
+	for _, name := range parts[:len(parts)-1] {
+		fd, err := openDir(dirfd, name)
+		_ = unix.Close(dirfd)
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to open directory %s: %w", name, err)
+		}
+
+		dirfd = fd
+
+		if user != nil {
+			if err := unix.Fchown(dirfd, int(user.UID), int(user.GID)); err != nil {
+				_ = unix.Close(dirfd)
+				return nil, fmt.Errorf("failed to chown %s: %w", name, err)
+			}
+		}
+	}
+
+	name := parts[len(parts)-1]
+	fd, err := unix.Openat(dirfd, name, unix.O_WRONLY|unix.O_CREAT|unix.O_EXCL|unix.O_NOFOLLOW|unix.O_CLOEXEC, uint32(perm.Perm()))
+	_ = unix.Close(dirfd)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", rel, err)
+	}
+
+	f := os.NewFile(uintptr(fd), filepath.Join(root, rel))
+
+	if user != nil {
+		if err := f.Chown(int(user.UID), int(user.GID)); err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("failed to chown %s: %w", rel, err)
+		}
+	}
+
+	return f, nil
+}
+
+// openDir 打开 dirfd 下的子目录 name，不存在时创建；name 是符号链接或其他非目录文件时返回 ErrUnsafePath.
+func openDir(dirfd int, name string) (int, error) {
+	if err := unix.Mkdirat(dirfd, name, 0o750); err != nil && !errors.Is(err, unix.EEXIST) {
+		return -1, err
+	}
+
+	fd, err := unix.Openat(dirfd, name, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0)
+	if errors.Is(err, unix.ELOOP) || errors.Is(err, unix.ENOTDIR) {
+		return -1, ErrUnsafePath
+	}
+
+	return fd, err
+}
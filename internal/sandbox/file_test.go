@@ -0,0 +1,54 @@
+package sandbox
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateFile(t *testing.T) {
+	root := t.TempDir()
+
+	f, err := CreateFile(root, filepath.Join("a", "b", "c.txt"), 0o600, nil)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "a", "b", "c.txt"), f.Name())
+
+	_, err = f.WriteString("content")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	// 不会截断已有的文件
+	_, err = CreateFile(root, filepath.Join("a", "b", "c.txt"), 0o600, nil)
+	require.ErrorIs(t, err, os.ErrExist)
+
+	_, err = CreateFile(root, "../escape.txt", 0o600, nil)
+	require.ErrorIs(t, err, ErrUnsafePath)
+}
+
+func TestCreateFile_Symlink(t *testing.T) {
+	root, outside := t.TempDir(), t.TempDir()
+	target := filepath.Join(outside, "host.txt")
+	require.NoError(t, os.WriteFile(target, []byte("host"), 0o600))
+
+	// 中间目录是符号链接
+	require.NoError(t, os.Symlink(outside, filepath.Join(root, "dir")))
+
+	_, err := CreateFile(root, filepath.Join("dir", "new.txt"), 0o600, nil)
+	require.ErrorIs(t, err, ErrUnsafePath)
+
+	// 文件本身是符号链接
+	require.NoError(t, os.Symlink(target, filepath.Join(root, "file.txt")))
+
+	_, err = CreateFile(root, "file.txt", 0o600, nil)
+	require.Error(t, err)
+
+	content, err := os.ReadFile(target) // #nosec G304 -- test file
+	require.NoError(t, err)
+	assert.Equal(t, "host", string(content))
+
+	_, err = os.Stat(filepath.Join(outside, "new.txt"))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,124 @@
+package sandbox
+
+import (
+	"fmt"
+	"runtime"
+
+	"golang.org/x/sys/unix"
+)
+
+// NetNS 沙箱持久的网络命名空间，沙箱内的所有命令共享同一个网络栈.
+type NetNS struct {
+	fd int
+}
+
+// NewNetNS 创建新的网络命名空间，loopback 为 true 时启用其中的回环网卡.
+// 需要 CAP_SYS_ADMIN 和 CAP_NET_ADMIN.
+func NewNetNS(loopback bool) (*NetNS, error) {
+	var ns *NetNS
+
+	err := onLockedThread(func() error {
+		if err := unix.Unshare(unix.CLONE_NEWNET); err != nil {
+			return fmt.Errorf("failed to create network namespace: %w", err)
+		}
+
+		fd, err := unix.Open("/proc/thread-self/ns/net", unix.O_RDONLY|unix.O_CLOEXEC, 0)
+		if err != nil {
+			return fmt.Errorf("failed to open network namespace: %w", err)
+		}
+
+		ns = &NetNS{fd: fd}
+
+		if loopback {
+			if err := setLinkUp("lo"); err != nil {
+				_ = ns.Close()
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ns, nil
+}
+
+// Do 在网络命名空间中执行 fn，fn 中启动的子进程会继承该网络命名空间.
+func (n *NetNS) Do(fn func() error) error {
+	return onLockedThread(func() error {
+		if err := unix.Setns(n.fd, unix.CLONE_NEWNET); err != nil {
+			return fmt.Errorf("failed to enter network namespace: %w", err)
+		}
+
+		return fn()
+	})
+}
+
+// Close 释放网络命名空间，已在其中运行的进程不受影响.
+func (n *NetNS) Close() error {
+	return unix.Close(n.fd)
+}
+
+// onLockedThread 在独占的系统线程上执行 fn，之后恢复线程原来的网络命名空间.
+// 无法恢复时线程保持锁定，goroutine 结束后由运行时销毁，不会被其他 goroutine 复用.
+func onLockedThread(fn func() error) error {
+	errCh := make(chan error, 1)
+
+	go func() {
+		runtime.LockOSThread()
+
+		origin, err := unix.Open("/proc/thread-self/ns/net", unix.O_RDONLY|unix.O_CLOEXEC, 0)
+		if err != nil {
+			errCh <- fmt.Errorf("failed to open network namespace: %w", err)
+			return
+		}
+
+		defer func() {
+			_ = unix.Close(origin)
+		}()
+
+		fnErr := fn()
+
+		if err := unix.Setns(origin, unix.CLONE_NEWNET); err != nil {
+			errCh <- fnErr
+			return
+		}
+
+		runtime.UnlockOSThread()
+
+		errCh <- fnErr
+	}()
+
+	return <-errCh
+}
+
+// setLinkUp 启用当前网络命名空间中的网卡.
+func setLinkUp(name string) error {
+	sock, err := unix.Socket(unix.AF_INET, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return fmt.Errorf("failed to create socket: %w", err)
+	}
+
+	defer func() {
+		_ = unix.Close(sock)
+	}()
+
+	ifr, err := unix.NewIfreq(name)
+	if err != nil {
+		return err
+	}
+
+	if err := unix.IoctlIfreq(sock, unix.SIOCGIFFLAGS, ifr); err != nil {
+		return fmt.Errorf("failed to get %s flags: %w", name, err)
+	}
+
+	ifr.SetUint16(ifr.Uint16() | unix.IFF_UP)
+
+	if err := unix.IoctlIfreq(sock, unix.SIOCSIFFLAGS, ifr); err != nil {
+		return fmt.Errorf("failed to bring up %s: %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package sandbox
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestNetNS 创建网络命名空间，权限不足时跳过测试.
+func newTestNetNS(t *testing.T, loopback bool) *NetNS {
+	t.Helper()
+
+	ns, err := NewNetNS(loopback)
+	if err != nil {
+		t.Skipf("network namespaces not available: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = ns.Close()
+	})
+
+	return ns
+}
+
+// interfaces 返回网络命名空间中的网卡.
+func interfaces(t *testing.T, ns *NetNS) []net.Interface {
+	t.Helper()
+
+	var ifaces []net.Interface
+
+	require.NoError(t, ns.Do(func() (err error) {
+		ifaces, err = net.Interfaces()
+		return err
+	}))
+
+	return ifaces
+}
+
+func TestNetNS_None(t *testing.T) {
+	ns := newTestNetNS(t, false)
+
+	ifaces := interfaces(t, ns)
+	require.Len(t, ifaces, 1)
+	assert.Equal(t, "lo", ifaces[0].Name)
+	assert.Zero(t, ifaces[0].Flags&net.FlagUp, "loopback should be down")
+
+	// 回环网卡未启用，连接本地地址失败
+	err := ns.Do(func() error {
+		conn, err := net.Dial("tcp", "127.0.0.1:1")
+		if err == nil {
+			_ = conn.Close()
+		}
+
+		return err
+	})
+	assert.ErrorContains(t, err, "network is unreachable")
+}
+
+func TestNetNS_Loopback(t *testing.T) {
+	ns := newTestNetNS(t, true)
+
+	ifaces := interfaces(t, ns)
+	require.Len(t, ifaces, 1)
+	assert.NotZero(t, ifaces[0].Flags&net.FlagUp, "loopback should be up")
+
+	// 命名空间中监听的端口在宿主机上不可见
+	var ln net.Listener
+
+	require.NoError(t, ns.Do(func() (err error) {
+		ln, err = net.Listen("tcp", "127.0.0.1:0")
+		return err
+	}))
+
+	defer func() {
+		_ = ln.Close()
+	}()
+
+	_, err := net.Dial("tcp", ln.Addr().String())
+	assert.Error(t, err)
+
+	// DialPort 在沙箱的命名空间中建立连接
+	registry := NewRegistry()
+	registry.Add(&Sandbox{ID: "sandbox-1", Network: NetworkLoopback, NetNS: ns})
+
+	conn, err := registry.DialPort(context.Background(), "sandbox-1", ln.Addr().(*net.TCPAddr).Port)
+	require.NoError(t, err)
+	_ = conn.Close()
+}
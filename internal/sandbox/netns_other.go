@@ -0,0 +1,26 @@
+//go:build !linux
+
+package sandbox
+
+import "errors"
+
+// errNetNSUnsupported 非 Linux 平台不支持网络命名空间.
+var errNetNSUnsupported = errors.New("network namespaces are only supported on Linux")
+
+// NetNS 沙箱持久的网络命名空间.
+type NetNS struct{}
+
+// NewNetNS 在非 Linux 平台上始终返回错误.
+func NewNetNS(_ bool) (*NetNS, error) {
+	return nil, errNetNSUnsupported
+}
+
+// Do 在非 Linux 平台上始终返回错误.
+func (n *NetNS) Do(_ func() error) error {
+	return errNetNSUnsupported
+}
+
+// Close 在非 Linux 平台上无需释放.
+func (n *NetNS) Close() error {
+	return nil
+}
@@ -0,0 +1,95 @@
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/HJH0924/agent-sandbox/internal/policy"
+)
+
+// ErrSandboxNotFound 沙箱不存在.
+var ErrSandboxNotFound = errors.New("sandbox not found")
+
+// NetworkPolicy 沙箱中命令的网络访问策略.
+type NetworkPolicy string
+
+const (
+	// NetworkNone 没有任何网络，包括回环地址.
+	NetworkNone NetworkPolicy = "none"
+	// NetworkLoopback 只能访问沙箱自己的回环地址.
+	NetworkLoopback NetworkPolicy = "loopback"
+	// NetworkFull 与宿主机共享网络.
+	NetworkFull NetworkPolicy = "full"
+)
+
+// ParseNetworkPolicy 解析网络策略名称.
+func ParseNetworkPolicy(name string) (NetworkPolicy, error) {
+	switch policy := NetworkPolicy(name); policy {
+	case NetworkNone, NetworkLoopback, NetworkFull:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown network policy %q", name)
+	}
+}
+
+// Sandbox 沙箱的运行时信息.
+type Sandbox struct {
+	ID        string
+	CreatedAt time.Time
+	// Network 实际生效的网络策略
+	Network NetworkPolicy
+	// NetNS 沙箱的网络命名空间，策略为 full 时为 nil
+	NetNS *NetNS
+	// Policy 沙箱的命令策略，在服务端策略之后生效
+	Policy *policy.Policy
+}
+
+// Registry 记录服务中所有沙箱的运行时信息.
+type Registry struct {
+	mu        sync.RWMutex
+	sandboxes map[string]*Sandbox
+}
+
+// NewRegistry 创建沙箱注册表.
+func NewRegistry() *Registry {
+	return &Registry{
+		sandboxes: make(map[string]*Sandbox),
+	}
+}
+
+// Add 注册沙箱.
+func (r *Registry) Add(sandbox *Sandbox) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sandboxes[sandbox.ID] = sandbox
+}
+
+// Get 查找沙箱.
+func (r *Registry) Get(sandboxID string) (*Sandbox, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sandbox, ok := r.sandboxes[sandboxID]
+	if !ok {
+		return nil, ErrSandboxNotFound
+	}
+
+	return sandbox, nil
+}
+
+// Remove 注销沙箱并释放其网络命名空间.
+func (r *Registry) Remove(sandboxID string) error {
+	r.mu.Lock()
+	sandbox, ok := r.sandboxes[sandboxID]
+	delete(r.sandboxes, sandboxID)
+	r.mu.Unlock()
+
+	if !ok || sandbox.NetNS == nil {
+		return nil
+	}
+
+	return sandbox.NetNS.Close()
+}
@@ -0,0 +1,111 @@
+package sandbox
+
+import (
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNetworkPolicy(t *testing.T) {
+	for _, name := range []string{"none", "loopback", "full"} {
+		policy, err := ParseNetworkPolicy(name)
+		require.NoError(t, err)
+		assert.Equal(t, NetworkPolicy(name), policy)
+	}
+
+	_, err := ParseNetworkPolicy("internet")
+	assert.Error(t, err)
+}
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+
+	_, err := registry.Get("sandbox-1")
+	require.ErrorIs(t, err, ErrSandboxNotFound)
+
+	sandbox := &Sandbox{ID: "sandbox-1", CreatedAt: time.Now(), Network: NetworkFull}
+	registry.Add(sandbox)
+
+	found, err := registry.Get("sandbox-1")
+	require.NoError(t, err)
+	assert.Same(t, sandbox, found)
+
+	require.NoError(t, registry.Remove("sandbox-1"))
+
+	_, err = registry.Get("sandbox-1")
+	require.ErrorIs(t, err, ErrSandboxNotFound)
+
+	// 删除不存在的沙箱不报错
+	require.NoError(t, registry.Remove("sandbox-1"))
+}
+
+func TestRegistry_DialPort(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			_, _ = conn.Write([]byte("hello"))
+			_ = conn.Close()
+		}
+	}()
+
+	port := listener.Addr().(*net.TCPAddr).Port
+	registry := NewRegistry()
+	registry.Add(&Sandbox{ID: "sandbox-1", CreatedAt: time.Now(), Network: NetworkFull})
+
+	// 未注册（如已销毁）的沙箱不回退到宿主机的回环地址
+	_, err = registry.DialPort(context.Background(), "sandbox-2", port)
+	require.ErrorIs(t, err, ErrSandboxNotFound)
+
+	// 共享网络的沙箱连接宿主机的回环地址
+	conn, err := registry.DialPort(context.Background(), "sandbox-1", port)
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(conn)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+	require.NoError(t, conn.Close())
+
+	require.NoError(t, listener.Close())
+
+	_, err = registry.DialPort(context.Background(), "sandbox-1", port)
+	assert.Error(t, err)
+}
+
+func TestRegistry_Dial(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			_ = conn.Close()
+		}
+	}()
+
+	port := strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+
+	registry := NewRegistry()
+	registry.Add(&Sandbox{ID: "sandbox-1", CreatedAt: time.Now(), Network: NetworkFull})
+
+	// localhost 可能先解析为 ::1，连接失败后继续尝试 127.0.0.1
+	conn, err := registry.Dial(context.Background(), "sandbox-1", net.JoinHostPort("localhost", port))
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	_, err = registry.Dial(context.Background(), "sandbox-1", "localhost")
+	assert.Error(t, err)
+
+	_, err = registry.Dial(context.Background(), "sandbox-2", net.JoinHostPort("localhost", port))
+	require.ErrorIs(t, err, ErrSandboxNotFound)
+}
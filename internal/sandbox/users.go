@@ -0,0 +1,173 @@
+// Package sandbox maps sandboxes to dedicated unprivileged users and workspaces.
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/HJH0924/agent-sandbox/internal/launcher"
+)
+
+var (
+	// ErrNoFreeUser 配置范围内的 UID 已全部分配.
+	ErrNoFreeUser = errors.New("no free sandbox user in configured range")
+	// ErrUserNotFound 沙箱没有分配用户.
+	ErrUserNotFound = errors.New("sandbox user not found")
+)
+
+// User 沙箱的专属用户和工作空间.
+type User struct {
+	UID       uint32
+	GID       uint32
+	Workspace string
+}
+
+// Users 将每个沙箱映射到配置范围内独立的 UID/GID，并为其创建只有该用户可访问的工作空间.
+type Users struct {
+	root  string
+	start uint32
+	count uint32
+
+	mu       sync.Mutex
+	users    map[string]*User
+	assigned map[uint32]string
+}
+
+// NewUsers 创建沙箱用户分配器，UID/GID 从 start 开始共 count 个，工作空间位于 root/<sandboxID>.
+func NewUsers(root string, start, count uint32) *Users {
+	return &Users{
+		root:     root,
+		start:    start,
+		count:    count,
+		users:    make(map[string]*User),
+		assigned: make(map[uint32]string),
+	}
+}
+
+// Create 为沙箱分配用户并创建工作空间，重复调用返回已分配的用户.
+func (u *Users) Create(sandboxID string) (*User, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if user, ok := u.users[sandboxID]; ok {
+		return user, nil
+	}
+
+	id, ok := u.nextFree()
+	if !ok {
+		return nil, ErrNoFreeUser
+	}
+
+	user := &User{
+		UID:       id,
+		GID:       id,
+		Workspace: filepath.Join(u.root, filepath.Base(sandboxID)),
+	}
+
+	if err := os.MkdirAll(user.Workspace, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create workspace: %w", err)
+	}
+
+	if err := user.Chown(user.Workspace); err != nil {
+		return nil, err
+	}
+
+	// MkdirAll 不会修改已存在目录的权限
+	if err := os.Chmod(user.Workspace, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to chmod workspace: %w", err)
+	}
+
+	u.users[sandboxID] = user
+	u.assigned[id] = sandboxID
+
+	return user, nil
+}
+
+// Lookup 查找沙箱的用户.
+func (u *Users) Lookup(sandboxID string) (*User, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	user, ok := u.users[sandboxID]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+// Release 结束沙箱用户的所有进程并将工作空间改回服务进程所有，然后释放 UID.
+// 之后分配到同一 UID 的沙箱无法再向旧进程发送信号或访问旧的工作空间，工作空间中的文件保留在磁盘上.
+// 失败时 UID 保持占用，避免分配给其他沙箱.
+func (u *Users) Release(sandboxID string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	user, ok := u.users[sandboxID]
+	if !ok {
+		return nil
+	}
+
+	if err := launcher.KillUser(user.UID, user.GID); err != nil {
+		return err
+	}
+
+	if err := reclaim(user.Workspace); err != nil {
+		return err
+	}
+
+	delete(u.assigned, user.UID)
+	delete(u.users, sandboxID)
+
+	return nil
+}
+
+// reclaim 将工作空间及其中的所有文件改回服务进程所有，并恢复工作空间目录的权限.
+// 改变所有者时内核会清除文件的 setuid/setgid 位.
+func reclaim(workspace string) error {
+	uid, gid := os.Geteuid(), os.Getegid()
+
+	// WalkDir 不跟随符号链接，Lchown 修改链接本身
+	err := filepath.WalkDir(workspace, func(path string, _ fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		return os.Lchown(path, uid, gid)
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to reclaim workspace: %w", err)
+	}
+
+	// 沙箱用户可能放宽过工作空间的权限
+	if err := os.Chmod(workspace, 0o700); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to chmod workspace: %w", err)
+	}
+
+	return nil
+}
+
+// nextFree 返回范围内第一个未分配的 UID.
+func (u *Users) nextFree() (uint32, bool) {
+	for i := range u.count {
+		id := u.start + i
+		if _, used := u.assigned[id]; !used {
+			return id, true
+		}
+	}
+
+	return 0, false
+}
+
+// Chown 将路径的所有者改为沙箱用户.
+func (user *User) Chown(path string) error {
+	if err := os.Lchown(path, int(user.UID), int(user.GID)); err != nil {
+		return fmt.Errorf("failed to chown %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package sandbox
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/HJH0924/agent-sandbox/internal/launcher"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	// 释放用户时通过重新执行测试二进制结束用户的进程
+	launcher.Init()
+	os.Exit(m.Run())
+}
+
+func TestUsers_Allocate(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("changing file ownership requires root")
+	}
+
+	root := t.TempDir()
+	users := NewUsers(root, 20000, 2)
+
+	first, err := users.Create("sandbox-1")
+	require.NoError(t, err)
+	assert.Equal(t, uint32(20000), first.UID)
+	assert.Equal(t, uint32(20000), first.GID)
+	assert.Equal(t, filepath.Join(root, "sandbox-1"), first.Workspace)
+
+	// 重复创建返回相同的用户
+	again, err := users.Create("sandbox-1")
+	require.NoError(t, err)
+	assert.Same(t, first, again)
+
+	second, err := users.Create("sandbox-2")
+	require.NoError(t, err)
+	assert.Equal(t, uint32(20001), second.UID)
+
+	// 范围已用尽
+	_, err = users.Create("sandbox-3")
+	require.ErrorIs(t, err, ErrNoFreeUser)
+
+	// 释放后 UID 可以重新分配
+	require.NoError(t, users.Release("sandbox-1"))
+
+	_, err = users.Lookup("sandbox-1")
+	require.ErrorIs(t, err, ErrUserNotFound)
+
+	third, err := users.Create("sandbox-3")
+	require.NoError(t, err)
+	assert.Equal(t, uint32(20000), third.UID)
+}
+
+func TestUsers_Workspace(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("changing file ownership requires root")
+	}
+
+	users := NewUsers(t.TempDir(), 20000, 10)
+
+	user, err := users.Create("sandbox-1")
+	require.NoError(t, err)
+
+	info, err := os.Stat(user.Workspace)
+	require.NoError(t, err)
+	assert.True(t, info.IsDir())
+	assert.Equal(t, os.FileMode(0o700), info.Mode().Perm())
+
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	require.True(t, ok)
+	assert.Equal(t, user.UID, stat.Uid)
+	assert.Equal(t, user.GID, stat.Gid)
+
+	found, err := users.Lookup("sandbox-1")
+	require.NoError(t, err)
+	assert.Same(t, user, found)
+}
+
+func TestUsers_Release(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("changing file ownership requires root")
+	}
+
+	users := NewUsers(t.TempDir(), 20000, 10)
+
+	user, err := users.Create("sandbox-1")
+	require.NoError(t, err)
+
+	// 沙箱用户留下的进程和放宽了权限的工作空间
+	cmd := exec.Command("sleep", "60")
+	cmd.SysProcAttr = &syscall.SysProcAttr{Credential: &syscall.Credential{Uid: user.UID, Gid: user.GID}}
+	require.NoError(t, cmd.Start())
+
+	file := filepath.Join(user.Workspace, "file")
+	require.NoError(t, os.WriteFile(file, []byte("data"), 0o666))
+	require.NoError(t, user.Chown(file))
+	require.NoError(t, os.Chmod(user.Workspace, 0o777))
+
+	require.NoError(t, users.Release("sandbox-1"))
+
+	// 进程已被结束
+	err = cmd.Wait()
+	var exitErr *exec.ExitError
+	require.ErrorAs(t, err, &exitErr)
+	status, ok := exitErr.Sys().(syscall.WaitStatus)
+	require.True(t, ok)
+	assert.Equal(t, syscall.SIGKILL, status.Signal())
+
+	// 工作空间及其中的文件改回当前用户所有，文件保留
+	for _, path := range []string{user.Workspace, file} {
+		info, err := os.Lstat(path)
+		require.NoError(t, err)
+
+		stat, ok := info.Sys().(*syscall.Stat_t)
+		require.True(t, ok)
+		assert.Equal(t, uint32(os.Geteuid()), stat.Uid, path)
+		assert.Equal(t, uint32(os.Getegid()), stat.Gid, path)
+	}
+
+	info, err := os.Stat(user.Workspace)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o700), info.Mode().Perm())
+}
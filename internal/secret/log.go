@@ -0,0 +1,83 @@
+package secret
+
+import (
+	"context"
+	"log/slog"
+)
+
+// logHandler 在输出日志前替换消息和属性中所有沙箱的密钥值.
+type logHandler struct {
+	next  slog.Handler
+	store *Store
+}
+
+// NewLogHandler 包装日志处理器，使服务日志中不出现任何沙箱的密钥值.
+// 通过 Logger.With 预先绑定的属性只按绑定时的密钥替换.
+func NewLogHandler(next slog.Handler, store *Store) slog.Handler {
+	return &logHandler{next: next, store: store}
+}
+
+// Enabled 实现 slog.Handler.
+func (h *logHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle 实现 slog.Handler.
+func (h *logHandler) Handle(ctx context.Context, r slog.Record) error {
+	redacted := slog.NewRecord(r.Time, r.Level, h.store.RedactAll(r.Message), r.PC)
+
+	r.Attrs(func(a slog.Attr) bool {
+		redacted.AddAttrs(h.redactAttr(a))
+		return true
+	})
+
+	return h.next.Handle(ctx, redacted)
+}
+
+// WithAttrs 实现 slog.Handler.
+func (h *logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		redacted = append(redacted, h.redactAttr(a))
+	}
+
+	return &logHandler{next: h.next.WithAttrs(redacted), store: h.store}
+}
+
+// WithGroup 实现 slog.Handler.
+func (h *logHandler) WithGroup(name string) slog.Handler {
+	return &logHandler{next: h.next.WithGroup(name), store: h.store}
+}
+
+// redactAttr 替换属性中的密钥值. 字符串、字符串列表和错误被替换，其他类型的值原样保留.
+func (h *logHandler) redactAttr(a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, h.store.RedactAll(a.Value.String()))
+	case slog.KindGroup:
+		attrs := a.Value.Group()
+
+		redacted := make([]slog.Attr, 0, len(attrs))
+		for _, attr := range attrs {
+			redacted = append(redacted, h.redactAttr(attr))
+		}
+
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(redacted...)}
+	case slog.KindAny:
+		switch v := a.Value.Any().(type) {
+		case error:
+			return slog.String(a.Key, h.store.RedactAll(v.Error()))
+		case []string:
+			redacted := make([]string, len(v))
+			for i, s := range v {
+				redacted[i] = h.store.RedactAll(s)
+			}
+
+			return slog.Any(a.Key, redacted)
+		}
+	}
+
+	return a
+}
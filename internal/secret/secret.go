@@ -0,0 +1,301 @@
+// Package secret keeps per-sandbox credentials that are injected into commands
+// as environment variables and scrubbed from everything the server returns or
+// logs, so that they never reach the agent's context.
+package secret
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// MaxSecretsPerSandbox 每个沙箱最多保存的密钥数.
+	MaxSecretsPerSandbox = 64
+	// MinValueSize 密钥值的最小字节数，过短的值会在输出中被大量误替换.
+	MinValueSize = 6
+	// MaxValueSize 密钥值的最大字节数.
+	MaxValueSize = 64 * 1024
+	// reservedPrefix 服务内部使用的环境变量前缀.
+	reservedPrefix = "AGENT_SANDBOX_"
+)
+
+var (
+	// ErrInvalidSecret 密钥名称或值无效.
+	ErrInvalidSecret = errors.New("invalid secret")
+	// ErrTooManySecrets 沙箱的密钥数已达上限.
+	ErrTooManySecrets = fmt.Errorf("too many secrets (max: %d)", MaxSecretsPerSandbox)
+
+	// namePattern 合法的环境变量名.
+	namePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	// reservedNames 由服务设置、不能被密钥覆盖的环境变量.
+	reservedNames = []string{"HOME", "PATH", "TERM"}
+)
+
+// Info 密钥的元数据，不包含密钥值.
+type Info struct {
+	Name      string
+	UpdatedAt time.Time
+}
+
+// entry 保存的密钥.
+type entry struct {
+	value     string
+	updatedAt time.Time
+}
+
+// Store 按沙箱保存密钥. 除 Set 外的方法都可以在 nil 上调用，此时没有任何密钥.
+type Store struct {
+	mu        sync.RWMutex
+	sandboxes map[string]map[string]entry
+	// replacers 每个沙箱的替换器，all 包含所有沙箱的密钥，用于服务日志
+	replacers map[string]*strings.Replacer
+	all       *strings.Replacer
+	// patterns 每个沙箱按匹配优先级排序的密钥值，用于替换输出流
+	patterns map[string][]pattern
+}
+
+// NewStore 创建密钥存储.
+func NewStore() *Store {
+	return &Store{
+		sandboxes: make(map[string]map[string]entry),
+		replacers: make(map[string]*strings.Replacer),
+		patterns:  make(map[string][]pattern),
+	}
+}
+
+// Set 按名称设置沙箱的密钥，已存在的同名密钥被覆盖. 任一密钥无效时不做任何修改.
+// 错误信息中只包含密钥名称.
+func (s *Store) Set(sandboxID string, secrets map[string]string) error {
+	for name, value := range secrets {
+		if err := validate(name, value); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := s.sandboxes[sandboxID]
+
+	count := len(current)
+
+	for name := range secrets {
+		if _, ok := current[name]; !ok {
+			count++
+		}
+	}
+
+	if count > MaxSecretsPerSandbox {
+		return ErrTooManySecrets
+	}
+
+	if current == nil {
+		current = make(map[string]entry, len(secrets))
+		s.sandboxes[sandboxID] = current
+	}
+
+	now := time.Now()
+
+	for name, value := range secrets {
+		current[name] = entry{value: value, updatedAt: now}
+	}
+
+	s.rebuildLocked(sandboxID)
+
+	return nil
+}
+
+// validate 检查密钥名称和值.
+func validate(name, value string) error {
+	switch {
+	case !namePattern.MatchString(name):
+		return fmt.Errorf("%w: name %q is not a valid environment variable name", ErrInvalidSecret, name)
+	case slices.Contains(reservedNames, name) || strings.HasPrefix(name, reservedPrefix):
+		return fmt.Errorf("%w: name %q is reserved", ErrInvalidSecret, name)
+	case len(value) < MinValueSize || len(value) > MaxValueSize:
+		return fmt.Errorf("%w: value of %q must be %d to %d bytes", ErrInvalidSecret, name, MinValueSize, MaxValueSize)
+	case strings.ContainsRune(value, 0):
+		return fmt.Errorf("%w: value of %q contains a NUL byte", ErrInvalidSecret, name)
+	}
+
+	return nil
+}
+
+// Delete 删除沙箱的密钥，不存在的名称被忽略.
+func (s *Store) Delete(sandboxID string, names []string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := s.sandboxes[sandboxID]
+	for _, name := range names {
+		delete(current, name)
+	}
+
+	s.rebuildLocked(sandboxID)
+}
+
+// Remove 删除沙箱的全部密钥，用于销毁沙箱.
+func (s *Store) Remove(sandboxID string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sandboxes, sandboxID)
+	s.rebuildLocked(sandboxID)
+}
+
+// List 按名称排序返回沙箱的密钥，不包含密钥值.
+func (s *Store) List(sandboxID string) []Info {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	infos := make([]Info, 0, len(s.sandboxes[sandboxID]))
+
+	for name, e := range s.sandboxes[sandboxID] {
+		infos = append(infos, Info{Name: name, UpdatedAt: e.updatedAt})
+	}
+
+	sort.Slice(infos, func(i, k int) bool {
+		return infos[i].Name < infos[k].Name
+	})
+
+	return infos
+}
+
+// Env 以 NAME=value 的形式按名称排序返回沙箱的密钥，用于注入命令的环境变量.
+func (s *Store) Env(sandboxID string) []string {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	env := make([]string, 0, len(s.sandboxes[sandboxID]))
+
+	for name, e := range s.sandboxes[sandboxID] {
+		env = append(env, name+"="+e.value)
+	}
+
+	sort.Strings(env)
+
+	return env
+}
+
+// Redact 将文本中沙箱的密钥值替换为 [REDACTED:NAME].
+func (s *Store) Redact(sandboxID, text string) string {
+	if s == nil {
+		return text
+	}
+
+	s.mu.RLock()
+	replacer := s.replacers[sandboxID]
+	s.mu.RUnlock()
+
+	if replacer == nil {
+		return text
+	}
+
+	return replacer.Replace(text)
+}
+
+// RedactAll 将文本中任意沙箱的密钥值替换为 [REDACTED:NAME]，用于不属于某个沙箱的服务日志.
+func (s *Store) RedactAll(text string) string {
+	if s == nil {
+		return text
+	}
+
+	s.mu.RLock()
+	replacer := s.all
+	s.mu.RUnlock()
+
+	if replacer == nil {
+		return text
+	}
+
+	return replacer.Replace(text)
+}
+
+// rebuildLocked 重新生成沙箱和全局的替换器. 调用方必须持有 s.mu.
+func (s *Store) rebuildLocked(sandboxID string) {
+	if len(s.sandboxes[sandboxID]) == 0 {
+		delete(s.sandboxes, sandboxID)
+		delete(s.replacers, sandboxID)
+		delete(s.patterns, sandboxID)
+	} else {
+		pairs := pairsOf(s.sandboxes[sandboxID])
+		s.replacers[sandboxID] = newReplacer(pairs)
+		s.patterns[sandboxID] = newPatterns(pairs)
+	}
+
+	var all []pair
+	for _, secrets := range s.sandboxes {
+		all = append(all, pairsOf(secrets)...)
+	}
+
+	s.all = nil
+	if len(all) > 0 {
+		s.all = newReplacer(all)
+	}
+}
+
+// pair 密钥名称和值.
+type pair struct {
+	name  string
+	value string
+}
+
+// pairsOf 返回沙箱密钥的名称和值.
+func pairsOf(secrets map[string]entry) []pair {
+	pairs := make([]pair, 0, len(secrets))
+	for name, e := range secrets {
+		pairs = append(pairs, pair{name: name, value: e.value})
+	}
+
+	return pairs
+}
+
+// sortPairs 按匹配优先级排序：较长的值优先，一个密钥是另一个的子串时不会只替换一部分.
+func sortPairs(pairs []pair) {
+	sort.Slice(pairs, func(i, k int) bool {
+		if len(pairs[i].value) != len(pairs[k].value) {
+			return len(pairs[i].value) > len(pairs[k].value)
+		}
+
+		return pairs[i].name < pairs[k].name
+	})
+}
+
+// newReplacer 创建替换器，同一位置较长的值优先匹配.
+func newReplacer(pairs []pair) *strings.Replacer {
+	sortPairs(pairs)
+
+	oldnew := make([]string, 0, 2*len(pairs))
+	for _, p := range pairs {
+		oldnew = append(oldnew, p.value, replacement(p.name))
+	}
+
+	return strings.NewReplacer(oldnew...)
+}
+
+// replacement 返回密钥值的替换文本.
+func replacement(name string) string {
+	return "[REDACTED:" + name + "]"
+}
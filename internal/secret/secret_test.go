@@ -0,0 +1,188 @@
+package secret
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	store := NewStore()
+
+	require.NoError(t, store.Set("sandbox-1", map[string]string{
+		"TOKEN":    "tok-123456",
+		"PASSWORD": "hunter22",
+	}))
+
+	assert.Equal(t, []string{"PASSWORD=hunter22", "TOKEN=tok-123456"}, store.Env("sandbox-1"))
+	assert.Empty(t, store.Env("sandbox-2"))
+
+	infos := store.List("sandbox-1")
+	require.Len(t, infos, 2)
+	assert.Equal(t, "PASSWORD", infos[0].Name)
+	assert.Equal(t, "TOKEN", infos[1].Name)
+	assert.False(t, infos[0].UpdatedAt.IsZero())
+
+	assert.Equal(t, "token=[REDACTED:TOKEN] pw=[REDACTED:PASSWORD]", store.Redact("sandbox-1", "token=tok-123456 pw=hunter22"))
+	assert.Equal(t, "tok-123456", store.Redact("sandbox-2", "tok-123456"))
+
+	// 覆盖同名密钥后旧值不再被替换
+	require.NoError(t, store.Set("sandbox-1", map[string]string{"TOKEN": "tok-654321"}))
+	assert.Equal(t, "tok-123456 [REDACTED:TOKEN]", store.Redact("sandbox-1", "tok-123456 tok-654321"))
+
+	store.Delete("sandbox-1", []string{"TOKEN", "MISSING"})
+	assert.Equal(t, []string{"PASSWORD=hunter22"}, store.Env("sandbox-1"))
+
+	store.Remove("sandbox-1")
+	assert.Empty(t, store.List("sandbox-1"))
+	assert.Equal(t, "hunter22", store.Redact("sandbox-1", "hunter22"))
+	assert.Equal(t, "hunter22", store.RedactAll("hunter22"))
+}
+
+func TestStore_Invalid(t *testing.T) {
+	store := NewStore()
+
+	for _, secrets := range []map[string]string{
+		{"1TOKEN": "value-123"},
+		{"MY-TOKEN": "value-123"},
+		{"HOME": "value-123"},
+		{"AGENT_SANDBOX_INIT_SPEC": "value-123"},
+		{"TOKEN": "short"},
+		{"TOKEN": strings.Repeat("x", MaxValueSize+1)},
+		{"TOKEN": "value\x00123"},
+	} {
+		err := store.Set("sandbox-1", secrets)
+		require.ErrorIs(t, err, ErrInvalidSecret)
+
+		for _, value := range secrets {
+			assert.NotContains(t, err.Error(), value)
+		}
+	}
+
+	// 任一密钥无效时不保存其他密钥
+	err := store.Set("sandbox-1", map[string]string{"TOKEN": "value-123", "BAD NAME": "value-123"})
+	require.ErrorIs(t, err, ErrInvalidSecret)
+	assert.Empty(t, store.List("sandbox-1"))
+
+	secrets := make(map[string]string, MaxSecretsPerSandbox+1)
+	for i := range MaxSecretsPerSandbox + 1 {
+		secrets["S"+strings.Repeat("X", i)] = "value-123"
+	}
+
+	assert.True(t, errors.Is(store.Set("sandbox-1", secrets), ErrTooManySecrets))
+}
+
+func TestStore_RedactLongestFirst(t *testing.T) {
+	store := NewStore()
+
+	require.NoError(t, store.Set("sandbox-1", map[string]string{
+		"SHORT": "abcdef",
+		"LONG":  "abcdef-ghijkl",
+	}))
+
+	assert.Equal(t, "[REDACTED:LONG] [REDACTED:SHORT]", store.Redact("sandbox-1", "abcdef-ghijkl abcdef"))
+}
+
+func TestStore_Nil(t *testing.T) {
+	var store *Store
+
+	assert.Empty(t, store.Env("sandbox-1"))
+	assert.Empty(t, store.List("sandbox-1"))
+	assert.Equal(t, "text", store.Redact("sandbox-1", "text"))
+	assert.Equal(t, "text", store.RedactAll("text"))
+	assert.Equal(t, "text", string(store.NewRedactor("sandbox-1").Redact([]byte("text"))))
+	store.Delete("sandbox-1", []string{"TOKEN"})
+	store.Remove("sandbox-1")
+}
+
+func TestLogHandler(t *testing.T) {
+	store := NewStore()
+	require.NoError(t, store.Set("sandbox-1", map[string]string{"TOKEN": "tok-123456"}))
+	require.NoError(t, store.Set("sandbox-2", map[string]string{"PASSWORD": "hunter22"}))
+
+	var buf bytes.Buffer
+
+	logger := slog.New(NewLogHandler(slog.NewJSONHandler(&buf, nil), store))
+	logger.With(slog.String("bound", "hunter22")).Info("running curl -H tok-123456",
+		slog.String("command", "echo tok-123456"),
+		slog.Any("argv", []string{"login", "hunter22"}),
+		slog.Any("error", errors.New("failed with hunter22")),
+		slog.Group("request", slog.String("header", "Bearer tok-123456")),
+		slog.Int("exit_code", 1))
+
+	output := buf.String()
+	assert.NotContains(t, output, "tok-123456")
+	assert.NotContains(t, output, "hunter22")
+	assert.Contains(t, output, `"msg":"running curl -H [REDACTED:TOKEN]"`)
+	assert.Contains(t, output, `"argv":["login","[REDACTED:PASSWORD]"]`)
+	assert.Contains(t, output, `"header":"Bearer [REDACTED:TOKEN]"`)
+	assert.Contains(t, output, `"exit_code":1`)
+}
+
+func TestRedactor(t *testing.T) {
+	store := NewStore()
+
+	require.NoError(t, store.Set("sandbox-1", map[string]string{
+		"TOKEN": "tok-123456",
+		"LONG":  "tok-123456-abcdef",
+	}))
+
+	redactor := store.NewRedactor("sandbox-1")
+
+	// 密钥值被拆分在两次写入中
+	assert.Equal(t, "token=", string(redactor.Redact([]byte("token=tok-12"))))
+	assert.Equal(t, "[REDACTED:TOKEN]\n", string(redactor.Redact([]byte("3456\n"))))
+
+	// 不可能是密钥值开头的输出立即返回
+	assert.Equal(t, "$ ", string(redactor.Redact([]byte("$ "))))
+
+	// 较短的值已经完整，但可能是较长的值的开头
+	assert.Empty(t, redactor.Redact([]byte("tok-123456-ab")))
+	assert.Equal(t, "[REDACTED:LONG]", string(redactor.Redact([]byte("cdef"))))
+
+	// 输出流结束时替换并返回保留的字节
+	assert.Empty(t, redactor.Redact([]byte("tok-123456-")))
+	assert.Equal(t, "[REDACTED:TOKEN]-", string(redactor.Flush()))
+	assert.Empty(t, redactor.Flush())
+}
+
+func TestRedactor_Splits(t *testing.T) {
+	store := NewStore()
+
+	require.NoError(t, store.Set("sandbox-1", map[string]string{
+		"SHORT":  "abcabd",
+		"LONG":   "abcabdabcabe",
+		"REPEAT": "aaaaaaaa",
+	}))
+
+	text := "xx abcabcabdabcabe aaaaaaaaaaa abcabdabcab abcabd aaaaaaa"
+	expected := store.Redact("sandbox-1", text)
+
+	// 任意位置拆分成两次写入都与整体替换的结果相同
+	for i := 0; i <= len(text); i++ {
+		redactor := store.NewRedactor("sandbox-1")
+
+		var out bytes.Buffer
+		out.Write(redactor.Redact([]byte(text[:i])))
+		out.Write(redactor.Redact([]byte(text[i:])))
+		out.Write(redactor.Flush())
+
+		assert.Equal(t, expected, out.String(), "split at %d", i)
+	}
+
+	// 逐字节写入
+	redactor := store.NewRedactor("sandbox-1")
+
+	var out bytes.Buffer
+	for i := range len(text) {
+		out.Write(redactor.Redact([]byte{text[i]}))
+	}
+
+	out.Write(redactor.Flush())
+	assert.Equal(t, expected, out.String())
+}
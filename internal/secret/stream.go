@@ -0,0 +1,172 @@
+package secret
+
+import "bytes"
+
+// pattern 替换输出流时使用的密钥值.
+type pattern struct {
+	value       []byte
+	replacement []byte
+	// fail KMP 前缀函数，fail[i] 为 value[:i+1] 最长的相同真前缀和后缀的长度
+	fail []int
+}
+
+// newPatterns 按匹配优先级创建沙箱的密钥值.
+func newPatterns(pairs []pair) []pattern {
+	sortPairs(pairs)
+
+	patterns := make([]pattern, 0, len(pairs))
+	for _, p := range pairs {
+		patterns = append(patterns, pattern{
+			value:       []byte(p.value),
+			replacement: []byte(replacement(p.name)),
+			fail:        prefixFunction([]byte(p.value)),
+		})
+	}
+
+	return patterns
+}
+
+// prefixFunction 计算 KMP 前缀函数.
+func prefixFunction(value []byte) []int {
+	fail := make([]int, len(value))
+
+	for i, k := 1, 0; i < len(value); i++ {
+		for k > 0 && value[i] != value[k] {
+			k = fail[k-1]
+		}
+
+		if value[i] == value[k] {
+			k++
+		}
+
+		fail[i] = k
+	}
+
+	return fail
+}
+
+// partialSuffix 返回 data 最长的、同时是 value 真前缀的后缀的长度.
+func (p *pattern) partialSuffix(data []byte) int {
+	// 真前缀比 value 短，只需要检查最后 len(value)-1 字节
+	if window := len(p.value) - 1; len(data) > window {
+		data = data[len(data)-window:]
+	}
+
+	k := 0
+	for _, c := range data {
+		for k > 0 && c != p.value[k] {
+			k = p.fail[k-1]
+		}
+
+		if c == p.value[k] {
+			k++
+		}
+	}
+
+	return k
+}
+
+// Redactor 替换一个输出流中沙箱的密钥值，跨越多次写入的密钥值同样被替换.
+// 末尾可能是密钥值开头的字节（最多为最长密钥值的长度减一）保留到下一次写入或 Flush 时再输出，
+// 其他字节立即输出，不会延迟交互式的输出. 结果与对整个输出流调用 Store.Redact 相同.
+// Redactor 不能并发使用；store 为 nil 时原样输出.
+type Redactor struct {
+	store     *Store
+	sandboxID string
+	tail      []byte
+}
+
+// NewRedactor 创建沙箱输出流的替换器，每次替换使用沙箱当时的密钥.
+func (s *Store) NewRedactor(sandboxID string) *Redactor {
+	return &Redactor{store: s, sandboxID: sandboxID}
+}
+
+// Redact 替换之前保留的字节与 data 拼接后的内容，返回可以输出的部分.
+func (r *Redactor) Redact(data []byte) []byte {
+	patterns := r.store.patternsOf(r.sandboxID)
+
+	buf := append(r.tail, data...)
+	r.tail = nil
+
+	if len(patterns) == 0 {
+		return buf
+	}
+
+	// 从 hold 开始的后缀可能是尚未写完的密钥值，从它之后开始的匹配等到后续输出到达时再处理
+	hold := len(buf)
+	for i := range patterns {
+		hold = min(hold, len(buf)-patterns[i].partialSuffix(buf))
+	}
+
+	// next 每个密钥值在 pos 之后的下一次出现位置，-1 表示不再出现
+	next := make([]int, len(patterns))
+	for i := range next {
+		next[i] = indexFrom(buf, patterns[i].value, 0)
+	}
+
+	var out bytes.Buffer
+
+	pos := 0
+
+	for {
+		// 最靠前的匹配，同一位置优先匹配较长的值
+		best := -1
+		for i := range patterns {
+			if next[i] >= 0 && next[i] < pos {
+				next[i] = indexFrom(buf, patterns[i].value, pos)
+			}
+
+			if next[i] >= 0 && (best < 0 || next[i] < next[best]) {
+				best = i
+			}
+		}
+
+		if best < 0 || next[best] >= hold {
+			break
+		}
+
+		out.Write(buf[pos:next[best]])
+		out.Write(patterns[best].replacement)
+		pos = next[best] + len(patterns[best].value)
+	}
+
+	end := max(pos, hold)
+	out.Write(buf[pos:end])
+	r.tail = append([]byte(nil), buf[end:]...)
+
+	return out.Bytes()
+}
+
+// Flush 返回替换后的保留字节，在输出流结束时调用.
+func (r *Redactor) Flush() []byte {
+	tail := r.tail
+	r.tail = nil
+
+	if len(tail) == 0 {
+		return nil
+	}
+
+	return []byte(r.store.Redact(r.sandboxID, string(tail)))
+}
+
+// patternsOf 返回沙箱当前的密钥值.
+func (s *Store) patternsOf(sandboxID string) []pattern {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.patterns[sandboxID]
+}
+
+// indexFrom 返回 value 在 data[from:] 中第一次出现的位置（相对 data），不存在时返回 -1.
+func indexFrom(data, value []byte, from int) int {
+	i := bytes.Index(data[from:], value)
+	if i < 0 {
+		return -1
+	}
+
+	return from + i
+}
@@ -0,0 +1,154 @@
+package testreport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// goTestEvent go test -json 输出的一个事件，见 go doc test2json.
+type goTestEvent struct {
+	Action      string
+	Package     string
+	Test        string
+	Elapsed     float64
+	Output      string
+	ImportPath  string
+	FailedBuild string
+	// OutputType Go 1.24 起标记 t.Error/t.Fatal 输出（error）和状态行（frame）
+	OutputType string
+}
+
+// goTest 一个测试或包的输出.
+type goTest struct {
+	index  int
+	output strings.Builder
+	// errors t.Error/t.Fatal 的输出，用于定位失败的位置
+	errors strings.Builder
+}
+
+// ParseGoTest 解析 go test -json 的输出. 无法解析为 JSON 的行（如 go vet 的输出）被忽略；
+// 编译失败或没有任何测试失败却失败的包记录为 StatusError.
+func ParseGoTest(data []byte) (*Report, error) {
+	var (
+		cases    []Case
+		tests    = make(map[string]*goTest)
+		packages = make(map[string]*goTest)
+		builds   = make(map[string]*strings.Builder)
+		failed   = make(map[string]bool)
+		duration time.Duration
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		var e goTestEvent
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+
+		switch {
+		case e.Action == "build-output":
+			if builds[e.ImportPath] == nil {
+				builds[e.ImportPath] = &strings.Builder{}
+			}
+
+			builds[e.ImportPath].WriteString(e.Output)
+		case e.Test == "":
+			p := packages[e.Package]
+			if p == nil {
+				p = &goTest{index: -1}
+				packages[e.Package] = p
+			}
+
+			switch e.Action {
+			case "output":
+				p.output.WriteString(e.Output)
+			case "pass", "skip":
+				duration += seconds(e.Elapsed)
+			case "fail":
+				duration += seconds(e.Elapsed)
+
+				// 包失败但没有失败的测试：编译失败、TestMain 退出或测试之外的 panic
+				if !failed[e.Package] {
+					message := p.output.String()
+					if e.FailedBuild != "" && builds[e.FailedBuild] != nil {
+						message = builds[e.FailedBuild].String()
+					}
+
+					c := Case{Suite: e.Package, Status: StatusError, Message: message}
+					c.File, c.Line = findLocation(message)
+					cases = append(cases, c)
+				}
+			}
+		default:
+			key := e.Package + "\x00" + e.Test
+
+			t := tests[key]
+			if t == nil {
+				t = &goTest{index: len(cases)}
+				tests[key] = t
+				cases = append(cases, Case{Name: e.Test, Suite: e.Package})
+			}
+
+			c := &cases[t.index]
+
+			switch e.Action {
+			case "output":
+				t.output.WriteString(e.Output)
+
+				if e.OutputType == "error" {
+					t.errors.WriteString(e.Output)
+				}
+			case "pass":
+				c.Status, c.Duration = StatusPassed, seconds(e.Elapsed)
+			case "skip":
+				c.Status, c.Duration = StatusSkipped, seconds(e.Elapsed)
+				c.Message = goTestMessage(t.output.String())
+			case "fail":
+				c.Status, c.Duration = StatusFailed, seconds(e.Elapsed)
+				c.Message = goTestMessage(t.output.String())
+				c.File, c.Line = findLocation(t.errors.String())
+
+				if c.File == "" {
+					c.File, c.Line = findLocation(c.Message)
+				}
+
+				failed[e.Package] = true
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// 运行被中断（如超时被终止）时没有结束事件的测试视为出错
+	for i := range cases {
+		if cases[i].Status == "" {
+			cases[i].Status = StatusError
+			cases[i].Message = "test did not finish"
+		}
+	}
+
+	return newReport(cases, duration), nil
+}
+
+// goTestMessage 去掉测试输出中 go test 自己输出的 "=== RUN"、"--- FAIL" 等状态行.
+func goTestMessage(output string) string {
+	var b strings.Builder
+
+	for _, line := range strings.SplitAfter(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "=== ") || strings.HasPrefix(trimmed, "--- ") {
+			continue
+		}
+
+		b.WriteString(line)
+	}
+
+	return b.String()
+}
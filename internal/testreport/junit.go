@@ -0,0 +1,158 @@
+package testreport
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// junitSuite JUnit XML 的 <testsuite>，可以嵌套.
+type junitSuite struct {
+	Name   string       `xml:"name,attr"`
+	Time   string       `xml:"time,attr"`
+	File   string       `xml:"file,attr"`
+	Suites []junitSuite `xml:"testsuite"`
+	Cases  []junitCase  `xml:"testcase"`
+}
+
+// junitCase JUnit XML 的 <testcase>.
+type junitCase struct {
+	Name      string         `xml:"name,attr"`
+	ClassName string         `xml:"classname,attr"`
+	Time      string         `xml:"time,attr"`
+	File      string         `xml:"file,attr"`
+	Line      string         `xml:"line,attr"`
+	Failures  []junitProblem `xml:"failure"`
+	Errors    []junitProblem `xml:"error"`
+	Skipped   *junitProblem  `xml:"skipped"`
+}
+
+// junitProblem <failure>、<error> 或 <skipped>.
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// ParseJUnit 解析 JUnit XML 报告，根元素可以是 <testsuites> 或 <testsuite>.
+func ParseJUnit(data []byte) (*Report, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	var root junitSuite
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse JUnit report: %w", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch start.Name.Local {
+		case "testsuites":
+			err = decoder.DecodeElement(&root, &start)
+		case "testsuite":
+			var suite junitSuite
+			err = decoder.DecodeElement(&suite, &start)
+			root = junitSuite{Suites: []junitSuite{suite}}
+		default:
+			return nil, errors.New("failed to parse JUnit report: root element is not <testsuites> or <testsuite>")
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse JUnit report: %w", err)
+		}
+
+		var cases []Case
+		collectJUnit(root, "", &cases)
+
+		return newReport(cases, parseSeconds(root.Time)), nil
+	}
+}
+
+// collectJUnit 收集测试套件（包括嵌套套件）中的测试.
+func collectJUnit(suite junitSuite, file string, cases *[]Case) {
+	if suite.File != "" {
+		file = suite.File
+	}
+
+	for _, tc := range suite.Cases {
+		c := Case{
+			Name:     tc.Name,
+			Suite:    tc.ClassName,
+			Status:   StatusPassed,
+			Duration: parseSeconds(tc.Time),
+			File:     tc.File,
+		}
+
+		if c.Suite == "" {
+			c.Suite = suite.Name
+		}
+
+		switch {
+		case len(tc.Failures) > 0:
+			c.Status, c.Message = StatusFailed, junitMessage(tc.Failures)
+		case len(tc.Errors) > 0:
+			c.Status, c.Message = StatusError, junitMessage(tc.Errors)
+		case tc.Skipped != nil:
+			c.Status, c.Message = StatusSkipped, junitMessage([]junitProblem{*tc.Skipped})
+		}
+
+		if line, err := strconv.Atoi(tc.Line); err == nil {
+			c.Line = line
+		}
+
+		// 没有 file/line 属性时从失败信息中查找位置
+		if c.Status == StatusFailed || c.Status == StatusError {
+			if c.File == "" && c.Line == 0 {
+				c.File, c.Line = findLocation(c.Message)
+			}
+		}
+
+		if c.File == "" && c.Status != StatusPassed {
+			c.File = file
+		}
+
+		*cases = append(*cases, c)
+	}
+
+	for _, child := range suite.Suites {
+		collectJUnit(child, file, cases)
+	}
+}
+
+// junitMessage 合并失败的 message 属性和正文.
+func junitMessage(problems []junitProblem) string {
+	parts := make([]string, 0, 2*len(problems))
+
+	for _, p := range problems {
+		message := strings.TrimSpace(p.Message)
+		text := strings.TrimSpace(p.Text)
+
+		if message != "" && !strings.Contains(text, message) {
+			parts = append(parts, message)
+		}
+
+		if text != "" {
+			parts = append(parts, text)
+		}
+	}
+
+	return strings.Join(parts, "\n")
+}
+
+// parseSeconds 解析以秒为单位的时间属性，无效时返回 0.
+func parseSeconds(s string) time.Duration {
+	f, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(s), ",", ""), 64)
+	if err != nil {
+		return 0
+	}
+
+	return seconds(f)
+}
@@ -0,0 +1,162 @@
+package testreport
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// tapResultPattern 匹配 "ok 1 - description # SKIP reason" 和 "not ok 2 description".
+	tapResultPattern = regexp.MustCompile(`^(not ok|ok)\b\s*(\d+)?\s*(?:-\s*)?([^#]*?)\s*(?:#\s*(\w+)\b\s*(.*))?$`)
+	// tapYAMLKeyPattern 匹配 YAML 诊断块中的顶层 "key: value".
+	tapYAMLKeyPattern = regexp.MustCompile(`^(\w+):\s*(.*)$`)
+)
+
+// ParseTAP 解析 TAP（Test Anything Protocol）输出. 失败测试之后缩进的 YAML 诊断块
+// （---/...）中的 message、file/line（或 at）和 duration_ms 被用作失败信息、位置和耗时；
+// 带 TODO 指令的失败测试按跳过统计. 嵌套的子测试按缩进忽略，只统计顶层测试.
+func ParseTAP(data []byte) (*Report, error) {
+	var (
+		cases  []Case
+		last   *Case
+		inYAML bool
+		yaml   strings.Builder
+		indent string
+	)
+
+	finishYAML := func() {
+		if last != nil {
+			applyTAPDiagnostics(last, yaml.String())
+		}
+
+		inYAML = false
+		yaml.Reset()
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		trimmed := strings.TrimSpace(line)
+
+		if inYAML {
+			if trimmed == "..." {
+				finishYAML()
+				continue
+			}
+
+			yaml.WriteString(strings.TrimPrefix(line, indent))
+			yaml.WriteString("\n")
+
+			continue
+		}
+
+		// 顶层测试结果不缩进
+		if line != trimmed {
+			if trimmed == "---" && last != nil {
+				inYAML = true
+				indent = line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+			}
+
+			continue
+		}
+
+		m := tapResultPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		c := Case{Name: m[3], Status: StatusPassed}
+		if c.Name == "" {
+			c.Name = "test " + m[2]
+		}
+
+		directive, reason := strings.ToUpper(m[4]), strings.TrimSpace(m[5])
+
+		switch {
+		case strings.HasPrefix(directive, "SKIP"), directive == "TODO":
+			c.Status, c.Message = StatusSkipped, reason
+		case m[1] == "not ok":
+			c.Status = StatusFailed
+		}
+
+		cases = append(cases, c)
+		last = &cases[len(cases)-1]
+	}
+
+	if inYAML {
+		finishYAML()
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return newReport(cases, 0), nil
+}
+
+// applyTAPDiagnostics 从 YAML 诊断块中读取失败信息、位置和耗时.
+// 位置可以是顶层的 file/line，也可以是 at 的值（"file:line"）或 at 下的 file/line.
+func applyTAPDiagnostics(c *Case, yaml string) {
+	var parent string
+
+	for _, line := range strings.Split(yaml, "\n") {
+		nested := strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+
+		m := tapYAMLKeyPattern.FindStringSubmatch(strings.TrimSpace(line))
+
+		switch {
+		case !nested && m != nil:
+			parent = m[1]
+		case nested && parent == "message":
+			// 多行字符串（如 message: |-）的续行
+			c.Message += "\n" + strings.TrimSpace(line)
+			continue
+		case nested && parent == "at" && m != nil:
+			// node-tap 等将位置写成 at 下的 file/line/column
+		default:
+			continue
+		}
+
+		key, value := m[1], unquote(m[2])
+
+		switch key {
+		case "message":
+			if !nested {
+				c.Message = strings.TrimLeft(value, "|>-")
+			}
+		case "file":
+			c.File = value
+		case "line":
+			c.Line, _ = strconv.Atoi(value)
+		case "at":
+			if !nested && value != "" {
+				c.File, c.Line = findLocation(value)
+			}
+		case "duration_ms":
+			if ms, err := strconv.ParseFloat(value, 64); err == nil && !nested {
+				c.Duration = time.Duration(ms * float64(time.Millisecond))
+			}
+		}
+	}
+
+	// 通过的测试只保留耗时和位置
+	if c.Status == StatusPassed {
+		c.Message = ""
+	}
+}
+
+// unquote 去掉 YAML 标量两端的引号.
+func unquote(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '\'' || value[0] == '"') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+
+	return value
+}
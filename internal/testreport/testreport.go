@@ -0,0 +1,188 @@
+// Package testreport parses the output of test runners (go test -json, JUnit
+// XML, TAP) into per-test results, so that agents get structured failures
+// instead of grepping raw output.
+package testreport
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxMessageSize 每个测试保留的失败信息的最大字节数.
+const maxMessageSize = 8 * 1024
+
+// ErrUnknownFormat 没有注册该格式的解析器.
+var ErrUnknownFormat = errors.New("unknown test report format")
+
+// Status 测试的结果.
+type Status string
+
+const (
+	// StatusPassed 测试通过.
+	StatusPassed Status = "passed"
+	// StatusFailed 测试断言失败.
+	StatusFailed Status = "failed"
+	// StatusSkipped 测试被跳过.
+	StatusSkipped Status = "skipped"
+	// StatusError 测试无法运行（如编译失败、panic 之外的运行器错误）.
+	StatusError Status = "error"
+)
+
+// Case 一个测试的结果.
+type Case struct {
+	Name string
+	// Suite 测试所属的包、类或文件
+	Suite    string
+	Status   Status
+	Duration time.Duration
+	// Message 失败、出错或跳过的原因
+	Message string
+	// File/Line 失败的位置，无法确定时为空
+	File string
+	Line int
+}
+
+// Summary 测试结果统计.
+type Summary struct {
+	Total   int
+	Passed  int
+	Failed  int
+	Skipped int
+	Errors  int
+	// Duration 运行器报告的总耗时，没有报告时为各测试耗时之和
+	Duration time.Duration
+}
+
+// Report 一次测试运行的结果.
+type Report struct {
+	Cases   []Case
+	Summary Summary
+}
+
+// Parser 将测试运行器的报告解析为测试结果.
+type Parser func(data []byte) (*Report, error)
+
+var (
+	parsersMu sync.RWMutex
+	parsers   = map[string]Parser{
+		"go":    ParseGoTest,
+		"junit": ParseJUnit,
+		"tap":   ParseTAP,
+	}
+)
+
+// Register 注册格式的解析器，已存在的同名解析器被替换.
+func Register(format string, parser Parser) {
+	parsersMu.Lock()
+	defer parsersMu.Unlock()
+
+	parsers[format] = parser
+}
+
+// Lookup 返回格式的解析器.
+func Lookup(format string) (Parser, error) {
+	parsersMu.RLock()
+	defer parsersMu.RUnlock()
+
+	parser, ok := parsers[format]
+	if !ok {
+		return nil, fmt.Errorf("%w %q (supported: %s)", ErrUnknownFormat, format, strings.Join(formatsLocked(), ", "))
+	}
+
+	return parser, nil
+}
+
+// Formats 返回已注册的格式，按名称排序.
+func Formats() []string {
+	parsersMu.RLock()
+	defer parsersMu.RUnlock()
+
+	return formatsLocked()
+}
+
+// formatsLocked 返回已注册的格式. 调用方必须持有 parsersMu.
+func formatsLocked() []string {
+	formats := make([]string, 0, len(parsers))
+	for format := range parsers {
+		formats = append(formats, format)
+	}
+
+	sort.Strings(formats)
+
+	return formats
+}
+
+// newReport 根据测试结果生成报告，duration <= 0 时使用各测试耗时之和.
+func newReport(cases []Case, duration time.Duration) *Report {
+	report := &Report{Cases: cases}
+	if report.Cases == nil {
+		report.Cases = []Case{}
+	}
+
+	var sum time.Duration
+
+	for i := range report.Cases {
+		c := &report.Cases[i]
+		c.Message = truncate(c.Message)
+		sum += c.Duration
+
+		report.Summary.Total++
+
+		switch c.Status {
+		case StatusPassed:
+			report.Summary.Passed++
+		case StatusFailed:
+			report.Summary.Failed++
+		case StatusSkipped:
+			report.Summary.Skipped++
+		case StatusError:
+			report.Summary.Errors++
+		}
+	}
+
+	report.Summary.Duration = duration
+	if duration <= 0 {
+		report.Summary.Duration = sum
+	}
+
+	return report
+}
+
+// truncate 截断过长的失败信息，保留开头.
+func truncate(message string) string {
+	message = strings.TrimSpace(message)
+	if len(message) <= maxMessageSize {
+		return message
+	}
+
+	return strings.ToValidUTF8(message[:maxMessageSize], "") + "\n... [truncated]"
+}
+
+// locationPattern 匹配失败信息中的 file.ext:line 位置，如 "foo_test.go:12: want 1"、"at src/a.test.js:3:7".
+var locationPattern = regexp.MustCompile(`([\w./\\-]+\.\w+):(\d+)`)
+
+// findLocation 返回失败信息中第一个 file:line 位置.
+func findLocation(message string) (string, int) {
+	m := locationPattern.FindStringSubmatch(message)
+	if m == nil {
+		return "", 0
+	}
+
+	line, err := strconv.Atoi(m[2])
+	if err != nil {
+		return "", 0
+	}
+
+	return m[1], line
+}
+
+// seconds 将秒数转换为时长.
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
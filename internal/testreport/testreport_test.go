@@ -0,0 +1,223 @@
+package testreport
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// goTestOutput 是 go test -json ./... 的真实输出：a 包中有通过、失败、跳过的测试和子测试，b 包编译失败.
+const goTestOutput = `{"Action":"start","Package":"example.com/gt/a"}
+{"Action":"run","Package":"example.com/gt/a","Test":"TestPass"}
+{"Action":"output","Package":"example.com/gt/a","Test":"TestPass","Output":"=== RUN   TestPass\n","OutputType":"frame"}
+{"Action":"output","Package":"example.com/gt/a","Test":"TestPass","Output":"--- PASS: TestPass (0.00s)\n","OutputType":"frame"}
+{"Action":"pass","Package":"example.com/gt/a","Test":"TestPass","Elapsed":0}
+{"Action":"run","Package":"example.com/gt/a","Test":"TestFail"}
+{"Action":"output","Package":"example.com/gt/a","Test":"TestFail","Output":"=== RUN   TestFail\n","OutputType":"frame"}
+{"Action":"output","Package":"example.com/gt/a","Test":"TestFail","Output":"    a_test.go:8: some log\n"}
+{"Action":"output","Package":"example.com/gt/a","Test":"TestFail","Output":"    a_test.go:9: want 1, got 2\n","OutputType":"error"}
+{"Action":"output","Package":"example.com/gt/a","Test":"TestFail","Output":"--- FAIL: TestFail (0.00s)\n","OutputType":"frame"}
+{"Action":"fail","Package":"example.com/gt/a","Test":"TestFail","Elapsed":0}
+{"Action":"run","Package":"example.com/gt/a","Test":"TestSkip"}
+{"Action":"output","Package":"example.com/gt/a","Test":"TestSkip","Output":"=== RUN   TestSkip\n","OutputType":"frame"}
+{"Action":"output","Package":"example.com/gt/a","Test":"TestSkip","Output":"    a_test.go:12: not today\n"}
+{"Action":"output","Package":"example.com/gt/a","Test":"TestSkip","Output":"--- SKIP: TestSkip (0.00s)\n","OutputType":"frame"}
+{"Action":"skip","Package":"example.com/gt/a","Test":"TestSkip","Elapsed":0}
+{"Action":"run","Package":"example.com/gt/a","Test":"TestSub"}
+{"Action":"output","Package":"example.com/gt/a","Test":"TestSub","Output":"=== RUN   TestSub\n","OutputType":"frame"}
+{"Action":"run","Package":"example.com/gt/a","Test":"TestSub/ok"}
+{"Action":"output","Package":"example.com/gt/a","Test":"TestSub/ok","Output":"=== RUN   TestSub/ok\n","OutputType":"frame"}
+{"Action":"output","Package":"example.com/gt/a","Test":"TestSub/ok","Output":"--- PASS: TestSub/ok (0.00s)\n","OutputType":"frame"}
+{"Action":"pass","Package":"example.com/gt/a","Test":"TestSub/ok","Elapsed":0}
+{"Action":"run","Package":"example.com/gt/a","Test":"TestSub/bad"}
+{"Action":"output","Package":"example.com/gt/a","Test":"TestSub/bad","Output":"=== RUN   TestSub/bad\n","OutputType":"frame"}
+{"Action":"output","Package":"example.com/gt/a","Test":"TestSub/bad","Output":"    a_test.go:16: boom\n","OutputType":"error"}
+{"Action":"output","Package":"example.com/gt/a","Test":"TestSub/bad","Output":"--- FAIL: TestSub/bad (0.00s)\n","OutputType":"frame"}
+{"Action":"fail","Package":"example.com/gt/a","Test":"TestSub/bad","Elapsed":0}
+{"Action":"output","Package":"example.com/gt/a","Test":"TestSub","Output":"--- FAIL: TestSub (0.00s)\n","OutputType":"frame"}
+{"Action":"fail","Package":"example.com/gt/a","Test":"TestSub","Elapsed":0}
+{"Action":"output","Package":"example.com/gt/a","Output":"FAIL\n","OutputType":"frame"}
+{"Action":"output","Package":"example.com/gt/a","Output":"FAIL\texample.com/gt/a\t0.003s\n","OutputType":"frame"}
+{"Action":"fail","Package":"example.com/gt/a","Elapsed":0.003}
+{"ImportPath":"example.com/gt/b [example.com/gt/b.test]","Action":"build-output","Output":"# example.com/gt/b [example.com/gt/b.test]\n"}
+{"ImportPath":"example.com/gt/b [example.com/gt/b.test]","Action":"build-output","Output":"b/b_test.go:5:33: undefined: undefined\n"}
+{"ImportPath":"example.com/gt/b [example.com/gt/b.test]","Action":"build-fail"}
+{"Action":"start","Package":"example.com/gt/b"}
+{"Action":"output","Package":"example.com/gt/b","Output":"FAIL\texample.com/gt/b [build failed]\n","OutputType":"frame"}
+{"Action":"fail","Package":"example.com/gt/b","Elapsed":0,"FailedBuild":"example.com/gt/b [example.com/gt/b.test]"}
+`
+
+func TestParseGoTest(t *testing.T) {
+	report, err := ParseGoTest([]byte("go: downloading example.com/x v1.0.0\n" + goTestOutput))
+	require.NoError(t, err)
+
+	cases := make(map[string]Case)
+	for _, c := range report.Cases {
+		cases[c.Suite+" "+c.Name] = c
+	}
+
+	assert.Equal(t, StatusPassed, cases["example.com/gt/a TestPass"].Status)
+
+	failed := cases["example.com/gt/a TestFail"]
+	assert.Equal(t, StatusFailed, failed.Status)
+	assert.Equal(t, "a_test.go:8: some log\n    a_test.go:9: want 1, got 2", failed.Message)
+	assert.Equal(t, "a_test.go", failed.File)
+	assert.Equal(t, 9, failed.Line)
+
+	skipped := cases["example.com/gt/a TestSkip"]
+	assert.Equal(t, StatusSkipped, skipped.Status)
+	assert.Equal(t, "a_test.go:12: not today", skipped.Message)
+
+	assert.Equal(t, StatusPassed, cases["example.com/gt/a TestSub/ok"].Status)
+	assert.Equal(t, 16, cases["example.com/gt/a TestSub/bad"].Line)
+	assert.Equal(t, StatusFailed, cases["example.com/gt/a TestSub"].Status)
+
+	broken := cases["example.com/gt/b "]
+	assert.Equal(t, StatusError, broken.Status)
+	assert.Contains(t, broken.Message, "undefined: undefined")
+	assert.Equal(t, "b/b_test.go", broken.File)
+	assert.Equal(t, 5, broken.Line)
+
+	assert.Equal(t, Summary{Total: 7, Passed: 2, Failed: 3, Skipped: 1, Errors: 1, Duration: 3 * time.Millisecond}, report.Summary)
+}
+
+func TestParseGoTest_Unfinished(t *testing.T) {
+	report, err := ParseGoTest([]byte(`{"Action":"run","Package":"p","Test":"TestHang"}
+{"Action":"output","Package":"p","Test":"TestHang","Output":"=== RUN   TestHang\n"}
+`))
+	require.NoError(t, err)
+	require.Len(t, report.Cases, 1)
+	assert.Equal(t, StatusError, report.Cases[0].Status)
+	assert.Equal(t, "test did not finish", report.Cases[0].Message)
+}
+
+func TestParseJUnit(t *testing.T) {
+	report, err := ParseJUnit([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<testsuites time="1.5">
+  <testsuite name="math" file="tests/test_math.py">
+    <testcase classname="tests.test_math" name="test_add" time="0.25"/>
+    <testcase classname="tests.test_math" name="test_sub" time="0.5">
+      <failure message="assert 1 == 2">def test_sub():
+&gt;       assert 1 == 2
+E       assert 1 == 2
+
+tests/test_math.py:7: AssertionError</failure>
+    </testcase>
+    <testcase classname="tests.test_math" name="test_div" file="tests/test_math.py" line="12">
+      <error message="ZeroDivisionError: division by zero"/>
+    </testcase>
+    <testcase classname="tests.test_math" name="test_pow">
+      <skipped message="not implemented"/>
+    </testcase>
+  </testsuite>
+</testsuites>`))
+	require.NoError(t, err)
+	require.Len(t, report.Cases, 4)
+
+	assert.Equal(t, Case{Name: "test_add", Suite: "tests.test_math", Status: StatusPassed, Duration: 250 * time.Millisecond}, report.Cases[0])
+
+	failed := report.Cases[1]
+	assert.Equal(t, StatusFailed, failed.Status)
+	assert.Contains(t, failed.Message, "E       assert 1 == 2")
+	assert.Contains(t, failed.Message, "AssertionError")
+	assert.Equal(t, "tests/test_math.py", failed.File)
+	assert.Equal(t, 7, failed.Line)
+
+	errored := report.Cases[2]
+	assert.Equal(t, StatusError, errored.Status)
+	assert.Equal(t, "ZeroDivisionError: division by zero", errored.Message)
+	assert.Equal(t, 12, errored.Line)
+
+	skipped := report.Cases[3]
+	assert.Equal(t, StatusSkipped, skipped.Status)
+	assert.Equal(t, "not implemented", skipped.Message)
+	assert.Equal(t, "tests/test_math.py", skipped.File)
+
+	assert.Equal(t, Summary{Total: 4, Passed: 1, Failed: 1, Skipped: 1, Errors: 1, Duration: 1500 * time.Millisecond}, report.Summary)
+}
+
+func TestParseJUnit_SingleSuite(t *testing.T) {
+	report, err := ParseJUnit([]byte(`<testsuite name="jest" time="0.1"><testcase name="renders" classname="App"/></testsuite>`))
+	require.NoError(t, err)
+	require.Len(t, report.Cases, 1)
+	assert.Equal(t, "App", report.Cases[0].Suite)
+
+	_, err = ParseJUnit([]byte(`<html></html>`))
+	require.Error(t, err)
+
+	_, err = ParseJUnit([]byte(`not xml`))
+	require.Error(t, err)
+}
+
+func TestParseTAP(t *testing.T) {
+	report, err := ParseTAP([]byte(`TAP version 13
+# Subtest: adds
+    ok 1 - inner
+    1..1
+ok 1 - adds
+  ---
+  duration_ms: 1.5
+  ...
+not ok 2 - subtracts
+  ---
+  duration_ms: 2
+  message: |-
+    Expected values to be strictly equal:
+    1 !== 2
+  at:
+    file: test/math.test.js
+    line: 9
+    column: 3
+  ...
+not ok 3 divides # TODO handle zero
+ok 4 - multiplies # SKIP slow
+not ok 5 - parses
+  ---
+  at: 'parse (/src/parse.test.js:21:5)'
+  ...
+1..5
+`))
+	require.NoError(t, err)
+	require.Len(t, report.Cases, 5)
+
+	assert.Equal(t, Case{Name: "adds", Status: StatusPassed, Duration: 1500 * time.Microsecond}, report.Cases[0])
+
+	failed := report.Cases[1]
+	assert.Equal(t, StatusFailed, failed.Status)
+	assert.Equal(t, "Expected values to be strictly equal:\n1 !== 2", failed.Message)
+	assert.Equal(t, "test/math.test.js", failed.File)
+	assert.Equal(t, 9, failed.Line)
+	assert.Equal(t, 2*time.Millisecond, failed.Duration)
+
+	assert.Equal(t, Case{Name: "divides", Status: StatusSkipped, Message: "handle zero"}, report.Cases[2])
+	assert.Equal(t, Case{Name: "multiplies", Status: StatusSkipped, Message: "slow"}, report.Cases[3])
+
+	assert.Equal(t, "/src/parse.test.js", report.Cases[4].File)
+	assert.Equal(t, 21, report.Cases[4].Line)
+
+	assert.Equal(t, Summary{Total: 5, Passed: 1, Failed: 2, Skipped: 2, Duration: 3500 * time.Microsecond}, report.Summary)
+}
+
+func TestLookup(t *testing.T) {
+	for _, format := range []string{"go", "junit", "tap"} {
+		_, err := Lookup(format)
+		require.NoError(t, err)
+	}
+
+	_, err := Lookup("nunit")
+	require.ErrorIs(t, err, ErrUnknownFormat)
+
+	Register("lines", func(data []byte) (*Report, error) {
+		return newReport([]Case{{Name: string(data), Status: StatusPassed}}, 0), nil
+	})
+
+	parser, err := Lookup("lines")
+	require.NoError(t, err)
+
+	report, err := parser([]byte("custom"))
+	require.NoError(t, err)
+	assert.Equal(t, "custom", report.Cases[0].Name)
+	assert.Contains(t, Formats(), "lines")
+}
@@ -0,0 +1,171 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: admin/v1/admin.proto
+
+package adminv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/HJH0924/agent-sandbox/sdk/go/admin/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// AdminServiceName is the fully-qualified name of the AdminService service.
+	AdminServiceName = "admin.v1.AdminService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// AdminServiceListPendingApprovalsProcedure is the fully-qualified name of the AdminService's
+	// ListPendingApprovals RPC.
+	AdminServiceListPendingApprovalsProcedure = "/admin.v1.AdminService/ListPendingApprovals"
+	// AdminServiceApproveProcedure is the fully-qualified name of the AdminService's Approve RPC.
+	AdminServiceApproveProcedure = "/admin.v1.AdminService/Approve"
+	// AdminServiceRejectProcedure is the fully-qualified name of the AdminService's Reject RPC.
+	AdminServiceRejectProcedure = "/admin.v1.AdminService/Reject"
+)
+
+// AdminServiceClient is a client for the admin.v1.AdminService service.
+type AdminServiceClient interface {
+	// ListPendingApprovals 列出等待审批的命令.
+	ListPendingApprovals(context.Context, *connect.Request[v1.ListPendingApprovalsRequest]) (*connect.Response[v1.ListPendingApprovalsResponse], error)
+	// Approve 批准命令，等待中的调用方随即执行命令.
+	Approve(context.Context, *connect.Request[v1.ApproveRequest]) (*connect.Response[v1.ApproveResponse], error)
+	// Reject 拒绝命令.
+	Reject(context.Context, *connect.Request[v1.RejectRequest]) (*connect.Response[v1.RejectResponse], error)
+}
+
+// NewAdminServiceClient constructs a client for the admin.v1.AdminService service. By default, it
+// uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and sends
+// uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewAdminServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) AdminServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	adminServiceMethods := v1.File_admin_v1_admin_proto.Services().ByName("AdminService").Methods()
+	return &adminServiceClient{
+		listPendingApprovals: connect.NewClient[v1.ListPendingApprovalsRequest, v1.ListPendingApprovalsResponse](
+			httpClient,
+			baseURL+AdminServiceListPendingApprovalsProcedure,
+			connect.WithSchema(adminServiceMethods.ByName("ListPendingApprovals")),
+			connect.WithClientOptions(opts...),
+		),
+		approve: connect.NewClient[v1.ApproveRequest, v1.ApproveResponse](
+			httpClient,
+			baseURL+AdminServiceApproveProcedure,
+			connect.WithSchema(adminServiceMethods.ByName("Approve")),
+			connect.WithClientOptions(opts...),
+		),
+		reject: connect.NewClient[v1.RejectRequest, v1.RejectResponse](
+			httpClient,
+			baseURL+AdminServiceRejectProcedure,
+			connect.WithSchema(adminServiceMethods.ByName("Reject")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// adminServiceClient implements AdminServiceClient.
+type adminServiceClient struct {
+	listPendingApprovals *connect.Client[v1.ListPendingApprovalsRequest, v1.ListPendingApprovalsResponse]
+	approve              *connect.Client[v1.ApproveRequest, v1.ApproveResponse]
+	reject               *connect.Client[v1.RejectRequest, v1.RejectResponse]
+}
+
+// ListPendingApprovals calls admin.v1.AdminService.ListPendingApprovals.
+func (c *adminServiceClient) ListPendingApprovals(ctx context.Context, req *connect.Request[v1.ListPendingApprovalsRequest]) (*connect.Response[v1.ListPendingApprovalsResponse], error) {
+	return c.listPendingApprovals.CallUnary(ctx, req)
+}
+
+// Approve calls admin.v1.AdminService.Approve.
+func (c *adminServiceClient) Approve(ctx context.Context, req *connect.Request[v1.ApproveRequest]) (*connect.Response[v1.ApproveResponse], error) {
+	return c.approve.CallUnary(ctx, req)
+}
+
+// Reject calls admin.v1.AdminService.Reject.
+func (c *adminServiceClient) Reject(ctx context.Context, req *connect.Request[v1.RejectRequest]) (*connect.Response[v1.RejectResponse], error) {
+	return c.reject.CallUnary(ctx, req)
+}
+
+// AdminServiceHandler is an implementation of the admin.v1.AdminService service.
+type AdminServiceHandler interface {
+	// ListPendingApprovals 列出等待审批的命令.
+	ListPendingApprovals(context.Context, *connect.Request[v1.ListPendingApprovalsRequest]) (*connect.Response[v1.ListPendingApprovalsResponse], error)
+	// Approve 批准命令，等待中的调用方随即执行命令.
+	Approve(context.Context, *connect.Request[v1.ApproveRequest]) (*connect.Response[v1.ApproveResponse], error)
+	// Reject 拒绝命令.
+	Reject(context.Context, *connect.Request[v1.RejectRequest]) (*connect.Response[v1.RejectResponse], error)
+}
+
+// NewAdminServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewAdminServiceHandler(svc AdminServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	adminServiceMethods := v1.File_admin_v1_admin_proto.Services().ByName("AdminService").Methods()
+	adminServiceListPendingApprovalsHandler := connect.NewUnaryHandler(
+		AdminServiceListPendingApprovalsProcedure,
+		svc.ListPendingApprovals,
+		connect.WithSchema(adminServiceMethods.ByName("ListPendingApprovals")),
+		connect.WithHandlerOptions(opts...),
+	)
+	adminServiceApproveHandler := connect.NewUnaryHandler(
+		AdminServiceApproveProcedure,
+		svc.Approve,
+		connect.WithSchema(adminServiceMethods.ByName("Approve")),
+		connect.WithHandlerOptions(opts...),
+	)
+	adminServiceRejectHandler := connect.NewUnaryHandler(
+		AdminServiceRejectProcedure,
+		svc.Reject,
+		connect.WithSchema(adminServiceMethods.ByName("Reject")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/admin.v1.AdminService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case AdminServiceListPendingApprovalsProcedure:
+			adminServiceListPendingApprovalsHandler.ServeHTTP(w, r)
+		case AdminServiceApproveProcedure:
+			adminServiceApproveHandler.ServeHTTP(w, r)
+		case AdminServiceRejectProcedure:
+			adminServiceRejectHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedAdminServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedAdminServiceHandler struct{}
+
+func (UnimplementedAdminServiceHandler) ListPendingApprovals(context.Context, *connect.Request[v1.ListPendingApprovalsRequest]) (*connect.Response[v1.ListPendingApprovalsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("admin.v1.AdminService.ListPendingApprovals is not implemented"))
+}
+
+func (UnimplementedAdminServiceHandler) Approve(context.Context, *connect.Request[v1.ApproveRequest]) (*connect.Response[v1.ApproveResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("admin.v1.AdminService.Approve is not implemented"))
+}
+
+func (UnimplementedAdminServiceHandler) Reject(context.Context, *connect.Request[v1.RejectRequest]) (*connect.Response[v1.RejectResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("admin.v1.AdminService.Reject is not implemented"))
+}
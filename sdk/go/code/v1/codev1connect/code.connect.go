@@ -0,0 +1,293 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: code/v1/code.proto
+
+package codev1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/HJH0924/agent-sandbox/sdk/go/code/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// CodeServiceName is the fully-qualified name of the CodeService service.
+	CodeServiceName = "code.v1.CodeService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// CodeServiceRunCodeProcedure is the fully-qualified name of the CodeService's RunCode RPC.
+	CodeServiceRunCodeProcedure = "/code.v1.CodeService/RunCode"
+	// CodeServiceCreateKernelProcedure is the fully-qualified name of the CodeService's CreateKernel
+	// RPC.
+	CodeServiceCreateKernelProcedure = "/code.v1.CodeService/CreateKernel"
+	// CodeServiceExecuteCellProcedure is the fully-qualified name of the CodeService's ExecuteCell RPC.
+	CodeServiceExecuteCellProcedure = "/code.v1.CodeService/ExecuteCell"
+	// CodeServiceInterruptKernelProcedure is the fully-qualified name of the CodeService's
+	// InterruptKernel RPC.
+	CodeServiceInterruptKernelProcedure = "/code.v1.CodeService/InterruptKernel"
+	// CodeServiceShutdownKernelProcedure is the fully-qualified name of the CodeService's
+	// ShutdownKernel RPC.
+	CodeServiceShutdownKernelProcedure = "/code.v1.CodeService/ShutdownKernel"
+	// CodeServiceRunTestsProcedure is the fully-qualified name of the CodeService's RunTests RPC.
+	CodeServiceRunTestsProcedure = "/code.v1.CodeService/RunTests"
+	// CodeServiceDiagnosticsProcedure is the fully-qualified name of the CodeService's Diagnostics RPC.
+	CodeServiceDiagnosticsProcedure = "/code.v1.CodeService/Diagnostics"
+)
+
+// CodeServiceClient is a client for the code.v1.CodeService service.
+type CodeServiceClient interface {
+	// RunCode 将代码写入工作空间中的临时文件，并用该语言配置的解释器运行.
+	RunCode(context.Context, *connect.Request[v1.RunCodeRequest]) (*connect.Response[v1.RunCodeResponse], error)
+	// CreateKernel 启动长期运行的交互式解释器，单元之间保留变量等状态.
+	CreateKernel(context.Context, *connect.Request[v1.CreateKernelRequest]) (*connect.Response[v1.CreateKernelResponse], error)
+	// ExecuteCell 在内核中运行代码单元，输出产生时即返回，最后一条消息为运行结果.
+	ExecuteCell(context.Context, *connect.Request[v1.ExecuteCellRequest]) (*connect.ServerStreamForClient[v1.ExecuteCellResponse], error)
+	// InterruptKernel 中断内核中正在运行的单元.
+	InterruptKernel(context.Context, *connect.Request[v1.InterruptKernelRequest]) (*connect.Response[v1.InterruptKernelResponse], error)
+	// ShutdownKernel 终止内核.
+	ShutdownKernel(context.Context, *connect.Request[v1.ShutdownKernelRequest]) (*connect.Response[v1.ShutdownKernelResponse], error)
+	// RunTests 运行服务端配置的测试命令，并将输出解析为每个测试的结果.
+	RunTests(context.Context, *connect.Request[v1.RunTestsRequest]) (*connect.Response[v1.RunTestsResponse], error)
+	// Diagnostics 运行服务端配置的编译器和 linter，并将输出解析为文件/行/列/严重程度/消息的诊断.
+	Diagnostics(context.Context, *connect.Request[v1.DiagnosticsRequest]) (*connect.Response[v1.DiagnosticsResponse], error)
+}
+
+// NewCodeServiceClient constructs a client for the code.v1.CodeService service. By default, it uses
+// the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and sends
+// uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewCodeServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) CodeServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	codeServiceMethods := v1.File_code_v1_code_proto.Services().ByName("CodeService").Methods()
+	return &codeServiceClient{
+		runCode: connect.NewClient[v1.RunCodeRequest, v1.RunCodeResponse](
+			httpClient,
+			baseURL+CodeServiceRunCodeProcedure,
+			connect.WithSchema(codeServiceMethods.ByName("RunCode")),
+			connect.WithClientOptions(opts...),
+		),
+		createKernel: connect.NewClient[v1.CreateKernelRequest, v1.CreateKernelResponse](
+			httpClient,
+			baseURL+CodeServiceCreateKernelProcedure,
+			connect.WithSchema(codeServiceMethods.ByName("CreateKernel")),
+			connect.WithClientOptions(opts...),
+		),
+		executeCell: connect.NewClient[v1.ExecuteCellRequest, v1.ExecuteCellResponse](
+			httpClient,
+			baseURL+CodeServiceExecuteCellProcedure,
+			connect.WithSchema(codeServiceMethods.ByName("ExecuteCell")),
+			connect.WithClientOptions(opts...),
+		),
+		interruptKernel: connect.NewClient[v1.InterruptKernelRequest, v1.InterruptKernelResponse](
+			httpClient,
+			baseURL+CodeServiceInterruptKernelProcedure,
+			connect.WithSchema(codeServiceMethods.ByName("InterruptKernel")),
+			connect.WithClientOptions(opts...),
+		),
+		shutdownKernel: connect.NewClient[v1.ShutdownKernelRequest, v1.ShutdownKernelResponse](
+			httpClient,
+			baseURL+CodeServiceShutdownKernelProcedure,
+			connect.WithSchema(codeServiceMethods.ByName("ShutdownKernel")),
+			connect.WithClientOptions(opts...),
+		),
+		runTests: connect.NewClient[v1.RunTestsRequest, v1.RunTestsResponse](
+			httpClient,
+			baseURL+CodeServiceRunTestsProcedure,
+			connect.WithSchema(codeServiceMethods.ByName("RunTests")),
+			connect.WithClientOptions(opts...),
+		),
+		diagnostics: connect.NewClient[v1.DiagnosticsRequest, v1.DiagnosticsResponse](
+			httpClient,
+			baseURL+CodeServiceDiagnosticsProcedure,
+			connect.WithSchema(codeServiceMethods.ByName("Diagnostics")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// codeServiceClient implements CodeServiceClient.
+type codeServiceClient struct {
+	runCode         *connect.Client[v1.RunCodeRequest, v1.RunCodeResponse]
+	createKernel    *connect.Client[v1.CreateKernelRequest, v1.CreateKernelResponse]
+	executeCell     *connect.Client[v1.ExecuteCellRequest, v1.ExecuteCellResponse]
+	interruptKernel *connect.Client[v1.InterruptKernelRequest, v1.InterruptKernelResponse]
+	shutdownKernel  *connect.Client[v1.ShutdownKernelRequest, v1.ShutdownKernelResponse]
+	runTests        *connect.Client[v1.RunTestsRequest, v1.RunTestsResponse]
+	diagnostics     *connect.Client[v1.DiagnosticsRequest, v1.DiagnosticsResponse]
+}
+
+// RunCode calls code.v1.CodeService.RunCode.
+func (c *codeServiceClient) RunCode(ctx context.Context, req *connect.Request[v1.RunCodeRequest]) (*connect.Response[v1.RunCodeResponse], error) {
+	return c.runCode.CallUnary(ctx, req)
+}
+
+// CreateKernel calls code.v1.CodeService.CreateKernel.
+func (c *codeServiceClient) CreateKernel(ctx context.Context, req *connect.Request[v1.CreateKernelRequest]) (*connect.Response[v1.CreateKernelResponse], error) {
+	return c.createKernel.CallUnary(ctx, req)
+}
+
+// ExecuteCell calls code.v1.CodeService.ExecuteCell.
+func (c *codeServiceClient) ExecuteCell(ctx context.Context, req *connect.Request[v1.ExecuteCellRequest]) (*connect.ServerStreamForClient[v1.ExecuteCellResponse], error) {
+	return c.executeCell.CallServerStream(ctx, req)
+}
+
+// InterruptKernel calls code.v1.CodeService.InterruptKernel.
+func (c *codeServiceClient) InterruptKernel(ctx context.Context, req *connect.Request[v1.InterruptKernelRequest]) (*connect.Response[v1.InterruptKernelResponse], error) {
+	return c.interruptKernel.CallUnary(ctx, req)
+}
+
+// ShutdownKernel calls code.v1.CodeService.ShutdownKernel.
+func (c *codeServiceClient) ShutdownKernel(ctx context.Context, req *connect.Request[v1.ShutdownKernelRequest]) (*connect.Response[v1.ShutdownKernelResponse], error) {
+	return c.shutdownKernel.CallUnary(ctx, req)
+}
+
+// RunTests calls code.v1.CodeService.RunTests.
+func (c *codeServiceClient) RunTests(ctx context.Context, req *connect.Request[v1.RunTestsRequest]) (*connect.Response[v1.RunTestsResponse], error) {
+	return c.runTests.CallUnary(ctx, req)
+}
+
+// Diagnostics calls code.v1.CodeService.Diagnostics.
+func (c *codeServiceClient) Diagnostics(ctx context.Context, req *connect.Request[v1.DiagnosticsRequest]) (*connect.Response[v1.DiagnosticsResponse], error) {
+	return c.diagnostics.CallUnary(ctx, req)
+}
+
+// CodeServiceHandler is an implementation of the code.v1.CodeService service.
+type CodeServiceHandler interface {
+	// RunCode 将代码写入工作空间中的临时文件，并用该语言配置的解释器运行.
+	RunCode(context.Context, *connect.Request[v1.RunCodeRequest]) (*connect.Response[v1.RunCodeResponse], error)
+	// CreateKernel 启动长期运行的交互式解释器，单元之间保留变量等状态.
+	CreateKernel(context.Context, *connect.Request[v1.CreateKernelRequest]) (*connect.Response[v1.CreateKernelResponse], error)
+	// ExecuteCell 在内核中运行代码单元，输出产生时即返回，最后一条消息为运行结果.
+	ExecuteCell(context.Context, *connect.Request[v1.ExecuteCellRequest], *connect.ServerStream[v1.ExecuteCellResponse]) error
+	// InterruptKernel 中断内核中正在运行的单元.
+	InterruptKernel(context.Context, *connect.Request[v1.InterruptKernelRequest]) (*connect.Response[v1.InterruptKernelResponse], error)
+	// ShutdownKernel 终止内核.
+	ShutdownKernel(context.Context, *connect.Request[v1.ShutdownKernelRequest]) (*connect.Response[v1.ShutdownKernelResponse], error)
+	// RunTests 运行服务端配置的测试命令，并将输出解析为每个测试的结果.
+	RunTests(context.Context, *connect.Request[v1.RunTestsRequest]) (*connect.Response[v1.RunTestsResponse], error)
+	// Diagnostics 运行服务端配置的编译器和 linter，并将输出解析为文件/行/列/严重程度/消息的诊断.
+	Diagnostics(context.Context, *connect.Request[v1.DiagnosticsRequest]) (*connect.Response[v1.DiagnosticsResponse], error)
+}
+
+// NewCodeServiceHandler builds an HTTP handler from the service implementation. It returns the path
+// on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewCodeServiceHandler(svc CodeServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	codeServiceMethods := v1.File_code_v1_code_proto.Services().ByName("CodeService").Methods()
+	codeServiceRunCodeHandler := connect.NewUnaryHandler(
+		CodeServiceRunCodeProcedure,
+		svc.RunCode,
+		connect.WithSchema(codeServiceMethods.ByName("RunCode")),
+		connect.WithHandlerOptions(opts...),
+	)
+	codeServiceCreateKernelHandler := connect.NewUnaryHandler(
+		CodeServiceCreateKernelProcedure,
+		svc.CreateKernel,
+		connect.WithSchema(codeServiceMethods.ByName("CreateKernel")),
+		connect.WithHandlerOptions(opts...),
+	)
+	codeServiceExecuteCellHandler := connect.NewServerStreamHandler(
+		CodeServiceExecuteCellProcedure,
+		svc.ExecuteCell,
+		connect.WithSchema(codeServiceMethods.ByName("ExecuteCell")),
+		connect.WithHandlerOptions(opts...),
+	)
+	codeServiceInterruptKernelHandler := connect.NewUnaryHandler(
+		CodeServiceInterruptKernelProcedure,
+		svc.InterruptKernel,
+		connect.WithSchema(codeServiceMethods.ByName("InterruptKernel")),
+		connect.WithHandlerOptions(opts...),
+	)
+	codeServiceShutdownKernelHandler := connect.NewUnaryHandler(
+		CodeServiceShutdownKernelProcedure,
+		svc.ShutdownKernel,
+		connect.WithSchema(codeServiceMethods.ByName("ShutdownKernel")),
+		connect.WithHandlerOptions(opts...),
+	)
+	codeServiceRunTestsHandler := connect.NewUnaryHandler(
+		CodeServiceRunTestsProcedure,
+		svc.RunTests,
+		connect.WithSchema(codeServiceMethods.ByName("RunTests")),
+		connect.WithHandlerOptions(opts...),
+	)
+	codeServiceDiagnosticsHandler := connect.NewUnaryHandler(
+		CodeServiceDiagnosticsProcedure,
+		svc.Diagnostics,
+		connect.WithSchema(codeServiceMethods.ByName("Diagnostics")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/code.v1.CodeService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case CodeServiceRunCodeProcedure:
+			codeServiceRunCodeHandler.ServeHTTP(w, r)
+		case CodeServiceCreateKernelProcedure:
+			codeServiceCreateKernelHandler.ServeHTTP(w, r)
+		case CodeServiceExecuteCellProcedure:
+			codeServiceExecuteCellHandler.ServeHTTP(w, r)
+		case CodeServiceInterruptKernelProcedure:
+			codeServiceInterruptKernelHandler.ServeHTTP(w, r)
+		case CodeServiceShutdownKernelProcedure:
+			codeServiceShutdownKernelHandler.ServeHTTP(w, r)
+		case CodeServiceRunTestsProcedure:
+			codeServiceRunTestsHandler.ServeHTTP(w, r)
+		case CodeServiceDiagnosticsProcedure:
+			codeServiceDiagnosticsHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedCodeServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedCodeServiceHandler struct{}
+
+func (UnimplementedCodeServiceHandler) RunCode(context.Context, *connect.Request[v1.RunCodeRequest]) (*connect.Response[v1.RunCodeResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("code.v1.CodeService.RunCode is not implemented"))
+}
+
+func (UnimplementedCodeServiceHandler) CreateKernel(context.Context, *connect.Request[v1.CreateKernelRequest]) (*connect.Response[v1.CreateKernelResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("code.v1.CodeService.CreateKernel is not implemented"))
+}
+
+func (UnimplementedCodeServiceHandler) ExecuteCell(context.Context, *connect.Request[v1.ExecuteCellRequest], *connect.ServerStream[v1.ExecuteCellResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("code.v1.CodeService.ExecuteCell is not implemented"))
+}
+
+func (UnimplementedCodeServiceHandler) InterruptKernel(context.Context, *connect.Request[v1.InterruptKernelRequest]) (*connect.Response[v1.InterruptKernelResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("code.v1.CodeService.InterruptKernel is not implemented"))
+}
+
+func (UnimplementedCodeServiceHandler) ShutdownKernel(context.Context, *connect.Request[v1.ShutdownKernelRequest]) (*connect.Response[v1.ShutdownKernelResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("code.v1.CodeService.ShutdownKernel is not implemented"))
+}
+
+func (UnimplementedCodeServiceHandler) RunTests(context.Context, *connect.Request[v1.RunTestsRequest]) (*connect.Response[v1.RunTestsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("code.v1.CodeService.RunTests is not implemented"))
+}
+
+func (UnimplementedCodeServiceHandler) Diagnostics(context.Context, *connect.Request[v1.DiagnosticsRequest]) (*connect.Response[v1.DiagnosticsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("code.v1.CodeService.Diagnostics is not implemented"))
+}
@@ -35,11 +35,20 @@ const (
 const (
 	// CoreServiceInitSandboxProcedure is the fully-qualified name of the CoreService's InitSandbox RPC.
 	CoreServiceInitSandboxProcedure = "/core.v1.CoreService/InitSandbox"
+	// CoreServiceGetSandboxProcedure is the fully-qualified name of the CoreService's GetSandbox RPC.
+	CoreServiceGetSandboxProcedure = "/core.v1.CoreService/GetSandbox"
+	// CoreServiceDestroySandboxProcedure is the fully-qualified name of the CoreService's
+	// DestroySandbox RPC.
+	CoreServiceDestroySandboxProcedure = "/core.v1.CoreService/DestroySandbox"
 )
 
 // CoreServiceClient is a client for the core.v1.CoreService service.
 type CoreServiceClient interface {
 	InitSandbox(context.Context, *connect.Request[v1.InitSandboxRequest]) (*connect.Response[v1.InitSandboxResponse], error)
+	// GetSandbox 返回当前 API Key 对应沙箱的信息.
+	GetSandbox(context.Context, *connect.Request[v1.GetSandboxRequest]) (*connect.Response[v1.GetSandboxResponse], error)
+	// DestroySandbox 销毁当前 API Key 对应的沙箱，停止其会话、终端、内核和定时任务，之后 API Key 失效.
+	DestroySandbox(context.Context, *connect.Request[v1.DestroySandboxRequest]) (*connect.Response[v1.DestroySandboxResponse], error)
 }
 
 // NewCoreServiceClient constructs a client for the core.v1.CoreService service. By default, it uses
@@ -59,12 +68,26 @@ func NewCoreServiceClient(httpClient connect.HTTPClient, baseURL string, opts ..
 			connect.WithSchema(coreServiceMethods.ByName("InitSandbox")),
 			connect.WithClientOptions(opts...),
 		),
+		getSandbox: connect.NewClient[v1.GetSandboxRequest, v1.GetSandboxResponse](
+			httpClient,
+			baseURL+CoreServiceGetSandboxProcedure,
+			connect.WithSchema(coreServiceMethods.ByName("GetSandbox")),
+			connect.WithClientOptions(opts...),
+		),
+		destroySandbox: connect.NewClient[v1.DestroySandboxRequest, v1.DestroySandboxResponse](
+			httpClient,
+			baseURL+CoreServiceDestroySandboxProcedure,
+			connect.WithSchema(coreServiceMethods.ByName("DestroySandbox")),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
 // coreServiceClient implements CoreServiceClient.
 type coreServiceClient struct {
-	initSandbox *connect.Client[v1.InitSandboxRequest, v1.InitSandboxResponse]
+	initSandbox    *connect.Client[v1.InitSandboxRequest, v1.InitSandboxResponse]
+	getSandbox     *connect.Client[v1.GetSandboxRequest, v1.GetSandboxResponse]
+	destroySandbox *connect.Client[v1.DestroySandboxRequest, v1.DestroySandboxResponse]
 }
 
 // InitSandbox calls core.v1.CoreService.InitSandbox.
@@ -72,9 +95,23 @@ func (c *coreServiceClient) InitSandbox(ctx context.Context, req *connect.Reques
 	return c.initSandbox.CallUnary(ctx, req)
 }
 
+// GetSandbox calls core.v1.CoreService.GetSandbox.
+func (c *coreServiceClient) GetSandbox(ctx context.Context, req *connect.Request[v1.GetSandboxRequest]) (*connect.Response[v1.GetSandboxResponse], error) {
+	return c.getSandbox.CallUnary(ctx, req)
+}
+
+// DestroySandbox calls core.v1.CoreService.DestroySandbox.
+func (c *coreServiceClient) DestroySandbox(ctx context.Context, req *connect.Request[v1.DestroySandboxRequest]) (*connect.Response[v1.DestroySandboxResponse], error) {
+	return c.destroySandbox.CallUnary(ctx, req)
+}
+
 // CoreServiceHandler is an implementation of the core.v1.CoreService service.
 type CoreServiceHandler interface {
 	InitSandbox(context.Context, *connect.Request[v1.InitSandboxRequest]) (*connect.Response[v1.InitSandboxResponse], error)
+	// GetSandbox 返回当前 API Key 对应沙箱的信息.
+	GetSandbox(context.Context, *connect.Request[v1.GetSandboxRequest]) (*connect.Response[v1.GetSandboxResponse], error)
+	// DestroySandbox 销毁当前 API Key 对应的沙箱，停止其会话、终端、内核和定时任务，之后 API Key 失效.
+	DestroySandbox(context.Context, *connect.Request[v1.DestroySandboxRequest]) (*connect.Response[v1.DestroySandboxResponse], error)
 }
 
 // NewCoreServiceHandler builds an HTTP handler from the service implementation. It returns the path
@@ -90,10 +127,26 @@ func NewCoreServiceHandler(svc CoreServiceHandler, opts ...connect.HandlerOption
 		connect.WithSchema(coreServiceMethods.ByName("InitSandbox")),
 		connect.WithHandlerOptions(opts...),
 	)
+	coreServiceGetSandboxHandler := connect.NewUnaryHandler(
+		CoreServiceGetSandboxProcedure,
+		svc.GetSandbox,
+		connect.WithSchema(coreServiceMethods.ByName("GetSandbox")),
+		connect.WithHandlerOptions(opts...),
+	)
+	coreServiceDestroySandboxHandler := connect.NewUnaryHandler(
+		CoreServiceDestroySandboxProcedure,
+		svc.DestroySandbox,
+		connect.WithSchema(coreServiceMethods.ByName("DestroySandbox")),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/core.v1.CoreService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case CoreServiceInitSandboxProcedure:
 			coreServiceInitSandboxHandler.ServeHTTP(w, r)
+		case CoreServiceGetSandboxProcedure:
+			coreServiceGetSandboxHandler.ServeHTTP(w, r)
+		case CoreServiceDestroySandboxProcedure:
+			coreServiceDestroySandboxHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -106,3 +159,11 @@ type UnimplementedCoreServiceHandler struct{}
 func (UnimplementedCoreServiceHandler) InitSandbox(context.Context, *connect.Request[v1.InitSandboxRequest]) (*connect.Response[v1.InitSandboxResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("core.v1.CoreService.InitSandbox is not implemented"))
 }
+
+func (UnimplementedCoreServiceHandler) GetSandbox(context.Context, *connect.Request[v1.GetSandboxRequest]) (*connect.Response[v1.GetSandboxResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("core.v1.CoreService.GetSandbox is not implemented"))
+}
+
+func (UnimplementedCoreServiceHandler) DestroySandbox(context.Context, *connect.Request[v1.DestroySandboxRequest]) (*connect.Response[v1.DestroySandboxResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("core.v1.CoreService.DestroySandbox is not implemented"))
+}
@@ -0,0 +1,146 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: preview/v1/preview.proto
+
+package previewv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/HJH0924/agent-sandbox/sdk/go/preview/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// PreviewServiceName is the fully-qualified name of the PreviewService service.
+	PreviewServiceName = "preview.v1.PreviewService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// PreviewServiceCreatePreviewURLProcedure is the fully-qualified name of the PreviewService's
+	// CreatePreviewURL RPC.
+	PreviewServiceCreatePreviewURLProcedure = "/preview.v1.PreviewService/CreatePreviewURL"
+	// PreviewServicePortForwardProcedure is the fully-qualified name of the PreviewService's
+	// PortForward RPC.
+	PreviewServicePortForwardProcedure = "/preview.v1.PreviewService/PortForward"
+)
+
+// PreviewServiceClient is a client for the preview.v1.PreviewService service.
+type PreviewServiceClient interface {
+	// CreatePreviewURL 为沙箱中监听在指定端口上的服务生成带短期令牌的预览路径，
+	// 浏览器等无法设置请求头的客户端可以直接打开该路径.
+	CreatePreviewURL(context.Context, *connect.Request[v1.CreatePreviewURLRequest]) (*connect.Response[v1.CreatePreviewURLResponse], error)
+	// PortForward 将一条 TCP 连接转发到沙箱中监听在回环地址上的端口，用于数据库、调试器等非 HTTP 服务.
+	// 客户端关闭发送方向时关闭到沙箱端口连接的写方向，沙箱端关闭连接后流结束.
+	PortForward(context.Context) *connect.BidiStreamForClient[v1.PortForwardRequest, v1.PortForwardResponse]
+}
+
+// NewPreviewServiceClient constructs a client for the preview.v1.PreviewService service. By
+// default, it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses,
+// and sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the
+// connect.WithGRPC() or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewPreviewServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) PreviewServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	previewServiceMethods := v1.File_preview_v1_preview_proto.Services().ByName("PreviewService").Methods()
+	return &previewServiceClient{
+		createPreviewURL: connect.NewClient[v1.CreatePreviewURLRequest, v1.CreatePreviewURLResponse](
+			httpClient,
+			baseURL+PreviewServiceCreatePreviewURLProcedure,
+			connect.WithSchema(previewServiceMethods.ByName("CreatePreviewURL")),
+			connect.WithClientOptions(opts...),
+		),
+		portForward: connect.NewClient[v1.PortForwardRequest, v1.PortForwardResponse](
+			httpClient,
+			baseURL+PreviewServicePortForwardProcedure,
+			connect.WithSchema(previewServiceMethods.ByName("PortForward")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// previewServiceClient implements PreviewServiceClient.
+type previewServiceClient struct {
+	createPreviewURL *connect.Client[v1.CreatePreviewURLRequest, v1.CreatePreviewURLResponse]
+	portForward      *connect.Client[v1.PortForwardRequest, v1.PortForwardResponse]
+}
+
+// CreatePreviewURL calls preview.v1.PreviewService.CreatePreviewURL.
+func (c *previewServiceClient) CreatePreviewURL(ctx context.Context, req *connect.Request[v1.CreatePreviewURLRequest]) (*connect.Response[v1.CreatePreviewURLResponse], error) {
+	return c.createPreviewURL.CallUnary(ctx, req)
+}
+
+// PortForward calls preview.v1.PreviewService.PortForward.
+func (c *previewServiceClient) PortForward(ctx context.Context) *connect.BidiStreamForClient[v1.PortForwardRequest, v1.PortForwardResponse] {
+	return c.portForward.CallBidiStream(ctx)
+}
+
+// PreviewServiceHandler is an implementation of the preview.v1.PreviewService service.
+type PreviewServiceHandler interface {
+	// CreatePreviewURL 为沙箱中监听在指定端口上的服务生成带短期令牌的预览路径，
+	// 浏览器等无法设置请求头的客户端可以直接打开该路径.
+	CreatePreviewURL(context.Context, *connect.Request[v1.CreatePreviewURLRequest]) (*connect.Response[v1.CreatePreviewURLResponse], error)
+	// PortForward 将一条 TCP 连接转发到沙箱中监听在回环地址上的端口，用于数据库、调试器等非 HTTP 服务.
+	// 客户端关闭发送方向时关闭到沙箱端口连接的写方向，沙箱端关闭连接后流结束.
+	PortForward(context.Context, *connect.BidiStream[v1.PortForwardRequest, v1.PortForwardResponse]) error
+}
+
+// NewPreviewServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewPreviewServiceHandler(svc PreviewServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	previewServiceMethods := v1.File_preview_v1_preview_proto.Services().ByName("PreviewService").Methods()
+	previewServiceCreatePreviewURLHandler := connect.NewUnaryHandler(
+		PreviewServiceCreatePreviewURLProcedure,
+		svc.CreatePreviewURL,
+		connect.WithSchema(previewServiceMethods.ByName("CreatePreviewURL")),
+		connect.WithHandlerOptions(opts...),
+	)
+	previewServicePortForwardHandler := connect.NewBidiStreamHandler(
+		PreviewServicePortForwardProcedure,
+		svc.PortForward,
+		connect.WithSchema(previewServiceMethods.ByName("PortForward")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/preview.v1.PreviewService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case PreviewServiceCreatePreviewURLProcedure:
+			previewServiceCreatePreviewURLHandler.ServeHTTP(w, r)
+		case PreviewServicePortForwardProcedure:
+			previewServicePortForwardHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedPreviewServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedPreviewServiceHandler struct{}
+
+func (UnimplementedPreviewServiceHandler) CreatePreviewURL(context.Context, *connect.Request[v1.CreatePreviewURLRequest]) (*connect.Response[v1.CreatePreviewURLResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("preview.v1.PreviewService.CreatePreviewURL is not implemented"))
+}
+
+func (UnimplementedPreviewServiceHandler) PortForward(context.Context, *connect.BidiStream[v1.PortForwardRequest, v1.PortForwardResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("preview.v1.PreviewService.PortForward is not implemented"))
+}
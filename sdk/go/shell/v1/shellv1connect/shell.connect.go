@@ -35,11 +35,58 @@ const (
 const (
 	// ShellServiceExecuteProcedure is the fully-qualified name of the ShellService's Execute RPC.
 	ShellServiceExecuteProcedure = "/shell.v1.ShellService/Execute"
+	// ShellServiceCreateSessionProcedure is the fully-qualified name of the ShellService's
+	// CreateSession RPC.
+	ShellServiceCreateSessionProcedure = "/shell.v1.ShellService/CreateSession"
+	// ShellServiceCloseSessionProcedure is the fully-qualified name of the ShellService's CloseSession
+	// RPC.
+	ShellServiceCloseSessionProcedure = "/shell.v1.ShellService/CloseSession"
+	// ShellServiceTerminalProcedure is the fully-qualified name of the ShellService's Terminal RPC.
+	ShellServiceTerminalProcedure = "/shell.v1.ShellService/Terminal"
+	// ShellServiceListExecutionsProcedure is the fully-qualified name of the ShellService's
+	// ListExecutions RPC.
+	ShellServiceListExecutionsProcedure = "/shell.v1.ShellService/ListExecutions"
+	// ShellServiceWaitForProcedure is the fully-qualified name of the ShellService's WaitFor RPC.
+	ShellServiceWaitForProcedure = "/shell.v1.ShellService/WaitFor"
+	// ShellServiceListRecordingsProcedure is the fully-qualified name of the ShellService's
+	// ListRecordings RPC.
+	ShellServiceListRecordingsProcedure = "/shell.v1.ShellService/ListRecordings"
+	// ShellServiceDownloadRecordingProcedure is the fully-qualified name of the ShellService's
+	// DownloadRecording RPC.
+	ShellServiceDownloadRecordingProcedure = "/shell.v1.ShellService/DownloadRecording"
+	// ShellServiceSetSecretsProcedure is the fully-qualified name of the ShellService's SetSecrets RPC.
+	ShellServiceSetSecretsProcedure = "/shell.v1.ShellService/SetSecrets"
+	// ShellServiceListSecretsProcedure is the fully-qualified name of the ShellService's ListSecrets
+	// RPC.
+	ShellServiceListSecretsProcedure = "/shell.v1.ShellService/ListSecrets"
+	// ShellServiceDeleteSecretsProcedure is the fully-qualified name of the ShellService's
+	// DeleteSecrets RPC.
+	ShellServiceDeleteSecretsProcedure = "/shell.v1.ShellService/DeleteSecrets"
 )
 
 // ShellServiceClient is a client for the shell.v1.ShellService service.
 type ShellServiceClient interface {
 	Execute(context.Context, *connect.Request[v1.ExecuteRequest]) (*connect.Response[v1.ExecuteResponse], error)
+	// CreateSession 创建持久 shell 会话，会话内的命令共享工作目录和环境变量.
+	CreateSession(context.Context, *connect.Request[v1.CreateSessionRequest]) (*connect.Response[v1.CreateSessionResponse], error)
+	// CloseSession 关闭持久 shell 会话.
+	CloseSession(context.Context, *connect.Request[v1.CloseSessionRequest]) (*connect.Response[v1.CloseSessionResponse], error)
+	// Terminal 打开一个交互式伪终端会话，客户端断开后可通过 session_id 重新连接.
+	Terminal(context.Context) *connect.BidiStreamForClient[v1.TerminalRequest, v1.TerminalResponse]
+	// ListExecutions 按从新到旧的顺序分页返回沙箱的命令执行历史.
+	ListExecutions(context.Context, *connect.Request[v1.ListExecutionsRequest]) (*connect.Response[v1.ListExecutionsResponse], error)
+	// WaitFor 等待端口可连接、HTTP 地址返回期望状态码、文件存在或日志中出现匹配的行.
+	WaitFor(context.Context, *connect.Request[v1.WaitForRequest]) (*connect.Response[v1.WaitForResponse], error)
+	// ListRecordings 按从新到旧的顺序返回沙箱的命令、终端和代码单元录制.
+	ListRecordings(context.Context, *connect.Request[v1.ListRecordingsRequest]) (*connect.Response[v1.ListRecordingsResponse], error)
+	// DownloadRecording 分块下载 asciicast v2 格式的录制文件，仍在录制时返回已写入的部分.
+	DownloadRecording(context.Context, *connect.Request[v1.DownloadRecordingRequest]) (*connect.ServerStreamForClient[v1.DownloadRecordingResponse], error)
+	// SetSecrets 按名称设置沙箱的密钥，密钥作为环境变量注入之后启动的命令，并从返回的输出中替换.
+	SetSecrets(context.Context, *connect.Request[v1.SetSecretsRequest]) (*connect.Response[v1.SetSecretsResponse], error)
+	// ListSecrets 返回沙箱的密钥名称，不返回密钥值.
+	ListSecrets(context.Context, *connect.Request[v1.ListSecretsRequest]) (*connect.Response[v1.ListSecretsResponse], error)
+	// DeleteSecrets 删除沙箱的密钥.
+	DeleteSecrets(context.Context, *connect.Request[v1.DeleteSecretsRequest]) (*connect.Response[v1.DeleteSecretsResponse], error)
 }
 
 // NewShellServiceClient constructs a client for the shell.v1.ShellService service. By default, it
@@ -59,12 +106,82 @@ func NewShellServiceClient(httpClient connect.HTTPClient, baseURL string, opts .
 			connect.WithSchema(shellServiceMethods.ByName("Execute")),
 			connect.WithClientOptions(opts...),
 		),
+		createSession: connect.NewClient[v1.CreateSessionRequest, v1.CreateSessionResponse](
+			httpClient,
+			baseURL+ShellServiceCreateSessionProcedure,
+			connect.WithSchema(shellServiceMethods.ByName("CreateSession")),
+			connect.WithClientOptions(opts...),
+		),
+		closeSession: connect.NewClient[v1.CloseSessionRequest, v1.CloseSessionResponse](
+			httpClient,
+			baseURL+ShellServiceCloseSessionProcedure,
+			connect.WithSchema(shellServiceMethods.ByName("CloseSession")),
+			connect.WithClientOptions(opts...),
+		),
+		terminal: connect.NewClient[v1.TerminalRequest, v1.TerminalResponse](
+			httpClient,
+			baseURL+ShellServiceTerminalProcedure,
+			connect.WithSchema(shellServiceMethods.ByName("Terminal")),
+			connect.WithClientOptions(opts...),
+		),
+		listExecutions: connect.NewClient[v1.ListExecutionsRequest, v1.ListExecutionsResponse](
+			httpClient,
+			baseURL+ShellServiceListExecutionsProcedure,
+			connect.WithSchema(shellServiceMethods.ByName("ListExecutions")),
+			connect.WithClientOptions(opts...),
+		),
+		waitFor: connect.NewClient[v1.WaitForRequest, v1.WaitForResponse](
+			httpClient,
+			baseURL+ShellServiceWaitForProcedure,
+			connect.WithSchema(shellServiceMethods.ByName("WaitFor")),
+			connect.WithClientOptions(opts...),
+		),
+		listRecordings: connect.NewClient[v1.ListRecordingsRequest, v1.ListRecordingsResponse](
+			httpClient,
+			baseURL+ShellServiceListRecordingsProcedure,
+			connect.WithSchema(shellServiceMethods.ByName("ListRecordings")),
+			connect.WithClientOptions(opts...),
+		),
+		downloadRecording: connect.NewClient[v1.DownloadRecordingRequest, v1.DownloadRecordingResponse](
+			httpClient,
+			baseURL+ShellServiceDownloadRecordingProcedure,
+			connect.WithSchema(shellServiceMethods.ByName("DownloadRecording")),
+			connect.WithClientOptions(opts...),
+		),
+		setSecrets: connect.NewClient[v1.SetSecretsRequest, v1.SetSecretsResponse](
+			httpClient,
+			baseURL+ShellServiceSetSecretsProcedure,
+			connect.WithSchema(shellServiceMethods.ByName("SetSecrets")),
+			connect.WithClientOptions(opts...),
+		),
+		listSecrets: connect.NewClient[v1.ListSecretsRequest, v1.ListSecretsResponse](
+			httpClient,
+			baseURL+ShellServiceListSecretsProcedure,
+			connect.WithSchema(shellServiceMethods.ByName("ListSecrets")),
+			connect.WithClientOptions(opts...),
+		),
+		deleteSecrets: connect.NewClient[v1.DeleteSecretsRequest, v1.DeleteSecretsResponse](
+			httpClient,
+			baseURL+ShellServiceDeleteSecretsProcedure,
+			connect.WithSchema(shellServiceMethods.ByName("DeleteSecrets")),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
 // shellServiceClient implements ShellServiceClient.
 type shellServiceClient struct {
-	execute *connect.Client[v1.ExecuteRequest, v1.ExecuteResponse]
+	execute           *connect.Client[v1.ExecuteRequest, v1.ExecuteResponse]
+	createSession     *connect.Client[v1.CreateSessionRequest, v1.CreateSessionResponse]
+	closeSession      *connect.Client[v1.CloseSessionRequest, v1.CloseSessionResponse]
+	terminal          *connect.Client[v1.TerminalRequest, v1.TerminalResponse]
+	listExecutions    *connect.Client[v1.ListExecutionsRequest, v1.ListExecutionsResponse]
+	waitFor           *connect.Client[v1.WaitForRequest, v1.WaitForResponse]
+	listRecordings    *connect.Client[v1.ListRecordingsRequest, v1.ListRecordingsResponse]
+	downloadRecording *connect.Client[v1.DownloadRecordingRequest, v1.DownloadRecordingResponse]
+	setSecrets        *connect.Client[v1.SetSecretsRequest, v1.SetSecretsResponse]
+	listSecrets       *connect.Client[v1.ListSecretsRequest, v1.ListSecretsResponse]
+	deleteSecrets     *connect.Client[v1.DeleteSecretsRequest, v1.DeleteSecretsResponse]
 }
 
 // Execute calls shell.v1.ShellService.Execute.
@@ -72,9 +189,79 @@ func (c *shellServiceClient) Execute(ctx context.Context, req *connect.Request[v
 	return c.execute.CallUnary(ctx, req)
 }
 
+// CreateSession calls shell.v1.ShellService.CreateSession.
+func (c *shellServiceClient) CreateSession(ctx context.Context, req *connect.Request[v1.CreateSessionRequest]) (*connect.Response[v1.CreateSessionResponse], error) {
+	return c.createSession.CallUnary(ctx, req)
+}
+
+// CloseSession calls shell.v1.ShellService.CloseSession.
+func (c *shellServiceClient) CloseSession(ctx context.Context, req *connect.Request[v1.CloseSessionRequest]) (*connect.Response[v1.CloseSessionResponse], error) {
+	return c.closeSession.CallUnary(ctx, req)
+}
+
+// Terminal calls shell.v1.ShellService.Terminal.
+func (c *shellServiceClient) Terminal(ctx context.Context) *connect.BidiStreamForClient[v1.TerminalRequest, v1.TerminalResponse] {
+	return c.terminal.CallBidiStream(ctx)
+}
+
+// ListExecutions calls shell.v1.ShellService.ListExecutions.
+func (c *shellServiceClient) ListExecutions(ctx context.Context, req *connect.Request[v1.ListExecutionsRequest]) (*connect.Response[v1.ListExecutionsResponse], error) {
+	return c.listExecutions.CallUnary(ctx, req)
+}
+
+// WaitFor calls shell.v1.ShellService.WaitFor.
+func (c *shellServiceClient) WaitFor(ctx context.Context, req *connect.Request[v1.WaitForRequest]) (*connect.Response[v1.WaitForResponse], error) {
+	return c.waitFor.CallUnary(ctx, req)
+}
+
+// ListRecordings calls shell.v1.ShellService.ListRecordings.
+func (c *shellServiceClient) ListRecordings(ctx context.Context, req *connect.Request[v1.ListRecordingsRequest]) (*connect.Response[v1.ListRecordingsResponse], error) {
+	return c.listRecordings.CallUnary(ctx, req)
+}
+
+// DownloadRecording calls shell.v1.ShellService.DownloadRecording.
+func (c *shellServiceClient) DownloadRecording(ctx context.Context, req *connect.Request[v1.DownloadRecordingRequest]) (*connect.ServerStreamForClient[v1.DownloadRecordingResponse], error) {
+	return c.downloadRecording.CallServerStream(ctx, req)
+}
+
+// SetSecrets calls shell.v1.ShellService.SetSecrets.
+func (c *shellServiceClient) SetSecrets(ctx context.Context, req *connect.Request[v1.SetSecretsRequest]) (*connect.Response[v1.SetSecretsResponse], error) {
+	return c.setSecrets.CallUnary(ctx, req)
+}
+
+// ListSecrets calls shell.v1.ShellService.ListSecrets.
+func (c *shellServiceClient) ListSecrets(ctx context.Context, req *connect.Request[v1.ListSecretsRequest]) (*connect.Response[v1.ListSecretsResponse], error) {
+	return c.listSecrets.CallUnary(ctx, req)
+}
+
+// DeleteSecrets calls shell.v1.ShellService.DeleteSecrets.
+func (c *shellServiceClient) DeleteSecrets(ctx context.Context, req *connect.Request[v1.DeleteSecretsRequest]) (*connect.Response[v1.DeleteSecretsResponse], error) {
+	return c.deleteSecrets.CallUnary(ctx, req)
+}
+
 // ShellServiceHandler is an implementation of the shell.v1.ShellService service.
 type ShellServiceHandler interface {
 	Execute(context.Context, *connect.Request[v1.ExecuteRequest]) (*connect.Response[v1.ExecuteResponse], error)
+	// CreateSession 创建持久 shell 会话，会话内的命令共享工作目录和环境变量.
+	CreateSession(context.Context, *connect.Request[v1.CreateSessionRequest]) (*connect.Response[v1.CreateSessionResponse], error)
+	// CloseSession 关闭持久 shell 会话.
+	CloseSession(context.Context, *connect.Request[v1.CloseSessionRequest]) (*connect.Response[v1.CloseSessionResponse], error)
+	// Terminal 打开一个交互式伪终端会话，客户端断开后可通过 session_id 重新连接.
+	Terminal(context.Context, *connect.BidiStream[v1.TerminalRequest, v1.TerminalResponse]) error
+	// ListExecutions 按从新到旧的顺序分页返回沙箱的命令执行历史.
+	ListExecutions(context.Context, *connect.Request[v1.ListExecutionsRequest]) (*connect.Response[v1.ListExecutionsResponse], error)
+	// WaitFor 等待端口可连接、HTTP 地址返回期望状态码、文件存在或日志中出现匹配的行.
+	WaitFor(context.Context, *connect.Request[v1.WaitForRequest]) (*connect.Response[v1.WaitForResponse], error)
+	// ListRecordings 按从新到旧的顺序返回沙箱的命令、终端和代码单元录制.
+	ListRecordings(context.Context, *connect.Request[v1.ListRecordingsRequest]) (*connect.Response[v1.ListRecordingsResponse], error)
+	// DownloadRecording 分块下载 asciicast v2 格式的录制文件，仍在录制时返回已写入的部分.
+	DownloadRecording(context.Context, *connect.Request[v1.DownloadRecordingRequest], *connect.ServerStream[v1.DownloadRecordingResponse]) error
+	// SetSecrets 按名称设置沙箱的密钥，密钥作为环境变量注入之后启动的命令，并从返回的输出中替换.
+	SetSecrets(context.Context, *connect.Request[v1.SetSecretsRequest]) (*connect.Response[v1.SetSecretsResponse], error)
+	// ListSecrets 返回沙箱的密钥名称，不返回密钥值.
+	ListSecrets(context.Context, *connect.Request[v1.ListSecretsRequest]) (*connect.Response[v1.ListSecretsResponse], error)
+	// DeleteSecrets 删除沙箱的密钥.
+	DeleteSecrets(context.Context, *connect.Request[v1.DeleteSecretsRequest]) (*connect.Response[v1.DeleteSecretsResponse], error)
 }
 
 // NewShellServiceHandler builds an HTTP handler from the service implementation. It returns the
@@ -90,10 +277,90 @@ func NewShellServiceHandler(svc ShellServiceHandler, opts ...connect.HandlerOpti
 		connect.WithSchema(shellServiceMethods.ByName("Execute")),
 		connect.WithHandlerOptions(opts...),
 	)
+	shellServiceCreateSessionHandler := connect.NewUnaryHandler(
+		ShellServiceCreateSessionProcedure,
+		svc.CreateSession,
+		connect.WithSchema(shellServiceMethods.ByName("CreateSession")),
+		connect.WithHandlerOptions(opts...),
+	)
+	shellServiceCloseSessionHandler := connect.NewUnaryHandler(
+		ShellServiceCloseSessionProcedure,
+		svc.CloseSession,
+		connect.WithSchema(shellServiceMethods.ByName("CloseSession")),
+		connect.WithHandlerOptions(opts...),
+	)
+	shellServiceTerminalHandler := connect.NewBidiStreamHandler(
+		ShellServiceTerminalProcedure,
+		svc.Terminal,
+		connect.WithSchema(shellServiceMethods.ByName("Terminal")),
+		connect.WithHandlerOptions(opts...),
+	)
+	shellServiceListExecutionsHandler := connect.NewUnaryHandler(
+		ShellServiceListExecutionsProcedure,
+		svc.ListExecutions,
+		connect.WithSchema(shellServiceMethods.ByName("ListExecutions")),
+		connect.WithHandlerOptions(opts...),
+	)
+	shellServiceWaitForHandler := connect.NewUnaryHandler(
+		ShellServiceWaitForProcedure,
+		svc.WaitFor,
+		connect.WithSchema(shellServiceMethods.ByName("WaitFor")),
+		connect.WithHandlerOptions(opts...),
+	)
+	shellServiceListRecordingsHandler := connect.NewUnaryHandler(
+		ShellServiceListRecordingsProcedure,
+		svc.ListRecordings,
+		connect.WithSchema(shellServiceMethods.ByName("ListRecordings")),
+		connect.WithHandlerOptions(opts...),
+	)
+	shellServiceDownloadRecordingHandler := connect.NewServerStreamHandler(
+		ShellServiceDownloadRecordingProcedure,
+		svc.DownloadRecording,
+		connect.WithSchema(shellServiceMethods.ByName("DownloadRecording")),
+		connect.WithHandlerOptions(opts...),
+	)
+	shellServiceSetSecretsHandler := connect.NewUnaryHandler(
+		ShellServiceSetSecretsProcedure,
+		svc.SetSecrets,
+		connect.WithSchema(shellServiceMethods.ByName("SetSecrets")),
+		connect.WithHandlerOptions(opts...),
+	)
+	shellServiceListSecretsHandler := connect.NewUnaryHandler(
+		ShellServiceListSecretsProcedure,
+		svc.ListSecrets,
+		connect.WithSchema(shellServiceMethods.ByName("ListSecrets")),
+		connect.WithHandlerOptions(opts...),
+	)
+	shellServiceDeleteSecretsHandler := connect.NewUnaryHandler(
+		ShellServiceDeleteSecretsProcedure,
+		svc.DeleteSecrets,
+		connect.WithSchema(shellServiceMethods.ByName("DeleteSecrets")),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/shell.v1.ShellService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case ShellServiceExecuteProcedure:
 			shellServiceExecuteHandler.ServeHTTP(w, r)
+		case ShellServiceCreateSessionProcedure:
+			shellServiceCreateSessionHandler.ServeHTTP(w, r)
+		case ShellServiceCloseSessionProcedure:
+			shellServiceCloseSessionHandler.ServeHTTP(w, r)
+		case ShellServiceTerminalProcedure:
+			shellServiceTerminalHandler.ServeHTTP(w, r)
+		case ShellServiceListExecutionsProcedure:
+			shellServiceListExecutionsHandler.ServeHTTP(w, r)
+		case ShellServiceWaitForProcedure:
+			shellServiceWaitForHandler.ServeHTTP(w, r)
+		case ShellServiceListRecordingsProcedure:
+			shellServiceListRecordingsHandler.ServeHTTP(w, r)
+		case ShellServiceDownloadRecordingProcedure:
+			shellServiceDownloadRecordingHandler.ServeHTTP(w, r)
+		case ShellServiceSetSecretsProcedure:
+			shellServiceSetSecretsHandler.ServeHTTP(w, r)
+		case ShellServiceListSecretsProcedure:
+			shellServiceListSecretsHandler.ServeHTTP(w, r)
+		case ShellServiceDeleteSecretsProcedure:
+			shellServiceDeleteSecretsHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -106,3 +373,43 @@ type UnimplementedShellServiceHandler struct{}
 func (UnimplementedShellServiceHandler) Execute(context.Context, *connect.Request[v1.ExecuteRequest]) (*connect.Response[v1.ExecuteResponse], error) {
 	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("shell.v1.ShellService.Execute is not implemented"))
 }
+
+func (UnimplementedShellServiceHandler) CreateSession(context.Context, *connect.Request[v1.CreateSessionRequest]) (*connect.Response[v1.CreateSessionResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("shell.v1.ShellService.CreateSession is not implemented"))
+}
+
+func (UnimplementedShellServiceHandler) CloseSession(context.Context, *connect.Request[v1.CloseSessionRequest]) (*connect.Response[v1.CloseSessionResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("shell.v1.ShellService.CloseSession is not implemented"))
+}
+
+func (UnimplementedShellServiceHandler) Terminal(context.Context, *connect.BidiStream[v1.TerminalRequest, v1.TerminalResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("shell.v1.ShellService.Terminal is not implemented"))
+}
+
+func (UnimplementedShellServiceHandler) ListExecutions(context.Context, *connect.Request[v1.ListExecutionsRequest]) (*connect.Response[v1.ListExecutionsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("shell.v1.ShellService.ListExecutions is not implemented"))
+}
+
+func (UnimplementedShellServiceHandler) WaitFor(context.Context, *connect.Request[v1.WaitForRequest]) (*connect.Response[v1.WaitForResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("shell.v1.ShellService.WaitFor is not implemented"))
+}
+
+func (UnimplementedShellServiceHandler) ListRecordings(context.Context, *connect.Request[v1.ListRecordingsRequest]) (*connect.Response[v1.ListRecordingsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("shell.v1.ShellService.ListRecordings is not implemented"))
+}
+
+func (UnimplementedShellServiceHandler) DownloadRecording(context.Context, *connect.Request[v1.DownloadRecordingRequest], *connect.ServerStream[v1.DownloadRecordingResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("shell.v1.ShellService.DownloadRecording is not implemented"))
+}
+
+func (UnimplementedShellServiceHandler) SetSecrets(context.Context, *connect.Request[v1.SetSecretsRequest]) (*connect.Response[v1.SetSecretsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("shell.v1.ShellService.SetSecrets is not implemented"))
+}
+
+func (UnimplementedShellServiceHandler) ListSecrets(context.Context, *connect.Request[v1.ListSecretsRequest]) (*connect.Response[v1.ListSecretsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("shell.v1.ShellService.ListSecrets is not implemented"))
+}
+
+func (UnimplementedShellServiceHandler) DeleteSecrets(context.Context, *connect.Request[v1.DeleteSecretsRequest]) (*connect.Response[v1.DeleteSecretsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("shell.v1.ShellService.DeleteSecrets is not implemented"))
+}